@@ -1,21 +1,20 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
-	"encoding/json"
 	"fmt"
-	"net"
-	"net/http"
+	"maps"
 	"os"
+	"slices"
 	"time"
 
+	"craftstory/internal/distribution/youtube"
 	"craftstory/pkg/config"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 )
 
 var (
@@ -30,10 +29,13 @@ var authCmd = &cobra.Command{
 	Long:  `Authenticate with YouTube or other services using credentials from .env`,
 }
 
+var authYouTubeStatus bool
+
 var authYouTubeCmd = &cobra.Command{
-	Use:   "youtube",
+	Use:   "youtube [account]",
 	Short: "Authenticate with YouTube (OAuth)",
-	Long:  `Complete YouTube OAuth flow using credentials from .env file.`,
+	Long:  `Complete YouTube OAuth flow using credentials from .env file, or for a named profile from youtube.accounts.`,
+	Args:  cobra.MaximumNArgs(1),
 	RunE:  runAuthYouTube,
 }
 
@@ -45,6 +47,7 @@ var authStatusCmd = &cobra.Command{
 }
 
 func init() {
+	authYouTubeCmd.Flags().BoolVar(&authYouTubeStatus, "status", false, "Show token validity, expiry and the authorized channel")
 	authCmd.AddCommand(authYouTubeCmd)
 	authCmd.AddCommand(authStatusCmd)
 	rootCmd.AddCommand(authCmd)
@@ -53,7 +56,7 @@ func init() {
 func runAuthStatus(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	cfg, err := config.Load(ctx)
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -71,6 +74,22 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 		fmt.Println(authErrorStyle.Render("✗ YouTube: missing YOUTUBE_CLIENT_ID or YOUTUBE_CLIENT_SECRET"))
 	}
 
+	for _, name := range slices.Sorted(maps.Keys(cfg.YouTube.Accounts)) {
+		account, _ := cfg.YouTubeAccount(name)
+		if _, err := os.Stat(account.TokenPath); err == nil {
+			fmt.Println(authSuccessStyle.Render(fmt.Sprintf("✓ YouTube (%s): authenticated (token exists)", name)))
+		} else {
+			fmt.Println(authErrorStyle.Render(fmt.Sprintf("✗ YouTube (%s): not authenticated", name)))
+			fmt.Println(authInfoStyle.Render("  Run: craftstory auth youtube " + name))
+		}
+	}
+
+	if cfg.TikTokAccessToken != "" {
+		fmt.Println(authSuccessStyle.Render("✓ TikTok: access token configured"))
+	} else {
+		fmt.Println(authInfoStyle.Render("○ TikTok: not configured (optional)"))
+	}
+
 	if cfg.GroqAPIKey != "" {
 		fmt.Println(authSuccessStyle.Render("✓ Groq: API key configured"))
 	} else {
@@ -110,106 +129,80 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 func runAuthYouTube(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
-	cfg, err := config.Load(ctx)
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if cfg.YouTubeClientID == "" || cfg.YouTubeClientSecret == "" {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	account, err := cfg.YouTubeAccount(name)
+	if err != nil {
+		return err
+	}
+	if account.ClientID == "" || account.ClientSecret == "" {
 		return fmt.Errorf("YOUTUBE_CLIENT_ID and YOUTUBE_CLIENT_SECRET must be set in .env")
 	}
 
-	return runYouTubeAuth(cfg.YouTubeClientID, cfg.YouTubeClientSecret, cfg.YouTubeTokenPath)
+	if authYouTubeStatus {
+		return printYouTubeStatus(ctx, account)
+	}
+	return runYouTubeAuth(account.ClientID, account.ClientSecret, account.TokenPath)
 }
 
-func runYouTubeAuth(clientID, clientSecret, tokenPath string) error {
-	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
-	successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
-
-	oauthConfig := &oauth2.Config{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		Endpoint:     google.Endpoint,
-		Scopes: []string{
-			"https://www.googleapis.com/auth/youtube.upload",
-			"https://www.googleapis.com/auth/youtube",
-		},
-		RedirectURL: "http://localhost:8085/callback",
+func printYouTubeStatus(ctx context.Context, account config.YouTubeAccount) error {
+	client := youtube.NewClient(youtube.Config{Auth: youtube.NewAuth(account.ClientID, account.ClientSecret, account.TokenPath)})
+	status, err := client.Status(ctx)
+	if status == nil {
+		fmt.Println(authErrorStyle.Render("✗ YouTube: not authenticated (" + account.TokenPath + ")"))
+		fmt.Println(authInfoStyle.Render("  Run: craftstory auth youtube"))
+		return err
 	}
 
-	codeChan := make(chan string, 1)
-	errChan := make(chan error, 1)
-
-	listener, err := net.Listen("tcp", ":8085")
-	if err != nil {
-		return fmt.Errorf("failed to start callback server: %w", err)
+	if status.Authenticated {
+		fmt.Println(authSuccessStyle.Render("✓ Access token valid until " + status.Expiry.Local().Format(time.RFC1123)))
+	} else {
+		fmt.Println(authErrorStyle.Render("✗ Access token expired at " + status.Expiry.Local().Format(time.RFC1123)))
 	}
-
-	server := &http.Server{
-		ReadHeaderTimeout: 10 * time.Second,
+	if status.HasRefreshToken {
+		fmt.Println(authSuccessStyle.Render("✓ Refresh token present"))
+	} else {
+		fmt.Println(authErrorStyle.Render("✗ No refresh token, re-run: craftstory auth youtube"))
 	}
+	if err != nil {
+		fmt.Println(authErrorStyle.Render("✗ Channel lookup failed: " + err.Error()))
+		return nil
+	}
+	fmt.Println(authSuccessStyle.Render("✓ Channel: " + status.Channel))
+	return nil
+}
 
-	server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/callback" {
-			http.NotFound(w, r)
-			return
-		}
-
-		code := r.URL.Query().Get("code")
-		if code == "" {
-			errChan <- fmt.Errorf("no code in callback")
-			_, _ = fmt.Fprintf(w, "<html><body><h1>Error</h1><p>No authorization code received.</p></body></html>")
-			return
-		}
+func runYouTubeAuth(clientID, clientSecret, tokenPath string) error {
+	auth := youtube.NewAuth(clientID, clientSecret, tokenPath)
 
-		codeChan <- code
-		_, _ = fmt.Fprintf(w, "<html><body><h1>Success!</h1><p>You can close this window and return to the terminal.</p></body></html>")
+	fmt.Println(authInfoStyle.Render("\nOpening browser for YouTube authentication..."))
+	err := auth.AuthorizeLocal(context.Background(), youtube.LocalFlow{
+		OpenBrowser: browser.OpenURL,
+		PromptCode:  promptAuthCode,
 	})
+	if err != nil {
+		return err
+	}
 
-	go func() {
-		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
-			errChan <- err
-		}
-	}()
-
-	defer func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-		defer cancel()
-		_ = server.Shutdown(ctx)
-	}()
-
-	authURL := oauthConfig.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	fmt.Println(infoStyle.Render("\nOpening browser for YouTube authentication..."))
-	fmt.Println(infoStyle.Render("If browser doesn't open, visit:\n" + authURL))
-
-	_ = browser.OpenURL(authURL)
-
-	fmt.Println(infoStyle.Render("\nWaiting for authentication..."))
-
-	select {
-	case code := <-codeChan:
-		token, err := oauthConfig.Exchange(context.Background(), code)
-		if err != nil {
-			return fmt.Errorf("failed to exchange code: %w", err)
-		}
-
-		data, err := json.MarshalIndent(token, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal token: %w", err)
-		}
-
-		if err := os.WriteFile(tokenPath, data, 0600); err != nil {
-			return fmt.Errorf("failed to save token: %w", err)
-		}
-
-		fmt.Println(successStyle.Render("✓ YouTube authentication complete"))
-		fmt.Println(successStyle.Render("  Token saved to: " + tokenPath))
-		return nil
+	fmt.Println(authSuccessStyle.Render("✓ YouTube authentication complete"))
+	fmt.Println(authSuccessStyle.Render("  Token saved to: " + tokenPath))
+	return nil
+}
 
-	case err := <-errChan:
-		return err
+func promptAuthCode(authURL string) (string, error) {
+	fmt.Println(authInfoStyle.Render("Could not open a browser. Visit this URL on any device:\n" + authURL))
+	fmt.Println(authInfoStyle.Render("\nAfter approving, paste the code or the full URL you were redirected to:"))
 
-	case <-time.After(5 * time.Minute):
-		return fmt.Errorf("authentication timed out")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read authorization code: %w", err)
 	}
+	return line, nil
 }
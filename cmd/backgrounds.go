@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"craftstory/internal/storage"
+	"craftstory/internal/video"
+	"craftstory/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultTargetDuration = 60.0
+
+var backgroundsCmd = &cobra.Command{
+	Use:   "backgrounds",
+	Short: "List available background clips",
+	Long: `List every clip in the background directory with its duration and
+resolution. Clips shorter than the target video duration are flagged.`,
+	RunE: runBackgrounds,
+}
+
+func init() {
+	rootCmd.AddCommand(backgroundsCmd)
+}
+
+func runBackgrounds(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	clips, err := storage.NewLocalStorage(cfg.Video.BackgroundDir, cfg.Video.OutputDir).ListBackgroundClips()
+	if err != nil {
+		return err
+	}
+	if len(clips) == 0 {
+		fmt.Printf("No background clips found in %s\n", cfg.Video.BackgroundDir)
+		return nil
+	}
+
+	target := backgroundTarget(cfg)
+	assembler := video.NewAssembler(cfg.Video.OutputDir, nil, nil)
+
+	fmt.Println(authInfoStyle.Render(fmt.Sprintf("\nBackground clips (target %.0fs):\n", target)))
+
+	short := 0
+	for _, clip := range clips {
+		name := filepath.Base(clip)
+		info, err := assembler.ProbeClip(ctx, clip)
+		if err != nil {
+			fmt.Println(authErrorStyle.Render(fmt.Sprintf("✗ %-40s %v", name, err)))
+			continue
+		}
+
+		line := fmt.Sprintf("%-40s %7.1fs  %dx%d", name, info.Duration, info.Width, info.Height)
+		if info.Duration < target {
+			short++
+			fmt.Println(authErrorStyle.Render("! " + line + "  (shorter than target)"))
+			continue
+		}
+		fmt.Println(authSuccessStyle.Render("✓ " + line))
+	}
+
+	fmt.Printf("\n%d clip(s), %d shorter than target\n", len(clips), short)
+	return nil
+}
+
+func backgroundTarget(cfg *config.Config) float64 {
+	if cfg.Content.TargetDuration > 0 {
+		return cfg.Content.TargetDuration
+	}
+	if cfg.Video.MaxDuration > 0 {
+		return cfg.Video.MaxDuration * 0.85
+	}
+	return defaultTargetDuration
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"craftstory/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	batchTopicsFile string
+	batchConcurrent int
+	batchUpload     bool
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Generate a video for every topic in a file",
+	Long: `Generate one video per line of a topics file (blank lines and lines starting
+with # are skipped). A failing topic does not stop the rest; a summary is
+printed at the end.`,
+	RunE: runBatch,
+}
+
+func init() {
+	batchCmd.Flags().StringVar(&batchTopicsFile, "topics", "", "File with one topic per line")
+	batchCmd.Flags().IntVarP(&batchConcurrent, "concurrency", "c", 1, "Number of videos to generate at once")
+	batchCmd.Flags().BoolVarP(&batchUpload, "upload", "u", false, "Upload each video after generation")
+	rootCmd.AddCommand(batchCmd)
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	if batchTopicsFile == "" {
+		return errors.New("please provide --topics")
+	}
+
+	topics, err := app.ReadTopics(batchTopicsFile)
+	if err != nil {
+		return err
+	}
+	if len(topics) == 0 {
+		return fmt.Errorf("no topics found in %s", batchTopicsFile)
+	}
+
+	ctx := cmd.Context()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	service, err := app.BuildService(cfg, verbose)
+	if err != nil {
+		return err
+	}
+
+	results := app.NewPipeline(service).GenerateBatch(ctx, topics, app.BatchOptions{
+		Concurrency: batchConcurrent,
+		Upload:      batchUpload,
+	})
+
+	failed := printBatchSummary(results)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d topics failed", failed, len(results))
+	}
+	return nil
+}
+
+func printBatchSummary(results []app.BatchResult) int {
+	failed := 0
+	fmt.Println(infoStyle.Render(fmt.Sprintf("\nBatch summary (%d topics):\n", len(results))))
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Println(authErrorStyle.Render(fmt.Sprintf("✗ %s: %v", result.Topic, result.Err)))
+		} else {
+			fmt.Println(successStyle.Render(fmt.Sprintf("✓ %s → %s", result.Topic, result.Result.VideoPath)))
+		}
+		for platform, upload := range result.Uploads {
+			if upload.Err == nil {
+				fmt.Printf("    %s: %s\n", platform, upload.Response.URL)
+			}
+		}
+	}
+	fmt.Printf("\n%d succeeded, %d failed\n", len(results)-failed, failed)
+	return failed
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"craftstory/internal/distribution/telegram"
-	"craftstory/pkg/config"
 
 	"github.com/spf13/cobra"
 )
@@ -21,7 +20,7 @@ func init() {
 }
 
 func runClear(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load(cmd.Context())
+	cfg, err := loadConfig(cmd.Context())
 	if err != nil {
 		return fmt.Errorf("load config: %w", err)
 	}
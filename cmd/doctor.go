@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"craftstory/internal/distribution/youtube"
+	"craftstory/internal/search/google"
+	"craftstory/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	doctorTimeout    = 15 * time.Second
+	groqModelsURL    = "https://api.groq.com/openai/v1/models"
+	elevenLabsURL    = "https://api.elevenlabs.io/v1/voices"
+	doctorQueryLimit = 1
+)
+
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkFailed
+	checkSkipped
+)
+
+type checkResult struct {
+	name   string
+	status checkStatus
+	detail string
+}
+
+var doctorCmd = &cobra.Command{
+	Use:     "doctor",
+	Aliases: []string{"check"},
+	Short:   "Validate configured credentials and tools",
+	Long: `Load config and actively check every configured service: LLM, TTS,
+image search, YouTube token and ffmpeg. Nothing is generated or uploaded.`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	results := []checkResult{
+		checkBinary("ffmpeg"),
+		checkBinary("ffprobe"),
+		checkGroq(ctx, cfg),
+		checkElevenLabs(ctx, cfg),
+		checkGoogleSearch(ctx, cfg),
+		checkYouTube(cfg),
+	}
+
+	fmt.Println(authInfoStyle.Render("\nCraftstory Doctor:\n"))
+
+	failed := 0
+	for _, r := range results {
+		switch r.status {
+		case checkOK:
+			fmt.Println(authSuccessStyle.Render(fmt.Sprintf("✓ %-14s %s", r.name, r.detail)))
+		case checkFailed:
+			failed++
+			fmt.Println(authErrorStyle.Render(fmt.Sprintf("✗ %-14s %s", r.name, r.detail)))
+		case checkSkipped:
+			fmt.Println(authInfoStyle.Render(fmt.Sprintf("○ %-14s %s", r.name, r.detail)))
+		}
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+func checkBinary(name string) checkResult {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return checkResult{name: name, status: checkFailed, detail: "not found in PATH"}
+	}
+	return checkResult{name: name, status: checkOK, detail: path}
+}
+
+func checkGroq(ctx context.Context, cfg *config.Config) checkResult {
+	if cfg.GroqAPIKey == "" {
+		return checkResult{name: "Groq", status: checkFailed, detail: "missing GROQ_API_KEY"}
+	}
+
+	if err := pingAPI(ctx, groqModelsURL, "Authorization", "Bearer "+cfg.GroqAPIKey); err != nil {
+		return checkResult{name: "Groq", status: checkFailed, detail: err.Error()}
+	}
+	return checkResult{name: "Groq", status: checkOK, detail: "API key valid"}
+}
+
+func checkElevenLabs(ctx context.Context, cfg *config.Config) checkResult {
+	if !cfg.ElevenLabs.Enabled {
+		return checkResult{name: "ElevenLabs", status: checkSkipped, detail: "disabled in config"}
+	}
+	apiKeys := cfg.ElevenLabsAPIKeys
+	if len(apiKeys) == 0 && cfg.ElevenLabsAPIKey != "" {
+		apiKeys = []string{cfg.ElevenLabsAPIKey}
+	}
+	if len(apiKeys) == 0 {
+		return checkResult{name: "ElevenLabs", status: checkFailed, detail: "missing ELEVENLABS_API_KEY"}
+	}
+
+	var lastErr error
+	valid := 0
+	for _, key := range apiKeys {
+		if err := pingAPI(ctx, elevenLabsURL, "xi-api-key", key); err != nil {
+			lastErr = err
+			continue
+		}
+		valid++
+	}
+
+	detail := fmt.Sprintf("%d/%d API key(s) valid", valid, len(apiKeys))
+	if valid == 0 {
+		return checkResult{name: "ElevenLabs", status: checkFailed, detail: fmt.Sprintf("%s: %v", detail, lastErr)}
+	}
+	return checkResult{name: "ElevenLabs", status: checkOK, detail: detail}
+}
+
+func checkGoogleSearch(ctx context.Context, cfg *config.Config) checkResult {
+	if cfg.GoogleSearchAPIKey == "" && cfg.GoogleSearchEngineID == "" {
+		return checkResult{name: "Google Search", status: checkSkipped, detail: "not configured (optional)"}
+	}
+	if cfg.GoogleSearchAPIKey == "" || cfg.GoogleSearchEngineID == "" {
+		return checkResult{name: "Google Search", status: checkFailed, detail: "partially configured"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+
+	client := google.NewClient(google.Config{
+		APIKey:   cfg.GoogleSearchAPIKey,
+		EngineID: cfg.GoogleSearchEngineID,
+	})
+	if _, err := client.Search(ctx, "test", doctorQueryLimit); err != nil {
+		return checkResult{name: "Google Search", status: checkFailed, detail: err.Error()}
+	}
+	return checkResult{name: "Google Search", status: checkOK, detail: "test query succeeded"}
+}
+
+func checkYouTube(cfg *config.Config) checkResult {
+	account, err := cfg.YouTubeAccount("")
+	if err != nil {
+		return checkResult{name: "YouTube", status: checkFailed, detail: err.Error()}
+	}
+	if account.ClientID == "" || account.ClientSecret == "" {
+		return checkResult{name: "YouTube", status: checkSkipped, detail: "not configured (optional)"}
+	}
+
+	auth := youtube.NewAuth(account.ClientID, account.ClientSecret, account.TokenPath)
+	if !auth.IsAuthenticated() {
+		return checkResult{name: "YouTube", status: checkFailed, detail: "token missing or expired, run: craftstory auth youtube"}
+	}
+	return checkResult{name: "YouTube", status: checkOK, detail: "token valid"}
+}
+
+func pingAPI(ctx context.Context, url, header, value string) error {
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set(header, value)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
@@ -5,7 +5,6 @@ import (
 	"log/slog"
 
 	"craftstory/internal/app"
-	"craftstory/pkg/config"
 
 	"github.com/spf13/cobra"
 )
@@ -14,30 +13,40 @@ var (
 	onceTopic     string
 	onceUseReddit bool
 	onceUpload    bool
+	onceResume    string
+	onceAudioOnly bool
 )
 
 var onceCmd = &cobra.Command{
 	Use:   "once",
 	Short: "Generate a single video",
-	Long:  `Generate a single video from a topic or random Reddit post.`,
+	Long:  `Generate a single video from a topic or the configured topic source (Reddit by default).`,
 	RunE:  runOnce,
 }
 
 func init() {
 	onceCmd.Flags().StringVarP(&onceTopic, "topic", "t", "", "Topic for video generation")
-	onceCmd.Flags().BoolVarP(&onceUseReddit, "reddit", "r", false, "Generate video from Reddit topic")
+	onceCmd.Flags().BoolVarP(&onceUseReddit, "reddit", "r", false, "Generate video from the configured topic source")
 	onceCmd.Flags().BoolVarP(&onceUpload, "upload", "u", false, "Upload to YouTube after generation")
+	onceCmd.Flags().StringVar(&onceResume, "resume", "", "Resume a failed generation from its session directory")
+	onceCmd.Flags().BoolVar(&onceAudioOnly, "audio-only", false, "Generate only the narrated audio and SRT captions, skipping video assembly")
 	rootCmd.AddCommand(onceCmd)
 }
 
 func runOnce(cmd *cobra.Command, args []string) error {
-	if onceTopic == "" && !onceUseReddit {
-		return errors.New("please provide --topic or --reddit")
+	if onceTopic == "" && !onceUseReddit && onceResume == "" {
+		return errors.New("please provide --topic, --reddit or --resume")
+	}
+	if onceAudioOnly && onceResume != "" {
+		return errors.New("--audio-only cannot be combined with --resume")
+	}
+	if onceAudioOnly && onceUpload {
+		return errors.New("--audio-only cannot be combined with --upload")
 	}
 
 	ctx := cmd.Context()
 
-	cfg, err := config.Load(ctx)
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		return err
 	}
@@ -50,10 +59,19 @@ func runOnce(cmd *cobra.Command, args []string) error {
 	pipeline := app.NewPipeline(service)
 
 	var genResult *app.GenerateResult
-	if onceUseReddit {
-		slog.Info("Generating video from Reddit...")
-		genResult, err = pipeline.GenerateFromReddit(ctx)
-	} else {
+	switch {
+	case onceResume != "":
+		genResult, err = pipeline.Resume(ctx, onceResume)
+	case onceAudioOnly && onceUseReddit:
+		slog.Info("Generating audio from topic source...")
+		genResult, err = pipeline.GenerateAudioFromSource(ctx)
+	case onceAudioOnly:
+		slog.Info("Generating audio...", "topic", onceTopic)
+		genResult, err = pipeline.GenerateAudio(ctx, onceTopic)
+	case onceUseReddit:
+		slog.Info("Generating video from topic source...")
+		genResult, err = pipeline.GenerateFromSource(ctx)
+	default:
 		slog.Info("Generating video...", "topic", onceTopic)
 		genResult, err = pipeline.Generate(ctx, onceTopic)
 	}
@@ -62,6 +80,16 @@ func runOnce(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if onceAudioOnly {
+		slog.Info("Audio generated",
+			"title", genResult.Title,
+			"audio", genResult.AudioPath,
+			"captions", genResult.CaptionsPath,
+			"duration", genResult.Duration,
+		)
+		return nil
+	}
+
 	slog.Info("Video generated",
 		"title", genResult.Title,
 		"tags", genResult.Tags,
@@ -70,17 +98,25 @@ func runOnce(cmd *cobra.Command, args []string) error {
 	)
 
 	if onceUpload {
-		slog.Info("Uploading to YouTube...")
-		resp, err := pipeline.Upload(ctx, app.UploadRequest{
+		slog.Info("Uploading...")
+		results, err := pipeline.UploadAll(ctx, app.UploadRequest{
 			VideoPath:   genResult.VideoPath,
 			Title:       genResult.Title,
-			Description: genResult.ScriptContent,
+			Description: genResult.Description,
 			Tags:        genResult.Tags,
+			Thumbnail:   genResult.ThumbnailPath,
+			Source:      genResult.Source,
 		})
 		if err != nil {
 			return err
 		}
-		slog.Info("Upload complete", "url", resp.URL)
+		for platform, result := range results {
+			if result.Err != nil {
+				slog.Error("Upload failed", "platform", platform, "error", result.Err)
+				continue
+			}
+			slog.Info("Upload complete", "platform", platform, "url", result.Response.URL)
+		}
 	}
 
 	return nil
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"craftstory/internal/app"
+	"craftstory/internal/distribution/telegram"
+	"craftstory/internal/review"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+const (
+	queueActionPreview = "preview"
+	queueActionApprove = "approve"
+	queueActionReject  = "reject"
+	queueActionBack    = "back"
+	queueActionRefresh = -1
+	queueActionQuit    = -2
+)
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Browse the approval queue and approve or reject videos locally",
+	Long: `Interactive browser for the approval queue shared with the Telegram bot.
+Play previews, approve (uploads immediately) or reject queued videos without Telegram.`,
+	RunE: runQueue,
+}
+
+func init() {
+	rootCmd.AddCommand(queueCmd)
+}
+
+func runQueue(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	browser := review.NewBrowser(cfg.Video.OutputDir)
+	var pipeline *app.Pipeline
+
+	for {
+		browser.Refresh()
+		if len(browser.Items()) == 0 {
+			fmt.Println(infoStyle.Render("Approval queue is empty"))
+			return nil
+		}
+
+		choice := browser.Cursor()
+		options := make([]huh.Option[int], 0, len(browser.Items())+2)
+		for i, video := range browser.Items() {
+			options = append(options, huh.NewOption(queueLabel(video), i))
+		}
+		options = append(options,
+			huh.NewOption("↻ Refresh", queueActionRefresh),
+			huh.NewOption("Quit", queueActionQuit),
+		)
+
+		if err := huh.NewSelect[int]().
+			Title(fmt.Sprintf("Approval queue (%d)", len(browser.Items()))).
+			Options(options...).
+			Value(&choice).
+			Run(); err != nil {
+			return err
+		}
+
+		switch choice {
+		case queueActionQuit:
+			return nil
+		case queueActionRefresh:
+			continue
+		}
+		browser.Select(choice)
+
+		video, err := browser.Selected()
+		if err != nil {
+			continue
+		}
+
+		var action string
+		if err := huh.NewSelect[string]().
+			Title(video.Title).
+			Description(video.Topic).
+			Options(
+				huh.NewOption("▶ Play preview", queueActionPreview),
+				huh.NewOption("✅ Approve and upload", queueActionApprove),
+				huh.NewOption("❌ Reject", queueActionReject),
+				huh.NewOption("← Back", queueActionBack),
+			).
+			Value(&action).
+			Run(); err != nil {
+			return err
+		}
+
+		switch action {
+		case queueActionPreview:
+			if err := playPreview(video); err != nil {
+				fmt.Println(warnStyle.Render(err.Error()))
+			}
+		case queueActionApprove:
+			if pipeline == nil {
+				service, err := app.BuildService(cfg, verbose)
+				if err != nil {
+					return err
+				}
+				pipeline = app.NewPipeline(service)
+			}
+			approveQueued(cmd, browser, pipeline)
+		case queueActionReject:
+			if _, err := browser.Reject(); err != nil {
+				fmt.Println(warnStyle.Render(err.Error()))
+				continue
+			}
+			fmt.Println(successStyle.Render("Rejected " + video.Title))
+			if err := app.CleanupSession(cfg, video.VideoPath, false); err != nil {
+				fmt.Println(warnStyle.Render("Failed to cleanup session: " + err.Error()))
+			}
+		}
+	}
+}
+
+func approveQueued(cmd *cobra.Command, browser *review.Browser, pipeline *app.Pipeline) {
+	video, err := browser.Approve()
+	if err != nil {
+		fmt.Println(warnStyle.Render(err.Error()))
+		return
+	}
+
+	fmt.Println(infoStyle.Render("Uploading " + video.Title + "..."))
+	results, err := pipeline.UploadAll(cmd.Context(), approvedUploadRequest(video))
+	var urls []string
+	if err == nil {
+		urls, err = uploadedURLs(results)
+	}
+	for _, url := range urls {
+		fmt.Println(successStyle.Render("Uploaded: " + url))
+	}
+	if err != nil {
+		fmt.Println(authErrorStyle.Render("Upload failed: " + err.Error()))
+		if len(urls) > 0 {
+			return
+		}
+		if err := browser.Requeue(video); err != nil {
+			fmt.Println(authErrorStyle.Render("Failed to requeue video: " + err.Error()))
+		}
+		return
+	}
+
+	if video.PreviewPath != "" {
+		_ = os.Remove(video.PreviewPath)
+	}
+	if err := pipeline.CleanupSession(video.VideoPath, true); err != nil {
+		fmt.Println(warnStyle.Render("Failed to cleanup session: " + err.Error()))
+	}
+}
+
+func queueLabel(video telegram.QueuedVideo) string {
+	label := fmt.Sprintf("%s  (%s)", video.Title, video.AddedAt.Format("Jan 2 15:04"))
+	if video.Priority > 0 {
+		label = "★ " + label
+	}
+	return label
+}
+
+func playPreview(video telegram.QueuedVideo) error {
+	path := video.PreviewPath
+	if path == "" {
+		path = video.VideoPath
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("preview not found: %s", path)
+	}
+
+	var opener string
+	switch runtime.GOOS {
+	case "darwin":
+		opener = "open"
+	case "windows":
+		opener = "explorer"
+	default:
+		opener = "xdg-open"
+	}
+
+	player := exec.Command(opener, path)
+	if err := player.Start(); err != nil {
+		return fmt.Errorf("open preview: %w", err)
+	}
+	go func() { _ = player.Wait() }()
+	return nil
+}
@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"log/slog"
 	"os"
 
+	"craftstory/pkg/config"
+
 	"github.com/spf13/cobra"
 )
 
-var verbose bool
+var (
+	verbose        bool
+	configPath     string
+	youtubeAccount string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "craftstory",
@@ -18,6 +25,8 @@ with text-to-speech narration, background visuals, and optional YouTube upload.`
 
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable debug logging")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", config.DefaultPath, "Path to a YAML or TOML config file")
+	rootCmd.PersistentFlags().StringVar(&youtubeAccount, "account", "", "YouTube account profile from youtube.accounts")
 	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
 		setupLogger()
 	}
@@ -27,6 +36,20 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+func loadConfig(ctx context.Context) (*config.Config, error) {
+	cfg, err := config.LoadFile(ctx, configPath)
+	if err != nil {
+		return nil, err
+	}
+	if youtubeAccount != "" {
+		if _, err := cfg.YouTubeAccount(youtubeAccount); err != nil {
+			return nil, err
+		}
+		cfg.YouTube.Account = youtubeAccount
+	}
+	return cfg, nil
+}
+
 func setupLogger() {
 	level := slog.LevelInfo
 	if verbose {
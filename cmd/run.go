@@ -2,28 +2,43 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"craftstory/internal/app"
+	"craftstory/internal/distribution"
 	"craftstory/internal/distribution/telegram"
+	"craftstory/internal/metrics"
+	"craftstory/internal/webhook"
 	"craftstory/pkg/config"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	runInterval time.Duration
-	runUpload   bool
+	runInterval     time.Duration
+	runUpload       bool
+	runDrainTimeout time.Duration
+)
+
+const (
+	webhookQueueSize    = 32
+	webhookEventTimeout = 15 * time.Second
 )
 
 var runCmd = &cobra.Command{
 	Use:   "run",
-	Short: "Cron mode: generate from Reddit, queue for approval, repeat",
-	Long: `Run in continuous mode, generating videos from Reddit posts at regular intervals.
+	Short: "Cron mode: generate from the topic source, queue for approval, repeat",
+	Long: `Run in continuous mode, generating videos from the configured topic source at regular intervals.
 Videos are queued for Telegram approval unless --upload is specified.`,
 	RunE: runCron,
 }
@@ -31,6 +46,7 @@ Videos are queued for Telegram approval unless --upload is specified.`,
 func init() {
 	runCmd.Flags().DurationVarP(&runInterval, "interval", "i", 15*time.Minute, "Interval between generations")
 	runCmd.Flags().BoolVarP(&runUpload, "upload", "u", false, "Upload directly instead of queueing for approval")
+	runCmd.Flags().DurationVar(&runDrainTimeout, "drain-timeout", 2*time.Minute, "How long to wait for in-flight work on shutdown")
 	rootCmd.AddCommand(runCmd)
 }
 
@@ -38,7 +54,7 @@ func runCron(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
 
-	cfg, err := config.Load(ctx)
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		return err
 	}
@@ -50,85 +66,224 @@ func runCron(cmd *cobra.Command, args []string) error {
 
 	pipeline := app.NewPipeline(service)
 	approval := service.Approval()
+	reporter := newCronReporter(ctx, service.Webhook(), metrics.NewCollector())
+	if approval != nil {
+		reporter.collector.SetQueueDepth(approval.Queue().Len)
+	}
+
+	if cfg.Metrics.Addr != "" {
+		serverDone := make(chan struct{})
+		go func() {
+			defer close(serverDone)
+			if err := reporter.collector.Serve(ctx, cfg.Metrics.Addr); err != nil {
+				slog.Error("Metrics server stopped", "error", err)
+			}
+		}()
+		defer func() {
+			cancel()
+			<-serverDone
+		}()
+		slog.Info("Serving metrics", "addr", cfg.Metrics.Addr)
+	}
+
+	acceptCtx, stopAccepting := context.WithCancel(ctx)
+	defer stopAccepting()
+
+	drained := make(chan struct{})
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go drainOnSignal(sigChan, stopAccepting, cancel, drained)
 
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+	defer signal.Stop(reloadChan)
+
+	var workers sync.WaitGroup
 	if !runUpload && approval != nil {
 		approval.StartBot()
 		defer approval.StopBot()
 
-		go handleApprovals(ctx, pipeline, approval)
-		go handleGenerations(ctx, pipeline, approval)
+		workers.Add(2)
+		go func() {
+			defer workers.Done()
+			handleApprovals(acceptCtx, ctx, pipeline, approval, reporter)
+		}()
+		go func() {
+			defer workers.Done()
+			handleGenerations(acceptCtx, ctx, &workers, pipeline, approval, reporter)
+		}()
 	}
 
-	slog.Info("Starting cron mode", "interval", runInterval, "approval", !runUpload && approval != nil)
+	interval := cronInterval(cmd, cfg)
+	slog.Info("Starting cron mode", "interval", interval, "approval", !runUpload && approval != nil)
 
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	backoff := app.NewBackoff(time.Minute, interval)
 
 	generate := func() {
+		if acceptCtx.Err() != nil {
+			return
+		}
 		if approval != nil && approval.Queue().IsFull() {
 			slog.Info("Queue is full, skipping generation")
 			return
 		}
 
-		slog.Info("Generating video from Reddit...")
-		genResult, err := pipeline.GenerateFromReddit(ctx)
+		slog.Info("Generating video from topic source...")
+		genResult, err := pipeline.GenerateFromSource(ctx)
+		reporter.generation(genResult, err)
+		if errors.Is(err, app.ErrLowDiskSpace) {
+			slog.Warn("Skipping generation", "error", err)
+			return
+		}
 		if err != nil {
-			slog.Error("Generation failed", "error", err)
+			delay := backoff.Failure()
+			ticker.Reset(interval + delay)
+			slog.Error("Generation failed", "error", err, "failures", backoff.Failures(), "next_attempt", interval+delay)
 			return
 		}
+		if backoff.Failures() > 0 {
+			backoff.Success()
+			ticker.Reset(interval)
+		}
 
 		slog.Info("Video generated", "title", genResult.Title, "tags", genResult.Tags, "path", genResult.VideoPath)
 
 		if runUpload {
-			resp, err := pipeline.Upload(ctx, app.UploadRequest{
+			results, err := pipeline.UploadAll(ctx, app.UploadRequest{
 				VideoPath:   genResult.VideoPath,
 				Title:       genResult.Title,
-				Description: genResult.ScriptContent,
+				Description: genResult.Description,
 				Tags:        genResult.Tags,
+				Thumbnail:   genResult.ThumbnailPath,
+				Source:      genResult.Source,
 			})
 			if err != nil {
 				slog.Error("Upload failed", "error", err)
+				reporter.upload(uploadEvent(genResult.Title, genResult.VideoPath, "", nil, err))
 				return
 			}
-			slog.Info("Upload complete", "url", resp.URL)
+			failed := false
+			for platform, result := range results {
+				reporter.upload(uploadEvent(genResult.Title, genResult.VideoPath, platform, result.Response, result.Err))
+				if errors.Is(result.Err, app.ErrUploadsPaused) {
+					failed = true
+					until, _ := pipeline.UploadsPausedUntil()
+					slog.Warn("Uploads paused, keeping video for later", "platform", platform, "until", until, "path", genResult.VideoPath)
+					if approval != nil && errors.Is(result.Err, distribution.ErrQuotaExceeded) {
+						approval.NotifyUploadsPaused(genResult.Title, until, nil)
+					}
+					continue
+				}
+				if result.Err != nil {
+					failed = true
+					slog.Error("Upload failed", "platform", platform, "error", result.Err)
+					continue
+				}
+				slog.Info("Upload complete", "platform", platform, "url", result.Response.URL)
+			}
+			if !failed {
+				cleanupSession(pipeline, genResult.VideoPath, true)
+			}
 			return
 		}
 
 		if approval != nil {
 			_, err := approval.RequestApproval(ctx, telegram.ApprovalRequest{
-				VideoPath:   genResult.VideoPath,
-				PreviewPath: genResult.PreviewPath,
-				Title:       genResult.Title,
-				Script:      genResult.ScriptContent,
-				Tags:        genResult.Tags,
+				VideoPath:     genResult.VideoPath,
+				ThumbnailPath: genResult.ThumbnailPath,
+				Topic:         genResult.Topic,
+				Title:         genResult.Title,
+				Script:        genResult.ScriptContent,
+				Description:   genResult.Description,
+				Tags:          genResult.Tags,
+				Source:        genResult.Source,
 			})
 			if err != nil {
 				slog.Error("Failed to queue for approval", "error", err)
+				return
 			}
+			attachPreview(ctx, &workers, pipeline, approval, reporter, genResult)
 		}
 	}
 
-	ticker := time.NewTicker(runInterval)
-	defer ticker.Stop()
+	reload := func() {
+		newCfg, err := loadConfig(ctx)
+		if err != nil {
+			slog.Error("Config reload failed, keeping current config", "error", err)
+			return
+		}
+		if restart := service.Reload(newCfg); len(restart) > 0 {
+			slog.Warn("Config sections changed that only apply after a restart", "sections", restart)
+		}
+
+		if next := cronInterval(cmd, newCfg); next != interval {
+			interval = next
+			backoff.Max = interval
+			ticker.Reset(interval)
+		}
+		slog.Info("Config reloaded",
+			"interval", interval,
+			"subreddits", newCfg.Reddit.Subreddits,
+			"visuals", newCfg.Visuals.Count,
+		)
+	}
 
 	generate()
 
-	for {
+	for acceptCtx.Err() == nil {
 		select {
-		case <-sigChan:
-			slog.Info("Shutting down...")
-			return nil
-		case <-ctx.Done():
-			return nil
+		case <-acceptCtx.Done():
+		case <-reloadChan:
+			reload()
 		case <-ticker.C:
 			generate()
 		}
 	}
+
+	workers.Wait()
+	reporter.close()
+	close(drained)
+	slog.Info("Shutdown complete")
+	return nil
+}
+
+func cronInterval(cmd *cobra.Command, cfg *config.Config) time.Duration {
+	if cmd.Flags().Changed("interval") || cfg.Run.Interval <= 0 {
+		return runInterval
+	}
+	return time.Duration(cfg.Run.Interval * float64(time.Second))
+}
+
+func drainOnSignal(sigChan <-chan os.Signal, stopAccepting, abort context.CancelFunc, drained <-chan struct{}) {
+	select {
+	case <-sigChan:
+		slog.Info("Shutting down, finishing in-flight work...", "timeout", runDrainTimeout)
+		stopAccepting()
+	case <-drained:
+		return
+	}
+
+	select {
+	case <-sigChan:
+		slog.Warn("Second signal received, aborting in-flight work")
+	case <-time.After(runDrainTimeout):
+		slog.Warn("Drain timeout reached, aborting in-flight work")
+	case <-drained:
+		return
+	}
+	abort()
 }
 
-func handleApprovals(ctx context.Context, pipeline *app.Pipeline, approval *telegram.ApprovalService) {
+func handleApprovals(acceptCtx, ctx context.Context, pipeline *app.Pipeline, approval *telegram.ApprovalService, reporter *cronReporter) {
+	var held sync.WaitGroup
+	defer held.Wait()
+
 	for {
-		result, video, err := approval.WaitForResult(ctx)
+		result, video, err := approval.WaitForResult(acceptCtx)
 		if err != nil {
 			return
 		}
@@ -138,25 +293,57 @@ func handleApprovals(ctx context.Context, pipeline *app.Pipeline, approval *tele
 		}
 
 		if !result.Approved {
-			slog.Info("Video rejected", "title", video.Title)
+			slog.Info("Video rejected", "title", video.Title, "reason", result.Message)
+			cleanupSession(pipeline, video.VideoPath, false)
 			continue
 		}
 
 		slog.Info("Video approved, uploading...", "title", video.Title)
-		resp, err := pipeline.Upload(ctx, app.UploadRequest{
-			VideoPath:   video.VideoPath,
-			Title:       video.Title,
-			Description: video.Script,
-			Tags:        video.Tags,
-		})
+		results, err := pipeline.UploadAll(ctx, approvedUploadRequest(*video))
+		if err != nil && ctx.Err() != nil {
+			slog.Warn("Upload interrupted by shutdown, requeueing for review", "title", video.Title)
+			if err := approval.Requeue(*video); err != nil {
+				slog.Error("Failed to requeue video", "title", video.Title, "error", err)
+			}
+			return
+		}
 		if err != nil {
 			slog.Error("Upload failed", "error", err)
+			reporter.upload(uploadEvent(video.Title, video.VideoPath, "", nil, err))
 			approval.NotifyUploadFailed(video.Title, err, video)
 			continue
 		}
+		for platform, result := range results {
+			reporter.upload(uploadEvent(video.Title, video.VideoPath, platform, result.Response, result.Err))
+		}
 
-		slog.Info("Upload complete", "title", video.Title, "url", resp.URL)
-		approval.NotifyUploadComplete(video.Title, resp.URL, video)
+		urls, err := uploadedURLs(results)
+		if err != nil && len(urls) == 0 && ctx.Err() != nil {
+			slog.Warn("Upload interrupted by shutdown, requeueing for review", "title", video.Title)
+			if err := approval.Requeue(*video); err != nil {
+				slog.Error("Failed to requeue video", "title", video.Title, "error", err)
+			}
+			return
+		}
+		if len(urls) == 0 && errors.Is(err, app.ErrUploadsPaused) {
+			until, _ := pipeline.UploadsPausedUntil()
+			slog.Warn("Uploads paused, holding approved video until quota reset", "title", video.Title, "until", until)
+			approval.NotifyUploadsPaused(video.Title, until, video)
+			held.Add(1)
+			go func(video telegram.QueuedVideo) {
+				defer held.Done()
+				requeueAt(acceptCtx, approval, video, until)
+			}(*video)
+			continue
+		}
+		if err != nil {
+			slog.Error("Upload failed", "title", video.Title, "uploaded", urls, "error", err)
+			approval.NotifyUploadFailed(video.Title, err, video)
+			continue
+		}
+
+		slog.Info("Upload complete", "title", video.Title, "urls", urls)
+		approval.NotifyUploadComplete(video.Title, strings.Join(urls, "\n"), video)
 
 		if video.PreviewPath != "" {
 			if err := os.Remove(video.PreviewPath); err != nil {
@@ -165,14 +352,64 @@ func handleApprovals(ctx context.Context, pipeline *app.Pipeline, approval *tele
 				slog.Debug("Cleaned up preview file", "path", video.PreviewPath)
 			}
 		}
+		cleanupSession(pipeline, video.VideoPath, true)
 	}
 }
 
-func handleGenerations(ctx context.Context, pipeline *app.Pipeline, approval *telegram.ApprovalService) {
+func approvedUploadRequest(video telegram.QueuedVideo) app.UploadRequest {
+	description := video.Description
+	if description == "" {
+		description = video.Script
+	}
+	return app.UploadRequest{
+		VideoPath:   video.VideoPath,
+		Title:       video.Title,
+		Description: description,
+		Tags:        video.Tags,
+		Thumbnail:   video.ThumbnailPath,
+		Account:     video.Account,
+		Source:      video.Source,
+	}
+}
+
+func uploadedURLs(results map[string]app.UploadResult) ([]string, error) {
+	var urls []string
+	var errs []error
+	for _, platform := range slices.Sorted(maps.Keys(results)) {
+		result := results[platform]
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		urls = append(urls, result.Response.URL)
+	}
+	return urls, errors.Join(errs...)
+}
+
+func requeueAt(ctx context.Context, approval *telegram.ApprovalService, video telegram.QueuedVideo, at time.Time) {
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	if err := approval.Requeue(video); err != nil {
+		slog.Error("Failed to requeue video", "title", video.Title, "error", err)
+	}
+}
+
+func cleanupSession(pipeline *app.Pipeline, videoPath string, keepVideo bool) {
+	if err := pipeline.CleanupSession(videoPath, keepVideo); err != nil {
+		slog.Warn("Failed to cleanup session artifacts", "path", videoPath, "error", err)
+	}
+}
+
+func handleGenerations(acceptCtx, ctx context.Context, previews *sync.WaitGroup, pipeline *app.Pipeline, approval *telegram.ApprovalService, reporter *cronReporter) {
 	for {
-		req, err := approval.WaitForGenerationRequest(ctx)
+		req, err := approval.WaitForGenerationRequest(acceptCtx)
 		if err != nil {
-			if ctx.Err() != nil {
+			if acceptCtx.Err() != nil {
 				return
 			}
 			time.Sleep(time.Second)
@@ -180,24 +417,166 @@ func handleGenerations(ctx context.Context, pipeline *app.Pipeline, approval *te
 		}
 
 		slog.Info("Processing generation request", "topic", req.Topic, "from_reddit", req.FromReddit, "chat_id", req.ChatID)
-		approval.NotifyGenerating(req.ChatID, req.Topic)
+		topic := req.Topic
+		if req.FromReddit {
+			topic = ""
+		}
+		if req.Account != "" && !pipeline.HasAccount(req.Account) {
+			approval.NotifyGenerationFailed(req.ChatID, fmt.Sprintf("Unknown YouTube account %q. Add it under youtube.accounts.", req.Account))
+			approval.FailGeneration(req.ChatID)
+			continue
+		}
+		approval.NotifyGenerating(req.ChatID, topic)
 
 		var genResult *app.GenerateResult
 		if req.FromReddit {
-			genResult, err = pipeline.GenerateFromReddit(ctx)
+			genResult, err = pipeline.GenerateFromSource(ctx)
 		} else {
 			genResult, err = pipeline.Generate(ctx, req.Topic)
 		}
+		if err != nil && ctx.Err() != nil {
+			slog.Warn("Generation interrupted by shutdown, will retry on next start", "topic", req.Topic)
+			return
+		}
+		reporter.generation(genResult, err)
 
 		if err != nil {
 			slog.Error("Generation failed", "error", err)
-			approval.NotifyGenerationFailed(req.ChatID, err.Error())
+			approval.NotifyGenerationFailed(req.ChatID, app.FailureMessage(err))
 			approval.FailGeneration(req.ChatID)
 			continue
 		}
 
 		slog.Info("Video generated", "title", genResult.Title, "tags", genResult.Tags, "path", genResult.VideoPath)
-		approval.NotifyGenerationComplete(req.ChatID, genResult.VideoPath, genResult.PreviewPath, genResult.Title, genResult.ScriptContent, genResult.Tags)
+		approval.NotifyGenerationComplete(req.ChatID, telegram.ApprovalRequest{
+			VideoPath:     genResult.VideoPath,
+			ThumbnailPath: genResult.ThumbnailPath,
+			Topic:         genResult.Topic,
+			Title:         genResult.Title,
+			Script:        genResult.ScriptContent,
+			Description:   genResult.Description,
+			Tags:          genResult.Tags,
+			Account:       req.Account,
+			Source:        genResult.Source,
+			ForReview:     req.ForReview,
+		})
 		approval.CompleteGeneration(req.ChatID)
+		attachPreview(ctx, previews, pipeline, approval, reporter, genResult)
+	}
+}
+
+func attachPreview(ctx context.Context, previews *sync.WaitGroup, pipeline *app.Pipeline, approval *telegram.ApprovalService, reporter *cronReporter, result *app.GenerateResult) {
+	previews.Add(1)
+	go func() {
+		defer previews.Done()
+		previewPath := pipeline.CreatePreview(ctx, result)
+		if approval.AttachPreview(result.VideoPath, previewPath) {
+			reporter.notify(webhook.Event{
+				Event:       webhook.EventPreview,
+				Success:     true,
+				Title:       result.Title,
+				VideoPath:   result.VideoPath,
+				PreviewPath: previewPath,
+			})
+		}
+	}()
+}
+
+type cronReporter struct {
+	hook      *webhook.Client
+	collector *metrics.Collector
+	events    chan webhook.Event
+	done      chan struct{}
+}
+
+func newCronReporter(ctx context.Context, hook *webhook.Client, collector *metrics.Collector) *cronReporter {
+	r := &cronReporter{hook: hook, collector: collector}
+	if hook == nil {
+		return r
+	}
+
+	r.events = make(chan webhook.Event, webhookQueueSize)
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		for event := range r.events {
+			sendCtx, cancel := context.WithTimeout(ctx, webhookEventTimeout)
+			if err := r.hook.Send(sendCtx, event); err != nil {
+				slog.Warn("Webhook delivery failed", "event", event.Event, "error", err)
+			}
+			cancel()
+		}
+	}()
+	return r
+}
+
+func (r *cronReporter) generation(result *app.GenerateResult, err error) {
+	var stages map[string]time.Duration
+	if result != nil {
+		stages = result.Metrics.Stages()
+	}
+	r.collector.ObserveGeneration(stages, err)
+	r.notify(generationEvent(result, err))
+}
+
+func (r *cronReporter) upload(event webhook.Event) {
+	r.collector.ObserveUpload(event.Platform, event.Success)
+	r.notify(event)
+}
+
+func (r *cronReporter) notify(event webhook.Event) {
+	if r.events == nil {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	select {
+	case r.events <- event:
+	default:
+		slog.Warn("Webhook queue full, dropping event", "event", event.Event)
+	}
+}
+
+func (r *cronReporter) close() {
+	if r.events == nil {
+		return
+	}
+	close(r.events)
+	<-r.done
+}
+
+func generationEvent(result *app.GenerateResult, err error) webhook.Event {
+	event := webhook.Event{Event: webhook.EventGeneration, Success: err == nil}
+	if err != nil {
+		event.Error = err.Error()
+		return event
+	}
+
+	event.Topic = result.Topic
+	event.Title = result.Title
+	event.VideoPath = result.VideoPath
+	event.ThumbnailPath = result.ThumbnailPath
+	event.Duration = result.Duration
+	return event
+}
+
+func uploadEvent(title, videoPath, platform string, resp *distribution.UploadResponse, err error) webhook.Event {
+	event := webhook.Event{
+		Event:     webhook.EventUpload,
+		Success:   err == nil,
+		Title:     title,
+		VideoPath: videoPath,
+		Platform:  platform,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if resp != nil {
+		event.URL = resp.URL
+		if event.Platform == "" {
+			event.Platform = resp.Platform
+		}
 	}
+	return event
 }
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"craftstory/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	uploadTitle       string
+	uploadDescription string
+	uploadTags        []string
+	uploadPrivacy     string
+	uploadPublishAt   string
+)
+
+var uploadCmd = &cobra.Command{
+	Use:   "upload <video.mp4>",
+	Short: "Upload an existing video file",
+	Long: `Upload a previously generated video without regenerating it.
+Title, description, tags and thumbnail are read from the session.json written next to
+the video during generation; flags override them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUploadFile,
+}
+
+func init() {
+	uploadCmd.Flags().StringVarP(&uploadTitle, "title", "t", "", "Video title")
+	uploadCmd.Flags().StringVarP(&uploadDescription, "description", "d", "", "Video description")
+	uploadCmd.Flags().StringSliceVar(&uploadTags, "tags", nil, "Comma-separated video tags")
+	uploadCmd.Flags().StringVar(&uploadPrivacy, "privacy", "", "Privacy status: public, unlisted or private")
+	uploadCmd.Flags().StringVar(&uploadPublishAt, "publish-at", "", "Schedule publishing at an RFC 3339 time, e.g. 2026-01-02T09:00:00Z")
+	rootCmd.AddCommand(uploadCmd)
+}
+
+func runUploadFile(cmd *cobra.Command, args []string) error {
+	request, err := app.LoadUploadRequest(args[0])
+	if err != nil {
+		return err
+	}
+	if uploadTitle != "" {
+		request.Title = uploadTitle
+	}
+	if uploadDescription != "" {
+		request.Description = uploadDescription
+	}
+	if len(uploadTags) > 0 {
+		request.Tags = uploadTags
+	}
+	request.Privacy = uploadPrivacy
+	if uploadPublishAt != "" {
+		publishAt, err := time.Parse(time.RFC3339, uploadPublishAt)
+		if err != nil {
+			return fmt.Errorf("parse --publish-at: %w", err)
+		}
+		request.PublishAt = publishAt
+	}
+	if request.Title == "" {
+		return errors.New("no title found, pass --title or upload a video from a session directory")
+	}
+
+	ctx := cmd.Context()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	service, err := app.BuildService(cfg, verbose)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Uploading...", "path", request.VideoPath, "title", request.Title)
+	resp, err := app.NewPipeline(service).Upload(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Upload complete", "url", resp.URL)
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"craftstory/internal/speech/elevenlabs"
+
+	"github.com/spf13/cobra"
+)
+
+var voicesCmd = &cobra.Command{
+	Use:   "voices",
+	Short: "List available ElevenLabs voices",
+	Long: `List every voice available to the configured ElevenLabs account with
+its name, ID and category, ready to copy into host_voice/guest_voice.`,
+	RunE: runVoices,
+}
+
+func init() {
+	rootCmd.AddCommand(voicesCmd)
+}
+
+func runVoices(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	client := elevenlabs.NewClient(elevenlabs.Config{APIKeys: cfg.ElevenLabsAPIKeys})
+	voices, err := client.ListVoices(ctx)
+	if errors.Is(err, elevenlabs.ErrMissingAPIKey) {
+		return errors.New("ELEVENLABS_API_KEY is not set; add it to .env or run 'craftstory setup'")
+	}
+	if err != nil {
+		return fmt.Errorf("list voices: %w", err)
+	}
+	if len(voices) == 0 {
+		fmt.Println("No voices available for this account")
+		return nil
+	}
+
+	slices.SortFunc(voices, func(a, b elevenlabs.Voice) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	fmt.Println(authInfoStyle.Render(fmt.Sprintf("\nElevenLabs voices (%d):\n", len(voices))))
+	fmt.Printf("%-30s %-24s %s\n", "NAME", "ID", "CATEGORY")
+	for _, voice := range voices {
+		fmt.Printf("%-30s %-24s %s\n", voice.Name, voice.ID, voice.Category)
+	}
+	return nil
+}
@@ -3,19 +3,31 @@ package app
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"craftstory/internal/distribution"
+	"craftstory/internal/llm"
 	"craftstory/internal/speech"
+	"craftstory/internal/video"
 	"craftstory/pkg/config"
 )
 
 type mockUploader struct {
+	platform string
 	response *distribution.UploadResponse
 	err      error
+	requests []distribution.UploadRequest
 }
 
-func (m *mockUploader) Upload(_ context.Context, _ distribution.UploadRequest) (*distribution.UploadResponse, error) {
+func (m *mockUploader) Upload(_ context.Context, req distribution.UploadRequest) (*distribution.UploadResponse, error) {
+	m.requests = append(m.requests, req)
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -27,9 +39,90 @@ func (m *mockUploader) SetPrivacy(_ context.Context, _, _ string) error {
 }
 
 func (m *mockUploader) Platform() string {
+	if m.platform != "" {
+		return m.platform
+	}
 	return "mock"
 }
 
+var errNoScripts = errors.New("no scripts left")
+
+type mockLLM struct {
+	scripts []string
+	topics  []string
+}
+
+func (m *mockLLM) GenerateScript(_ context.Context, topic string, _ int) (string, error) {
+	m.topics = append(m.topics, topic)
+	if len(m.scripts) == 0 {
+		return "", errNoScripts
+	}
+	script := m.scripts[0]
+	m.scripts = m.scripts[1:]
+	return script, nil
+}
+
+func (m *mockLLM) GenerateConversation(ctx context.Context, topic string, _ []string, wordCount int) (string, error) {
+	return m.GenerateScript(ctx, topic, wordCount)
+}
+
+func (m *mockLLM) GenerateVisuals(_ context.Context, _ string, _ int) ([]llm.VisualCue, error) {
+	return nil, nil
+}
+
+func (m *mockLLM) GenerateTitle(_ context.Context, _ string) (string, error) {
+	return "", nil
+}
+
+func (m *mockLLM) GenerateDescription(_ context.Context, _ string) (string, error) {
+	return "", nil
+}
+
+func (m *mockLLM) GenerateTags(_ context.Context, _ string, _ int) ([]string, error) {
+	return nil, nil
+}
+
+type mockAssembler struct {
+	mu           sync.Mutex
+	duration     float64
+	delay        time.Duration
+	calls        int
+	reencodeSize int
+	reencodeErr  error
+	previews     int
+}
+
+func (m *mockAssembler) Assemble(ctx context.Context, req video.AssembleRequest) (*video.AssembleResult, error) {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(m.delay):
+	}
+	return &video.AssembleResult{OutputPath: req.OutputPath, Duration: m.duration}, nil
+}
+
+func (m *mockAssembler) CreatePreview(_ context.Context, videoPath string, _ float64) (string, error) {
+	m.mu.Lock()
+	m.previews++
+	m.mu.Unlock()
+	return videoPath + ".preview.mp4", nil
+}
+
+func (m *mockAssembler) GenerateThumbnailWithTitle(_ context.Context, videoPath string, _ float64, _ string) (string, error) {
+	return videoPath + ".jpg", nil
+}
+
+func (m *mockAssembler) Reencode(_ context.Context, videoPath string, _ int64) (string, error) {
+	if m.reencodeErr != nil {
+		return "", m.reencodeErr
+	}
+	path := videoPath + ".reencoded.mp4"
+	return path, os.WriteFile(path, make([]byte, m.reencodeSize), 0644)
+}
+
 func TestServiceCreation(t *testing.T) {
 	cfg := &config.Config{}
 	svc := NewService(ServiceOptions{Config: cfg})
@@ -99,7 +192,7 @@ func TestPipelineUpload(t *testing.T) {
 				},
 			}
 
-			svc := NewService(ServiceOptions{Config: cfg, Uploader: mockUp})
+			svc := NewService(ServiceOptions{Config: cfg, Uploaders: []distribution.Uploader{mockUp}})
 			pipeline := NewPipeline(svc)
 
 			resp, err := pipeline.Upload(t.Context(), tt.req)
@@ -116,6 +209,210 @@ func TestPipelineUpload(t *testing.T) {
 	}
 }
 
+func TestPipelineUploadPrivacy(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         UploadRequest
+		wantPrivacy string
+		wantErr     bool
+	}{
+		{name: "redditDefault", req: UploadRequest{Source: topicSourceReddit}, wantPrivacy: "private"},
+		{name: "manualDefault", req: UploadRequest{Source: topicSourceManual}, wantPrivacy: "unlisted"},
+		{name: "configuredSource", req: UploadRequest{Source: topicSourceStatic}, wantPrivacy: "unlisted"},
+		{name: "unknownSource", req: UploadRequest{}, wantPrivacy: "public"},
+		{name: "override", req: UploadRequest{Source: topicSourceReddit, Privacy: "public"}, wantPrivacy: "public"},
+		{name: "invalidOverride", req: UploadRequest{Privacy: "friends"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUp := &mockUploader{response: &distribution.UploadResponse{ID: "abc123"}}
+			cfg := &config.Config{
+				YouTube: config.YouTubeConfig{
+					PrivacyStatus:   "public",
+					PrivacyBySource: map[string]string{topicSourceStatic: "unlisted"},
+				},
+			}
+			pipeline := NewPipeline(NewService(ServiceOptions{Config: cfg, Uploaders: []distribution.Uploader{mockUp}}))
+
+			tt.req.VideoPath = "/path/to/video.mp4"
+			_, err := pipeline.Upload(t.Context(), tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Upload() error = nil, want error")
+				}
+				if len(mockUp.requests) != 0 {
+					t.Errorf("uploader called %d times, want 0", len(mockUp.requests))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+			if got := mockUp.requests[0].Privacy; got != tt.wantPrivacy {
+				t.Errorf("Privacy = %q, want %q", got, tt.wantPrivacy)
+			}
+		})
+	}
+}
+
+func TestPipelineUploadSizeLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		upload    config.UploadConfig
+		assembler *mockAssembler
+		wantErr   error
+		wantPath  string
+	}{
+		{
+			name:     "underLimit",
+			size:     512,
+			upload:   config.UploadConfig{MaxFileSizeMB: 0.001},
+			wantPath: "video.mp4",
+		},
+		{
+			name:    "overLimit",
+			size:    2048,
+			upload:  config.UploadConfig{MaxFileSizeMB: 0.001},
+			wantErr: ErrFileTooLarge,
+		},
+		{
+			name:      "overLimitReencoded",
+			size:      2048,
+			upload:    config.UploadConfig{MaxFileSizeMB: 0.001, Reencode: true},
+			assembler: &mockAssembler{reencodeSize: 512},
+			wantPath:  "video.mp4.reencoded.mp4",
+		},
+		{
+			name:      "reencodeStillTooLarge",
+			size:      2048,
+			upload:    config.UploadConfig{MaxFileSizeMB: 0.001, Reencode: true},
+			assembler: &mockAssembler{reencodeSize: 1500},
+			wantErr:   ErrFileTooLarge,
+		},
+		{
+			name:      "reencodeFails",
+			size:      2048,
+			upload:    config.UploadConfig{MaxFileSizeMB: 0.001, Reencode: true},
+			assembler: &mockAssembler{reencodeErr: errors.New("ffmpeg failed")},
+			wantErr:   ErrFileTooLarge,
+		},
+		{
+			name:     "noLimit",
+			size:     2048,
+			wantPath: "video.mp4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			videoPath := filepath.Join(dir, "video.mp4")
+			if err := os.WriteFile(videoPath, make([]byte, tt.size), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			uploader := &mockUploader{response: &distribution.UploadResponse{ID: "abc"}}
+			opts := ServiceOptions{Config: &config.Config{Upload: tt.upload}, Uploaders: []distribution.Uploader{uploader}}
+			if tt.assembler != nil {
+				opts.Assembler = tt.assembler
+			}
+			pipeline := NewPipeline(NewService(opts))
+
+			_, err := pipeline.Upload(t.Context(), UploadRequest{VideoPath: videoPath, Title: "Title"})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Upload() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(uploader.requests) != 0 {
+					t.Error("Upload() sent an oversized video to the uploader")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+			if got := uploader.requests[0].FilePath; got != filepath.Join(dir, tt.wantPath) {
+				t.Errorf("uploaded %q, want %q", got, filepath.Join(dir, tt.wantPath))
+			}
+		})
+	}
+}
+
+func TestPipelineUploadAll(t *testing.T) {
+	youtube := &mockUploader{
+		platform: "youtube",
+		response: &distribution.UploadResponse{ID: "yt1", Platform: "youtube"},
+	}
+	tiktok := &mockUploader{
+		platform: "tiktok",
+		err:      errors.New("token expired"),
+	}
+
+	cfg := &config.Config{
+		YouTube: config.YouTubeConfig{PrivacyStatus: "private"},
+	}
+	svc := NewService(ServiceOptions{Config: cfg, Uploaders: []distribution.Uploader{youtube, tiktok}})
+	pipeline := NewPipeline(svc)
+
+	results, err := pipeline.UploadAll(t.Context(), UploadRequest{VideoPath: "/path/to/video.mp4", Title: "Test"})
+	if err != nil {
+		t.Fatalf("UploadAll() error = %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("UploadAll() returned %d results, want 2", len(results))
+	}
+	if r := results["youtube"]; r.Err != nil || r.Response == nil || r.Response.ID != "yt1" {
+		t.Errorf("youtube result = %+v, want successful upload", r)
+	}
+	if r := results["tiktok"]; r.Err == nil || r.Response != nil {
+		t.Errorf("tiktok result = %+v, want error", r)
+	}
+}
+
+func TestServiceOptionsDeprecatedUploader(t *testing.T) {
+	youtube := &mockUploader{
+		platform: "youtube",
+		response: &distribution.UploadResponse{ID: "yt1", Platform: "youtube"},
+	}
+	tiktok := &mockUploader{
+		platform: "tiktok",
+		response: &distribution.UploadResponse{ID: "tt1", Platform: "tiktok"},
+	}
+
+	cfg := &config.Config{
+		YouTube: config.YouTubeConfig{PrivacyStatus: "private"},
+	}
+	pipeline := NewPipeline(NewService(ServiceOptions{
+		Config:    cfg,
+		Uploader:  youtube,
+		Uploaders: []distribution.Uploader{tiktok},
+	}))
+
+	results, err := pipeline.UploadAll(t.Context(), UploadRequest{VideoPath: "/path/to/video.mp4", Title: "Test"})
+	if err != nil {
+		t.Fatalf("UploadAll() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("UploadAll() returned %d results, want 2", len(results))
+	}
+	for _, platform := range []string{"youtube", "tiktok"} {
+		if r := results[platform]; r.Err != nil || r.Response == nil {
+			t.Errorf("%s result = %+v, want successful upload", platform, r)
+		}
+	}
+}
+
+func TestPipelineUploadAllNoUploaders(t *testing.T) {
+	pipeline := NewPipeline(NewService(ServiceOptions{Config: &config.Config{}}))
+
+	if _, err := pipeline.UploadAll(t.Context(), UploadRequest{}); err == nil {
+		t.Error("UploadAll() expected error with no uploaders")
+	}
+}
+
 func TestGenerateResultStruct(t *testing.T) {
 	result := GenerateResult{
 		Title:         "Test Title",
@@ -249,3 +546,1264 @@ func TestMaxDurationZeroAllowsAnyDuration(t *testing.T) {
 		t.Error("expected zero maxDuration to allow any duration")
 	}
 }
+
+func TestSchedulePublish(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		publishAt time.Time
+		youtube   config.YouTubeConfig
+		privacy   string
+		want      time.Time
+		wantErr   string
+	}{
+		{
+			name:    "unscheduled",
+			privacy: "public",
+			want:    time.Time{},
+		},
+		{
+			name:    "delay",
+			youtube: config.YouTubeConfig{PublishDelay: "21h"},
+			privacy: "private",
+			want:    time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "configPublishAt",
+			youtube: config.YouTubeConfig{PublishAt: "2026-01-02T18:00:00Z"},
+			privacy: "unlisted",
+			want:    time.Date(2026, 1, 2, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "requestOverridesConfig",
+			publishAt: time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC),
+			youtube:   config.YouTubeConfig{PublishAt: "2026-01-02T18:00:00Z", PublishDelay: "1h"},
+			privacy:   "private",
+			want:      time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "configPublishAtOverridesDelay",
+			youtube: config.YouTubeConfig{PublishAt: "2026-01-02T18:00:00Z", PublishDelay: "1h"},
+			privacy: "private",
+			want:    time.Date(2026, 1, 2, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "pastRejected",
+			publishAt: time.Date(2025, 12, 31, 9, 0, 0, 0, time.UTC),
+			privacy:   "private",
+			wantErr:   "not in the future",
+		},
+		{
+			name:    "pastConfigRejected",
+			youtube: config.YouTubeConfig{PublishAt: "2025-12-31T09:00:00Z"},
+			privacy: "private",
+			wantErr: "not in the future",
+		},
+		{
+			name:    "publicRejected",
+			youtube: config.YouTubeConfig{PublishDelay: "1h"},
+			privacy: "public",
+			wantErr: "public privacy status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schedulePublish(tt.publishAt, tt.youtube, tt.privacy, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("schedulePublish() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("schedulePublish() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("schedulePublish() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateScriptLengthRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		scripts     []string
+		retries     int
+		want        string
+		wantCalls   int
+		wantHintFor string
+	}{
+		{
+			name:      "acceptableFirstTry",
+			scripts:   []string{words(10)},
+			retries:   2,
+			want:      words(10),
+			wantCalls: 1,
+		},
+		{
+			name:        "tooLongThenAcceptable",
+			scripts:     []string{words(20), words(11)},
+			retries:     2,
+			want:        words(11),
+			wantCalls:   2,
+			wantHintFor: "shorter",
+		},
+		{
+			name:        "tooShortThenAcceptable",
+			scripts:     []string{words(3), words(9)},
+			retries:     2,
+			want:        words(9),
+			wantCalls:   2,
+			wantHintFor: "longer",
+		},
+		{
+			name:      "givesUpAfterRetries",
+			scripts:   []string{words(30), words(25), words(22)},
+			retries:   2,
+			want:      words(22),
+			wantCalls: 3,
+		},
+		{
+			name:      "zeroDisablesRetries",
+			scripts:   []string{words(30), words(10)},
+			retries:   0,
+			want:      words(30),
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockLLM{scripts: tt.scripts}
+			cfg := &config.Config{
+				Content: config.ContentConfig{
+					WordCount:       10,
+					LengthTolerance: 0.2,
+					LengthRetries:   &tt.retries,
+				},
+			}
+			pipeline := NewPipeline(NewService(ServiceOptions{Config: cfg, LLM: mock}))
+			generation := pipeline.newGenerationContext(t.Context())
+
+			got, err := generation.generateScript("cats")
+			if err != nil {
+				t.Fatalf("generateScript() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("generateScript() returned %d words, want %d", len(strings.Fields(got)), len(strings.Fields(tt.want)))
+			}
+			if len(mock.topics) != tt.wantCalls {
+				t.Errorf("LLM called %d times, want %d", len(mock.topics), tt.wantCalls)
+			}
+			if mock.topics[0] != "cats" {
+				t.Errorf("first prompt topic = %q, want %q", mock.topics[0], "cats")
+			}
+			if tt.wantHintFor != "" && !strings.Contains(mock.topics[1], tt.wantHintFor) {
+				t.Errorf("retry prompt %q does not ask for %s script", mock.topics[1], tt.wantHintFor)
+			}
+		})
+	}
+}
+
+func TestGenerateScriptTurns(t *testing.T) {
+	monologue := "Host: " + words(10)
+	balanced := "Host: " + words(4) + "\nGuest: " + words(3) + "\nHost: " + words(3)
+	long := balanced + "\nGuest: extra\nHost: more"
+
+	tests := []struct {
+		name      string
+		scripts   []string
+		minTurns  int
+		maxTurns  int
+		want      string
+		wantCalls int
+	}{
+		{
+			name:      "withinLimits",
+			scripts:   []string{balanced},
+			minTurns:  2,
+			maxTurns:  4,
+			want:      balanced,
+			wantCalls: 1,
+		},
+		{
+			name:      "tooFewTurnsRegenerates",
+			scripts:   []string{monologue, balanced},
+			minTurns:  2,
+			want:      balanced,
+			wantCalls: 2,
+		},
+		{
+			name:      "tooManyTurnsTruncated",
+			scripts:   []string{long},
+			maxTurns:  3,
+			want:      balanced,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retries := 1
+			mock := &mockLLM{scripts: tt.scripts}
+			cfg := &config.Config{
+				Content: config.ContentConfig{
+					WordCount:        10,
+					LengthTolerance:  0.5,
+					LengthRetries:    &retries,
+					ConversationMode: true,
+					MinTurns:         tt.minTurns,
+					MaxTurns:         tt.maxTurns,
+				},
+				ElevenLabs: config.ElevenLabsConfig{
+					HostVoice:  config.VoiceConfig{ID: "host-id", Name: "Host"},
+					GuestVoice: config.VoiceConfig{ID: "guest-id", Name: "Guest"},
+				},
+			}
+			generation := NewPipeline(NewService(ServiceOptions{Config: cfg, LLM: mock})).newGenerationContext(t.Context())
+
+			got, err := generation.generateScript("cats")
+			if err != nil {
+				t.Fatalf("generateScript() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("generateScript() = %q, want %q", got, tt.want)
+			}
+			if len(mock.topics) != tt.wantCalls {
+				t.Errorf("LLM called %d times, want %d", len(mock.topics), tt.wantCalls)
+			}
+			if tt.wantCalls > 1 && !strings.Contains(mock.topics[1], "turns") {
+				t.Errorf("retry prompt %q does not ask for more turns", mock.topics[1])
+			}
+		})
+	}
+}
+
+func TestCalculateWordCountWordsPerMinute(t *testing.T) {
+	tests := []struct {
+		name    string
+		content config.ContentConfig
+		speed   float64
+		want    int
+	}{
+		{name: "defaultRate", content: config.ContentConfig{TargetDuration: 60}, want: 150},
+		{name: "configuredRate", content: config.ContentConfig{TargetDuration: 60, WordsPerMinute: 180}, want: 180},
+		{name: "rateScaledBySpeed", content: config.ContentConfig{TargetDuration: 60, WordsPerMinute: 180}, speed: 1.5, want: 270},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Content: tt.content, ElevenLabs: config.ElevenLabsConfig{Speed: tt.speed}}
+			generation := NewPipeline(NewService(ServiceOptions{Config: cfg})).newGenerationContext(t.Context())
+			if got := generation.calculateWordCount(); got != tt.want {
+				t.Errorf("calculateWordCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubredditStyle(t *testing.T) {
+	reddit := config.RedditConfig{
+		StyleBySubreddit: map[string]string{"Jokes": "Punchy and silly"},
+		DefaultStyle:     "Plain and friendly",
+	}
+
+	tests := []struct {
+		name      string
+		subreddit string
+		wantStyle string
+	}{
+		{name: "mappedSubreddit", subreddit: "jokes", wantStyle: "Punchy and silly"},
+		{name: "unmappedSubredditUsesDefault", subreddit: "science", wantStyle: "Plain and friendly"},
+		{name: "manualTopicHasNoStyle", subreddit: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockLLM{scripts: []string{"script"}}
+			cfg := &config.Config{Reddit: reddit}
+			generation := NewPipeline(NewService(ServiceOptions{Config: cfg, LLM: mock})).newGenerationContext(t.Context())
+			generation.style = generation.styleFor(tt.subreddit)
+
+			if _, err := generation.requestScript("topic", 100); err != nil {
+				t.Fatalf("requestScript() error = %v", err)
+			}
+
+			prompt := mock.topics[0]
+			if tt.wantStyle == "" {
+				if prompt != "topic" {
+					t.Errorf("prompt = %q, want unchanged topic", prompt)
+				}
+				return
+			}
+			if !strings.HasPrefix(prompt, "topic") || !strings.Contains(prompt, "STYLE: "+tt.wantStyle) {
+				t.Errorf("prompt = %q, want topic with style %q", prompt, tt.wantStyle)
+			}
+		})
+	}
+}
+
+func TestCreatePreview(t *testing.T) {
+	tests := []struct {
+		name        string
+		telegram    config.TelegramConfig
+		duration    float64
+		wantPreview bool
+	}{
+		{name: "longVideo", duration: 60, wantPreview: true},
+		{name: "shortVideo", duration: 20},
+		{name: "disabled", telegram: config.TelegramConfig{DisablePreview: true}, duration: 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := &mockAssembler{}
+			cfg := &config.Config{Telegram: tt.telegram}
+			pipeline := NewPipeline(NewService(ServiceOptions{Config: cfg, Assembler: assembler}))
+
+			path := pipeline.CreatePreview(t.Context(), &GenerateResult{VideoPath: "video.mp4", Duration: tt.duration})
+
+			if (path != "") != tt.wantPreview {
+				t.Errorf("CreatePreview() = %q, want preview %v", path, tt.wantPreview)
+			}
+			if (assembler.previews > 0) != tt.wantPreview {
+				t.Errorf("CreatePreview calls = %d, want preview %v", assembler.previews, tt.wantPreview)
+			}
+		})
+	}
+}
+
+func TestServiceReload(t *testing.T) {
+	svc := NewService(ServiceOptions{Config: &config.Config{Content: config.ContentConfig{TargetDuration: 60}}})
+	pipeline := NewPipeline(svc)
+
+	inFlight := pipeline.newGenerationContext(t.Context())
+
+	svc.Reload(&config.Config{Content: config.ContentConfig{TargetDuration: 120}})
+	reloaded := pipeline.newGenerationContext(t.Context())
+
+	if got := inFlight.calculateWordCount(); got != 150 {
+		t.Errorf("in-flight calculateWordCount() = %d, want 150", got)
+	}
+	if got := reloaded.calculateWordCount(); got != 300 {
+		t.Errorf("reloaded calculateWordCount() = %d, want 300", got)
+	}
+}
+
+func TestServiceReloadRestartSections(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(cfg *config.Config)
+		want   []string
+	}{
+		{
+			name:   "liveOnly",
+			update: func(cfg *config.Config) { cfg.Content.TargetDuration = 120 },
+		},
+		{
+			name: "restartRequired",
+			update: func(cfg *config.Config) {
+				cfg.Groq.Model = "other"
+				crf := 18
+				cfg.Video.CRF = &crf
+				cfg.TelegramBotToken = "new-token"
+			},
+			want: []string{"credentials", "groq", "video"},
+		},
+		{
+			name: "uploadRetriesAndConcurrency",
+			update: func(cfg *config.Config) {
+				cfg.YouTube.UploadRetries = 5
+				cfg.MaxConcurrentGenerations = 3
+			},
+			want: []string{"youtube.upload_retries", "max_concurrent_generations"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := &config.Config{Groq: config.GroqConfig{Model: "llama"}, TelegramBotToken: "token"}
+			svc := NewService(ServiceOptions{Config: current})
+
+			next := *current
+			tt.update(&next)
+			if got := svc.Reload(&next); !slices.Equal(got, tt.want) {
+				t.Errorf("Reload() = %v, want %v", got, tt.want)
+			}
+			if svc.config() != &next {
+				t.Error("Reload() did not swap the config")
+			}
+		})
+	}
+}
+
+func TestPipelineMaxConcurrentGenerations(t *testing.T) {
+	pipeline := NewPipeline(NewService(ServiceOptions{Config: &config.Config{MaxConcurrentGenerations: 2}}))
+
+	var releases []func()
+	for range 2 {
+		release, err := pipeline.acquire(t.Context())
+		if err != nil {
+			t.Fatalf("acquire() error = %v", err)
+		}
+		releases = append(releases, release)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		release, err := pipeline.acquire(t.Context())
+		if err != nil {
+			t.Errorf("acquire() error = %v", err)
+			return
+		}
+		acquired <- release
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("third generation started while two were running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	releases[0]()
+
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("third generation did not start after a slot was released")
+	}
+	releases[1]()
+}
+
+func TestPipelineAcquireCancelled(t *testing.T) {
+	pipeline := NewPipeline(NewService(ServiceOptions{Config: &config.Config{MaxConcurrentGenerations: 1}}))
+	release, err := pipeline.acquire(t.Context())
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if _, err := pipeline.acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("acquire() error = %v, want context.Canceled", err)
+	}
+}
+
+func words(n int) string {
+	return strings.TrimSpace(strings.Repeat("word ", n))
+}
+
+func TestFindBlockedTerm(t *testing.T) {
+	blocklist := []string{"badword", "very bad phrase"}
+
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{name: "noMatch", script: "A perfectly clean script about cats.", want: ""},
+		{name: "exactWord", script: "This has a badword in it.", want: "badword"},
+		{name: "caseInsensitive", script: "This has a BADWORD in it.", want: "badword"},
+		{name: "punctuationStripped", script: "Stop saying \"badword!\"", want: "badword"},
+		{name: "substringNotMatched", script: "Some badwords and notbadword variants.", want: ""},
+		{name: "phraseMatched", script: "That was a Very Bad Phrase, honestly.", want: "very bad phrase"},
+		{name: "partialPhraseNotMatched", script: "That was a very bad idea.", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findBlockedTerm(tt.script, blocklist); got != tt.want {
+				t.Errorf("findBlockedTerm() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureSafeScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		onUnsafe string
+		scripts  []string
+		wantErr  bool
+	}{
+		{name: "abortByDefault", scripts: []string{"a badword here"}, wantErr: true},
+		{name: "regenerateOnce", onUnsafe: "regenerate", scripts: []string{"a clean one here"}},
+		{name: "regenerateStillUnsafe", onUnsafe: "regenerate", scripts: []string{"another badword"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Content: config.ContentConfig{
+					Blocklist:       []string{"badword"},
+					OnUnsafe:        tt.onUnsafe,
+					LengthTolerance: 10,
+				},
+			}
+			mock := &mockLLM{scripts: tt.scripts}
+			pipeline := NewPipeline(NewService(ServiceOptions{Config: cfg, LLM: mock}))
+			generation := pipeline.newGenerationContext(t.Context())
+
+			_, err := generation.ensureSafeScript("topic", "original badword script")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ensureSafeScript() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProgressLogger(t *testing.T) {
+	var logged []float64
+	report := newProgressLogger(10, func(percent float64) {
+		logged = append(logged, percent)
+	})
+
+	for _, p := range []float64{1, 4, 9.9, 10, 12, 35, 36, 100, 2, 5} {
+		report(p)
+	}
+
+	want := []float64{1, 10, 35, 100, 2}
+	if len(logged) != len(want) {
+		t.Fatalf("logged %v, want %v", logged, want)
+	}
+	for i := range want {
+		if logged[i] != want[i] {
+			t.Errorf("logged[%d] = %v, want %v", i, logged[i], want[i])
+		}
+	}
+}
+
+func TestGenerateMetrics(t *testing.T) {
+	cfg := &config.Config{
+		Content: config.ContentConfig{WordCount: 10},
+		Video:   config.VideoConfig{OutputDir: t.TempDir()},
+	}
+	pipeline := NewPipeline(NewService(ServiceOptions{
+		Config:    cfg,
+		LLM:       &mockLLM{scripts: []string{words(10)}},
+		TTS:       speech.NewStubProvider(speech.DefaultWordsPerMinute),
+		Assembler: &mockAssembler{duration: 60},
+	}))
+
+	result, err := pipeline.Generate(t.Context(), "cats")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	stages := map[string]time.Duration{
+		"script":    result.Metrics.Script,
+		"metadata":  result.Metrics.Metadata,
+		"audio":     result.Metrics.Audio,
+		"images":    result.Metrics.Images,
+		"assembly":  result.Metrics.Assembly,
+		"thumbnail": result.Metrics.Thumbnail,
+		"total":     result.Metrics.Total,
+	}
+	for name, d := range stages {
+		if d <= 0 {
+			t.Errorf("%s duration = %v, want > 0", name, d)
+		}
+	}
+	if result.Metrics.Total < result.Metrics.Script+result.Metrics.Audio+result.Metrics.Assembly {
+		t.Errorf("total %v is less than the sum of its stages", result.Metrics.Total)
+	}
+}
+
+func TestGenerateAudio(t *testing.T) {
+	cfg := &config.Config{
+		Content: config.ContentConfig{WordCount: 10},
+		Video:   config.VideoConfig{OutputDir: t.TempDir()},
+	}
+	assembler := &mockAssembler{duration: 60}
+	pipeline := NewPipeline(NewService(ServiceOptions{
+		Config:    cfg,
+		LLM:       &mockLLM{scripts: []string{words(10)}},
+		TTS:       speech.NewStubProvider(speech.DefaultWordsPerMinute),
+		Assembler: assembler,
+	}))
+
+	result, err := pipeline.GenerateAudio(t.Context(), "cats")
+	if err != nil {
+		t.Fatalf("GenerateAudio() error = %v", err)
+	}
+
+	if assembler.calls != 0 {
+		t.Errorf("Assemble called %d times, want 0", assembler.calls)
+	}
+	if result.VideoPath != "" {
+		t.Errorf("VideoPath = %q, want empty", result.VideoPath)
+	}
+	if result.Duration <= 0 {
+		t.Errorf("Duration = %v, want > 0", result.Duration)
+	}
+	if _, err := os.Stat(result.AudioPath); err != nil {
+		t.Errorf("audio file not written: %v", err)
+	}
+
+	captions, err := os.ReadFile(result.CaptionsPath)
+	if err != nil {
+		t.Fatalf("captions not written: %v", err)
+	}
+	if !strings.Contains(string(captions), "-->") {
+		t.Errorf("captions = %q, want SRT cues", captions)
+	}
+}
+
+func TestResume(t *testing.T) {
+	tests := []struct {
+		name          string
+		script        string
+		state         *sessionState
+		audio         []byte
+		wantLLMCalls  int
+		wantScript    string
+		wantAudioKept bool
+	}{
+		{
+			name:         "existingScriptNotOverwritten",
+			script:       "my saved script",
+			state:        &sessionState{Topic: "cats", Title: "Cats"},
+			wantLLMCalls: 0,
+			wantScript:   "my saved script",
+		},
+		{
+			name:          "existingAudioReused",
+			script:        "my saved script",
+			state:         &sessionState{Topic: "cats", Title: "Cats", Timings: []speech.WordTiming{{Word: "my", StartTime: 0, EndTime: 1}}},
+			audio:         []byte("ID3 audio"),
+			wantScript:    "my saved script",
+			wantAudioKept: true,
+		},
+		{
+			name:       "corruptAudioRegenerated",
+			script:     "my saved script",
+			state:      &sessionState{Topic: "cats", Title: "Cats", Timings: []speech.WordTiming{{Word: "my", StartTime: 0, EndTime: 1}}},
+			audio:      []byte{},
+			wantScript: "my saved script",
+		},
+		{
+			name:         "missingScriptGenerated",
+			state:        &sessionState{Topic: "cats"},
+			wantLLMCalls: 1,
+			wantScript:   words(10),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			sess := &session{dir: dir}
+			if err := sess.saveState(tt.state); err != nil {
+				t.Fatalf("saveState() error = %v", err)
+			}
+			if tt.script != "" {
+				if err := os.WriteFile(sess.scriptPath(), []byte(tt.script), 0644); err != nil {
+					t.Fatalf("failed to write script: %v", err)
+				}
+			}
+			if tt.audio != nil {
+				if err := os.WriteFile(sess.audioPath(), tt.audio, 0644); err != nil {
+					t.Fatalf("failed to write audio: %v", err)
+				}
+			}
+
+			mock := &mockLLM{scripts: []string{words(10)}}
+			cfg := &config.Config{
+				Content: config.ContentConfig{WordCount: 10},
+				Video:   config.VideoConfig{OutputDir: t.TempDir()},
+			}
+			pipeline := NewPipeline(NewService(ServiceOptions{
+				Config:    cfg,
+				LLM:       mock,
+				TTS:       speech.NewStubProvider(speech.DefaultWordsPerMinute),
+				Assembler: &mockAssembler{duration: 10},
+			}))
+
+			result, err := pipeline.Resume(t.Context(), dir)
+			if err != nil {
+				t.Fatalf("Resume() error = %v", err)
+			}
+
+			if len(mock.topics) != tt.wantLLMCalls {
+				t.Errorf("script generated %d times, want %d", len(mock.topics), tt.wantLLMCalls)
+			}
+			if result.ScriptContent != tt.wantScript {
+				t.Errorf("ScriptContent = %q, want %q", result.ScriptContent, tt.wantScript)
+			}
+			if result.OutputDir != dir {
+				t.Errorf("OutputDir = %q, want %q", result.OutputDir, dir)
+			}
+
+			saved, err := os.ReadFile(sess.scriptPath())
+			if err != nil {
+				t.Fatalf("failed to read script: %v", err)
+			}
+			if string(saved) != tt.wantScript {
+				t.Errorf("script file = %q, want %q", saved, tt.wantScript)
+			}
+
+			audio, err := os.ReadFile(sess.audioPath())
+			if err != nil {
+				t.Fatalf("failed to read audio: %v", err)
+			}
+			if kept := string(audio) == string(tt.audio); kept != tt.wantAudioKept {
+				t.Errorf("audio kept = %v, want %v", kept, tt.wantAudioKept)
+			}
+		})
+	}
+}
+
+func TestResumeMissingDir(t *testing.T) {
+	pipeline := NewPipeline(NewService(ServiceOptions{Config: &config.Config{}}))
+	if _, err := pipeline.Resume(t.Context(), filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing session dir")
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "simpleTitle", input: "Why Cats Purr", want: "why_cats_purr"},
+		{name: "pathSeparators", input: "AC/DC vs ../etc", want: "ac_dc_vs_etc"},
+		{name: "truncatedWithoutTrailingSeparator", input: strings.Repeat("ab ", 30), want: strings.TrimSuffix(strings.Repeat("ab_", 17), "_")},
+		{name: "empty", input: "???", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slugify(tt.input); got != tt.want {
+				t.Errorf("slugify(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderFilename(t *testing.T) {
+	data := filenameData{Title: "why_cats_purr", Topic: "cats", Date: "2024-05-01", ID: "20240501_120000"}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{name: "titleAndDate", template: "{{.Date}}_{{.Title}}", want: "2024-05-01_why_cats_purr.mp4"},
+		{name: "explicitExtension", template: "{{.Topic}}.mp4", want: "cats.mp4"},
+		{name: "separatorsReplaced", template: "{{.Topic}}/{{.Title}}", want: "cats_why_cats_purr.mp4"},
+		{name: "unknownField", template: "{{.Author}}", wantErr: true},
+		{name: "emptyResult", template: "{{.Title | printf \"%.0s\"}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderFilename(tt.template, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderFilename() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("renderFilename() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueFilename(t *testing.T) {
+	dir := t.TempDir()
+
+	if got := uniqueFilename(dir, "cats.mp4"); got != "cats.mp4" {
+		t.Errorf("uniqueFilename() = %q, want %q", got, "cats.mp4")
+	}
+
+	for _, name := range []string{"cats.mp4", "cats_2.mp4"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	if got := uniqueFilename(dir, "cats.mp4"); got != "cats_3.mp4" {
+		t.Errorf("uniqueFilename() = %q, want %q", got, "cats_3.mp4")
+	}
+}
+
+func TestSessionVideoPath(t *testing.T) {
+	dir := t.TempDir()
+
+	sess := &session{dir: dir}
+	sess.nameVideo(&sessionState{Title: "Why Cats Purr"})
+	if got := sess.videoPath(); got != filepath.Join(dir, defaultVideoName) {
+		t.Errorf("videoPath() without template = %q", got)
+	}
+
+	sess = &session{dir: dir, id: "20240501_120000", filenameTemplate: "{{.ID}}_{{.Title}}"}
+	sess.nameVideo(&sessionState{Title: "Why Cats Purr?"})
+	if got := sess.videoPath(); got != filepath.Join(dir, "20240501_120000_why_cats_purr.mp4") {
+		t.Errorf("videoPath() with template = %q", got)
+	}
+}
+
+func TestSessionVideoPathResume(t *testing.T) {
+	dir := t.TempDir()
+	state := &sessionState{Title: "Why Cats Purr"}
+
+	sess := &session{dir: dir, id: "20240501_120000", filenameTemplate: "{{.ID}}_{{.Title}}"}
+	sess.nameVideo(state)
+	if err := sess.saveState(state); err != nil {
+		t.Fatalf("saveState() error = %v", err)
+	}
+	if err := os.WriteFile(sess.videoPath(), nil, 0644); err != nil {
+		t.Fatalf("failed to write video: %v", err)
+	}
+
+	resumed := &session{id: "20240502_090000", filenameTemplate: "{{.ID}}_{{.Title}}"}
+	resumedState, err := resumed.resume(dir)
+	if err != nil {
+		t.Fatalf("resume() error = %v", err)
+	}
+	resumed.nameVideo(resumedState)
+	if got := resumed.videoPath(); got != sess.videoPath() {
+		t.Errorf("resumed videoPath() = %q, want %q", got, sess.videoPath())
+	}
+}
+
+func TestCleanupSession(t *testing.T) {
+	artifacts := []string{"script.txt", "audio.mp3", "audio.srt", "session.json", "video.jpg", "preview_1.mp4"}
+
+	tests := []struct {
+		name          string
+		keepArtifacts bool
+		keepVideo     bool
+		noState       bool
+		otherDir      bool
+		wantErr       bool
+		wantFiles     []string
+		wantDir       bool
+	}{
+		{name: "uploadKeepsOnlyVideo", keepVideo: true, wantFiles: []string{"video.mp4"}, wantDir: true},
+		{name: "rejectRemovesSession"},
+		{name: "keepArtifacts", keepArtifacts: true, keepVideo: true, wantFiles: append([]string{"video.mp4"}, artifacts...), wantDir: true},
+		{name: "notASession", keepVideo: true, noState: true, wantErr: true, wantFiles: []string{"video.mp4", "script.txt", "audio.mp3", "audio.srt", "video.jpg", "preview_1.mp4"}, wantDir: true},
+		{name: "outsideOutputDir", otherDir: true, wantErr: true, wantFiles: append([]string{"video.mp4"}, artifacts...), wantDir: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := t.TempDir()
+			base := outputDir
+			if tt.otherDir {
+				base = t.TempDir()
+			}
+			dir := filepath.Join(base, "20240501_120000_test")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range append([]string{"video.mp4"}, artifacts...) {
+				if tt.noState && name == "session.json" {
+					continue
+				}
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			sibling := filepath.Join(outputDir, "20240501_130000_other")
+			if err := os.MkdirAll(sibling, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := &config.Config{Video: config.VideoConfig{OutputDir: outputDir, KeepArtifacts: tt.keepArtifacts}}
+			err := CleanupSession(cfg, filepath.Join(dir, "video.mp4"), tt.keepVideo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CleanupSession() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if _, err := os.Stat(sibling); err != nil {
+				t.Errorf("other session removed: %v", err)
+			}
+			entries, err := os.ReadDir(dir)
+			if (err == nil) != tt.wantDir {
+				t.Fatalf("session dir exists = %v, want %v", err == nil, tt.wantDir)
+			}
+			if !tt.wantDir {
+				return
+			}
+			for _, name := range tt.wantFiles {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("%s removed, want kept", name)
+				}
+			}
+			if len(entries) != len(tt.wantFiles) {
+				t.Errorf("session has %d files, want %d", len(entries), len(tt.wantFiles))
+			}
+		})
+	}
+}
+
+func TestGenerationSeed(t *testing.T) {
+	pipeline := NewPipeline(NewService(ServiceOptions{Config: &config.Config{Seed: 1234}}))
+
+	first := pipeline.newGenerationContext(t.Context()).rng
+	second := pipeline.newGenerationContext(t.Context()).rng
+	for range 5 {
+		if a, b := randomInt(first, 1000), randomInt(second, 1000); a != b {
+			t.Fatalf("seeded runs diverged: %d != %d", a, b)
+		}
+	}
+
+	if got := randomInt(newRand(0), 0); got != 0 {
+		t.Errorf("randomInt(n=0) = %d, want 0", got)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		groups [][]string
+		want   []string
+	}{
+		{
+			name:   "generatedThenDefaults",
+			limit:  500,
+			groups: [][]string{{"space", "nasa"}, {"shorts", "facts"}},
+			want:   []string{"space", "nasa", "shorts", "facts"},
+		},
+		{
+			name:   "dedupesCaseInsensitively",
+			limit:  500,
+			groups: [][]string{{"Elon Musk", "tesla"}, {"elon musk", "Tesla", "shorts"}},
+			want:   []string{"Elon Musk", "tesla", "shorts"},
+		},
+		{
+			name:   "skipsBlank",
+			limit:  500,
+			groups: [][]string{{" ", "space "}, nil},
+			want:   []string{"space"},
+		},
+		{
+			name:   "capsTotalLength",
+			limit:  12,
+			groups: [][]string{{"space", "nasa"}, {"shorts"}},
+			want:   []string{"space", "nasa"},
+		},
+		{
+			name:   "countsQuotesForSpaces",
+			limit:  15,
+			groups: [][]string{{"deep space", "nasa"}, {"ab"}},
+			want:   []string{"deep space", "ab"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeTags(tt.limit, tt.groups...)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("mergeTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMusicMood(t *testing.T) {
+	cfg := &config.Config{
+		Music: config.MusicConfig{
+			Mood:  "chill",
+			Moods: map[string]string{"ghost": "dark", "deep space": "epic", "": "ignored"},
+		},
+	}
+	generation := NewPipeline(NewService(ServiceOptions{Config: cfg})).newGenerationContext(t.Context())
+
+	tests := []struct {
+		name  string
+		topic string
+		want  string
+	}{
+		{name: "keywordMatch", topic: "The Ghost of Hampton Court", want: "dark"},
+		{name: "phraseMatch", topic: "What lives in deep space?", want: "epic"},
+		{name: "partialWordIgnored", topic: "Ghostwriters explained", want: "chill"},
+		{name: "defaultMood", topic: "Why cats purr", want: "chill"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := generation.musicMood(tt.topic); got != tt.want {
+				t.Errorf("musicMood(%q) = %q, want %q", tt.topic, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content config.ContentConfig
+		script  string
+		want    string
+	}{
+		{
+			name:    "disabled",
+			content: config.ContentConfig{},
+			script:  "Cats purr for many reasons. Here is why.",
+			want:    "",
+		},
+		{
+			name:    "configuredText",
+			content: config.ContentConfig{HookText: "Wait for it", HookFromScript: true},
+			script:  "Cats purr for many reasons.",
+			want:    "Wait for it",
+		},
+		{
+			name:    "firstSentence",
+			content: config.ContentConfig{HookFromScript: true},
+			script:  "Cats purr for many reasons! Here is why.",
+			want:    "Cats purr for many reasons!",
+		},
+		{
+			name:    "longSentenceTruncated",
+			content: config.ContentConfig{HookFromScript: true},
+			script:  words(20),
+			want:    words(12) + " ...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Content: tt.content}
+			generation := NewPipeline(NewService(ServiceOptions{Config: cfg})).newGenerationContext(t.Context())
+			if got := generation.hookText(tt.script); got != tt.want {
+				t.Errorf("hookText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveVoice(t *testing.T) {
+	cfg := &config.Config{
+		ElevenLabs: config.ElevenLabsConfig{
+			HostVoice:      config.VoiceConfig{ID: "host-id", Name: "Host"},
+			GuestVoice:     config.VoiceConfig{ID: "guest-id", Name: "Guest"},
+			SpeakerAliases: map[string]string{"Narrator": "Host", "Curious Friend": "Guest"},
+		},
+	}
+	generation := NewPipeline(NewService(ServiceOptions{Config: cfg})).newGenerationContext(t.Context())
+
+	tests := []struct {
+		name    string
+		speaker string
+		wantID  string
+		wantOK  bool
+	}{
+		{name: "exact", speaker: "Host", wantID: "host-id", wantOK: true},
+		{name: "upperCase", speaker: "HOST", wantID: "host-id", wantOK: true},
+		{name: "article", speaker: "The Guest", wantID: "guest-id", wantOK: true},
+		{name: "articleUpperCase", speaker: "THE HOST", wantID: "host-id", wantOK: true},
+		{name: "alias", speaker: "Narrator", wantID: "host-id", wantOK: true},
+		{name: "aliasWithArticle", speaker: "the curious friend", wantID: "guest-id", wantOK: true},
+		{name: "unknown", speaker: "Stranger", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			voice, ok := generation.resolveVoice(tt.speaker)
+			if ok != tt.wantOK {
+				t.Fatalf("resolveVoice(%q) ok = %v, want %v", tt.speaker, ok, tt.wantOK)
+			}
+			if voice.ID != tt.wantID {
+				t.Errorf("resolveVoice(%q) = %q, want %q", tt.speaker, voice.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestStageTimeout(t *testing.T) {
+	cfg := &config.Config{
+		Content:  config.ContentConfig{WordCount: 10},
+		Video:    config.VideoConfig{OutputDir: t.TempDir()},
+		Timeouts: config.TimeoutsConfig{Assemble: 0.05},
+	}
+	pipeline := NewPipeline(NewService(ServiceOptions{
+		Config:    cfg,
+		LLM:       &mockLLM{scripts: []string{words(10)}},
+		TTS:       speech.NewStubProvider(speech.DefaultWordsPerMinute),
+		Assembler: &mockAssembler{duration: 60, delay: time.Minute},
+	}))
+
+	start := time.Now()
+	_, err := pipeline.Generate(t.Context(), "cats")
+	if err == nil {
+		t.Fatal("expected assemble stage to time out")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "assemble stage timed out") {
+		t.Errorf("error = %q, want stage-specific message", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Generate() took %v, expected cancellation at the deadline", elapsed)
+	}
+}
+
+func TestWithStageTimeoutParentCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := withStageTimeout(ctx, "upload", 10, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if strings.Contains(err.Error(), "timed out") {
+		t.Errorf("parent cancellation reported as timeout: %v", err)
+	}
+}
+
+func TestPipelineUploadAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		account string
+		want    string
+		wantErr bool
+	}{
+		{name: "defaultAccount", want: "default"},
+		{name: "namedAccount", account: "gaming", want: "gaming"},
+		{name: "unknownAccount", account: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploaders := map[string]*mockUploader{
+				"default": {platform: "youtube", response: &distribution.UploadResponse{ID: "default"}},
+				"gaming":  {platform: "youtube", response: &distribution.UploadResponse{ID: "gaming"}},
+			}
+			svc := NewService(ServiceOptions{
+				Config:    &config.Config{},
+				Uploaders: []distribution.Uploader{uploaders["default"]},
+				Accounts:  map[string]distribution.Uploader{"gaming": uploaders["gaming"]},
+			})
+			pipeline := NewPipeline(svc)
+
+			resp, err := pipeline.Upload(t.Context(), UploadRequest{VideoPath: "/path/to/video.mp4", Title: "Test", Account: tt.account})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Upload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if resp.ID != tt.want {
+				t.Errorf("Upload() ID = %q, want %q", resp.ID, tt.want)
+			}
+			for name, uploader := range uploaders {
+				want := 0
+				if name == tt.want {
+					want = 1
+				}
+				if len(uploader.requests) != want {
+					t.Errorf("%s uploads = %d, want %d", name, len(uploader.requests), want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadUploadRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   string
+		thumb   bool
+		want    UploadRequest
+		wantErr bool
+	}{
+		{
+			name:  "sidecar",
+			state: `{"topic":"cats","source":"reddit","title":"Why Cats Purr","tags":["cats","facts"],"description":"All about purring","chapters":[{"Title":"Intro","Start":0},{"Title":"Science","Start":12}]}`,
+			thumb: true,
+			want: UploadRequest{
+				Title:       "Why Cats Purr",
+				Description: "All about purring\n\n0:00 Intro\n0:12 Science",
+				Tags:        []string{"cats", "facts"},
+				Source:      topicSourceReddit,
+			},
+		},
+		{name: "noSidecar"},
+		{name: "invalidSidecar", state: "{", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			videoPath := filepath.Join(dir, "video.mp4")
+			if err := os.WriteFile(videoPath, []byte("video"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.state != "" {
+				if err := os.WriteFile(filepath.Join(dir, "session.json"), []byte(tt.state), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tt.want.VideoPath = videoPath
+			if tt.thumb {
+				tt.want.Thumbnail = filepath.Join(dir, "video_thumbnail.jpg")
+				if err := os.WriteFile(tt.want.Thumbnail, []byte("jpg"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := LoadUploadRequest(videoPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadUploadRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadUploadRequest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadUploadRequestMissingVideo(t *testing.T) {
+	if _, err := LoadUploadRequest(filepath.Join(t.TempDir(), "missing.mp4")); err == nil {
+		t.Error("LoadUploadRequest() error = nil, want error for missing video")
+	}
+}
+
+func TestUploadExistingVideo(t *testing.T) {
+	dir := t.TempDir()
+	videoPath := filepath.Join(dir, "video.mp4")
+	if err := os.WriteFile(videoPath, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := `{"title":"Why Cats Purr","tags":["cats"],"description":"All about purring"}`
+	if err := os.WriteFile(filepath.Join(dir, "session.json"), []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mockUp := &mockUploader{response: &distribution.UploadResponse{ID: "abc123"}}
+	cfg := &config.Config{YouTube: config.YouTubeConfig{PrivacyStatus: "private"}}
+	pipeline := NewPipeline(NewService(ServiceOptions{Config: cfg, Uploaders: []distribution.Uploader{mockUp}}))
+
+	request, err := LoadUploadRequest(videoPath)
+	if err != nil {
+		t.Fatalf("LoadUploadRequest() error = %v", err)
+	}
+	if _, err := pipeline.Upload(t.Context(), request); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if len(mockUp.requests) != 1 {
+		t.Fatalf("uploader called %d times, want 1", len(mockUp.requests))
+	}
+	got := mockUp.requests[0]
+	if got.FilePath != videoPath || got.Title != "Why Cats Purr" || got.Description != "All about purring" {
+		t.Errorf("upload request = %+v", got)
+	}
+}
@@ -0,0 +1,30 @@
+package app
+
+import "time"
+
+type Backoff struct {
+	Initial  time.Duration
+	Max      time.Duration
+	failures int
+}
+
+func NewBackoff(initial, max time.Duration) *Backoff {
+	return &Backoff{Initial: initial, Max: max}
+}
+
+func (b *Backoff) Failure() time.Duration {
+	b.failures++
+	delay := b.Initial
+	for i := 1; i < b.failures && delay < b.Max; i++ {
+		delay *= 2
+	}
+	return min(delay, b.Max)
+}
+
+func (b *Backoff) Success() {
+	b.failures = 0
+}
+
+func (b *Backoff) Failures() int {
+	return b.failures
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	backoff := NewBackoff(time.Minute, 5*time.Minute)
+
+	steps := []struct {
+		name         string
+		success      bool
+		wantDelay    time.Duration
+		wantFailures int
+	}{
+		{name: "firstFailure", wantDelay: time.Minute, wantFailures: 1},
+		{name: "secondFailure", wantDelay: 2 * time.Minute, wantFailures: 2},
+		{name: "thirdFailure", wantDelay: 4 * time.Minute, wantFailures: 3},
+		{name: "cappedAtMax", wantDelay: 5 * time.Minute, wantFailures: 4},
+		{name: "stillCapped", wantDelay: 5 * time.Minute, wantFailures: 5},
+		{name: "successResets", success: true},
+		{name: "failureAfterReset", wantDelay: time.Minute, wantFailures: 1},
+	}
+
+	for _, step := range steps {
+		if step.success {
+			backoff.Success()
+		} else if got := backoff.Failure(); got != step.wantDelay {
+			t.Errorf("%s: Failure() = %v, want %v", step.name, got, step.wantDelay)
+		}
+		if got := backoff.Failures(); got != step.wantFailures {
+			t.Errorf("%s: Failures() = %d, want %d", step.name, got, step.wantFailures)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+type BatchOptions struct {
+	Concurrency int
+	Upload      bool
+}
+
+type BatchResult struct {
+	Topic   string
+	Result  *GenerateResult
+	Uploads map[string]UploadResult
+	Err     error
+}
+
+func ReadTopics(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open topics file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var topics []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		topics = append(topics, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read topics file: %w", err)
+	}
+	return topics, nil
+}
+
+func (pipeline *Pipeline) GenerateBatch(ctx context.Context, topics []string, opts BatchOptions) []BatchResult {
+	concurrency := max(opts.Concurrency, 1)
+	results := make([]BatchResult, len(topics))
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, topic := range topics {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = BatchResult{Topic: topic, Err: ctx.Err()}
+				return
+			}
+			defer func() { <-slots }()
+
+			results[i] = pipeline.generateBatchItem(ctx, topic, i+1, len(topics), opts.Upload)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (pipeline *Pipeline) generateBatchItem(ctx context.Context, topic string, index, total int, upload bool) BatchResult {
+	slog.Info("Generating batch video", "topic", topic, "item", index, "total", total)
+	result := BatchResult{Topic: topic}
+
+	result.Result, result.Err = pipeline.Generate(ctx, topic)
+	if result.Err != nil {
+		slog.Error("Batch topic failed", "topic", topic, "error", result.Err)
+		return result
+	}
+	if !upload {
+		return result
+	}
+
+	result.Uploads, result.Err = pipeline.UploadAll(ctx, UploadRequest{
+		VideoPath:   result.Result.VideoPath,
+		Title:       result.Result.Title,
+		Description: result.Result.Description,
+		Tags:        result.Result.Tags,
+		Thumbnail:   result.Result.ThumbnailPath,
+		Source:      result.Result.Source,
+	})
+	if result.Err != nil {
+		result.Err = fmt.Errorf("upload: %w", result.Err)
+		slog.Error("Batch upload failed", "topic", topic, "error", result.Err)
+		return result
+	}
+
+	var errs []error
+	for _, upload := range result.Uploads {
+		if upload.Err != nil {
+			errs = append(errs, upload.Err)
+		}
+	}
+	result.Err = errors.Join(errs...)
+	return result
+}
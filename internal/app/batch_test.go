@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"craftstory/internal/speech"
+	"craftstory/pkg/config"
+)
+
+type batchLLM struct {
+	mockLLM
+	failTopic string
+}
+
+func (m *batchLLM) GenerateScript(_ context.Context, topic string, wordCount int) (string, error) {
+	if topic == m.failTopic {
+		return "", errors.New("llm refused")
+	}
+	return words(wordCount), nil
+}
+
+func (m *batchLLM) GenerateConversation(ctx context.Context, topic string, _ []string, wordCount int) (string, error) {
+	return m.GenerateScript(ctx, topic, wordCount)
+}
+
+func TestReadTopics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "topics.txt")
+	content := "space facts\n\n# skipped\n  ancient mysteries  \n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	topics, err := ReadTopics(path)
+	if err != nil {
+		t.Fatalf("ReadTopics() error = %v", err)
+	}
+	want := []string{"space facts", "ancient mysteries"}
+	if !slices.Equal(topics, want) {
+		t.Errorf("ReadTopics() = %q, want %q", topics, want)
+	}
+
+	if _, err := ReadTopics(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("ReadTopics() expected error for missing file")
+	}
+}
+
+func TestGenerateBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "topics.txt")
+	if err := os.WriteFile(path, []byte("cats\nbad topic\ndogs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	topics, err := ReadTopics(path)
+	if err != nil {
+		t.Fatalf("ReadTopics() error = %v", err)
+	}
+
+	assembler := &mockAssembler{duration: 60}
+	cfg := &config.Config{
+		Content: config.ContentConfig{WordCount: 10},
+		Video:   config.VideoConfig{OutputDir: t.TempDir()},
+	}
+	pipeline := NewPipeline(NewService(ServiceOptions{
+		Config:    cfg,
+		LLM:       &batchLLM{failTopic: "bad topic"},
+		TTS:       speech.NewStubProvider(speech.DefaultWordsPerMinute),
+		Assembler: assembler,
+	}))
+
+	results := pipeline.GenerateBatch(t.Context(), topics, BatchOptions{Concurrency: 2})
+
+	if len(results) != len(topics) {
+		t.Fatalf("GenerateBatch() returned %d results, want %d", len(results), len(topics))
+	}
+	for i, result := range results {
+		if result.Topic != topics[i] {
+			t.Errorf("results[%d].Topic = %q, want %q", i, result.Topic, topics[i])
+		}
+		wantFailed := result.Topic == "bad topic"
+		if (result.Err != nil) != wantFailed {
+			t.Errorf("%s: error = %v, want failed %v", result.Topic, result.Err, wantFailed)
+		}
+		if !wantFailed && result.Result == nil {
+			t.Errorf("%s: missing generate result", result.Topic)
+		}
+	}
+	if assembler.calls != 2 {
+		t.Errorf("assembled %d videos, want 2", assembler.calls)
+	}
+}
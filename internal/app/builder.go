@@ -1,29 +1,49 @@
 package app
 
 import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"craftstory/internal/content/hackernews"
 	"craftstory/internal/content/reddit"
 	"craftstory/internal/distribution"
 	"craftstory/internal/distribution/telegram"
+	"craftstory/internal/distribution/tiktok"
 	"craftstory/internal/distribution/youtube"
 	"craftstory/internal/llm/groq"
 	"craftstory/internal/search"
+	"craftstory/internal/search/duckduckgo"
 	"craftstory/internal/search/google"
 	"craftstory/internal/search/tenor"
 	"craftstory/internal/speech"
 	"craftstory/internal/speech/elevenlabs"
 	"craftstory/internal/storage"
 	"craftstory/internal/video"
+	"craftstory/internal/webhook"
 	"craftstory/pkg/config"
+	"craftstory/pkg/httputil"
 	"craftstory/pkg/prompts"
 )
 
+const staleTempAge = time.Hour
+
 func BuildService(cfg *config.Config, verbose bool) (*Service, error) {
-	p, err := prompts.Load()
+	p, err := prompts.LoadWithOverrides(cfg.PromptsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := httputil.NewTransport(httputil.TransportConfig{
+		Proxy:     cfg.HTTP.Proxy,
+		UserAgent: cfg.HTTP.UserAgent,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	llmClient, err := groq.NewClient(cfg.GroqAPIKey, cfg.Groq.Model, p)
+	llmClient, err := groq.NewClient(cfg.GroqAPIKey, cfg.Groq.Model, cfg.Content.Language, cfg.Groq.RequestsPerMinute, p, transport)
 	if err != nil {
 		return nil, err
 	}
@@ -35,18 +55,17 @@ func BuildService(cfg *config.Config, verbose bool) (*Service, error) {
 			apiKeys = []string{cfg.ElevenLabsAPIKey}
 		}
 		ttsProvider = elevenlabs.NewClient(elevenlabs.Config{
-			APIKeys:    apiKeys,
-			VoiceID:    cfg.ElevenLabs.HostVoice.ID,
-			Speed:      cfg.ElevenLabs.Speed,
-			Stability:  cfg.ElevenLabs.Stability,
-			Similarity: cfg.ElevenLabs.Similarity,
+			APIKeys:        apiKeys,
+			VoiceID:        cfg.ElevenLabs.HostVoice.ID,
+			Speed:          cfg.ElevenLabs.Speed,
+			Stability:      cfg.ElevenLabs.Stability,
+			Similarity:     cfg.ElevenLabs.Similarity,
+			Model:          cfg.ElevenLabs.Model,
+			Language:       cfg.Content.Language,
+			WordsPerMinute: wordsPerMinute(cfg),
 		})
 	} else {
-		wordsPerMinute := speech.DefaultWordsPerMinute * cfg.ElevenLabs.Speed
-		if wordsPerMinute <= 0 {
-			wordsPerMinute = speech.DefaultWordsPerMinute
-		}
-		ttsProvider = speech.NewStubProvider(wordsPerMinute)
+		ttsProvider = speech.NewStubProvider(wordsPerMinute(cfg))
 	}
 
 	localStorage := storage.NewLocalStorage(cfg.Video.BackgroundDir, cfg.Video.OutputDir)
@@ -55,14 +74,20 @@ func BuildService(cfg *config.Config, verbose bool) (*Service, error) {
 	}
 
 	subtitleGen := video.NewSubtitleGenerator(video.SubtitleOptions{
-		FontName:     cfg.Subtitles.FontName,
-		FontSize:     cfg.Subtitles.FontSize,
-		PrimaryColor: cfg.Subtitles.PrimaryColor,
-		OutlineColor: cfg.Subtitles.OutlineColor,
-		OutlineSize:  cfg.Subtitles.OutlineSize,
-		ShadowSize:   cfg.Subtitles.ShadowSize,
-		Bold:         cfg.Subtitles.Bold,
-		Offset:       cfg.Subtitles.Offset,
+		FontName:        cfg.Subtitles.FontName,
+		FontSize:        cfg.Subtitles.FontSize,
+		PrimaryColor:    cfg.Subtitles.PrimaryColor,
+		OutlineColor:    cfg.Subtitles.OutlineColor,
+		OutlineSize:     cfg.Subtitles.OutlineSize,
+		ShadowSize:      cfg.Subtitles.ShadowSize,
+		Bold:            cfg.Subtitles.Bold,
+		Offset:          cfg.Subtitles.Offset,
+		EmphasisWords:   cfg.Subtitles.EmphasisWords,
+		SafeZoneBottom:  cfg.Subtitles.SafeZoneBottom,
+		MinWordDuration: cfg.Subtitles.MinWordDuration,
+		WordsPerCue:     cfg.Subtitles.WordsPerCue,
+		BackgroundBox:   cfg.Subtitles.BackgroundBox,
+		BoxColor:        cfg.Subtitles.BoxColor,
 	})
 
 	var musicDir string
@@ -71,51 +96,119 @@ func BuildService(cfg *config.Config, verbose bool) (*Service, error) {
 	}
 
 	assembler := video.NewAssemblerWithOptions(video.AssemblerOptions{
-		OutputDir:    cfg.Video.OutputDir,
-		Resolution:   cfg.Video.Resolution,
-		Threads:      cfg.Video.Threads,
-		SubtitleGen:  subtitleGen,
-		BgProvider:   localStorage,
-		MusicDir:     musicDir,
-		MusicVolume:  cfg.Music.Volume,
-		MusicFadeIn:  cfg.Music.FadeIn,
-		MusicFadeOut: cfg.Music.FadeOut,
+		OutputDir:      cfg.Video.OutputDir,
+		CacheDir:       cfg.Video.CacheDir,
+		Resolution:     cfg.Video.Resolution,
+		Threads:        cfg.Video.Threads,
+		SubtitleGen:    subtitleGen,
+		BgProvider:     localStorage,
+		MusicDir:       musicDir,
+		MusicVolume:    cfg.Music.Volume,
+		MusicFadeIn:    cfg.Music.FadeIn,
+		MusicFadeOut:   cfg.Music.FadeOut,
+		Ducking:        cfg.Music.Ducking,
+		DuckThreshold:  cfg.Music.DuckThreshold,
+		DuckRatio:      cfg.Music.DuckRatio,
+		Normalize:      cfg.Audio.Normalize,
+		TargetLUFS:     cfg.Audio.TargetLUFS,
+		TruePeak:       cfg.Audio.TruePeak,
+		KenBurns:       cfg.Visuals.KenBurns,
+		HookDuration:   cfg.Content.HookDuration,
+		SafeZoneBottom: cfg.Subtitles.SafeZoneBottom,
+		ExportSRT:      cfg.Subtitles.ExportSRT,
+		Encoder:        cfg.Video.Encoder,
+		CRF:            cfg.Video.CRF,
+		Preset:         cfg.Video.Preset,
+		BitrateKbps:    cfg.Video.BitrateKbps,
+		TwoPass:        cfg.Video.TwoPass,
+		Mirror:         cfg.Video.MirrorBackground,
+		SubscribePath:  cfg.Video.SubscribeOverlay.Path,
+		SubscribeDur:   cfg.Video.SubscribeOverlay.Duration,
+		Crossfade:      cfg.Video.CrossfadeDuration,
+		Watermark: video.Watermark{
+			Path:     cfg.Video.Watermark.Path,
+			Position: cfg.Video.Watermark.Position,
+			X:        cfg.Video.Watermark.X,
+			Y:        cfg.Video.Watermark.Y,
+			Opacity:  cfg.Video.Watermark.Opacity,
+			Scale:    cfg.Video.Watermark.Scale,
+			Layer:    cfg.Video.Watermark.Layer,
+		},
+		ProgressFunc: newProgressLogger(progressLogStep, logAssemblyProgress),
 		Verbose:      verbose,
 	})
+	if removed, err := assembler.CleanupTemps(staleTempAge); err != nil {
+		slog.Warn("Failed to clean up temp files", "error", err)
+	} else if removed > 0 {
+		slog.Info("Removed stale temp files", "count", removed)
+	}
 
-	redditClient := reddit.NewClient()
+	redditClient := reddit.NewClient(reddit.Config{
+		UserAgent:    cfg.HTTP.UserAgent,
+		Transport:    transport,
+		ClientID:     cfg.Reddit.ClientID,
+		ClientSecret: cfg.Reddit.ClientSecret,
+		Username:     cfg.Reddit.Username,
+		Password:     cfg.Reddit.Password,
+	})
 
-	var imageSearch *google.Client
-	if cfg.GoogleSearchAPIKey != "" && cfg.GoogleSearchEngineID != "" {
-		imageSearch = google.NewClient(google.Config{
-			APIKey:   cfg.GoogleSearchAPIKey,
-			EngineID: cfg.GoogleSearchEngineID,
-		})
+	hackerNewsClient := hackernews.NewClient(hackernews.Config{
+		UserAgent: cfg.HTTP.UserAgent,
+		Transport: transport,
+	})
+
+	imageSource, err := newImageSource(cfg, transport)
+	if err != nil {
+		return nil, err
 	}
 
 	var gifSearch *tenor.Client
 	if cfg.TenorAPIKey != "" && cfg.Visuals.GIFEnabled {
-		gifSearch = tenor.NewClient(tenor.Config{APIKey: cfg.TenorAPIKey})
+		gifSearch = tenor.NewClient(tenor.Config{APIKey: cfg.TenorAPIKey, Transport: transport})
 	}
 
 	var fetcher *search.Fetcher
-	if imageSearch != nil || gifSearch != nil {
+	if imageSource != nil || gifSearch != nil {
 		var gifSearcher search.GIFSearcher
 		if gifSearch != nil {
 			gifSearcher = gifSearch
 		}
-		fetcher = search.NewFetcher(imageSearch, gifSearcher, search.FetcherConfig{
+		fetcher = search.NewFetcher(imageSource, gifSearcher, search.FetcherConfig{
 			MaxDisplayTime: cfg.Visuals.MaxDisplayTime,
 			ImageWidth:     cfg.Visuals.ImageWidth,
 			ImageHeight:    cfg.Visuals.ImageHeight,
 			MinGap:         cfg.Visuals.MinGap,
+			MinDuration:    cfg.Visuals.MinDuration,
+			MaxDuration:    cfg.Visuals.MaxDuration,
+			MaxCount:       cfg.Visuals.MaxCount,
+			MinWidth:       cfg.Visuals.MinWidth,
+			MinHeight:      cfg.Visuals.MinHeight,
+			Orientation:    cfg.Visuals.Orientation,
 		})
 	}
 
-	var ytUploader distribution.Uploader
-	if cfg.YouTubeClientID != "" && cfg.YouTubeClientSecret != "" {
-		auth := youtube.NewAuth(cfg.YouTubeClientID, cfg.YouTubeClientSecret, cfg.YouTubeTokenPath)
-		ytUploader = youtube.NewClient(auth)
+	var uploaders []distribution.Uploader
+	account, err := cfg.YouTubeAccount("")
+	if err != nil {
+		return nil, err
+	}
+	if account.ClientID != "" && account.ClientSecret != "" {
+		uploaders = append(uploaders, newYouTubeUploader(cfg, account))
+	}
+
+	youtubeAccounts := make(map[string]distribution.Uploader, len(cfg.YouTube.Accounts))
+	for name := range cfg.YouTube.Accounts {
+		account, _ := cfg.YouTubeAccount(name)
+		if account.ClientID != "" && account.ClientSecret != "" {
+			youtubeAccounts[name] = newYouTubeUploader(cfg, account)
+		}
+	}
+
+	if cfg.TikTokAccessToken != "" {
+		uploaders = append(uploaders, tiktok.NewClient(tiktok.Config{
+			AccessToken:  cfg.TikTokAccessToken,
+			PrivacyLevel: cfg.TikTok.PrivacyLevel,
+		}))
 	}
 
 	var approval *telegram.ApprovalService
@@ -125,16 +218,52 @@ func BuildService(cfg *config.Config, verbose bool) (*Service, error) {
 	}
 
 	service := NewService(ServiceOptions{
-		Config:    cfg,
-		LLM:       llmClient,
-		TTS:       ttsProvider,
-		Uploader:  ytUploader,
-		Assembler: assembler,
-		Storage:   localStorage,
-		Reddit:    redditClient,
-		Fetcher:   fetcher,
-		Approval:  approval,
+		Config:     cfg,
+		LLM:        llmClient,
+		TTS:        ttsProvider,
+		Uploaders:  uploaders,
+		Accounts:   youtubeAccounts,
+		Assembler:  assembler,
+		Storage:    localStorage,
+		Reddit:     redditClient,
+		HackerNews: hackerNewsClient,
+		Fetcher:    fetcher,
+		Approval:   approval,
+		Webhook: webhook.NewClient(webhook.Config{
+			URL:       cfg.WebhookURL,
+			Secret:    cfg.WebhookSecret,
+			Transport: transport,
+		}),
 	})
 
 	return service, nil
 }
+
+func newImageSource(cfg *config.Config, transport http.RoundTripper) (search.ImageSource, error) {
+	switch cfg.Visuals.SearchProvider {
+	case "", "google":
+		if cfg.GoogleSearchAPIKey == "" || cfg.GoogleSearchEngineID == "" {
+			return nil, nil
+		}
+		return google.NewClient(google.Config{
+			APIKey:    cfg.GoogleSearchAPIKey,
+			EngineID:  cfg.GoogleSearchEngineID,
+			Transport: transport,
+			MinWidth:  cfg.Visuals.MinWidth,
+			MinHeight: cfg.Visuals.MinHeight,
+			ImgSize:   cfg.Visuals.SearchImgSize,
+			ImgType:   cfg.Visuals.SearchImgType,
+		}), nil
+	case "duckduckgo":
+		return duckduckgo.NewClient(duckduckgo.Config{Transport: transport}), nil
+	default:
+		return nil, fmt.Errorf("unknown visuals search provider %q", cfg.Visuals.SearchProvider)
+	}
+}
+
+func newYouTubeUploader(cfg *config.Config, account config.YouTubeAccount) distribution.Uploader {
+	return youtube.NewClient(youtube.Config{
+		Auth:          youtube.NewAuth(account.ClientID, account.ClientSecret, account.TokenPath),
+		UploadRetries: cfg.YouTube.UploadRetries,
+	})
+}
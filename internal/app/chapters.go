@@ -0,0 +1,97 @@
+package app
+
+import (
+	"strings"
+
+	"craftstory/internal/llm"
+	"craftstory/internal/speech"
+	"craftstory/internal/video"
+)
+
+const (
+	chapterBlockStart = "0:00 "
+	minChapterLength  = 10.0
+	minChapters       = 3
+	chapterTitleWords = 6
+)
+
+func buildChapters(timings []speech.WordTiming) []video.Chapter {
+	if len(timings) == 0 {
+		return nil
+	}
+
+	var chapters []video.Chapter
+	var title []string
+	collecting := false
+	for i, timing := range timings {
+		turnStart := i == 0 || timing.Speaker != timings[i-1].Speaker
+		if turnStart {
+			collecting = false
+			if len(chapters) == 0 || timing.StartTime-chapters[len(chapters)-1].Start >= minChapterLength {
+				chapters = appendChapterTitle(chapters, title)
+				chapters = append(chapters, video.Chapter{Start: timing.StartTime})
+				title = nil
+				collecting = true
+			}
+		}
+		if collecting && len(title) < chapterTitleWords {
+			title = append(title, timing.Word)
+		}
+	}
+	chapters = appendChapterTitle(chapters, title)
+
+	end := timings[len(timings)-1].EndTime
+	if len(chapters) > 1 && end-chapters[len(chapters)-1].Start < minChapterLength {
+		chapters = chapters[:len(chapters)-1]
+	}
+	if len(chapters) < minChapters {
+		return nil
+	}
+	return chapters
+}
+
+func appendChapterTitle(chapters []video.Chapter, words []string) []video.Chapter {
+	if len(chapters) == 0 {
+		return chapters
+	}
+	chapters[len(chapters)-1].Title = strings.TrimRight(strings.Join(words, " "), ",;:-")
+	return chapters
+}
+
+func withChapters(description string, chapters []video.Chapter) string {
+	if len(chapters) == 0 {
+		return description
+	}
+	lines := video.FormatChapters(chapters)
+	if description == "" {
+		return lines
+	}
+	return description + "\n\n" + lines
+}
+
+func fitDescription(description string) string {
+	if len(description) <= llm.MaxDescriptionLength {
+		return description
+	}
+
+	var prose, chapters string
+	switch {
+	case strings.HasPrefix(description, chapterBlockStart):
+		chapters = description
+	default:
+		i := strings.LastIndex(description, "\n\n"+chapterBlockStart)
+		if i < 0 {
+			return llm.TruncateDescription(description, llm.MaxDescriptionLength)
+		}
+		prose, chapters = description[:i], description[i+2:]
+	}
+
+	room := llm.MaxDescriptionLength - len(chapters) - len("\n\n")
+	if room <= 0 {
+		return llm.TruncateDescription(description, llm.MaxDescriptionLength)
+	}
+	if prose = llm.TruncateDescription(prose, room); prose == "" {
+		return chapters
+	}
+	return prose + "\n\n" + chapters
+}
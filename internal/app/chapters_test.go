@@ -0,0 +1,136 @@
+package app
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"craftstory/internal/llm"
+	"craftstory/internal/speech"
+	"craftstory/internal/video"
+)
+
+func turn(speaker string, start float64, words ...string) []speech.WordTiming {
+	timings := make([]speech.WordTiming, len(words))
+	for i, word := range words {
+		timings[i] = speech.WordTiming{Word: word, StartTime: start + float64(i), EndTime: start + float64(i) + 1, Speaker: speaker}
+	}
+	return timings
+}
+
+func TestBuildChapters(t *testing.T) {
+	tests := []struct {
+		name    string
+		timings []speech.WordTiming
+		want    []video.Chapter
+	}{
+		{
+			name: "speakerTurns",
+			timings: slices.Concat(
+				turn("Alice", 0, "Why", "do", "cats", "purr,"),
+				turn("Bob", 12, "It", "is", "a", "vibration", "in", "the", "larynx"),
+				turn("Alice", 25, "Do", "big", "cats", "purr?"),
+				turn("Bob", 40, "Only", "some", "of", "them", "do", "actually", "purr", "really", "loudly", "ok"),
+			),
+			want: []video.Chapter{
+				{Title: "Why do cats purr", Start: 0},
+				{Title: "It is a vibration in the", Start: 12},
+				{Title: "Do big cats purr?", Start: 25},
+				{Title: "Only some of them do actually", Start: 40},
+			},
+		},
+		{
+			name: "shortTurnsMerged",
+			timings: slices.Concat(
+				turn("Alice", 0, "One"),
+				turn("Bob", 3, "Two"),
+				turn("Alice", 11, "Three"),
+				turn("Bob", 22, "Four"),
+				turn("Alice", 33, "Five", "six", "seven", "eight", "nine", "ten", "eleven", "twelve", "thirteen", "fourteen"),
+			),
+			want: []video.Chapter{
+				{Title: "One", Start: 0},
+				{Title: "Three", Start: 11},
+				{Title: "Four", Start: 22},
+				{Title: "Five six seven eight nine ten", Start: 33},
+			},
+		},
+		{
+			name: "shortLastChapterDropped",
+			timings: slices.Concat(
+				turn("Alice", 0, "One"),
+				turn("Bob", 10, "Two"),
+				turn("Alice", 20, "Three"),
+				turn("Bob", 30, "Four"),
+			),
+			want: []video.Chapter{{Title: "One", Start: 0}, {Title: "Two", Start: 10}, {Title: "Three", Start: 20}},
+		},
+		{
+			name:    "tooFewChapters",
+			timings: slices.Concat(turn("Alice", 0, "One"), turn("Bob", 12, "Two")),
+		},
+		{
+			name:    "singleSpeaker",
+			timings: turn("", 0, "Just", "one", "narrator", "talking"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildChapters(tt.timings); !slices.Equal(got, tt.want) {
+				t.Errorf("buildChapters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithChapters(t *testing.T) {
+	chapters := []video.Chapter{{Title: "Cats", Start: 0}, {Title: "Dogs", Start: 12}}
+
+	tests := []struct {
+		name        string
+		description string
+		chapters    []video.Chapter
+		want        string
+	}{
+		{name: "appended", description: "All about pets", chapters: chapters, want: "All about pets\n\n0:00 Cats\n0:12 Dogs"},
+		{name: "emptyDescription", chapters: chapters, want: "0:00 Cats\n0:12 Dogs"},
+		{name: "noChapters", description: "All about pets", want: "All about pets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withChapters(tt.description, tt.chapters); got != tt.want {
+				t.Errorf("withChapters() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitDescription(t *testing.T) {
+	block := "0:00 Cats\n0:12 Dogs\n0:30 Birds"
+	long := strings.Repeat("word ", llm.MaxDescriptionLength/5+10)
+
+	tests := []struct {
+		name        string
+		description string
+		wantSuffix  string
+		wantPrefix  string
+	}{
+		{name: "withinLimit", description: "All about pets\n\n" + block, wantPrefix: "All about pets\n\n", wantSuffix: block},
+		{name: "proseTruncatedChaptersKept", description: long + "\n\n" + block, wantPrefix: "word word", wantSuffix: "\n\n" + block},
+		{name: "noChapters", description: long, wantPrefix: "word word", wantSuffix: "word"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fitDescription(tt.description)
+			if len(got) > llm.MaxDescriptionLength {
+				t.Errorf("fitDescription() length = %d, want at most %d", len(got), llm.MaxDescriptionLength)
+			}
+			if !strings.HasPrefix(got, tt.wantPrefix) || !strings.HasSuffix(got, tt.wantSuffix) {
+				t.Errorf("fitDescription() = %q..., want prefix %q and suffix %q", got[:min(len(got), 40)], tt.wantPrefix, tt.wantSuffix)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"craftstory/pkg/config"
+)
+
+var freeSpace = diskFree
+
+func checkDiskSpace(cfg *config.Config) error {
+	if cfg.Video.MinFreeMB <= 0 {
+		return nil
+	}
+
+	dir := cfg.Video.OutputDir
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+
+	free, err := freeSpace(dir)
+	if err != nil {
+		slog.Warn("Failed to check free disk space, continuing", "dir", dir, "error", err)
+		return nil
+	}
+	if free < uint64(cfg.Video.MinFreeMB)<<20 {
+		return fmt.Errorf("%w: %s has %d MB free, video.min_free_mb requires %d MB", ErrLowDiskSpace, dir, free>>20, cfg.Video.MinFreeMB)
+	}
+	return nil
+}
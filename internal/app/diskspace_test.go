@@ -0,0 +1,68 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"craftstory/pkg/config"
+)
+
+func stubFreeSpace(t *testing.T, free uint64, err error) {
+	t.Helper()
+	original := freeSpace
+	t.Cleanup(func() { freeSpace = original })
+	freeSpace = func(string) (uint64, error) { return free, err }
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	tests := []struct {
+		name      string
+		minFreeMB int
+		free      uint64
+		statErr   error
+		wantErr   bool
+	}{
+		{name: "enoughSpace", minFreeMB: 100, free: 200 << 20},
+		{name: "lowSpace", minFreeMB: 100, free: 50 << 20, wantErr: true},
+		{name: "disabled", free: 0},
+		{name: "statFailsContinues", minFreeMB: 100, statErr: errors.New("statfs failed")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubFreeSpace(t, tt.free, tt.statErr)
+			cfg := &config.Config{Video: config.VideoConfig{OutputDir: t.TempDir(), MinFreeMB: tt.minFreeMB}}
+
+			err := checkDiskSpace(cfg)
+			if tt.wantErr {
+				if !errors.Is(err, ErrLowDiskSpace) {
+					t.Errorf("checkDiskSpace() error = %v, want ErrLowDiskSpace", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("checkDiskSpace() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateLowDiskSpace(t *testing.T) {
+	stubFreeSpace(t, 10<<20, nil)
+
+	mock := &mockLLM{scripts: []string{words(10)}}
+	assembler := &mockAssembler{duration: 60}
+	cfg := &config.Config{
+		Content: config.ContentConfig{WordCount: 10},
+		Video:   config.VideoConfig{OutputDir: t.TempDir(), MinFreeMB: 500},
+	}
+	pipeline := NewPipeline(NewService(ServiceOptions{Config: cfg, LLM: mock, Assembler: assembler}))
+
+	_, err := pipeline.Generate(t.Context(), "cats")
+	if !errors.Is(err, ErrLowDiskSpace) {
+		t.Fatalf("Generate() error = %v, want ErrLowDiskSpace", err)
+	}
+	if len(mock.topics) != 0 || assembler.calls != 0 {
+		t.Errorf("generation ran despite low disk space: script calls = %d, assemble calls = %d", len(mock.topics), assembler.calls)
+	}
+}
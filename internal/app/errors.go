@@ -0,0 +1,52 @@
+package app
+
+import (
+	"errors"
+
+	"craftstory/internal/content/reddit"
+	"craftstory/internal/llm"
+)
+
+var (
+	ErrNoTopics         = errors.New("no topics available")
+	ErrDurationExceeded = errors.New("audio exceeds max duration")
+	ErrBlockedContent   = errors.New("script contains blocked content")
+	ErrFileTooLarge     = errors.New("video exceeds upload size limit")
+	ErrLowDiskSpace     = errors.New("not enough free disk space")
+	ErrUploadsPaused    = errors.New("uploads paused until quota reset")
+)
+
+func isContentError(err error) bool {
+	return errors.Is(err, llm.ErrEmptyResponse) ||
+		errors.Is(err, ErrBlockedContent) ||
+		errors.Is(err, ErrDurationExceeded)
+}
+
+func FailureMessage(err error) string {
+	if hint := failureHint(err); hint != "" {
+		return hint + "\n\n" + err.Error()
+	}
+	return err.Error()
+}
+
+func failureHint(err error) string {
+	switch {
+	case errors.Is(err, ErrNoTopics):
+		return "No new topics are available right now. Try again later or send a topic."
+	case errors.Is(err, ErrDurationExceeded):
+		return "The narration ran past video.max_duration. Try a narrower topic."
+	case errors.Is(err, ErrBlockedContent):
+		return "The script hit the content blocklist. Try a different topic."
+	case errors.Is(err, llm.ErrEmptyResponse):
+		return "The language model returned nothing, which usually means it refused the topic. Try a different one."
+	case errors.Is(err, llm.ErrNoProviders):
+		return "No language model is configured. Set GROQ_API_KEY."
+	case errors.Is(err, ErrLowDiskSpace):
+		return "The server is low on disk space. Free some space before generating again."
+	case errors.Is(err, reddit.ErrUnauthorized):
+		return "Reddit rejected the credentials. Check the reddit client_id, client_secret, username and password."
+	case errors.Is(err, reddit.ErrRateLimited):
+		return "Reddit is rate limiting requests. Try again in a few minutes."
+	}
+	return ""
+}
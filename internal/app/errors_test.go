@@ -0,0 +1,101 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"craftstory/internal/content/reddit"
+	"craftstory/internal/llm"
+	"craftstory/internal/speech"
+	"craftstory/pkg/config"
+)
+
+func TestPipelineErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		scripts []string
+		wantErr error
+	}{
+		{
+			name:    "emptyScript",
+			scripts: []string{"   "},
+			wantErr: llm.ErrEmptyResponse,
+		},
+		{
+			name:    "blockedContent",
+			cfg:     config.Config{Content: config.ContentConfig{Blocklist: []string{"forbidden"}}},
+			scripts: []string{words(9) + " forbidden"},
+			wantErr: ErrBlockedContent,
+		},
+		{
+			name:    "durationExceeded",
+			cfg:     config.Config{Video: config.VideoConfig{MaxDuration: 1}},
+			scripts: []string{words(10)},
+			wantErr: ErrDurationExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Content.WordCount = 10
+			cfg.Video.OutputDir = t.TempDir()
+			pipeline := NewPipeline(NewService(ServiceOptions{
+				Config:    &cfg,
+				LLM:       &mockLLM{scripts: tt.scripts},
+				TTS:       speech.NewStubProvider(speech.DefaultWordsPerMinute),
+				Assembler: &mockAssembler{duration: 60},
+			}))
+
+			_, err := pipeline.Generate(t.Context(), "cats")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Generate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNoTopicsError(t *testing.T) {
+	cfg := &config.Config{TopicSource: "static"}
+	pipeline := NewPipeline(NewService(ServiceOptions{Config: cfg}))
+
+	if _, err := pipeline.GenerateFromSource(t.Context()); !errors.Is(err, ErrNoTopics) {
+		t.Errorf("GenerateFromSource() error = %v, want ErrNoTopics", err)
+	}
+}
+
+func TestFailureMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantHint string
+	}{
+		{name: "noTopics", err: fmt.Errorf("%w: no posts", ErrNoTopics), wantHint: "No new topics"},
+		{name: "durationExceeded", err: fmt.Errorf("assemble: %w", ErrDurationExceeded), wantHint: "max_duration"},
+		{name: "llmRefusal", err: fmt.Errorf("all llm providers failed: %w", llm.ErrEmptyResponse), wantHint: "refused"},
+		{name: "redditAuth", err: fmt.Errorf("fetch reddit posts: %w", reddit.ErrUnauthorized), wantHint: "credentials"},
+		{name: "lowDisk", err: ErrLowDiskSpace, wantHint: "disk space"},
+		{name: "unknown", err: errors.New("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FailureMessage(tt.err)
+			if !strings.HasSuffix(got, tt.err.Error()) {
+				t.Errorf("FailureMessage() = %q, want original error included", got)
+			}
+			if tt.wantHint == "" {
+				if got != tt.err.Error() {
+					t.Errorf("FailureMessage() = %q, want plain error", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.wantHint) {
+				t.Errorf("FailureMessage() = %q, want hint containing %q", got, tt.wantHint)
+			}
+		})
+	}
+}
@@ -1,12 +1,62 @@
 package app
 
 import (
+	"log/slog"
+	"math"
 	"math/rand"
+	"sync"
+	"time"
+
+	"craftstory/internal/speech"
+	"craftstory/pkg/config"
 )
 
-func randomInt(n int) int {
+const progressLogStep = 10.0
+
+func randomInt(rng *rand.Rand, n int) int {
 	if n <= 0 {
 		return 0
 	}
-	return rand.Intn(n)
+	return rng.Intn(n)
+}
+
+func newRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+func wordsPerMinute(cfg *config.Config) float64 {
+	base := cfg.Content.WordsPerMinute
+	if base <= 0 {
+		base = speech.DefaultWordsPerMinute
+	}
+	speed := cfg.ElevenLabs.Speed
+	if speed <= 0 {
+		speed = 1.0
+	}
+	return base * speed
+}
+
+func newProgressLogger(step float64, log func(percent float64)) func(percent float64) {
+	var mu sync.Mutex
+	next := 0.0
+	return func(percent float64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if percent < next-step {
+			next = 0
+		}
+		if percent < next {
+			return
+		}
+		log(percent)
+		next = (math.Floor(percent/step) + 1) * step
+	}
+}
+
+func logAssemblyProgress(percent float64) {
+	slog.Info("Assembling video", "progress", math.Round(percent))
 }
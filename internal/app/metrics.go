@@ -0,0 +1,44 @@
+package app
+
+import "time"
+
+type Metrics struct {
+	Script    time.Duration
+	Metadata  time.Duration
+	Audio     time.Duration
+	Images    time.Duration
+	Assembly  time.Duration
+	Thumbnail time.Duration
+	Total     time.Duration
+}
+
+func timeStage(stage *time.Duration) func() {
+	start := time.Now()
+	return func() {
+		*stage += time.Since(start)
+	}
+}
+
+func (m *Metrics) Stages() map[string]time.Duration {
+	return map[string]time.Duration{
+		"script":    m.Script,
+		"metadata":  m.Metadata,
+		"audio":     m.Audio,
+		"images":    m.Images,
+		"assembly":  m.Assembly,
+		"thumbnail": m.Thumbnail,
+		"total":     m.Total,
+	}
+}
+
+func (m *Metrics) logAttrs() []any {
+	return []any{
+		"script", m.Script.Round(time.Millisecond),
+		"metadata", m.Metadata.Round(time.Millisecond),
+		"audio", m.Audio.Round(time.Millisecond),
+		"images", m.Images.Round(time.Millisecond),
+		"assembly", m.Assembly.Round(time.Millisecond),
+		"thumbnail", m.Thumbnail.Round(time.Millisecond),
+		"total", m.Total.Round(time.Millisecond),
+	}
+}
@@ -4,28 +4,50 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"math"
+	"math/rand"
 	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"craftstory/internal/dialogue"
 	"craftstory/internal/distribution"
+	"craftstory/internal/llm"
 	"craftstory/internal/search"
 	"craftstory/internal/speech"
 	"craftstory/internal/video"
+	"craftstory/pkg/config"
+)
+
+const (
+	maxHookWords         = 12
+	maxTagChars          = 500
+	defaultLengthRetries = 2
 )
 
 type Pipeline struct {
 	service *Service
+	slots   chan struct{}
 }
 
 type GenerateResult struct {
+	Topic         string
+	Source        string
 	Title         string
 	Tags          []string
+	Description   string
 	ScriptContent string
 	OutputDir     string
 	AudioPath     string
 	VideoPath     string
-	PreviewPath   string
+	ThumbnailPath string
+	CaptionsPath  string
 	Duration      float64
+	Chapters      []video.Chapter
+	Metrics       Metrics
 }
 
 type UploadRequest struct {
@@ -33,15 +55,30 @@ type UploadRequest struct {
 	Title       string
 	Description string
 	Tags        []string
+	Thumbnail   string
+	Account     string
+	Source      string
+	Privacy     string
+	PublishAt   time.Time
+}
+
+type UploadResult struct {
+	Response *distribution.UploadResponse
+	Err      error
 }
 
 type generationContext struct {
 	ctx            context.Context
+	cfg            *config.Config
 	pipeline       *Pipeline
 	session        *session
 	voices         []speech.VoiceConfig
 	voiceMap       map[string]speech.VoiceConfig
 	isConversation bool
+	metrics        Metrics
+	rng            *rand.Rand
+	audioOnly      bool
+	style          string
 }
 
 type audioResult struct {
@@ -52,95 +89,403 @@ type audioResult struct {
 }
 
 func NewPipeline(service *Service) *Pipeline {
-	return &Pipeline{service: service}
+	pipeline := &Pipeline{service: service}
+	if n := service.config().MaxConcurrentGenerations; n > 0 {
+		pipeline.slots = make(chan struct{}, n)
+	}
+	return pipeline
+}
+
+func (pipeline *Pipeline) acquire(ctx context.Context) (func(), error) {
+	if pipeline.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case pipeline.slots <- struct{}{}:
+		return func() { <-pipeline.slots }, nil
+	default:
+	}
+
+	slog.Info("Waiting for a free generation slot", "max", cap(pipeline.slots))
+	select {
+	case pipeline.slots <- struct{}{}:
+		return func() { <-pipeline.slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for generation slot: %w", ctx.Err())
+	}
 }
 
 func (pipeline *Pipeline) Generate(ctx context.Context, topic string) (*GenerateResult, error) {
 	generation := pipeline.newGenerationContext(ctx)
+	return generation.run(&sessionState{Topic: topic, Source: topicSourceManual})
+}
 
-	slog.Info("Generating script...", "conversation", generation.isConversation)
-	script, err := generation.generateScript(topic)
+func (pipeline *Pipeline) GenerateAudio(ctx context.Context, topic string) (*GenerateResult, error) {
+	generation := pipeline.newGenerationContext(ctx)
+	generation.audioOnly = true
+	return generation.run(&sessionState{Topic: topic, Source: topicSourceManual})
+}
+
+func (pipeline *Pipeline) Resume(ctx context.Context, sessionDir string) (*GenerateResult, error) {
+	generation := pipeline.newGenerationContext(ctx)
+	state, err := generation.session.resume(sessionDir)
 	if err != nil {
 		return nil, err
 	}
 
-	title := generation.generateTitle(script, topic)
-	tags := generation.generateTags(script)
-	if err := generation.session.finalize(title); err != nil {
+	slog.Info("Resuming session", "dir", sessionDir)
+	return generation.run(state)
+}
+
+func (generation *generationContext) run(state *sessionState) (*GenerateResult, error) {
+	if err := checkDiskSpace(generation.cfg); err != nil {
 		return nil, err
 	}
-	_ = os.WriteFile(generation.session.scriptPath(), []byte(script), 0644)
 
-	slog.Info("Generating audio...", "length", len(script))
-	audio, err := generation.generateAudio(script)
+	release, err := generation.pipeline.acquire(generation.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	start := time.Now()
+	timeouts := generation.cfg.Timeouts
+	generation.style = generation.styleFor(state.Subreddit)
+
+	var script string
+	err = generation.stage("script", timeouts.Script, func() error {
+		var err error
+		script, err = generation.loadOrGenerateScript(state.Topic)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Title == "" {
+		state.Title = generation.generateTitle(script, state.Topic)
+		state.Tags = generation.generateTags(script)
+	}
+	if state.Description == "" {
+		state.Description = generation.generateDescription(script)
+	}
+	if generation.session.dir == "" {
+		if err := generation.session.finalize(state.Title); err != nil {
+			return nil, err
+		}
+	}
+	if generation.session.existingScript() == "" {
+		_ = os.WriteFile(generation.session.scriptPath(), []byte(script), 0644)
+	}
+	generation.session.nameVideo(state)
+	if err := generation.session.saveState(state); err != nil {
+		return nil, err
+	}
+
+	var audio *audioResult
+	err = generation.stage("audio", timeouts.Audio, func() error {
+		var err error
+		audio, err = generation.loadOrGenerateAudio(script, state)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	if err := os.WriteFile(generation.session.audioPath(), audio.data, 0644); err != nil {
-		return nil, fmt.Errorf("save audio: %w", err)
+
+	if generation.audioOnly {
+		return generation.audioOnlyResult(state, script, audio, start)
 	}
 
 	slog.Info("Fetching images...")
 	images := generation.fetchImages(script, audio.timings)
 
 	slog.Info("Assembling video...", "overlays", len(images))
-	result, err := generation.assemble(audio, images)
+	var result *video.AssembleResult
+	err = generation.stage("assemble", timeouts.Assemble, func() error {
+		var err error
+		result, err = generation.assemble(audio, images, generation.musicMood(state.Topic))
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	var previewPath string
-	previewDuration := generation.pipeline.service.cfg.Telegram.PreviewDuration
-	if previewDuration <= 0 {
-		previewDuration = 30
-	}
-	if result.Duration > previewDuration {
-		slog.Info("Creating preview...", "duration", previewDuration)
-		previewPath, err = generation.pipeline.service.assembler.CreatePreview(ctx, result.OutputPath, previewDuration)
-		if err != nil {
-			slog.Warn("Failed to create preview", "error", err)
+	if len(result.Chapters) > 0 || len(state.Chapters) > 0 {
+		state.Chapters = result.Chapters
+		if err := generation.session.saveState(state); err != nil {
+			slog.Warn("Failed to save chapters", "error", err)
 		}
 	}
 
+	thumbnailPath := generation.createThumbnail(result, state.Title)
+
+	generation.metrics.Total = time.Since(start)
+	slog.Info("Generation timings", generation.metrics.logAttrs()...)
+
 	return &GenerateResult{
-		Title:         title,
-		Tags:          tags,
+		Topic:         state.Topic,
+		Source:        state.Source,
+		Title:         state.Title,
+		Tags:          state.Tags,
+		Description:   withChapters(state.Description, result.Chapters),
 		ScriptContent: script,
 		OutputDir:     generation.session.dir,
 		AudioPath:     generation.session.audioPath(),
 		VideoPath:     result.OutputPath,
-		PreviewPath:   previewPath,
+		ThumbnailPath: thumbnailPath,
+		CaptionsPath:  result.CaptionsPath,
 		Duration:      result.Duration,
+		Chapters:      result.Chapters,
+		Metrics:       generation.metrics,
+	}, nil
+}
+
+func (generation *generationContext) audioOnlyResult(state *sessionState, script string, audio *audioResult, start time.Time) (*GenerateResult, error) {
+	captionsPath, err := generation.writeCaptions(audio)
+	if err != nil {
+		return nil, err
+	}
+
+	generation.metrics.Total = time.Since(start)
+	slog.Info("Generation timings", generation.metrics.logAttrs()...)
+
+	return &GenerateResult{
+		Topic:         state.Topic,
+		Source:        state.Source,
+		Title:         state.Title,
+		Tags:          state.Tags,
+		Description:   state.Description,
+		ScriptContent: script,
+		OutputDir:     generation.session.dir,
+		AudioPath:     generation.session.audioPath(),
+		CaptionsPath:  captionsPath,
+		Duration:      audio.duration,
+		Metrics:       generation.metrics,
 	}, nil
 }
 
+func (generation *generationContext) writeCaptions(audio *audioResult) (string, error) {
+	subtitleGen := video.NewSubtitleGenerator(video.SubtitleOptions{})
+	subtitles := subtitleGen.GenerateFromTimings(audio.timings)
+	if len(subtitles) == 0 {
+		subtitles = subtitleGen.Generate(audio.script, audio.duration)
+	}
+
+	path := generation.session.captionsPath()
+	if err := os.WriteFile(path, []byte(subtitleGen.ToSRT(subtitles)), 0644); err != nil {
+		return "", fmt.Errorf("write captions: %w", err)
+	}
+	return path, nil
+}
+
+func (generation *generationContext) loadOrGenerateScript(topic string) (string, error) {
+	if script := generation.session.existingScript(); script != "" {
+		slog.Info("Reusing existing script", "path", generation.session.scriptPath())
+		return script, nil
+	}
+	if topic == "" {
+		return "", fmt.Errorf("%w: no script or topic to generate from", ErrNoTopics)
+	}
+
+	slog.Info("Generating script...", "conversation", generation.isConversation)
+	script, err := generation.generateScript(topic)
+	if err != nil {
+		return "", err
+	}
+	return generation.ensureSafeScript(topic, script)
+}
+
+func (generation *generationContext) loadOrGenerateAudio(script string, state *sessionState) (*audioResult, error) {
+	if audio, ok := generation.session.existingAudio(state); ok {
+		slog.Info("Reusing existing audio", "path", generation.session.audioPath())
+		return audio, nil
+	}
+
+	slog.Info("Generating audio...", "length", len(script))
+	audio, err := generation.generateAudio(script)
+	if err != nil {
+		return nil, err
+	}
+	if err := generation.session.writeAudio(audio.data); err != nil {
+		return nil, err
+	}
+
+	state.AudioScript = audio.script
+	state.Timings = audio.timings
+	if err := generation.session.saveState(state); err != nil {
+		return nil, err
+	}
+	return audio, nil
+}
+
 func (pipeline *Pipeline) newGenerationContext(ctx context.Context) *generationContext {
-	cfg := pipeline.service.cfg
-	voices := pipeline.voices()
+	cfg := pipeline.service.config()
+	voices := voicesFor(cfg)
 	return &generationContext{
 		ctx:            ctx,
+		cfg:            cfg,
 		pipeline:       pipeline,
-		session:        newSession(cfg.Video.OutputDir),
+		session:        newSession(cfg.Video.OutputDir, cfg.Video.FilenameTemplate),
 		voices:         voices,
 		voiceMap:       speech.BuildVoiceMap(voices),
 		isConversation: cfg.Content.ConversationMode && len(voices) >= 2,
+		rng:            newRand(cfg.Seed),
 	}
 }
 
 func (generation *generationContext) generateScript(topic string) (string, error) {
-	llmClient := generation.pipeline.service.llm
+	defer timeStage(&generation.metrics.Script)()
+
+	cfg := generation.cfg
 	wordCount := generation.calculateWordCount()
 
+	tolerance := cfg.Content.LengthTolerance
+	if tolerance <= 0 {
+		tolerance = 0.25
+	}
+	retries := defaultLengthRetries
+	if cfg.Content.LengthRetries != nil {
+		retries = *cfg.Content.LengthRetries
+	}
+
+	script, err := generation.requestScript(topic, wordCount)
+	if err != nil {
+		return "", err
+	}
+	script, err = generation.enforceLength(topic, wordCount, tolerance, retries, script)
+	if err != nil {
+		return "", err
+	}
+	return generation.enforceTurns(topic, wordCount, retries, script)
+}
+
+func (generation *generationContext) enforceLength(topic string, wordCount int, tolerance float64, retries int, script string) (string, error) {
+	for attempt := 1; attempt <= retries; attempt++ {
+		actual := generation.countWords(script)
+		if withinTolerance(actual, wordCount, tolerance) {
+			return script, nil
+		}
+
+		slog.Warn("Script length off target, regenerating", "words", actual, "target", wordCount, "attempt", attempt)
+		var err error
+		script, err = generation.requestScript(lengthInstruction(topic, actual, wordCount), wordCount)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if actual := generation.countWords(script); !withinTolerance(actual, wordCount, tolerance) {
+		slog.Warn("Script length still off target, using last attempt", "words", actual, "target", wordCount)
+	}
+	return script, nil
+}
+
+func (generation *generationContext) enforceTurns(topic string, wordCount, retries int, script string) (string, error) {
+	if !generation.isConversation {
+		return script, nil
+	}
+	content := generation.cfg.Content
+
+	if content.MinTurns > 0 {
+		for attempt := 1; attempt <= retries; attempt++ {
+			turns := generation.parseDialogue(script).Turns()
+			if turns >= content.MinTurns {
+				break
+			}
+
+			slog.Warn("Conversation has too few turns, regenerating", "turns", turns, "min", content.MinTurns, "attempt", attempt)
+			var err error
+			script, err = generation.requestScript(turnsInstruction(topic, turns, content.MinTurns), wordCount)
+			if err != nil {
+				return "", err
+			}
+		}
+		if turns := generation.parseDialogue(script).Turns(); turns < content.MinTurns {
+			slog.Warn("Conversation still has too few turns, using last attempt", "turns", turns, "min", content.MinTurns)
+		}
+	}
+
+	if content.MaxTurns > 0 {
+		if turns := generation.parseDialogue(script).Turns(); turns > content.MaxTurns {
+			slog.Info("Truncating conversation at speaker boundary", "turns", turns, "max", content.MaxTurns)
+			script = dialogue.TruncateTurns(script, content.MaxTurns)
+		}
+	}
+	return script, nil
+}
+
+func turnsInstruction(topic string, actual, minTurns int) string {
+	return fmt.Sprintf("%s\n\nIMPORTANT: your previous conversation had only %d speaker turns. Alternate speakers for at least %d turns.", topic, actual, minTurns)
+}
+
+func (generation *generationContext) requestScript(topic string, wordCount int) (string, error) {
+	llmClient := generation.pipeline.service.llm
+	topic = styleInstruction(topic, generation.style)
+
+	var script string
+	var err error
+	if generation.isConversation {
+		script, err = llmClient.GenerateConversation(generation.ctx, topic, generation.speakerNames(), wordCount)
+	} else {
+		script, err = llmClient.GenerateScript(generation.ctx, topic, wordCount)
+	}
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(script) == "" {
+		return "", fmt.Errorf("generate script: %w", llm.ErrEmptyResponse)
+	}
+	return script, nil
+}
+
+func (generation *generationContext) countWords(script string) int {
 	if generation.isConversation {
-		names := generation.speakerNames()
-		return llmClient.GenerateConversation(generation.ctx, topic, names, wordCount)
+		if parsed := generation.parseDialogue(script); !parsed.IsEmpty() {
+			return len(strings.Fields(parsed.FullText()))
+		}
+	}
+	return len(strings.Fields(script))
+}
+
+func withinTolerance(actual, target int, tolerance float64) bool {
+	if target <= 0 {
+		return true
+	}
+	deviation := math.Abs(float64(actual-target)) / float64(target)
+	return deviation <= tolerance
+}
+
+func (generation *generationContext) styleFor(subreddit string) string {
+	if subreddit == "" {
+		return ""
+	}
+	reddit := generation.cfg.Reddit
+	for name, style := range reddit.StyleBySubreddit {
+		if strings.EqualFold(name, subreddit) {
+			return style
+		}
 	}
+	return reddit.DefaultStyle
+}
 
-	return llmClient.GenerateScript(generation.ctx, topic, wordCount)
+func styleInstruction(topic, style string) string {
+	if style == "" {
+		return topic
+	}
+	return fmt.Sprintf("%s\n\nSTYLE: %s", topic, style)
+}
+
+func lengthInstruction(topic string, actual, target int) string {
+	direction := "shorter"
+	if actual < target {
+		direction = "longer"
+	}
+	return fmt.Sprintf("%s\n\nIMPORTANT: your previous script was %d words. Make it %s: exactly %d words.", topic, actual, direction, target)
 }
 
 func (generation *generationContext) calculateWordCount() int {
-	cfg := generation.pipeline.service.cfg
+	cfg := generation.cfg
 
 	if cfg.Content.WordCount > 0 {
 		return cfg.Content.WordCount
@@ -151,13 +496,7 @@ func (generation *generationContext) calculateWordCount() int {
 		targetDuration = cfg.Video.MaxDuration * 0.85
 	}
 
-	speed := cfg.ElevenLabs.Speed
-	if speed <= 0 {
-		speed = 1.0
-	}
-
-	wordsPerMinute := speech.DefaultWordsPerMinute * speed
-	wordCount := int(targetDuration * wordsPerMinute / 60.0)
+	wordCount := int(targetDuration * wordsPerMinute(cfg) / 60.0)
 
 	if wordCount < 50 {
 		wordCount = 50
@@ -178,6 +517,8 @@ func (generation *generationContext) speakerNames() []string {
 }
 
 func (generation *generationContext) generateTitle(script, fallback string) string {
+	defer timeStage(&generation.metrics.Metadata)()
+
 	title, err := generation.pipeline.service.llm.GenerateTitle(generation.ctx, script)
 	if err != nil {
 		slog.Warn("Failed to generate title", "error", err)
@@ -186,20 +527,73 @@ func (generation *generationContext) generateTitle(script, fallback string) stri
 	return title
 }
 
+func (generation *generationContext) generateDescription(script string) string {
+	defer timeStage(&generation.metrics.Metadata)()
+
+	description, err := generation.pipeline.service.llm.GenerateDescription(generation.ctx, script)
+	if err != nil {
+		slog.Warn("Failed to generate description, using script", "error", err)
+		return llm.CleanDescription(script)
+	}
+	return description
+}
+
 func (generation *generationContext) generateTags(script string) []string {
-	cfg := generation.pipeline.service.cfg
+	defer timeStage(&generation.metrics.Metadata)()
+
+	cfg := generation.cfg
 	count := 10
 
 	tags, err := generation.pipeline.service.llm.GenerateTags(generation.ctx, script, count)
 	if err != nil {
 		slog.Warn("Failed to generate tags", "error", err)
-		return cfg.YouTube.DefaultTags
 	}
 
-	return append(tags, cfg.YouTube.DefaultTags...)
+	return mergeTags(maxTagChars, tags, cfg.YouTube.DefaultTags)
+}
+
+func mergeTags(limit int, groups ...[]string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	total := 0
+
+	for _, group := range groups {
+		for _, tag := range group {
+			tag = strings.TrimSpace(tag)
+			key := strings.ToLower(tag)
+			if tag == "" || seen[key] {
+				continue
+			}
+
+			size := tagLength(tag)
+			if len(result) > 0 {
+				size++
+			}
+			if total+size > limit {
+				slog.Debug("Dropping tag over length limit", "tag", tag)
+				continue
+			}
+
+			seen[key] = true
+			total += size
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+func tagLength(tag string) int {
+	length := utf8.RuneCountInString(tag)
+	if strings.Contains(tag, " ") {
+		length += 2
+	}
+	return length
 }
 
 func (generation *generationContext) generateAudio(script string) (*audioResult, error) {
+	defer timeStage(&generation.metrics.Audio)()
+
 	if !generation.isConversation {
 		return generation.generateSingleAudio(script)
 	}
@@ -211,16 +605,29 @@ func (generation *generationContext) generateSingleAudio(script string) (*audioR
 	if err != nil {
 		return nil, fmt.Errorf("generate speech: %w", err)
 	}
+	segment := generation.trimSilence(video.AudioSegment{Audio: result.Audio, Timings: result.Timings})
 	return &audioResult{
-		data:     result.Audio,
-		timings:  result.Timings,
-		duration: speech.Duration(result.Timings),
+		data:     segment.Audio,
+		timings:  segment.Timings,
+		duration: speech.Duration(segment.Timings),
 		script:   script,
 	}, nil
 }
 
+func (generation *generationContext) trimSilence(segment video.AudioSegment) video.AudioSegment {
+	if !generation.cfg.Audio.TrimSilence {
+		return segment
+	}
+	trimmed, err := video.NewAudioStitcher(generation.cfg.Video.OutputDir).TrimSilence(generation.ctx, segment)
+	if err != nil {
+		slog.Warn("Failed to trim silence, using untrimmed audio", "error", err)
+		return segment
+	}
+	return trimmed
+}
+
 func (generation *generationContext) generateConversationAudio(script string) (*audioResult, error) {
-	parsed := dialogue.Parse(script)
+	parsed := generation.parseDialogue(script)
 	if parsed.IsEmpty() {
 		return generation.generateSingleAudio(script)
 	}
@@ -230,7 +637,7 @@ func (generation *generationContext) generateConversationAudio(script string) (*
 		return nil, err
 	}
 
-	stitched, err := video.NewAudioStitcher(generation.pipeline.service.cfg.Video.OutputDir).Stitch(generation.ctx, segments)
+	stitched, err := video.NewAudioStitcher(generation.cfg.Video.OutputDir).Stitch(generation.ctx, segments)
 	if err != nil {
 		return nil, fmt.Errorf("stitch audio: %w", err)
 	}
@@ -243,6 +650,37 @@ func (generation *generationContext) generateConversationAudio(script string) (*
 	}, nil
 }
 
+func (generation *generationContext) parseDialogue(script string) *dialogue.Script {
+	return dialogue.ParseWithOptions(script, dialogue.ParseOptions{
+		KeepDirections: generation.cfg.Content.KeepStageDirections,
+	})
+}
+
+func (generation *generationContext) resolveVoice(speaker string) (speech.VoiceConfig, bool) {
+	if voice, ok := generation.voiceMap[speaker]; ok {
+		return voice, true
+	}
+
+	key := dialogue.NormalizeSpeaker(speaker)
+	for alias, name := range generation.cfg.ElevenLabs.SpeakerAliases {
+		if dialogue.NormalizeSpeaker(alias) != key {
+			continue
+		}
+		if voice, ok := generation.voiceMap[name]; ok {
+			slog.Info("Resolved speaker via alias", "speaker", speaker, "voice", voice.Name)
+			return voice, true
+		}
+	}
+
+	for name, voice := range generation.voiceMap {
+		if dialogue.NormalizeSpeaker(name) == key {
+			slog.Info("Resolved speaker via normalization", "speaker", speaker, "voice", voice.Name)
+			return voice, true
+		}
+	}
+	return speech.VoiceConfig{}, false
+}
+
 func (generation *generationContext) generateSpeechSegments(parsed *dialogue.Script) ([]video.AudioSegment, error) {
 	segments := make([]video.AudioSegment, len(parsed.Lines))
 	defaultVoice := generation.voices[0]
@@ -255,10 +693,12 @@ func (generation *generationContext) generateSpeechSegments(parsed *dialogue.Scr
 
 	jobs := make([]lineJob, len(parsed.Lines))
 	for i, line := range parsed.Lines {
-		voice, ok := generation.voiceMap[line.Speaker]
+		voice, ok := generation.resolveVoice(line.Speaker)
 		if !ok {
 			slog.Warn("unknown speaker, using default", "speaker", line.Speaker)
 			voice = defaultVoice
+		} else {
+			line.Speaker = voice.Name
 		}
 		jobs[i] = lineJob{index: i, line: line, voice: voice}
 	}
@@ -271,7 +711,7 @@ func (generation *generationContext) generateSpeechSegments(parsed *dialogue.Scr
 
 	results := make(chan result, len(jobs))
 
-	parallelism := generation.pipeline.service.cfg.ElevenLabs.TTSParallelism
+	parallelism := generation.cfg.ElevenLabs.TTSParallelism
 	if parallelism <= 0 {
 		parallelism = 2
 	}
@@ -291,11 +731,11 @@ func (generation *generationContext) generateSpeechSegments(parsed *dialogue.Scr
 
 			results <- result{
 				index: j.index,
-				segment: video.AudioSegment{
+				segment: generation.trimSilence(video.AudioSegment{
 					Audio:   speechResult.Audio,
 					Timings: speechResult.Timings,
 					Speaker: j.line.Speaker,
-				},
+				}),
 			}
 		}(job)
 	}
@@ -312,17 +752,22 @@ func (generation *generationContext) generateSpeechSegments(parsed *dialogue.Scr
 }
 
 func (generation *generationContext) fetchImages(script string, timings []speech.WordTiming) []video.ImageOverlay {
+	defer timeStage(&generation.metrics.Images)()
+
 	fetcher := generation.pipeline.service.fetcher
 	if fetcher == nil {
-		slog.Warn("Image fetcher not configured (missing GOOGLE_SEARCH_API_KEY or GOOGLE_SEARCH_ENGINE_ID)")
+		slog.Warn("Image fetcher not configured (missing GOOGLE_SEARCH_API_KEY or GOOGLE_SEARCH_ENGINE_ID, or set visuals.search_provider)")
 		return nil
 	}
 
-	cfg := generation.pipeline.service.cfg
+	cfg := generation.cfg
 	count := cfg.Visuals.Count
 	if count <= 0 {
 		count = 5
 	}
+	if cfg.Visuals.MaxCount > 0 && count > cfg.Visuals.MaxCount {
+		count = cfg.Visuals.MaxCount
+	}
 
 	slog.Info("Generating visual cues from script...", "count", count)
 	cues, err := generation.pipeline.service.llm.GenerateVisuals(generation.ctx, script, count)
@@ -340,10 +785,12 @@ func (generation *generationContext) fetchImages(script string, timings []speech
 	})
 }
 
-func (generation *generationContext) assemble(audio *audioResult, images []video.ImageOverlay) (*video.AssembleResult, error) {
-	cfg := generation.pipeline.service.cfg
+func (generation *generationContext) assemble(audio *audioResult, images []video.ImageOverlay, mood string) (*video.AssembleResult, error) {
+	defer timeStage(&generation.metrics.Assembly)()
+
+	cfg := generation.cfg
 	if cfg.Video.MaxDuration > 0 && audio.duration > cfg.Video.MaxDuration {
-		return nil, fmt.Errorf("audio duration %.1fs exceeds limit of %.0fs", audio.duration, cfg.Video.MaxDuration)
+		return nil, fmt.Errorf("%w: audio is %.1fs, limit is %.0fs", ErrDurationExceeded, audio.duration, cfg.Video.MaxDuration)
 	}
 
 	speakerColors := speech.BuildSpeakerColors(generation.voiceMap)
@@ -356,11 +803,106 @@ func (generation *generationContext) assemble(audio *audioResult, images []video
 		WordTimings:   audio.timings,
 		ImageOverlays: images,
 		SpeakerColors: speakerColors,
+		MusicMood:     mood,
+		HookText:      generation.hookText(audio.script),
+		Chapters:      generation.chapters(audio.timings),
+		Rand:          generation.rng,
 	})
 }
 
-func (pipeline *Pipeline) voices() []speech.VoiceConfig {
-	cfg := pipeline.service.cfg
+func (generation *generationContext) chapters(timings []speech.WordTiming) []video.Chapter {
+	if !generation.cfg.Content.Chapters {
+		return nil
+	}
+	return buildChapters(timings)
+}
+
+func (generation *generationContext) hookText(script string) string {
+	content := generation.cfg.Content
+	if content.HookText != "" {
+		return content.HookText
+	}
+	if !content.HookFromScript {
+		return ""
+	}
+	return firstSentence(script)
+}
+
+func firstSentence(script string) string {
+	text := strings.TrimSpace(script)
+	if parsed := dialogue.Parse(text); !parsed.IsEmpty() {
+		text = parsed.FullText()
+	}
+
+	if i := strings.IndexAny(text, ".!?"); i >= 0 {
+		text = text[:i+1]
+	}
+
+	words := strings.Fields(text)
+	if len(words) > maxHookWords {
+		words = append(words[:maxHookWords], "...")
+	}
+	return strings.Join(words, " ")
+}
+
+func (generation *generationContext) musicMood(topic string) string {
+	music := generation.cfg.Music
+	words := normalizeWords(topic)
+
+	keywords := slices.Sorted(maps.Keys(music.Moods))
+	for _, keyword := range keywords {
+		sequence := normalizeWords(keyword)
+		if len(sequence) > 0 && containsSequence(words, sequence) {
+			return music.Moods[keyword]
+		}
+	}
+	return music.Mood
+}
+
+func (generation *generationContext) createThumbnail(result *video.AssembleResult, title string) string {
+	defer timeStage(&generation.metrics.Thumbnail)()
+
+	at := generation.cfg.Video.ThumbnailAt
+	if at <= 0 {
+		at = 1.0
+	}
+	at = min(at, result.Duration)
+
+	slog.Info("Creating thumbnail...", "at", at)
+	path, err := generation.pipeline.service.assembler.GenerateThumbnailWithTitle(generation.ctx, result.OutputPath, at, title)
+	if err != nil {
+		slog.Warn("Failed to create thumbnail", "error", err)
+		return ""
+	}
+	return path
+}
+
+func (pipeline *Pipeline) CreatePreview(ctx context.Context, result *GenerateResult) string {
+	cfg := pipeline.service.config()
+	if cfg.Telegram.DisablePreview {
+		return ""
+	}
+
+	previewDuration := cfg.Telegram.PreviewDuration
+	if previewDuration <= 0 {
+		previewDuration = 30
+	}
+	if result.Duration <= previewDuration {
+		return ""
+	}
+
+	slog.Info("Creating preview...", "duration", previewDuration)
+	start := time.Now()
+	path, err := pipeline.service.assembler.CreatePreview(ctx, result.VideoPath, previewDuration)
+	if err != nil {
+		slog.Warn("Failed to create preview, reviewers will get the full video", "error", err)
+		return ""
+	}
+	slog.Info("Preview ready", "path", path, "elapsed", time.Since(start).Round(time.Millisecond))
+	return path
+}
+
+func voicesFor(cfg *config.Config) []speech.VoiceConfig {
 	var result []speech.VoiceConfig
 
 	if cfg.ElevenLabs.HostVoice.ID != "" {
@@ -374,68 +916,285 @@ func (pipeline *Pipeline) voices() []speech.VoiceConfig {
 	return result
 }
 
-func (pipeline *Pipeline) GenerateFromReddit(ctx context.Context) (*GenerateResult, error) {
-	topic, err := pipeline.fetchRedditTopic(ctx)
+func (pipeline *Pipeline) GenerateFromSource(ctx context.Context) (*GenerateResult, error) {
+	return pipeline.generateFromSource(ctx, false)
+}
+
+func (pipeline *Pipeline) GenerateAudioFromSource(ctx context.Context) (*GenerateResult, error) {
+	return pipeline.generateFromSource(ctx, true)
+}
+
+func (pipeline *Pipeline) generateFromSource(ctx context.Context, audioOnly bool) (*GenerateResult, error) {
+	cfg := pipeline.service.config()
+	if err := checkDiskSpace(cfg); err != nil {
+		return nil, err
+	}
+	source, err := pipeline.topicSource(cfg, newRand(cfg.Seed))
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.generateWithRetries(ctx, source, cfg.Reddit.MaxAttempts, audioOnly)
+}
+
+func (pipeline *Pipeline) generateWithRetries(ctx context.Context, source TopicSource, maxAttempts int, audioOnly bool) (*GenerateResult, error) {
+	maxAttempts = max(maxAttempts, 1)
+	tried := make(map[string]bool)
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		topic, subreddit, err := nextUntriedTopic(ctx, source, tried)
+		if err != nil {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w (previous topic failed: %v)", err, lastErr)
+			}
+			return nil, err
+		}
+		tried[topic] = true
+
+		generation := pipeline.newGenerationContext(ctx)
+		generation.audioOnly = audioOnly
+		result, err := generation.run(&sessionState{Topic: topic, Subreddit: subreddit, Source: sourceName(source)})
+		if err == nil || !isContentError(err) {
+			return result, err
+		}
+
+		lastErr = err
+		if attempt < maxAttempts {
+			slog.Warn("Topic failed, trying another", "topic", topic, "attempt", attempt, "max_attempts", maxAttempts, "error", err)
+		}
+	}
+	if maxAttempts == 1 {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("no usable topic after %d attempts: %w", maxAttempts, lastErr)
+}
+
+func (pipeline *Pipeline) CleanupSession(videoPath string, keepVideo bool) error {
+	return CleanupSession(pipeline.service.config(), videoPath, keepVideo)
+}
+
+func (pipeline *Pipeline) Upload(ctx context.Context, request UploadRequest) (*distribution.UploadResponse, error) {
+	uploaders, err := pipeline.uploadersFor(request.Account)
+	if err != nil {
+		return nil, err
+	}
+	if len(uploaders) == 0 {
+		return nil, fmt.Errorf("uploader not configured (missing YouTube credentials)")
+	}
+
+	uploadReq, err := pipeline.buildUploadRequest(ctx, request)
 	if err != nil {
 		return nil, err
 	}
-	return pipeline.Generate(ctx, topic)
+
+	response, err := pipeline.uploadTo(ctx, uploaders[0], uploadReq)
+	if err != nil {
+		return nil, fmt.Errorf("upload video: %w", err)
+	}
+	return response, nil
 }
 
-func (pipeline *Pipeline) fetchRedditTopic(ctx context.Context) (string, error) {
-	cfg := pipeline.service.cfg
-	redditCfg := cfg.Reddit
+func (pipeline *Pipeline) uploadTo(ctx context.Context, uploader distribution.Uploader, req distribution.UploadRequest) (*distribution.UploadResponse, error) {
+	pauses := pipeline.service.pauses
+	if err := pauses.check(uploader.Platform(), time.Now()); err != nil {
+		return nil, err
+	}
 
-	subreddits := redditCfg.Subreddits
-	if len(subreddits) == 0 {
-		subreddits = []string{"cscareerquestions", "learnprogramming"}
+	var response *distribution.UploadResponse
+	err := withStageTimeout(ctx, "upload", pipeline.service.config().Timeouts.Upload, func(ctx context.Context) error {
+		var err error
+		response, err = uploader.Upload(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, pauses.record(uploader.Platform(), err, time.Now())
 	}
+	return response, nil
+}
 
-	subreddit := subreddits[randomInt(len(subreddits))]
-	sort := redditCfg.Sort
-	if sort == "" {
-		sort = "hot"
+func (pipeline *Pipeline) HasAccount(name string) bool {
+	_, ok := pipeline.service.accounts[name]
+	return ok
+}
+
+func (pipeline *Pipeline) uploadersFor(account string) ([]distribution.Uploader, error) {
+	if account == "" {
+		return pipeline.service.uploaders, nil
 	}
-	postLimit := redditCfg.PostLimit
-	if postLimit <= 0 {
-		postLimit = 10
+	selected, ok := pipeline.service.accounts[account]
+	if !ok {
+		return nil, fmt.Errorf("youtube account %q is not configured", account)
 	}
 
-	slog.Info("Fetching Reddit posts", "subreddit", subreddit, "sort", sort)
-	posts, err := pipeline.service.reddit.GetSubredditPosts(ctx, subreddit, sort, postLimit)
+	uploaders := []distribution.Uploader{selected}
+	for _, uploader := range pipeline.service.uploaders {
+		if uploader.Platform() != selected.Platform() {
+			uploaders = append(uploaders, uploader)
+		}
+	}
+	return uploaders, nil
+}
+
+func (pipeline *Pipeline) UploadsPausedUntil() (time.Time, bool) {
+	return pipeline.service.pauses.latest(time.Now())
+}
+
+func (pipeline *Pipeline) UploadAll(ctx context.Context, request UploadRequest) (map[string]UploadResult, error) {
+	uploaders, err := pipeline.uploadersFor(request.Account)
 	if err != nil {
-		return "", fmt.Errorf("fetch reddit posts: %w", err)
+		return nil, err
 	}
-	if len(posts) == 0 {
-		return "", fmt.Errorf("no posts found in subreddit: %s", subreddit)
+	if len(uploaders) == 0 {
+		return nil, fmt.Errorf("no uploaders configured")
 	}
 
-	post := posts[randomInt(len(posts))]
-	slog.Info("Selected post", "title", post.Title)
+	uploadReq, err := pipeline.buildUploadRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	type platformResult struct {
+		platform string
+		result   UploadResult
+	}
 
-	return post.Title, nil
+	results := make(chan platformResult, len(uploaders))
+	for _, uploader := range uploaders {
+		go func(u distribution.Uploader) {
+			response, err := pipeline.uploadTo(ctx, u, uploadReq)
+			if err != nil {
+				err = fmt.Errorf("upload to %s: %w", u.Platform(), err)
+			}
+			results <- platformResult{
+				platform: u.Platform(),
+				result:   UploadResult{Response: response, Err: err},
+			}
+		}(uploader)
+	}
+
+	byPlatform := make(map[string]UploadResult, len(uploaders))
+	for range uploaders {
+		r := <-results
+		byPlatform[r.platform] = r.result
+	}
+
+	return byPlatform, nil
 }
 
-func (pipeline *Pipeline) Upload(ctx context.Context, request UploadRequest) (*distribution.UploadResponse, error) {
-	if pipeline.service.uploader == nil {
-		return nil, fmt.Errorf("uploader not configured (missing YouTube credentials)")
+func (pipeline *Pipeline) buildUploadRequest(ctx context.Context, request UploadRequest) (distribution.UploadRequest, error) {
+	cfg := pipeline.service.config()
+
+	videoPath, err := pipeline.ensureUploadSize(ctx, cfg.Upload, request.VideoPath)
+	if err != nil {
+		return distribution.UploadRequest{}, err
 	}
 
-	cfg := pipeline.service.cfg
 	tags := request.Tags
 	if len(tags) == 0 {
 		tags = cfg.YouTube.DefaultTags
 	}
 
-	response, err := pipeline.service.uploader.Upload(ctx, distribution.UploadRequest{
-		FilePath:    request.VideoPath,
+	privacy := request.Privacy
+	if privacy == "" {
+		privacy = sourcePrivacy(cfg.YouTube, request.Source)
+	}
+	if privacy != "" {
+		if err := config.ValidatePrivacy(privacy); err != nil {
+			return distribution.UploadRequest{}, err
+		}
+	}
+
+	publishAt, err := schedulePublish(request.PublishAt, cfg.YouTube, privacy, time.Now())
+	if err != nil {
+		return distribution.UploadRequest{}, err
+	}
+
+	return distribution.UploadRequest{
+		FilePath:    videoPath,
 		Title:       request.Title,
-		Description: request.Description,
+		Description: fitDescription(request.Description),
 		Tags:        tags,
-		Privacy:     cfg.YouTube.PrivacyStatus,
-	})
+		Privacy:     privacy,
+		Thumbnail:   request.Thumbnail,
+		PlaylistID:  cfg.YouTube.PlaylistID,
+		PublishAt:   publishAt,
+	}, nil
+}
+
+func sourcePrivacy(cfg config.YouTubeConfig, source string) string {
+	if privacy := cfg.PrivacyBySource[source]; privacy != "" {
+		return privacy
+	}
+	if privacy := defaultSourcePrivacy[source]; privacy != "" {
+		return privacy
+	}
+	return cfg.PrivacyStatus
+}
+
+func (pipeline *Pipeline) ensureUploadSize(ctx context.Context, cfg config.UploadConfig, videoPath string) (string, error) {
+	if cfg.MaxFileSizeMB <= 0 {
+		return videoPath, nil
+	}
+	limit := int64(cfg.MaxFileSizeMB * 1024 * 1024)
+
+	size, err := fileSize(videoPath)
 	if err != nil {
-		return nil, fmt.Errorf("upload video: %w", err)
+		return "", err
 	}
-	return response, nil
+	if size <= limit {
+		return videoPath, nil
+	}
+	if !cfg.Reencode || pipeline.service.assembler == nil {
+		return "", fileTooLarge(videoPath, size, cfg.MaxFileSizeMB)
+	}
+
+	slog.Warn("Video exceeds upload size limit, re-encoding", "path", videoPath, "size_mb", bytesToMB(size), "limit_mb", cfg.MaxFileSizeMB)
+	reencoded, err := pipeline.service.assembler.Reencode(ctx, videoPath, limit)
+	if err != nil {
+		return "", fmt.Errorf("%w (re-encode failed: %v)", fileTooLarge(videoPath, size, cfg.MaxFileSizeMB), err)
+	}
+
+	size, err = fileSize(reencoded)
+	if err != nil {
+		return "", err
+	}
+	if size > limit {
+		return "", fileTooLarge(reencoded, size, cfg.MaxFileSizeMB)
+	}
+	return reencoded, nil
+}
+
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("stat video: %w", err)
+	}
+	return info.Size(), nil
+}
+
+func fileTooLarge(path string, size int64, limitMB float64) error {
+	return fmt.Errorf("%w: %s is %.1f MB, limit is %g MB", ErrFileTooLarge, path, bytesToMB(size), limitMB)
+}
+
+func bytesToMB(size int64) float64 {
+	return float64(size) / (1024 * 1024)
+}
+
+func schedulePublish(publishAt time.Time, yt config.YouTubeConfig, privacy string, now time.Time) (time.Time, error) {
+	if publishAt.IsZero() {
+		publishAt = yt.PublishAtTime()
+	}
+	if delay := yt.PublishDelayDuration(); publishAt.IsZero() && delay > 0 {
+		publishAt = now.Add(delay)
+	}
+	if publishAt.IsZero() {
+		return time.Time{}, nil
+	}
+	if !publishAt.After(now) {
+		return time.Time{}, fmt.Errorf("publish time %s is not in the future", publishAt.Format(time.RFC3339))
+	}
+	if privacy == "public" {
+		return time.Time{}, fmt.Errorf("scheduled publishing cannot be combined with public privacy status")
+	}
+	return publishAt, nil
 }
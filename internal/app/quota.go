@@ -0,0 +1,103 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"craftstory/internal/distribution"
+)
+
+const quotaResetZone = "America/Los_Angeles"
+
+type uploadPauses struct {
+	mu       sync.Mutex
+	dataFile string
+	until    map[string]time.Time
+}
+
+func newUploadPauses(dataDir string) *uploadPauses {
+	pauses := &uploadPauses{
+		dataFile: filepath.Join(dataDir, "upload_pauses.json"),
+		until:    make(map[string]time.Time),
+	}
+	pauses.load()
+	return pauses
+}
+
+func (p *uploadPauses) active(platform string, now time.Time) (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	until, ok := p.until[platform]
+	return until, ok && now.Before(until)
+}
+
+func (p *uploadPauses) latest(now time.Time) (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var latest time.Time
+	for _, until := range p.until {
+		if until.After(latest) {
+			latest = until
+		}
+	}
+	return latest, now.Before(latest)
+}
+
+func (p *uploadPauses) pause(platform string, until time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.until[platform] = until
+	p.save()
+}
+
+func (p *uploadPauses) load() {
+	data, err := os.ReadFile(p.dataFile)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, &p.until)
+}
+
+func (p *uploadPauses) save() {
+	data, err := json.MarshalIndent(p.until, "", "  ")
+	if err != nil {
+		return
+	}
+
+	_ = os.MkdirAll(filepath.Dir(p.dataFile), 0755)
+	if err := os.WriteFile(p.dataFile, data, 0644); err != nil {
+		slog.Warn("Failed to save upload pause state", "path", p.dataFile, "error", err)
+	}
+}
+
+func (p *uploadPauses) check(platform string, now time.Time) error {
+	if until, ok := p.active(platform, now); ok {
+		return fmt.Errorf("%w: %s until %s", ErrUploadsPaused, platform, until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (p *uploadPauses) record(platform string, err error, now time.Time) error {
+	if !errors.Is(err, distribution.ErrQuotaExceeded) {
+		return err
+	}
+	until := nextQuotaReset(now)
+	p.pause(platform, until)
+	slog.Warn("Upload quota exhausted, pausing uploads", "platform", platform, "until", until)
+	return fmt.Errorf("%w: %w", ErrUploadsPaused, err)
+}
+
+func nextQuotaReset(now time.Time) time.Time {
+	loc, err := time.LoadLocation(quotaResetZone)
+	if err != nil {
+		loc = time.FixedZone("PST", -8*60*60)
+	}
+	local := now.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+}
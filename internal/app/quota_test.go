@@ -0,0 +1,101 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"craftstory/internal/distribution"
+	"craftstory/pkg/config"
+)
+
+func TestPipelineUploadQuotaExceeded(t *testing.T) {
+	dir := t.TempDir()
+	youtube := &mockUploader{
+		platform: "youtube",
+		err:      fmt.Errorf("upload session failed (403): %w", distribution.ErrQuotaExceeded),
+	}
+	cfg := &config.Config{Video: config.VideoConfig{OutputDir: dir}}
+	pipeline := NewPipeline(NewService(ServiceOptions{Config: cfg, Uploaders: []distribution.Uploader{youtube}}))
+	req := UploadRequest{VideoPath: "/path/to/video.mp4", Title: "Test"}
+
+	_, err := pipeline.Upload(t.Context(), req)
+	if !errors.Is(err, ErrUploadsPaused) || !errors.Is(err, distribution.ErrQuotaExceeded) {
+		t.Fatalf("Upload() error = %v, want ErrUploadsPaused wrapping ErrQuotaExceeded", err)
+	}
+
+	until, paused := pipeline.UploadsPausedUntil()
+	if !paused || !until.After(time.Now()) {
+		t.Fatalf("UploadsPausedUntil() = %v, %v, want future pause", until, paused)
+	}
+
+	if _, err := pipeline.Upload(t.Context(), req); !errors.Is(err, ErrUploadsPaused) {
+		t.Errorf("Upload() while paused error = %v, want ErrUploadsPaused", err)
+	}
+	if len(youtube.requests) != 1 {
+		t.Errorf("uploader called %d times, want 1", len(youtube.requests))
+	}
+
+	reloaded := newUploadPauses(dir)
+	if got, ok := reloaded.active("youtube", time.Now()); !ok || !got.Equal(until) {
+		t.Errorf("persisted pause = %v, %v, want %v", got, ok, until)
+	}
+}
+
+func TestPipelineUploadAllQuotaPausesOnlyPlatform(t *testing.T) {
+	youtube := &mockUploader{platform: "youtube", err: distribution.ErrQuotaExceeded}
+	tiktok := &mockUploader{platform: "tiktok", response: &distribution.UploadResponse{ID: "tt1"}}
+	cfg := &config.Config{Video: config.VideoConfig{OutputDir: t.TempDir()}}
+	pipeline := NewPipeline(NewService(ServiceOptions{Config: cfg, Uploaders: []distribution.Uploader{youtube, tiktok}}))
+	req := UploadRequest{VideoPath: "/path/to/video.mp4", Title: "Test"}
+
+	for range 2 {
+		results, err := pipeline.UploadAll(t.Context(), req)
+		if err != nil {
+			t.Fatalf("UploadAll() error = %v", err)
+		}
+		if !errors.Is(results["youtube"].Err, ErrUploadsPaused) {
+			t.Errorf("youtube error = %v, want ErrUploadsPaused", results["youtube"].Err)
+		}
+		if results["tiktok"].Err != nil {
+			t.Errorf("tiktok error = %v, want nil", results["tiktok"].Err)
+		}
+	}
+	if len(youtube.requests) != 1 || len(tiktok.requests) != 2 {
+		t.Errorf("uploads = youtube %d, tiktok %d, want 1 and 2", len(youtube.requests), len(tiktok.requests))
+	}
+}
+
+func TestNextQuotaReset(t *testing.T) {
+	pacific := time.FixedZone("PDT", -7*60*60)
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "morning",
+			now:  time.Date(2026, 7, 1, 9, 0, 0, 0, pacific),
+			want: time.Date(2026, 7, 2, 0, 0, 0, 0, pacific),
+		},
+		{
+			name: "justBeforeMidnight",
+			now:  time.Date(2026, 7, 1, 23, 59, 0, 0, pacific),
+			want: time.Date(2026, 7, 2, 0, 0, 0, 0, pacific),
+		},
+		{
+			name: "utcInput",
+			now:  time.Date(2026, 7, 2, 3, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 7, 2, 0, 0, 0, 0, pacific),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextQuotaReset(tt.now); !got.Equal(tt.want) {
+				t.Errorf("nextQuotaReset(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"craftstory/internal/search"
+)
+
+const onUnsafeRegenerate = "regenerate"
+
+func (generation *generationContext) ensureSafeScript(topic, script string) (string, error) {
+	cfg := generation.cfg
+	term := findBlockedTerm(script, cfg.Content.Blocklist)
+	if term == "" {
+		return script, nil
+	}
+
+	if cfg.Content.OnUnsafe != onUnsafeRegenerate {
+		return "", fmt.Errorf("%w: script contains %q", ErrBlockedContent, term)
+	}
+
+	slog.Warn("Script contains blocked term, regenerating", "term", term)
+	script, err := generation.generateScript(topic)
+	if err != nil {
+		return "", err
+	}
+
+	if term := findBlockedTerm(script, cfg.Content.Blocklist); term != "" {
+		return "", fmt.Errorf("%w: regenerated script still contains %q", ErrBlockedContent, term)
+	}
+	return script, nil
+}
+
+func findBlockedTerm(script string, blocklist []string) string {
+	words := normalizeWords(script)
+
+	for _, term := range blocklist {
+		termWords := normalizeWords(term)
+		if len(termWords) == 0 {
+			continue
+		}
+		if containsSequence(words, termWords) {
+			return term
+		}
+	}
+	return ""
+}
+
+func normalizeWords(text string) []string {
+	fields := strings.Fields(text)
+	words := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if word := search.CleanWord(field); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func containsSequence(words, sequence []string) bool {
+	for i := 0; i+len(sequence) <= len(words); i++ {
+		match := true
+		for j := range sequence {
+			if words[i+j] != sequence[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,12 @@
 package app
 
 import (
+	"context"
+	"reflect"
+	"slices"
+	"sync"
+
+	"craftstory/internal/content/hackernews"
 	"craftstory/internal/content/reddit"
 	"craftstory/internal/distribution"
 	"craftstory/internal/distribution/telegram"
@@ -9,47 +15,143 @@ import (
 	"craftstory/internal/speech"
 	"craftstory/internal/storage"
 	"craftstory/internal/video"
+	"craftstory/internal/webhook"
 	"craftstory/pkg/config"
 )
 
+type VideoAssembler interface {
+	Assemble(ctx context.Context, req video.AssembleRequest) (*video.AssembleResult, error)
+	CreatePreview(ctx context.Context, videoPath string, duration float64) (string, error)
+	GenerateThumbnailWithTitle(ctx context.Context, videoPath string, atSeconds float64, title string) (string, error)
+	Reencode(ctx context.Context, videoPath string, maxBytes int64) (string, error)
+}
+
 type Service struct {
-	cfg       *config.Config
-	llm       llm.Client
-	tts       speech.Provider
-	uploader  distribution.Uploader
-	assembler *video.Assembler
-	storage   *storage.LocalStorage
-	reddit    *reddit.Client
-	fetcher   *search.Fetcher
-	approval  *telegram.ApprovalService
+	mu         sync.RWMutex
+	cfg        *config.Config
+	llm        llm.Client
+	tts        speech.Provider
+	uploaders  []distribution.Uploader
+	accounts   map[string]distribution.Uploader
+	assembler  VideoAssembler
+	storage    *storage.LocalStorage
+	reddit     *reddit.Client
+	hackerNews *hackernews.Client
+	fetcher    *search.Fetcher
+	approval   *telegram.ApprovalService
+	webhook    *webhook.Client
+	pauses     *uploadPauses
 }
 
 type ServiceOptions struct {
 	Config    *config.Config
 	LLM       llm.Client
 	TTS       speech.Provider
-	Uploader  distribution.Uploader
-	Assembler *video.Assembler
-	Storage   *storage.LocalStorage
-	Reddit    *reddit.Client
-	Fetcher   *search.Fetcher
-	Approval  *telegram.ApprovalService
+	Uploaders []distribution.Uploader
+	// Deprecated: use Uploaders.
+	Uploader   distribution.Uploader
+	Accounts   map[string]distribution.Uploader
+	Assembler  VideoAssembler
+	Storage    *storage.LocalStorage
+	Reddit     *reddit.Client
+	HackerNews *hackernews.Client
+	Fetcher    *search.Fetcher
+	Approval   *telegram.ApprovalService
+	Webhook    *webhook.Client
 }
 
 func NewService(opts ServiceOptions) *Service {
+	var dataDir string
+	if opts.Config != nil {
+		dataDir = opts.Config.Video.OutputDir
+	}
+
+	uploaders := opts.Uploaders
+	if opts.Uploader != nil {
+		uploaders = append(slices.Clone(uploaders), opts.Uploader)
+	}
+
 	return &Service{
-		cfg:       opts.Config,
-		llm:       opts.LLM,
-		tts:       opts.TTS,
-		uploader:  opts.Uploader,
-		assembler: opts.Assembler,
-		storage:   opts.Storage,
-		reddit:    opts.Reddit,
-		fetcher:   opts.Fetcher,
-		approval:  opts.Approval,
+		cfg:        opts.Config,
+		llm:        opts.LLM,
+		tts:        opts.TTS,
+		uploaders:  uploaders,
+		accounts:   opts.Accounts,
+		assembler:  opts.Assembler,
+		storage:    opts.Storage,
+		reddit:     opts.Reddit,
+		hackerNews: opts.HackerNews,
+		fetcher:    opts.Fetcher,
+		approval:   opts.Approval,
+		webhook:    opts.Webhook,
+		pauses:     newUploadPauses(dataDir),
 	}
 }
 
+func (s *Service) config() *config.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+var restartSections = []struct {
+	name    string
+	section func(cfg *config.Config) any
+}{
+	{"credentials", func(cfg *config.Config) any {
+		return []any{cfg.GroqAPIKey, cfg.YouTubeClientID, cfg.YouTubeClientSecret, cfg.YouTubeTokenPath, cfg.GoogleSearchAPIKey,
+			cfg.GoogleSearchEngineID, cfg.TelegramBotToken, cfg.ElevenLabsAPIKey, cfg.ElevenLabsAPIKeys, cfg.TenorAPIKey,
+			cfg.TikTokAccessToken, cfg.WebhookSecret}
+	}},
+	{"groq", func(cfg *config.Config) any { return cfg.Groq }},
+	{"elevenlabs", func(cfg *config.Config) any { return cfg.ElevenLabs }},
+	{"video", func(cfg *config.Config) any { return cfg.Video }},
+	{"music", func(cfg *config.Config) any { return cfg.Music }},
+	{"subtitles", func(cfg *config.Config) any { return cfg.Subtitles }},
+	{"audio", func(cfg *config.Config) any {
+		return []any{cfg.Audio.Normalize, cfg.Audio.TargetLUFS, cfg.Audio.TruePeak}
+	}},
+	{"content.language", func(cfg *config.Config) any { return cfg.Content.Language }},
+	{"content.hook_duration", func(cfg *config.Config) any { return cfg.Content.HookDuration }},
+	{"reddit.credentials", func(cfg *config.Config) any {
+		return []any{cfg.Reddit.ClientID, cfg.Reddit.ClientSecret, cfg.Reddit.Username, cfg.Reddit.Password}
+	}},
+	{"visuals.search", func(cfg *config.Config) any {
+		v := cfg.Visuals
+		v.Count = 0
+		return v
+	}},
+	{"youtube.accounts", func(cfg *config.Config) any { return []any{cfg.YouTube.Account, cfg.YouTube.Accounts} }},
+	{"youtube.upload_retries", func(cfg *config.Config) any { return cfg.YouTube.UploadRetries }},
+	{"tiktok", func(cfg *config.Config) any { return cfg.TikTok }},
+	{"telegram", func(cfg *config.Config) any { return cfg.Telegram }},
+	{"http", func(cfg *config.Config) any { return cfg.HTTP }},
+	{"webhook_url", func(cfg *config.Config) any { return cfg.WebhookURL }},
+	{"metrics", func(cfg *config.Config) any { return cfg.Metrics }},
+	{"prompts_dir", func(cfg *config.Config) any { return cfg.PromptsDir }},
+	{"max_concurrent_generations", func(cfg *config.Config) any { return cfg.MaxConcurrentGenerations }},
+}
+
+func (s *Service) Reload(cfg *config.Config) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var restart []string
+	if s.cfg != nil {
+		for _, rs := range restartSections {
+			if !reflect.DeepEqual(rs.section(s.cfg), rs.section(cfg)) {
+				restart = append(restart, rs.name)
+			}
+		}
+	}
+	s.cfg = cfg
+	return restart
+}
+
 func (s *Service) Approval() *telegram.ApprovalService {
 	return s.approval
 }
+
+func (s *Service) Webhook() *webhook.Client {
+	return s.webhook
+}
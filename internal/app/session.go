@@ -1,45 +1,297 @@
 package app
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"text/template"
 	"time"
+
+	"craftstory/internal/speech"
+	"craftstory/internal/video"
+	"craftstory/pkg/config"
+)
+
+const (
+	maxSlugLength    = 50
+	defaultVideoName = "video.mp4"
 )
 
 type session struct {
-	id      string
-	dir     string
-	baseDir string
+	id               string
+	dir              string
+	baseDir          string
+	filenameTemplate string
+	videoName        string
+}
+
+type filenameData struct {
+	Title string
+	Topic string
+	Date  string
+	ID    string
+}
+
+type sessionState struct {
+	Topic       string              `json:"topic"`
+	Subreddit   string              `json:"subreddit,omitempty"`
+	Source      string              `json:"source,omitempty"`
+	Title       string              `json:"title"`
+	Tags        []string            `json:"tags"`
+	Description string              `json:"description,omitempty"`
+	AudioScript string              `json:"audio_script,omitempty"`
+	Timings     []speech.WordTiming `json:"timings,omitempty"`
+	Chapters    []video.Chapter     `json:"chapters,omitempty"`
+	VideoName   string              `json:"video_name,omitempty"`
 }
 
 var sanitizeRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
 
-func newSession(baseDir string) *session {
+func newSession(baseDir, filenameTemplate string) *session {
 	return &session{
-		id:      time.Now().Format("20060102_150405"),
-		baseDir: baseDir,
+		id:               time.Now().Format("20060102_150405"),
+		baseDir:          baseDir,
+		filenameTemplate: filenameTemplate,
 	}
 }
 
 func (s *session) finalize(title string) error {
-	sanitized := sanitizeForPath(title)
+	sanitized := slugify(title)
 	if sanitized == "" {
 		sanitized = "untitled"
 	}
-	if len(sanitized) > 50 {
-		sanitized = sanitized[:50]
-	}
 
 	s.dir = filepath.Join(s.baseDir, fmt.Sprintf("%s_%s", s.id, sanitized))
 	return os.MkdirAll(s.dir, 0755)
 }
 
-func (s *session) audioPath() string  { return filepath.Join(s.dir, "audio.mp3") }
-func (s *session) videoPath() string  { return filepath.Join(s.dir, "video.mp4") }
-func (s *session) scriptPath() string { return filepath.Join(s.dir, "script.txt") }
+func (s *session) nameVideo(state *sessionState) {
+	if state.VideoName != "" {
+		s.videoName = state.VideoName
+		return
+	}
+	if s.filenameTemplate == "" {
+		return
+	}
+
+	name, err := renderFilename(s.filenameTemplate, filenameData{
+		Title: slugify(state.Title),
+		Topic: slugify(state.Topic),
+		Date:  time.Now().Format("2006-01-02"),
+		ID:    s.id,
+	})
+	if err != nil {
+		slog.Warn("Invalid filename template, using default", "template", s.filenameTemplate, "error", err)
+		return
+	}
+	s.videoName = uniqueFilename(s.dir, name)
+	state.VideoName = s.videoName
+}
+
+func (s *session) resume(dir string) (*sessionState, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("session dir: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("session dir %s is not a directory", dir)
+	}
+	s.dir = dir
+
+	state := &sessionState{}
+	data, err := os.ReadFile(s.statePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read session state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parse session state: %w", err)
+	}
+	return state, nil
+}
+
+func (s *session) saveState(state *sessionState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal session state: %w", err)
+	}
+	if err := os.WriteFile(s.statePath(), data, 0644); err != nil {
+		return fmt.Errorf("save session state: %w", err)
+	}
+	return nil
+}
+
+func (s *session) existingScript() string {
+	if s.dir == "" {
+		return ""
+	}
+	data, err := os.ReadFile(s.scriptPath())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func (s *session) existingAudio(state *sessionState) (*audioResult, bool) {
+	if s.dir == "" || len(state.Timings) == 0 {
+		return nil, false
+	}
+	data, err := os.ReadFile(s.audioPath())
+	if err != nil || video.DetectAudioFormat(data) == ".bin" {
+		return nil, false
+	}
+	return &audioResult{
+		data:     data,
+		timings:  state.Timings,
+		duration: speech.Duration(state.Timings),
+		script:   state.AudioScript,
+	}, true
+}
+
+func LoadUploadRequest(videoPath string) (UploadRequest, error) {
+	if _, err := os.Stat(videoPath); err != nil {
+		return UploadRequest{}, fmt.Errorf("video: %w", err)
+	}
+	request := UploadRequest{VideoPath: videoPath}
+
+	thumbnail := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + "_thumbnail.jpg"
+	if _, err := os.Stat(thumbnail); err == nil {
+		request.Thumbnail = thumbnail
+	}
+
+	s := &session{dir: filepath.Dir(videoPath)}
+	data, err := os.ReadFile(s.statePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return request, nil
+	}
+	if err != nil {
+		return UploadRequest{}, fmt.Errorf("read session state: %w", err)
+	}
+
+	var state sessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return UploadRequest{}, fmt.Errorf("parse session state: %w", err)
+	}
+	request.Title = state.Title
+	request.Description = withChapters(state.Description, state.Chapters)
+	request.Tags = state.Tags
+	request.Source = state.Source
+	return request, nil
+}
+
+func (s *session) writeAudio(data []byte) error {
+	tmp := s.audioPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("save audio: %w", err)
+	}
+	if err := os.Rename(tmp, s.audioPath()); err != nil {
+		return fmt.Errorf("save audio: %w", err)
+	}
+	return nil
+}
+
+func (s *session) audioPath() string    { return filepath.Join(s.dir, "audio.mp3") }
+func (s *session) scriptPath() string   { return filepath.Join(s.dir, "script.txt") }
+func (s *session) captionsPath() string { return filepath.Join(s.dir, "audio.srt") }
+func (s *session) statePath() string    { return filepath.Join(s.dir, "session.json") }
+
+func (s *session) videoPath() string {
+	if s.videoName == "" {
+		return filepath.Join(s.dir, defaultVideoName)
+	}
+	return filepath.Join(s.dir, s.videoName)
+}
+
+func CleanupSession(cfg *config.Config, videoPath string, keepVideo bool) error {
+	if cfg.Video.KeepArtifacts || videoPath == "" {
+		return nil
+	}
+
+	dir := filepath.Dir(videoPath)
+	if !isSessionDir(cfg.Video.OutputDir, dir) {
+		return fmt.Errorf("%s is not a session directory in %s", dir, cfg.Video.OutputDir)
+	}
+	if !keepVideo {
+		return os.RemoveAll(dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read session dir: %w", err)
+	}
+	var errs []error
+	for _, entry := range entries {
+		if entry.Name() == filepath.Base(videoPath) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isSessionDir(outputDir, dir string) bool {
+	base, err := filepath.Abs(outputDir)
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil || filepath.Dir(abs) != base {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(abs, "session.json"))
+	return err == nil
+}
+
+func renderFilename(tmpl string, data filenameData) (string, error) {
+	t, err := template.New("filename").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parse filename template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render filename template: %w", err)
+	}
+
+	name := strings.TrimSuffix(strings.TrimSpace(buf.String()), ".mp4")
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	name = strings.Trim(name, "._ ")
+	if name == "" {
+		return "", fmt.Errorf("filename template %q rendered an empty name", tmpl)
+	}
+	return name + ".mp4", nil
+}
+
+func uniqueFilename(dir, name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	candidate := name
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, candidate)); errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+}
+
+func slugify(s string) string {
+	slug := sanitizeForPath(s)
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "_-")
+	}
+	return slug
+}
 
 func sanitizeForPath(s string) string {
 	s = strings.ToLower(s)
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func withStageTimeout(ctx context.Context, stage string, seconds float64, fn func(context.Context) error) error {
+	if seconds <= 0 {
+		return fn(ctx)
+	}
+
+	timeout := time.Duration(seconds * float64(time.Second))
+	stageCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(stageCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s stage timed out after %s: %w", stage, timeout, context.DeadlineExceeded)
+	}
+	return err
+}
+
+func (generation *generationContext) stage(name string, seconds float64, fn func() error) error {
+	parent := generation.ctx
+	defer func() { generation.ctx = parent }()
+
+	return withStageTimeout(parent, name, seconds, func(ctx context.Context) error {
+		generation.ctx = ctx
+		return fn()
+	})
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+
+	"craftstory/internal/content/hackernews"
+	"craftstory/internal/content/reddit"
+	"craftstory/pkg/config"
+)
+
+const (
+	topicSourceReddit     = "reddit"
+	topicSourceHackerNews = "hackernews"
+	topicSourceStatic     = "static"
+	topicSourceManual     = "topic"
+
+	maxTopicPicks = 5
+)
+
+var defaultSourcePrivacy = map[string]string{
+	topicSourceReddit:     "private",
+	topicSourceHackerNews: "private",
+	topicSourceManual:     "unlisted",
+}
+
+type TopicSource interface {
+	NextTopic(ctx context.Context) (string, error)
+}
+
+type subredditTopicSource interface {
+	nextPost(ctx context.Context) (string, string, error)
+}
+
+type redditPosts interface {
+	GetSubredditPosts(ctx context.Context, subreddit, sort string, limit int) ([]reddit.Post, error)
+}
+
+type hackerNewsStories interface {
+	Stories(ctx context.Context, feed string, limit int) ([]hackernews.Story, error)
+}
+
+type redditSource struct {
+	client redditPosts
+	cfg    config.RedditConfig
+	rng    *rand.Rand
+}
+
+type hackerNewsSource struct {
+	client hackerNewsStories
+	cfg    config.HackerNewsConfig
+	rng    *rand.Rand
+}
+
+type staticSource struct {
+	topics []string
+	rng    *rand.Rand
+}
+
+func (pipeline *Pipeline) topicSource(cfg *config.Config, rng *rand.Rand) (TopicSource, error) {
+	switch cfg.TopicSource {
+	case "", topicSourceReddit:
+		return &redditSource{client: pipeline.service.reddit, cfg: cfg.Reddit, rng: rng}, nil
+	case topicSourceHackerNews:
+		return &hackerNewsSource{client: pipeline.service.hackerNews, cfg: cfg.HackerNews, rng: rng}, nil
+	case topicSourceStatic:
+		return &staticSource{topics: cfg.StaticTopics, rng: rng}, nil
+	default:
+		return nil, fmt.Errorf("unknown topic source %q", cfg.TopicSource)
+	}
+}
+
+func sourceName(source TopicSource) string {
+	switch source.(type) {
+	case *redditSource:
+		return topicSourceReddit
+	case *hackerNewsSource:
+		return topicSourceHackerNews
+	case *staticSource:
+		return topicSourceStatic
+	default:
+		return ""
+	}
+}
+
+func nextTopic(ctx context.Context, source TopicSource) (string, string, error) {
+	if s, ok := source.(subredditTopicSource); ok {
+		return s.nextPost(ctx)
+	}
+	topic, err := source.NextTopic(ctx)
+	return topic, "", err
+}
+
+func nextUntriedTopic(ctx context.Context, source TopicSource, tried map[string]bool) (string, string, error) {
+	var topic, subreddit string
+	for range maxTopicPicks {
+		var err error
+		topic, subreddit, err = nextTopic(ctx, source)
+		if err != nil {
+			return "", "", err
+		}
+		if !tried[topic] {
+			break
+		}
+	}
+	return topic, subreddit, nil
+}
+
+func (s *redditSource) NextTopic(ctx context.Context) (string, error) {
+	topic, _, err := s.nextPost(ctx)
+	return topic, err
+}
+
+func (s *redditSource) nextPost(ctx context.Context) (string, string, error) {
+	subreddits := s.cfg.Subreddits
+	if len(subreddits) == 0 {
+		subreddits = []string{"cscareerquestions", "learnprogramming"}
+	}
+
+	subreddit := subreddits[randomInt(s.rng, len(subreddits))]
+	sort := s.cfg.Sort
+	if sort == "" {
+		sort = "hot"
+	}
+	postLimit := s.cfg.PostLimit
+	if postLimit <= 0 {
+		postLimit = 10
+	}
+
+	slog.Info("Fetching Reddit posts", "subreddit", subreddit, "sort", sort)
+	posts, err := s.client.GetSubredditPosts(ctx, subreddit, sort, postLimit)
+	if err != nil {
+		return "", "", fmt.Errorf("fetch reddit posts: %w", err)
+	}
+	if len(posts) == 0 {
+		return "", "", fmt.Errorf("%w: no posts found in subreddit %s", ErrNoTopics, subreddit)
+	}
+
+	post := posts[randomInt(s.rng, len(posts))]
+	slog.Info("Selected post", "title", post.Title)
+
+	return post.Title, subreddit, nil
+}
+
+func (s *hackerNewsSource) NextTopic(ctx context.Context) (string, error) {
+	slog.Info("Fetching Hacker News stories", "feed", s.cfg.Feed)
+	stories, err := s.client.Stories(ctx, s.cfg.Feed, s.cfg.PostLimit)
+	if err != nil {
+		return "", fmt.Errorf("fetch hacker news stories: %w", err)
+	}
+	if len(stories) == 0 {
+		return "", fmt.Errorf("%w: no hacker news stories found", ErrNoTopics)
+	}
+
+	story := stories[randomInt(s.rng, len(stories))]
+	slog.Info("Selected story", "title", story.Title)
+
+	return story.Title, nil
+}
+
+func (s *staticSource) NextTopic(_ context.Context) (string, error) {
+	if len(s.topics) == 0 {
+		return "", fmt.Errorf("%w: no static topics configured", ErrNoTopics)
+	}
+	topic := s.topics[randomInt(s.rng, len(s.topics))]
+	slog.Info("Selected static topic", "topic", topic)
+	return topic, nil
+}
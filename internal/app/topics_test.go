@@ -0,0 +1,217 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"craftstory/internal/content/hackernews"
+	"craftstory/internal/content/reddit"
+	"craftstory/internal/llm"
+	"craftstory/internal/speech"
+	"craftstory/pkg/config"
+)
+
+type mockReddit struct {
+	posts     []reddit.Post
+	err       error
+	subreddit string
+}
+
+func (m *mockReddit) GetSubredditPosts(_ context.Context, subreddit, _ string, _ int) ([]reddit.Post, error) {
+	m.subreddit = subreddit
+	return m.posts, m.err
+}
+
+type mockHackerNews struct {
+	stories []hackernews.Story
+	err     error
+	feed    string
+}
+
+func (m *mockHackerNews) Stories(_ context.Context, feed string, _ int) ([]hackernews.Story, error) {
+	m.feed = feed
+	return m.stories, m.err
+}
+
+func TestRedditSource(t *testing.T) {
+	tests := []struct {
+		name          string
+		client        *mockReddit
+		wantTopic     string
+		wantSubreddit string
+		wantErr       bool
+	}{
+		{
+			name:          "picksPost",
+			client:        &mockReddit{posts: []reddit.Post{{Title: "How do I switch careers?"}}},
+			wantTopic:     "How do I switch careers?",
+			wantSubreddit: "golang",
+		},
+		{name: "noPosts", client: &mockReddit{}, wantErr: true},
+		{name: "fetchError", client: &mockReddit{err: errors.New("rate limited")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &redditSource{client: tt.client, cfg: config.RedditConfig{Subreddits: []string{"golang"}}, rng: newRand(1)}
+
+			topic, subreddit, err := nextTopic(t.Context(), source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("nextTopic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if topic != tt.wantTopic || subreddit != tt.wantSubreddit {
+				t.Errorf("nextTopic() = (%q, %q), want (%q, %q)", topic, subreddit, tt.wantTopic, tt.wantSubreddit)
+			}
+		})
+	}
+}
+
+func TestHackerNewsSource(t *testing.T) {
+	tests := []struct {
+		name      string
+		client    *mockHackerNews
+		wantTopic string
+		wantErr   bool
+	}{
+		{
+			name:      "picksStory",
+			client:    &mockHackerNews{stories: []hackernews.Story{{Title: "Show HN: A tiny compiler"}}},
+			wantTopic: "Show HN: A tiny compiler",
+		},
+		{name: "noStories", client: &mockHackerNews{}, wantErr: true},
+		{name: "fetchError", client: &mockHackerNews{err: errors.New("unavailable")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &hackerNewsSource{client: tt.client, cfg: config.HackerNewsConfig{Feed: "best"}, rng: newRand(1)}
+
+			topic, subreddit, err := nextTopic(t.Context(), source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("nextTopic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if topic != tt.wantTopic || subreddit != "" {
+				t.Errorf("nextTopic() = (%q, %q), want (%q, \"\")", topic, subreddit, tt.wantTopic)
+			}
+			if tt.client.feed != "best" {
+				t.Errorf("feed = %q, want best", tt.client.feed)
+			}
+		})
+	}
+}
+
+func TestStaticSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		topics  []string
+		wantErr bool
+	}{
+		{name: "picksTopic", topics: []string{"Black holes", "Deep sea creatures"}},
+		{name: "noTopics", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &staticSource{topics: tt.topics, rng: newRand(1)}
+
+			topic, err := source.NextTopic(t.Context())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NextTopic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Contains(tt.topics, topic) {
+				t.Errorf("NextTopic() = %q, want one of %v", topic, tt.topics)
+			}
+		})
+	}
+}
+
+func TestTopicSourceSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    TopicSource
+		wantErr bool
+	}{
+		{name: "defaultsToReddit", want: &redditSource{}},
+		{name: "reddit", source: "reddit", want: &redditSource{}},
+		{name: "hackerNews", source: "hackernews", want: &hackerNewsSource{}},
+		{name: "static", source: "static", want: &staticSource{}},
+		{name: "unknown", source: "twitter", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{TopicSource: tt.source}
+			pipeline := NewPipeline(NewService(ServiceOptions{Config: cfg}))
+
+			got, err := pipeline.topicSource(cfg, newRand(1))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("topicSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotType, wantType := fmt.Sprintf("%T", got), fmt.Sprintf("%T", tt.want); gotType != wantType {
+				t.Errorf("topicSource() = %s, want %s", gotType, wantType)
+			}
+		})
+	}
+}
+
+func TestGenerateWithRetries(t *testing.T) {
+	posts := []reddit.Post{{Title: "First post"}, {Title: "Second post"}}
+
+	tests := []struct {
+		name        string
+		client      *mockReddit
+		scripts     []string
+		maxAttempts int
+		wantCalls   int
+		wantErr     error
+	}{
+		{name: "refusalThenSuccess", client: &mockReddit{posts: posts}, scripts: []string{"", words(10)}, maxAttempts: 3, wantCalls: 2},
+		{name: "noRetryByDefault", client: &mockReddit{posts: posts}, scripts: []string{"", words(10)}, wantCalls: 1, wantErr: llm.ErrEmptyResponse},
+		{name: "attemptsExhausted", client: &mockReddit{posts: posts}, scripts: []string{"", ""}, maxAttempts: 2, wantCalls: 2, wantErr: llm.ErrEmptyResponse},
+		{name: "infrastructureErrorNotRetried", client: &mockReddit{posts: posts}, maxAttempts: 3, wantCalls: 1, wantErr: errNoScripts},
+		{name: "authErrorNotRetried", client: &mockReddit{err: reddit.ErrUnauthorized}, maxAttempts: 3, wantErr: reddit.ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockLLM{scripts: tt.scripts}
+			cfg := &config.Config{
+				Content: config.ContentConfig{WordCount: 10},
+				Video:   config.VideoConfig{OutputDir: t.TempDir()},
+			}
+			pipeline := NewPipeline(NewService(ServiceOptions{
+				Config:    cfg,
+				LLM:       mock,
+				TTS:       speech.NewStubProvider(speech.DefaultWordsPerMinute),
+				Assembler: &mockAssembler{duration: 60},
+			}))
+			source := &redditSource{client: tt.client, cfg: config.RedditConfig{Subreddits: []string{"golang"}}, rng: newRand(1)}
+
+			result, err := pipeline.generateWithRetries(t.Context(), source, tt.maxAttempts, false)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("generateWithRetries() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("generateWithRetries() error = %v", err)
+			}
+
+			if len(mock.topics) != tt.wantCalls {
+				t.Fatalf("script requests = %d, want %d", len(mock.topics), tt.wantCalls)
+			}
+			if tt.wantCalls == 2 && mock.topics[0] == mock.topics[1] {
+				t.Errorf("retried the same post %q", mock.topics[0])
+			}
+			if tt.wantErr == nil && result.Topic != mock.topics[1] {
+				t.Errorf("result topic = %q, want second post %q", result.Topic, mock.topics[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package hackernews
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"craftstory/pkg/httputil"
+)
+
+const (
+	baseURL        = "https://hacker-news.firebaseio.com/v0"
+	defaultTimeout = 30 * time.Second
+	defaultFeed    = "top"
+	defaultLimit   = 10
+)
+
+type Client struct {
+	httpClient *httputil.RetryClient
+	baseURL    string
+	userAgent  string
+}
+
+type Config struct {
+	UserAgent string
+	Transport http.RoundTripper
+}
+
+type Story struct {
+	ID          int
+	Title       string
+	URL         string
+	Author      string
+	Score       int
+	NumComments int
+}
+
+type item struct {
+	ID          int    `json:"id"`
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	By          string `json:"by"`
+	Score       int    `json:"score"`
+	Descendants int    `json:"descendants"`
+	Dead        bool   `json:"dead"`
+	Deleted     bool   `json:"deleted"`
+}
+
+func NewClient(cfg Config) *Client {
+	return &Client{
+		httpClient: httputil.NewRetryClient(&http.Client{
+			Timeout:   defaultTimeout,
+			Transport: cfg.Transport,
+		}, httputil.DefaultRetryConfig()),
+		baseURL:   baseURL,
+		userAgent: cfg.UserAgent,
+	}
+}
+
+func (c *Client) Stories(ctx context.Context, feed string, limit int) ([]Story, error) {
+	if feed == "" {
+		feed = defaultFeed
+	}
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	var ids []int
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/%sstories.json", c.baseURL, feed), &ids); err != nil {
+		return nil, fmt.Errorf("fetch %s stories: %w", feed, err)
+	}
+
+	stories := make([]Story, 0, limit)
+	for _, id := range ids {
+		if len(stories) == limit {
+			break
+		}
+
+		var it item
+		if err := c.getJSON(ctx, fmt.Sprintf("%s/item/%d.json", c.baseURL, id), &it); err != nil {
+			return nil, fmt.Errorf("fetch item %d: %w", id, err)
+		}
+		if it.Type != "story" || it.Dead || it.Deleted || it.Title == "" {
+			continue
+		}
+
+		stories = append(stories, Story{
+			ID:          it.ID,
+			Title:       it.Title,
+			URL:         it.URL,
+			Author:      it.By,
+			Score:       it.Score,
+			NumComments: it.Descendants,
+		})
+	}
+
+	return stories, nil
+}
+
+func (c *Client) getJSON(ctx context.Context, url string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("hacker news api error: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if err := json.Unmarshal(body, dest); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
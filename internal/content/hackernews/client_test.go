@@ -0,0 +1,90 @@
+package hackernews
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStories(t *testing.T) {
+	items := map[string]item{
+		"1": {ID: 1, Type: "story", Title: "Show HN: A tiny compiler", By: "alice", Score: 120, Descendants: 40},
+		"2": {ID: 2, Type: "job", Title: "We're hiring"},
+		"3": {ID: 3, Type: "story", Title: "Dead story", Dead: true},
+		"4": {ID: 4, Type: "story", Title: "Why Go generics took a decade", URL: "https://example.com"},
+		"5": {ID: 5, Type: "story", Title: "Over the limit"},
+	}
+
+	tests := []struct {
+		name       string
+		feed       string
+		limit      int
+		wantPath   string
+		wantTitles []string
+	}{
+		{
+			name:       "defaultFeed",
+			limit:      2,
+			wantPath:   "/topstories.json",
+			wantTitles: []string{"Show HN: A tiny compiler", "Why Go generics took a decade"},
+		},
+		{
+			name:       "bestFeed",
+			feed:       "best",
+			limit:      1,
+			wantPath:   "/beststories.json",
+			wantTitles: []string{"Show HN: A tiny compiler"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var listPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if id, ok := strings.CutPrefix(r.URL.Path, "/item/"); ok {
+					_ = json.NewEncoder(w).Encode(items[strings.TrimSuffix(id, ".json")])
+					return
+				}
+				listPath = r.URL.Path
+				_, _ = w.Write([]byte(`[1, 2, 3, 4, 5]`))
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{})
+			client.baseURL = server.URL
+
+			stories, err := client.Stories(context.Background(), tt.feed, tt.limit)
+			if err != nil {
+				t.Fatalf("Stories() error = %v", err)
+			}
+			if listPath != tt.wantPath {
+				t.Errorf("list path = %q, want %q", listPath, tt.wantPath)
+			}
+			if len(stories) != len(tt.wantTitles) {
+				t.Fatalf("Stories() returned %d stories, want %d", len(stories), len(tt.wantTitles))
+			}
+			for i, want := range tt.wantTitles {
+				if stories[i].Title != want {
+					t.Errorf("stories[%d].Title = %q, want %q", i, stories[i].Title, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStoriesError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{})
+	client.baseURL = server.URL
+
+	if _, err := client.Stories(context.Background(), "top", 5); err == nil {
+		t.Error("Stories() expected error for 404 response")
+	}
+}
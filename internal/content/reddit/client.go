@@ -3,10 +3,14 @@ package reddit
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"craftstory/pkg/httputil"
 )
 
 const (
@@ -15,9 +19,26 @@ const (
 	userAgent      = "craftstory/1.0"
 )
 
+var (
+	ErrUnauthorized = errors.New("reddit api error: unauthorized")
+	ErrRateLimited  = errors.New("reddit api error: rate limited")
+)
+
 type Client struct {
-	httpClient *http.Client
+	httpClient *httputil.RetryClient
 	baseURL    string
+	oauthURL   string
+	userAgent  string
+	auth       *tokenSource
+}
+
+type Config struct {
+	UserAgent    string
+	Transport    http.RoundTripper
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string
 }
 
 type Post struct {
@@ -50,13 +71,38 @@ type postData struct {
 	NumComments int     `json:"num_comments"`
 }
 
-func NewClient() *Client {
-	return &Client{
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-		},
-		baseURL: baseURL,
+func NewClient(cfg Config) *Client {
+	agent := cfg.UserAgent
+	if agent == "" {
+		agent = userAgent
+	}
+
+	httpClient := httputil.NewRetryClient(&http.Client{
+		Timeout:   defaultTimeout,
+		Transport: cfg.Transport,
+	}, httputil.DefaultRetryConfig())
+
+	client := &Client{
+		httpClient: httpClient,
+		baseURL:    baseURL,
+		oauthURL:   oauthURL,
+		userAgent:  agent,
+	}
+
+	if cfg.ClientID != "" && cfg.ClientSecret != "" {
+		client.auth = &tokenSource{
+			httpClient:   httpClient,
+			tokenURL:     tokenURL,
+			userAgent:    agent,
+			clientID:     cfg.ClientID,
+			clientSecret: cfg.ClientSecret,
+			username:     cfg.Username,
+			password:     cfg.Password,
+			now:          time.Now,
+		}
 	}
+
+	return client
 }
 
 func (c *Client) GetSubredditPosts(ctx context.Context, subreddit, sort string, limit int) ([]Post, error) {
@@ -67,9 +113,9 @@ func (c *Client) GetSubredditPosts(ctx context.Context, subreddit, sort string,
 		limit = 25
 	}
 
-	url := fmt.Sprintf("%s/r/%s/%s.json?limit=%d", c.baseURL, subreddit, sort, limit)
+	path := fmt.Sprintf("/r/%s/%s.json?limit=%d", subreddit, sort, limit)
 
-	body, err := c.doRequest(ctx, url)
+	body, err := c.get(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -87,13 +133,40 @@ func (c *Client) GetSubredditPosts(ctx context.Context, subreddit, sort string,
 	return posts, nil
 }
 
-func (c *Client) doRequest(ctx context.Context, url string) ([]byte, error) {
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	if c.auth == nil {
+		return c.doRequest(ctx, c.baseURL+path, "")
+	}
+
+	token, err := c.auth.Token(ctx)
+	if err != nil {
+		slog.Warn("Reddit OAuth failed, falling back to anonymous access", "error", err)
+		return c.doRequest(ctx, c.baseURL+path, "")
+	}
+
+	body, err := c.doRequest(ctx, c.oauthURL+path, token)
+	if !errors.Is(err, ErrUnauthorized) {
+		return body, err
+	}
+
+	c.auth.Invalidate()
+	token, err = c.auth.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("refresh reddit token: %w", err)
+	}
+	return c.doRequest(ctx, c.oauthURL+path, token)
+}
+
+func (c *Client) doRequest(ctx context.Context, url, token string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("User-Agent", c.userAgent)
+	if token != "" {
+		req.Header.Set("Authorization", "bearer "+token)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -101,7 +174,13 @@ func (c *Client) doRequest(ctx context.Context, url string) ([]byte, error) {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s", ErrUnauthorized, resp.Status)
+	case http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: %s", ErrRateLimited, resp.Status)
+	default:
 		return nil, fmt.Errorf("reddit api error: %s", resp.Status)
 	}
 
@@ -3,11 +3,28 @@ package reddit
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"craftstory/pkg/httputil"
 )
 
+func newTestClient(server *httptest.Server) *Client {
+	return &Client{
+		httpClient: httputil.NewRetryClient(server.Client(), httputil.RetryConfig{
+			MaxRetries:   2,
+			InitialDelay: time.Millisecond,
+			MaxDelay:     time.Millisecond,
+		}),
+		baseURL:   server.URL,
+		userAgent: userAgent,
+	}
+}
+
 func TestGetSubredditPosts(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -98,8 +115,7 @@ func TestGetSubredditPosts(t *testing.T) {
 			}))
 			defer server.Close()
 
-			client := NewClient()
-			client.baseURL = server.URL
+			client := newTestClient(server)
 
 			ctx := context.Background()
 			posts, err := client.GetSubredditPosts(ctx, tt.subreddit, tt.sort, tt.limit)
@@ -122,8 +138,7 @@ func TestLimitValidation(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewClient()
-	client.baseURL = server.URL
+	client := newTestClient(server)
 
 	ctx := context.Background()
 
@@ -167,3 +182,86 @@ func TestPostFromData(t *testing.T) {
 		t.Errorf("Score = %d, want %d", post.Score, data.Score)
 	}
 }
+
+func TestGetSubredditPostsRetriesRateLimit(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"children":[{"data":{"title":"Post"}}]}}`))
+	}))
+	defer server.Close()
+
+	posts, err := newTestClient(server).GetSubredditPosts(context.Background(), "test", "hot", 5)
+	if err != nil {
+		t.Fatalf("GetSubredditPosts() error = %v", err)
+	}
+	if len(posts) != 1 || posts[0].Title != "Post" {
+		t.Errorf("GetSubredditPosts() = %+v, want one post titled Post", posts)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestGetSubredditPostsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErr: ErrUnauthorized},
+		{name: "forbidden", status: http.StatusForbidden, wantErr: ErrUnauthorized},
+		{name: "rateLimited", status: http.StatusTooManyRequests, wantErr: ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			_, err := newTestClient(server).GetSubredditPosts(context.Background(), "test", "hot", 5)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetSubredditPosts() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientUserAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{name: "default", want: userAgent},
+		{name: "configured", userAgent: "linux:craftstory:v2 (by /u/tester)", want: "linux:craftstory:v2 (by /u/tester)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("User-Agent")
+				_ = json.NewEncoder(w).Encode(listingResponse{})
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{UserAgent: tt.userAgent})
+			client.baseURL = server.URL
+
+			if _, err := client.GetSubredditPosts(context.Background(), "test", "hot", 5); err != nil {
+				t.Fatalf("GetSubredditPosts() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("User-Agent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"craftstory/pkg/httputil"
+)
+
+const (
+	oauthURL     = "https://oauth.reddit.com"
+	tokenURL     = "https://www.reddit.com/api/v1/access_token"
+	expiryMargin = time.Minute
+)
+
+type tokenSource struct {
+	httpClient   *httputil.RetryClient
+	tokenURL     string
+	userAgent    string
+	clientID     string
+	clientSecret string
+	username     string
+	password     string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+	now    func() time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Error       string `json:"error"`
+}
+
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.now().Before(s.expiry) {
+		return s.token, nil
+	}
+
+	token, expiresIn, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token
+	s.expiry = s.now().Add(time.Duration(expiresIn)*time.Second - expiryMargin)
+	return s.token, nil
+}
+
+func (s *tokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
+
+func (s *tokenSource) fetch(ctx context.Context) (string, int, error) {
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {s.username},
+		"password":   {s.password},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("create token request: %w", err)
+	}
+	req.SetBasicAuth(s.clientID, s.clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", s.userAgent)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("send token request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf("read token response: %w", err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", 0, fmt.Errorf("%w: token request %s", ErrUnauthorized, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("reddit token error: %s", resp.Status)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", 0, fmt.Errorf("parse token response: %w", err)
+	}
+	if token.Error != "" {
+		return "", 0, fmt.Errorf("%w: token error %s", ErrUnauthorized, token.Error)
+	}
+	if token.AccessToken == "" {
+		return "", 0, fmt.Errorf("reddit token response missing access_token")
+	}
+	return token.AccessToken, token.ExpiresIn, nil
+}
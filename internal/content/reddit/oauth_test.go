@@ -0,0 +1,154 @@
+package reddit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newOAuthServer(t *testing.T, tokens *int32, authHeaders *[]string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/access_token", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(tokens, 1)
+
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+		if r.Form.Get("grant_type") != "password" || r.Form.Get("username") != "bot" || r.Form.Get("password") != "hunter2" {
+			t.Errorf("token form = %v, want password grant for bot", r.Form)
+		}
+
+		_ = json.NewEncoder(w).Encode(tokenResponse{
+			AccessToken: fmt.Sprintf("token%d", n),
+			TokenType:   "bearer",
+			ExpiresIn:   3600,
+		})
+	})
+	mux.HandleFunc("/r/", func(w http.ResponseWriter, r *http.Request) {
+		*authHeaders = append(*authHeaders, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"data":{"children":[{"data":{"title":"Post"}}]}}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func newOAuthClient(server *httptest.Server, secret string) *Client {
+	client := NewClient(Config{ClientID: "client", ClientSecret: secret, Username: "bot", Password: "hunter2"})
+	client.baseURL = server.URL
+	client.oauthURL = server.URL
+	client.auth.tokenURL = server.URL + "/api/v1/access_token"
+	return client
+}
+
+func TestOAuthToken(t *testing.T) {
+	var tokens int32
+	var headers []string
+	server := newOAuthServer(t, &tokens, &headers)
+	defer server.Close()
+
+	client := newOAuthClient(server, "secret")
+	now := time.Now()
+	client.auth.now = func() time.Time { return now }
+
+	tests := []struct {
+		name        string
+		advance     time.Duration
+		wantToken   string
+		wantFetches int32
+	}{
+		{name: "fetchesToken", wantToken: "token1", wantFetches: 1},
+		{name: "reusesValidToken", advance: 30 * time.Minute, wantToken: "token1", wantFetches: 1},
+		{name: "refreshesExpiredToken", advance: 30 * time.Minute, wantToken: "token2", wantFetches: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+			token, err := client.auth.Token(context.Background())
+			if err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+			if token != tt.wantToken {
+				t.Errorf("Token() = %q, want %q", token, tt.wantToken)
+			}
+			if got := atomic.LoadInt32(&tokens); got != tt.wantFetches {
+				t.Errorf("token fetches = %d, want %d", got, tt.wantFetches)
+			}
+		})
+	}
+}
+
+func TestOAuthRequestHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     string
+		anonymous  bool
+		wantHeader string
+	}{
+		{name: "authenticated", secret: "secret", wantHeader: "bearer token1"},
+		{name: "tokenFailureFallsBackToAnonymous", secret: "wrong", wantHeader: ""},
+		{name: "notConfigured", anonymous: true, wantHeader: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tokens int32
+			var headers []string
+			server := newOAuthServer(t, &tokens, &headers)
+			defer server.Close()
+
+			client := NewClient(Config{})
+			client.baseURL = server.URL
+			if !tt.anonymous {
+				client = newOAuthClient(server, tt.secret)
+			}
+
+			posts, err := client.GetSubredditPosts(context.Background(), "golang", "hot", 5)
+			if err != nil {
+				t.Fatalf("GetSubredditPosts() error = %v", err)
+			}
+			if len(posts) != 1 {
+				t.Fatalf("GetSubredditPosts() returned %d posts, want 1", len(posts))
+			}
+			if len(headers) != 1 || headers[0] != tt.wantHeader {
+				t.Errorf("Authorization headers = %q, want [%q]", headers, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestOAuthRefreshesOnUnauthorized(t *testing.T) {
+	var tokens int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/access_token", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&tokens, 1)
+		_ = json.NewEncoder(w).Encode(tokenResponse{AccessToken: fmt.Sprintf("token%d", n), ExpiresIn: 3600})
+	})
+	mux.HandleFunc("/r/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bearer token2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"children":[]}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := newOAuthClient(server, "secret")
+	if _, err := client.GetSubredditPosts(context.Background(), "golang", "hot", 5); err != nil {
+		t.Fatalf("GetSubredditPosts() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&tokens); got != 2 {
+		t.Errorf("token fetches = %d, want 2", got)
+	}
+}
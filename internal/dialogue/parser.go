@@ -18,13 +18,29 @@ type Script struct {
 
 var linePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9 ]*?)\s*:\s*(.+)$`)
 var stickerPattern = regexp.MustCompile(`^\[s(\d+)\]\s*`)
+var boldPattern = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+var directionPattern = regexp.MustCompile(`\*[a-z][a-z ,'-]*\*|\([^)]*\)|\[[^\]]*\]`)
+var spaceBeforePunctPattern = regexp.MustCompile(`\s+([,.!?;:])`)
+
+var speakerArticles = map[string]bool{"the": true, "a": true, "an": true}
+
+type ParseOptions struct {
+	KeepDirections bool
+}
 
 func Parse(text string) *Script {
+	return ParseWithOptions(text, ParseOptions{})
+}
+
+func ParseWithOptions(text string, opts ParseOptions) *Script {
 	lines := strings.Split(text, "\n")
 	script := &Script{
 		Lines: make([]Line, 0),
 	}
 
+	speaker := ""
+	turnOpen := false
+
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" {
@@ -36,33 +52,81 @@ func Parse(text string) *Script {
 		}
 
 		matches := linePattern.FindStringSubmatch(line)
-		if len(matches) == 3 {
-			speaker := strings.TrimSpace(matches[1])
-			text := strings.TrimSpace(matches[2])
-			if strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")") {
+		if len(matches) != 3 {
+			if speaker == "" {
 				continue
 			}
+			text := cleanText(line, opts)
+			if text == "" {
+				continue
+			}
+			if turnOpen {
+				last := &script.Lines[len(script.Lines)-1]
+				last.Text += " " + text
+				continue
+			}
+			script.Lines = append(script.Lines, Line{Speaker: speaker, Text: text})
+			turnOpen = true
+			continue
+		}
 
-			stickerID := 0
-			if stickerMatches := stickerPattern.FindStringSubmatch(text); len(stickerMatches) >= 2 {
-				if n, err := strconv.Atoi(stickerMatches[1]); err == nil {
-					stickerID = n
-				}
-				text = strings.TrimPrefix(text, stickerMatches[0])
+		speaker = strings.TrimSpace(matches[1])
+		turnOpen = false
+		text := strings.TrimSpace(matches[2])
+		if strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")") {
+			continue
+		}
+
+		stickerID := 0
+		if stickerMatches := stickerPattern.FindStringSubmatch(text); len(stickerMatches) >= 2 {
+			if n, err := strconv.Atoi(stickerMatches[1]); err == nil {
+				stickerID = n
 			}
+			text = strings.TrimPrefix(text, stickerMatches[0])
+		}
 
-			text = stripFormatting(text)
-			script.Lines = append(script.Lines, Line{
-				Speaker:   speaker,
-				Text:      text,
-				StickerID: stickerID,
-			})
+		text = cleanText(text, opts)
+		if text == "" {
+			continue
 		}
+		script.Lines = append(script.Lines, Line{
+			Speaker:   speaker,
+			Text:      text,
+			StickerID: stickerID,
+		})
+		turnOpen = true
 	}
 
 	return script
 }
 
+func NormalizeSpeaker(name string) string {
+	fields := strings.Fields(strings.ToLower(name))
+	if len(fields) > 1 && speakerArticles[fields[0]] {
+		fields = fields[1:]
+	}
+	return strings.Join(fields, " ")
+}
+
+func cleanText(text string, opts ParseOptions) string {
+	if !opts.KeepDirections {
+		text = stripDirections(text)
+	}
+	return stripFormatting(text)
+}
+
+func stripDirections(text string) string {
+	text = boldPattern.ReplaceAllString(text, "$1")
+	text = directionPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if stickerPattern.MatchString(match) {
+			return match
+		}
+		return " "
+	})
+	text = strings.Join(strings.Fields(text), " ")
+	return spaceBeforePunctPattern.ReplaceAllString(text, "$1")
+}
+
 func stripFormatting(text string) string {
 	text = strings.ReplaceAll(text, "*", "")
 	text = strings.ReplaceAll(text, "_", "")
@@ -84,6 +148,42 @@ func (s *Script) Speakers() []string {
 	return speakers
 }
 
+func (s *Script) Turns() int {
+	turns := 0
+	for i, line := range s.Lines {
+		if i == 0 || line.Speaker != s.Lines[i-1].Speaker {
+			turns++
+		}
+	}
+	return turns
+}
+
+func TruncateTurns(text string, maxTurns int) string {
+	if maxTurns <= 0 {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	turns := 0
+	speaker := ""
+	for i, line := range lines {
+		matches := linePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if len(matches) != 3 {
+			continue
+		}
+		name := strings.TrimSpace(matches[1])
+		if name == speaker {
+			continue
+		}
+		speaker = name
+		turns++
+		if turns > maxTurns {
+			return strings.TrimRight(strings.Join(lines[:i], "\n"), "\n ")
+		}
+	}
+	return text
+}
+
 func (s *Script) IsEmpty() bool {
 	return len(s.Lines) == 0
 }
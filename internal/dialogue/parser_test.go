@@ -331,3 +331,219 @@ func TestParseStripFormattingWithSticker(t *testing.T) {
 		t.Errorf("Text = %q, want %q", line.Text, "Bold and italic text")
 	}
 }
+
+func TestParseStageDirections(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		opts      ParseOptions
+		wantLines []Line
+	}{
+		{
+			name:  "asteriskAction",
+			input: "Host: *laughs* You won't believe this.",
+			wantLines: []Line{
+				{Speaker: "Host", Text: "You won't believe this."},
+			},
+		},
+		{
+			name:  "parentheticalMidLine",
+			input: "Guest: Wait (pause), are you serious?",
+			wantLines: []Line{
+				{Speaker: "Guest", Text: "Wait, are you serious?"},
+			},
+		},
+		{
+			name:  "bracketedAction",
+			input: "Host: [whispering] It was all fake.",
+			wantLines: []Line{
+				{Speaker: "Host", Text: "It was all fake."},
+			},
+		},
+		{
+			name:  "mixedDialogueAndActions",
+			input: "Host: So *leans in* here's the thing.\nGuest: *gasps*\nGuest: No way! (laughs) Tell me more.",
+			wantLines: []Line{
+				{Speaker: "Host", Text: "So here's the thing."},
+				{Speaker: "Guest", Text: "No way! Tell me more."},
+			},
+		},
+		{
+			name:  "boldKept",
+			input: "Host: This is **huge** news",
+			wantLines: []Line{
+				{Speaker: "Host", Text: "This is huge news"},
+			},
+		},
+		{
+			name:  "singleAsteriskEmphasisKept",
+			input: "Host: That was *Incredible*, honestly",
+			wantLines: []Line{
+				{Speaker: "Host", Text: "That was Incredible, honestly"},
+			},
+		},
+		{
+			name:  "keepDirections",
+			input: "Host: *laughs* No way (pause) really",
+			opts:  ParseOptions{KeepDirections: true},
+			wantLines: []Line{
+				{Speaker: "Host", Text: "laughs No way (pause) really"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := ParseWithOptions(tt.input, tt.opts)
+
+			if len(script.Lines) != len(tt.wantLines) {
+				t.Fatalf("ParseWithOptions() got %d lines, want %d: %+v", len(script.Lines), len(tt.wantLines), script.Lines)
+			}
+			for i, want := range tt.wantLines {
+				if script.Lines[i] != want {
+					t.Errorf("line %d = %+v, want %+v", i, script.Lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseContinuationLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantLines []Line
+	}{
+		{
+			name:  "twoLineContinuation",
+			input: "Host: So here's the thing\nit was never real.\nGuest: Wait, what?",
+			wantLines: []Line{
+				{Speaker: "Host", Text: "So here's the thing it was never real."},
+				{Speaker: "Guest", Text: "Wait, what?"},
+			},
+		},
+		{
+			name:  "leadingLineWithoutSpeaker",
+			input: "Here is your script\nHost: Hello\nGuest: Hi",
+			wantLines: []Line{
+				{Speaker: "Host", Text: "Hello"},
+				{Speaker: "Guest", Text: "Hi"},
+			},
+		},
+		{
+			name:  "continuationAfterSkippedTurn",
+			input: "Host: Hello\nGuest: *gasps*\nNo way!",
+			wantLines: []Line{
+				{Speaker: "Host", Text: "Hello"},
+				{Speaker: "Guest", Text: "No way!"},
+			},
+		},
+		{
+			name:  "continuationSkipsStageDirectionLine",
+			input: "Host: First part\n(pause)\nsecond part",
+			wantLines: []Line{
+				{Speaker: "Host", Text: "First part second part"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := Parse(tt.input)
+
+			if len(script.Lines) != len(tt.wantLines) {
+				t.Fatalf("Parse() got %d lines, want %d: %+v", len(script.Lines), len(tt.wantLines), script.Lines)
+			}
+			for i, want := range tt.wantLines {
+				if script.Lines[i] != want {
+					t.Errorf("line %d = %+v, want %+v", i, script.Lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeSpeaker(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain", input: "Host", want: "host"},
+		{name: "upperCase", input: "HOST", want: "host"},
+		{name: "article", input: "The Host", want: "host"},
+		{name: "articleUpperCase", input: "THE HOST", want: "host"},
+		{name: "extraWhitespace", input: "  the   Curious  Friend ", want: "curious friend"},
+		{name: "articleOnly", input: "The", want: "the"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeSpeaker(tt.input); got != tt.want {
+				t.Errorf("NormalizeSpeaker(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptTurns(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "empty", input: "", want: 0},
+		{name: "monologue", input: "Host: One\nHost: Two\nHost: Three", want: 1},
+		{name: "alternating", input: "Host: One\nGuest: Two\nHost: Three", want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input).Turns(); got != tt.want {
+				t.Errorf("Turns() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateTurns(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxTurns int
+		want     string
+	}{
+		{
+			name:     "underLimit",
+			input:    "Host: One\nGuest: Two",
+			maxTurns: 3,
+			want:     "Host: One\nGuest: Two",
+		},
+		{
+			name:     "cutsAtSpeakerBoundary",
+			input:    "Host: One\nGuest: Two\nHost: Three\nGuest: Four",
+			maxTurns: 2,
+			want:     "Host: One\nGuest: Two",
+		},
+		{
+			name:     "keepsWholeTurn",
+			input:    "Host: One\nHost: still one\nGuest: Two\ncontinued two\n\nHost: Three",
+			maxTurns: 2,
+			want:     "Host: One\nHost: still one\nGuest: Two\ncontinued two",
+		},
+		{
+			name:     "disabled",
+			input:    "Host: One\nGuest: Two\nHost: Three",
+			maxTurns: 0,
+			want:     "Host: One\nGuest: Two\nHost: Three",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateTurns(tt.input, tt.maxTurns); got != tt.want {
+				t.Errorf("TruncateTurns() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -13,8 +13,18 @@ import (
 )
 
 const (
-	callbackApprove = "approve"
-	callbackReject  = "reject"
+	callbackApprove    = "approve"
+	callbackReject     = "reject"
+	callbackRegenerate = "regenerate"
+	callbackFull       = "full"
+	callbackQueuePrev  = "queue:prev"
+	callbackQueueNext  = "queue:next"
+
+	rejectReasonTimeout = time.Minute
+	noReasonGiven       = "no reason given"
+	regeneratedReason   = "regenerated"
+	maxBotUploadBytes   = 50 << 20
+	queuePageSize       = 3
 )
 
 type ApprovalService struct {
@@ -24,6 +34,7 @@ type ApprovalService struct {
 	reviewers       map[int64]Reviewer
 	reviewersMu     sync.RWMutex
 	dataFile        string
+	pendingFile     string
 	pollOffset      int
 	stopPoll        chan struct{}
 	pollWg          sync.WaitGroup
@@ -33,20 +44,42 @@ type ApprovalService struct {
 	resultChan      chan *ApprovalResult
 	generationQueue *GenerationQueue
 	genRequestChan  chan GenerationRequest
+	pendingReason   *reasonPrompt
+	reasonTimeout   time.Duration
+	lastRejected    *QueuedVideo
+	stats           *statsStore
+	queuePages      map[int64]int
+	queuePagesMu    sync.Mutex
+}
+
+type reasonPrompt struct {
+	chatID     int64
+	reviewerID int64
+	video      *QueuedVideo
+	timer      *time.Timer
 }
 
 type ApprovalRequest struct {
-	VideoPath   string
-	PreviewPath string
-	Title       string
-	Script      string
-	Tags        []string
+	VideoPath     string
+	PreviewPath   string
+	ThumbnailPath string
+	Topic         string
+	Title         string
+	Script        string
+	Description   string
+	Tags          []string
+	Priority      int
+	Account       string
+	Source        string
+	ForReview     bool
 }
 
 type ApprovalResult struct {
 	Approved   bool
 	Message    string
 	ReviewerID int64
+
+	video *QueuedVideo
 }
 
 func NewApprovalService(client *Client, dataDir string, defaultChatID int64, previewDuration float64) *ApprovalService {
@@ -59,17 +92,24 @@ func NewApprovalService(client *Client, dataDir string, defaultChatID int64, pre
 		previewDuration: previewDuration,
 		reviewers:       make(map[int64]Reviewer),
 		dataFile:        filepath.Join(dataDir, "reviewers.json"),
+		pendingFile:     filepath.Join(dataDir, "pending_video.json"),
 		stopPoll:        make(chan struct{}),
 		queue:           NewVideoQueue(dataDir),
 		resultChan:      make(chan *ApprovalResult, 1),
 		generationQueue: NewGenerationQueue(dataDir),
 		genRequestChan:  make(chan GenerationRequest, maxGenerationQueueSize),
+		reasonTimeout:   rejectReasonTimeout,
+		stats:           newStatsStore(dataDir),
+		queuePages:      make(map[int64]int),
 	}
 	svc.loadReviewers()
+	svc.restorePending()
 	return svc
 }
 
 func (s *ApprovalService) StartBot() {
+	s.resendDeletedPending()
+
 	s.pollWg.Add(1)
 	go s.pollCommands()
 }
@@ -101,6 +141,73 @@ func (s *ApprovalService) QueueVideo(video QueuedVideo) error {
 	return nil
 }
 
+func (s *ApprovalService) AttachPreview(videoPath, previewPath string) bool {
+	if previewPath == "" {
+		return false
+	}
+
+	attached := false
+	s.queue.Update(func(items []QueuedVideo) []QueuedVideo {
+		for i := range items {
+			if items[i].VideoPath == videoPath {
+				items[i].PreviewPath = previewPath
+				attached = true
+			}
+		}
+		return items
+	})
+	if attached {
+		slog.Debug("Preview attached to queued video", "path", previewPath)
+		return true
+	}
+
+	s.pendingMu.Lock()
+	if s.pendingVideo == nil || s.pendingVideo.VideoPath != videoPath {
+		s.pendingMu.Unlock()
+		slog.Debug("Discarding preview: video no longer awaiting review", "path", previewPath)
+		_ = os.Remove(previewPath)
+		return false
+	}
+	video := *s.pendingVideo
+	s.pendingMu.Unlock()
+
+	video.PreviewPath = previewPath
+	if video.MessageID != 0 && video.ChatID != 0 {
+		err := s.client.EditMessageVideo(video.ChatID, video.MessageID, previewPath, s.reviewCaption(&video), newReviewKeyboard(true))
+		if err != nil {
+			slog.Warn("Failed to replace review video with preview, keeping full video", "title", video.Title, "error", err)
+			_ = os.Remove(previewPath)
+			return false
+		}
+	}
+
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	if s.pendingVideo == nil || s.pendingVideo.VideoPath != videoPath {
+		slog.Debug("Discarding preview: video reviewed while the preview was sent", "path", previewPath)
+		_ = os.Remove(previewPath)
+		return false
+	}
+	s.pendingVideo.PreviewPath = previewPath
+	s.savePending()
+	slog.Info("Review video replaced with preview", "title", video.Title)
+	return true
+}
+
+func (s *ApprovalService) reviewCaption(video *QueuedVideo) string {
+	caption := fmt.Sprintf("%s\n\n📹 Video %d/%d remaining in queue", bold(video.Title), s.queue.Len()+1, maxQueueSize)
+	if video.PreviewPath != "" {
+		caption += fmt.Sprintf("\n\n⏱ Preview \\(%.0fs\\)", s.previewDuration)
+	}
+	return caption
+}
+
+func (s *ApprovalService) Requeue(video QueuedVideo) error {
+	video.MessageID = 0
+	video.ChatID = 0
+	return s.queue.Add(video)
+}
+
 func (s *ApprovalService) sendNextVideoTo(chatID int64) {
 	s.pendingMu.Lock()
 	if s.pendingVideo != nil {
@@ -119,19 +226,17 @@ func (s *ApprovalService) sendNextVideoTo(chatID int64) {
 	s.pendingVideo = video
 	s.pendingMu.Unlock()
 
+	s.sendPending(chatID, video)
+}
+
+func (s *ApprovalService) sendPending(chatID int64, video *QueuedVideo) {
 	videoToSend := video.VideoPath
 	if video.PreviewPath != "" {
 		videoToSend = video.PreviewPath
 	}
 	slog.Debug("Sending video for review", "title", video.Title, "path", videoToSend, "has_preview", video.PreviewPath != "")
 
-	caption := fmt.Sprintf("*%s*\n\n📹 Video %d/%d remaining in queue", video.Title, s.queue.Len()+1, maxQueueSize)
-	if video.PreviewPath != "" {
-		caption += fmt.Sprintf("\n\n⏱ Preview (%.0fs)", s.previewDuration)
-	}
-	keyboard := NewApprovalKeyboard(callbackApprove, callbackReject)
-
-	resp, err := s.client.SendVideo(chatID, videoToSend, caption, keyboard)
+	resp, err := s.client.SendVideo(chatID, videoToSend, s.reviewCaption(video), newReviewKeyboard(video.PreviewPath != ""))
 	if err != nil {
 		slog.Error("Failed to send video", "error", err)
 		s.pendingMu.Lock()
@@ -144,6 +249,7 @@ func (s *ApprovalService) sendNextVideoTo(chatID int64) {
 	s.pendingMu.Lock()
 	s.pendingVideo.MessageID = resp.MessageID
 	s.pendingVideo.ChatID = chatID
+	s.savePending()
 	s.pendingMu.Unlock()
 
 	slog.Info("Video sent for review", "title", video.Title, "chat_id", chatID, "message_id", resp.MessageID)
@@ -156,10 +262,14 @@ func (s *ApprovalService) notifyQueueStatus() {
 	count := s.queue.Len()
 	msg := fmt.Sprintf("📹 New video queued (%d/%d in queue)\n\nType /review to review.", count, maxQueueSize)
 	for _, reviewer := range s.reviewers {
-		_ = s.client.SendMessage(reviewer.ChatID, msg)
+		s.sendPlain(reviewer.ChatID, msg)
 	}
 }
 
+func (s *ApprovalService) sendPlain(chatID int64, text string) {
+	_ = s.client.SendMessage(chatID, escapeMarkdown(text))
+}
+
 func (s *ApprovalService) pollCommands() {
 	defer s.pollWg.Done()
 	slog.Info("Telegram bot started")
@@ -198,15 +308,29 @@ func (s *ApprovalService) handleUpdate(update Update) {
 	chat := update.Message.Chat
 	user := update.Message.From
 
+	if !strings.HasPrefix(text, "/") && s.captureRejectReason(chat.ID, text) {
+		return
+	}
+
 	switch {
 	case strings.HasPrefix(text, "/generate"):
 		s.handleGenerateCommand(chat, text)
+	case strings.HasPrefix(text, "/regenerate"):
+		s.handleRegenerateCommand(chat, text)
+	case strings.HasPrefix(text, "/settitle"):
+		s.handleSetTitleCommand(chat, text)
+	case strings.HasPrefix(text, "/settags"):
+		s.handleSetTagsCommand(chat, text)
 	case strings.HasPrefix(text, "/review"):
 		s.handleReviewCommand(chat, user)
+	case strings.HasPrefix(text, "/full"):
+		s.handleFullCommand(chat)
 	case strings.HasPrefix(text, "/queue"):
 		s.handleQueueCommand(chat)
 	case strings.HasPrefix(text, "/status"):
 		s.handleStatusCommand(chat)
+	case strings.HasPrefix(text, "/stats"):
+		s.handleStatsCommand(chat)
 	case strings.HasPrefix(text, "/stop"):
 		s.handleStopCommand(chat, user)
 	case strings.HasPrefix(text, "/help"), strings.HasPrefix(text, "/start"):
@@ -218,66 +342,141 @@ func (s *ApprovalService) handleHelpCommand(chat *Chat) {
 	msg := `*Craftstory Bot*
 
 *Commands:*
-/generate [topic] - Generate video (Reddit topic if empty)
-/status - Generation queue status
-/help - Show this message
+/generate \[@account\] \[topic\] \- Generate video \(Reddit topic if empty\)
+/status \- Generation queue status
+/stats \- Lifetime generation and review counts
+/help \- Show this message
 
 *Admin:*
-/review - Review next video
-/queue - Approval queue status
-/stop - Unsubscribe from notifications`
+/review \- Review next video
+/full \- Send the full\-quality pending video
+/regenerate \[reddit\] \- Rebuild the pending or last rejected video
+/settitle \<text\> \- Change the pending video's title
+/settags \<a,b,c\> \- Change the pending video's tags
+/queue \- Approval queue status
+/stop \- Unsubscribe from notifications`
 	_ = s.client.SendMessage(chat.ID, msg)
 }
 
 func (s *ApprovalService) handleGenerateCommand(chat *Chat, text string) {
-	topic := strings.TrimSpace(strings.TrimPrefix(text, "/generate"))
-	fromReddit := topic == ""
+	account, topic := parseGenerateArgs(strings.TrimPrefix(text, "/generate"))
 
-	if s.generationQueue.IsFull() {
-		_ = s.client.SendMessage(chat.ID, "Queue full. Please wait.")
+	s.enqueueGeneration(GenerationRequest{
+		Topic:      topic,
+		ChatID:     chat.ID,
+		FromReddit: topic == "",
+		Account:    account,
+	})
+}
+
+func parseGenerateArgs(args string) (string, string) {
+	args = strings.TrimSpace(args)
+	if !strings.HasPrefix(args, "@") {
+		return "", args
+	}
+	account, topic, _ := strings.Cut(args[1:], " ")
+	return account, strings.TrimSpace(topic)
+}
+
+func (s *ApprovalService) handleRegenerateCommand(chat *Chat, text string) {
+	if s.defaultChatID != 0 && chat.ID != s.defaultChatID {
+		s.sendPlain(chat.ID, "Review commands only available in admin chat.")
 		return
 	}
 
-	request := GenerationRequest{
-		Topic:      topic,
-		ChatID:     chat.ID,
+	fromReddit := strings.TrimSpace(strings.TrimPrefix(text, "/regenerate")) == "reddit"
+	s.regenerate(chat.ID, fromReddit)
+}
+
+func (s *ApprovalService) regenerate(chatID int64, fromReddit bool) {
+	s.pendingMu.Lock()
+	video := s.pendingVideo
+	cleared := video != nil
+	if cleared {
+		if s.pendingReason != nil {
+			s.pendingReason.timer.Stop()
+			s.pendingReason = nil
+		}
+		s.pendingVideo = nil
+		s.savePending()
+		s.lastRejected = video
+		slog.Info("Video rejected for regeneration", "title", video.Title)
+	} else {
+		video = s.lastRejected
+	}
+	s.pendingMu.Unlock()
+
+	if cleared {
+		s.resultChan <- &ApprovalResult{Approved: false, Message: regeneratedReason, video: video}
+		s.notifyRemaining(chatID)
+	}
+
+	if video == nil {
+		s.sendPlain(chatID, "No rejected video to regenerate.")
+		return
+	}
+
+	if video.Topic == "" && !fromReddit {
+		s.sendPlain(chatID, "Original topic unknown. Use /regenerate reddit for a new post.")
+		return
+	}
+
+	if s.enqueueGeneration(GenerationRequest{
+		Topic:      video.Topic,
+		ChatID:     chatID,
 		FromReddit: fromReddit,
+		Account:    video.Account,
+		ForReview:  true,
+	}) {
+		s.pendingMu.Lock()
+		if s.lastRejected == video {
+			s.lastRejected = nil
+		}
+		s.pendingMu.Unlock()
+	}
+}
+
+func (s *ApprovalService) enqueueGeneration(request GenerationRequest) bool {
+	if s.generationQueue.IsFull() {
+		s.sendPlain(request.ChatID, "Queue full. Please wait.")
+		return false
 	}
 
 	if err := s.generationQueue.Add(request); err != nil {
-		_ = s.client.SendMessage(chat.ID, fmt.Sprintf("Failed to queue: %s", err.Error()))
-		return
+		s.sendPlain(request.ChatID, fmt.Sprintf("Failed to queue: %s", err.Error()))
+		return false
 	}
 
 	position := s.generationQueue.Len()
 	var msg string
-	if fromReddit {
+	if request.FromReddit {
 		msg = fmt.Sprintf("Queued generation from Reddit\nPosition: %d", position)
 	} else {
-		msg = fmt.Sprintf("Queued generation\nTopic: %s\nPosition: %d", topic, position)
+		msg = fmt.Sprintf("Queued generation\nTopic: %s\nPosition: %d", request.Topic, position)
 	}
 
 	if s.generationQueue.IsGenerating() {
 		msg += "\n\nGenerating another video..."
 	}
 
-	_ = s.client.SendMessage(chat.ID, msg)
+	s.sendPlain(request.ChatID, msg)
 
 	select {
 	case s.genRequestChan <- request:
 	default:
 	}
+	return true
 }
 
 func (s *ApprovalService) handleStatusCommand(chat *Chat) {
 	requests := s.generationQueue.List()
 
 	if len(requests) == 0 {
-		_ = s.client.SendMessage(chat.ID, "Generation queue empty.\n\nUse /generate to create a video.")
+		s.sendPlain(chat.ID, "Generation queue empty.\n\nUse /generate to create a video.")
 		return
 	}
 
-	msg := fmt.Sprintf("*Generation Queue* (%d/%d)\n\n", len(requests), maxGenerationQueueSize)
+	msg := fmt.Sprintf("*Generation Queue* \\(%d/%d\\)\n\n", len(requests), maxGenerationQueueSize)
 	for i, req := range requests {
 		status := "⏳"
 		if req.Status == "generating" {
@@ -288,14 +487,22 @@ func (s *ApprovalService) handleStatusCommand(chat *Chat) {
 			topic = "(Reddit)"
 		}
 		age := time.Since(req.AddedAt).Round(time.Second)
-		msg += fmt.Sprintf("%s %d. %s (%v ago)\n", status, i+1, topic, age)
+		msg += escapeMarkdown(fmt.Sprintf("%s %d. %s (%v ago)\n", status, i+1, topic, age))
 	}
 	_ = s.client.SendMessage(chat.ID, msg)
 }
 
+func (s *ApprovalService) handleStatsCommand(chat *Chat) {
+	_ = s.client.SendMessage(chat.ID, s.stats.snapshot().String())
+}
+
+func (s *ApprovalService) Stats() Stats {
+	return s.stats.snapshot()
+}
+
 func (s *ApprovalService) handleReviewCommand(chat *Chat, user *User) {
 	if s.defaultChatID != 0 && chat.ID != s.defaultChatID {
-		_ = s.client.SendMessage(chat.ID, "Review commands only available in admin chat.")
+		s.sendPlain(chat.ID, "Review commands only available in admin chat.")
 		return
 	}
 
@@ -309,29 +516,128 @@ func (s *ApprovalService) handleReviewCommand(chat *Chat, user *User) {
 		s.reviewers[chat.ID] = reviewer
 		s.saveReviewers()
 		slog.Info("Reviewer registered", "name", user.FirstName, "chat_id", chat.ID)
-		_ = s.client.SendMessage(chat.ID, "Registered as reviewer.")
+		s.sendPlain(chat.ID, "Registered as reviewer.")
 	}
 	s.reviewersMu.Unlock()
 
 	s.pendingMu.Lock()
 	if s.pendingVideo != nil {
 		s.pendingMu.Unlock()
-		_ = s.client.SendMessage(chat.ID, "A video is being reviewed. Please wait.")
+		s.sendPlain(chat.ID, "A video is being reviewed. Please wait.")
 		return
 	}
 	s.pendingMu.Unlock()
 
 	if s.queue.Len() == 0 {
-		_ = s.client.SendMessage(chat.ID, "No videos in queue.")
+		s.sendPlain(chat.ID, "No videos in queue.")
 		return
 	}
 
 	s.sendNextVideoTo(chat.ID)
 }
 
+func (s *ApprovalService) handleFullCommand(chat *Chat) {
+	if s.defaultChatID != 0 && chat.ID != s.defaultChatID {
+		s.sendPlain(chat.ID, "Review commands only available in admin chat.")
+		return
+	}
+
+	s.pendingMu.Lock()
+	video := s.pendingVideo
+	s.pendingMu.Unlock()
+
+	if video == nil {
+		s.sendPlain(chat.ID, "No video pending review.")
+		return
+	}
+	s.sendFullVideo(chat.ID, video)
+}
+
+func (s *ApprovalService) sendFullVideo(chatID int64, video *QueuedVideo) {
+	info, err := os.Stat(video.VideoPath)
+	if err != nil {
+		_ = s.client.SendMessage(chatID, fmt.Sprintf("Full video not found\n\n`%s`", escapeCode(video.VideoPath)))
+		return
+	}
+
+	if info.Size() > maxBotUploadBytes {
+		msg := escapeMarkdown(fmt.Sprintf("Full video is %.1f MB, over Telegram's %d MB bot upload limit.",
+			float64(info.Size())/(1<<20), maxBotUploadBytes>>20))
+		msg += fmt.Sprintf("\n\nPath: `%s`", escapeCode(video.VideoPath))
+		_ = s.client.SendMessage(chatID, msg)
+		return
+	}
+
+	caption := bold(video.Title) + "\n\n🎬 Full video"
+	if _, err := s.client.SendVideo(chatID, video.VideoPath, caption, nil); err != nil {
+		slog.Error("Failed to send full video", "title", video.Title, "error", err)
+		s.sendPlain(chatID, fmt.Sprintf("Failed to send full video: %s", err.Error()))
+	}
+}
+
+func (s *ApprovalService) handleSetTitleCommand(chat *Chat, text string) {
+	title := strings.TrimSpace(strings.TrimPrefix(text, "/settitle"))
+	if title == "" {
+		s.sendPlain(chat.ID, "Usage: /settitle <text>")
+		return
+	}
+
+	s.editPending(chat, func(video *QueuedVideo) {
+		video.Title = title
+	})
+}
+
+func (s *ApprovalService) handleSetTagsCommand(chat *Chat, text string) {
+	tags := parseTags(strings.TrimPrefix(text, "/settags"))
+	if len(tags) == 0 {
+		s.sendPlain(chat.ID, "Usage: /settags <tag1,tag2,...>")
+		return
+	}
+
+	s.editPending(chat, func(video *QueuedVideo) {
+		video.Tags = tags
+	})
+}
+
+func (s *ApprovalService) editPending(chat *Chat, edit func(video *QueuedVideo)) {
+	if s.defaultChatID != 0 && chat.ID != s.defaultChatID {
+		s.sendPlain(chat.ID, "Review commands only available in admin chat.")
+		return
+	}
+
+	s.pendingMu.Lock()
+	if s.pendingVideo == nil {
+		s.pendingMu.Unlock()
+		s.sendPlain(chat.ID, "No video pending review.")
+		return
+	}
+	edit(s.pendingVideo)
+	s.savePending()
+	video := *s.pendingVideo
+	s.pendingMu.Unlock()
+
+	slog.Info("Pending video edited", "title", video.Title, "tags", video.Tags)
+
+	if video.MessageID != 0 && video.ChatID != 0 {
+		caption := bold(video.Title) + "\n\n✏️ Edited"
+		if len(video.Tags) > 0 {
+			caption += "\nTags: " + escapeMarkdown(strings.Join(video.Tags, ", "))
+		}
+		_ = s.client.EditMessageCaption(video.ChatID, video.MessageID, caption)
+		_ = s.client.EditMessageReplyMarkup(video.ChatID, video.MessageID, newReviewKeyboard(video.PreviewPath != ""))
+	}
+
+	s.sendPlain(chat.ID, "Metadata updated.")
+}
+
 func (s *ApprovalService) handleCallbackQuery(cb *CallbackQuery) {
 	slog.Debug("Callback received", "data", cb.Data, "from", cb.From.ID)
 
+	if cb.Data == callbackQueuePrev || cb.Data == callbackQueueNext {
+		s.handleQueuePage(cb)
+		return
+	}
+
 	if cb.Message != nil && s.defaultChatID != 0 && cb.Message.Chat.ID != s.defaultChatID {
 		slog.Debug("Callback rejected: wrong chat", "chat_id", cb.Message.Chat.ID, "expected", s.defaultChatID)
 		_ = s.client.AnswerCallbackQuery(cb.ID, "Not authorized")
@@ -348,51 +654,211 @@ func (s *ApprovalService) handleCallbackQuery(cb *CallbackQuery) {
 		return
 	}
 
+	_ = s.client.AnswerCallbackQuery(cb.ID, "")
+
+	if cb.Data == callbackFull {
+		if cb.Message != nil {
+			s.sendFullVideo(cb.Message.Chat.ID, video)
+		}
+		return
+	}
+
+	if cb.Data == callbackRegenerate {
+		if cb.Message == nil {
+			return
+		}
+		_ = s.client.EditMessageReplyMarkup(cb.Message.Chat.ID, cb.Message.MessageID, nil)
+		caption := bold(video.Title) + "\n\n🔄 Regenerating"
+		_ = s.client.EditMessageCaption(cb.Message.Chat.ID, cb.Message.MessageID, caption)
+		s.regenerate(cb.Message.Chat.ID, false)
+		return
+	}
+
 	approved := cb.Data == callbackApprove
 	slog.Info("Video decision", "approved", approved, "title", video.Title)
-
-	_ = s.client.AnswerCallbackQuery(cb.ID, "")
+	s.stats.update(func(stats *Stats) {
+		if approved {
+			stats.Approved++
+		} else {
+			stats.Rejected++
+		}
+	})
 
 	if cb.Message != nil {
 		_ = s.client.EditMessageReplyMarkup(cb.Message.Chat.ID, cb.Message.MessageID, nil)
 
 		if approved {
-			caption := fmt.Sprintf("*%s*\n\n⏳ Uploading...", video.Title)
+			caption := bold(video.Title) + "\n\n⏳ Uploading\\.\\.\\."
 			_ = s.client.EditMessageCaption(cb.Message.Chat.ID, cb.Message.MessageID, caption)
 		} else {
-			caption := fmt.Sprintf("*%s*\n\n❌ Rejected", video.Title)
+			caption := bold(video.Title) + "\n\n❌ Rejected"
 			_ = s.client.EditMessageCaption(cb.Message.Chat.ID, cb.Message.MessageID, caption)
 		}
 	}
 
-	result := &ApprovalResult{
+	if !approved {
+		s.pendingMu.Lock()
+		s.lastRejected = video
+		s.pendingMu.Unlock()
+	}
+
+	if !approved && cb.Message != nil {
+		s.promptRejectReason(cb.Message.Chat.ID, cb.From.ID, video)
+		return
+	}
+
+	s.resultChan <- &ApprovalResult{
 		Approved:   approved,
 		ReviewerID: cb.From.ID,
 	}
 
-	s.resultChan <- result
+	if cb.Message != nil {
+		s.notifyRemaining(cb.Message.Chat.ID)
+	}
+}
+
+func (s *ApprovalService) promptRejectReason(chatID, reviewerID int64, video *QueuedVideo) {
+	prompt := &reasonPrompt{
+		chatID:     chatID,
+		reviewerID: reviewerID,
+		video:      video,
+	}
+
+	s.pendingMu.Lock()
+	s.pendingReason = prompt
+	prompt.timer = time.AfterFunc(s.reasonTimeout, func() {
+		s.completeRejection(prompt, noReasonGiven)
+	})
+	s.pendingMu.Unlock()
+
+	msg := fmt.Sprintf("Why was this rejected? Reply with a short reason (skipped after %v).", s.reasonTimeout)
+	s.sendPlain(chatID, msg)
+}
+
+func (s *ApprovalService) captureRejectReason(chatID int64, text string) bool {
+	s.pendingMu.Lock()
+	prompt := s.pendingReason
+	s.pendingMu.Unlock()
+
+	if prompt == nil || prompt.chatID != chatID {
+		return false
+	}
+
+	if s.completeRejection(prompt, text) {
+		s.sendPlain(chatID, "Rejection reason recorded.")
+	}
+	return true
+}
+
+func (s *ApprovalService) completeRejection(prompt *reasonPrompt, reason string) bool {
+	s.pendingMu.Lock()
+	if s.pendingReason != prompt || s.pendingVideo != prompt.video {
+		s.pendingMu.Unlock()
+		return false
+	}
+	s.pendingReason = nil
+	prompt.timer.Stop()
+	s.pendingVideo = nil
+	s.savePending()
+	s.pendingMu.Unlock()
+
+	slog.Info("Video rejected", "title", prompt.video.Title, "reason", reason, "reviewer", prompt.reviewerID)
+
+	s.resultChan <- &ApprovalResult{
+		Approved:   false,
+		Message:    reason,
+		ReviewerID: prompt.reviewerID,
+		video:      prompt.video,
+	}
+
+	s.notifyRemaining(prompt.chatID)
+	return true
+}
 
+func (s *ApprovalService) notifyRemaining(chatID int64) {
 	remaining := s.queue.Len()
-	if remaining > 0 && cb.Message != nil {
+	if remaining > 0 {
 		msg := fmt.Sprintf("%d video(s) remaining. Type /review to continue.", remaining)
-		_ = s.client.SendMessage(cb.Message.Chat.ID, msg)
+		s.sendPlain(chatID, msg)
 	}
 }
 
 func (s *ApprovalService) handleQueueCommand(chat *Chat) {
 	videos := s.queue.List()
 	if len(videos) == 0 {
-		_ = s.client.SendMessage(chat.ID, "Approval queue empty.")
+		s.sendPlain(chat.ID, "Approval queue empty.")
+		return
+	}
+
+	s.queuePagesMu.Lock()
+	s.queuePages[chat.ID] = 0
+	s.queuePagesMu.Unlock()
+
+	msg, keyboard, _ := renderQueuePage(videos, 0)
+	_ = s.client.SendMessageWithKeyboard(chat.ID, msg, keyboard)
+}
+
+func (s *ApprovalService) handleQueuePage(cb *CallbackQuery) {
+	_ = s.client.AnswerCallbackQuery(cb.ID, "")
+	if cb.Message == nil {
 		return
 	}
+	chatID := cb.Message.Chat.ID
 
-	msg := fmt.Sprintf("*Approval Queue* (%d/%d)\n\n", len(videos), maxQueueSize)
-	for i, v := range videos {
+	s.queuePagesMu.Lock()
+	page := s.queuePages[chatID]
+	if cb.Data == callbackQueueNext {
+		page++
+	} else {
+		page--
+	}
+
+	videos := s.queue.List()
+	msg, keyboard, page := renderQueuePage(videos, page)
+	s.queuePages[chatID] = page
+	s.queuePagesMu.Unlock()
+
+	if len(videos) == 0 {
+		msg = escapeMarkdown("Approval queue empty.")
+	}
+	_ = s.client.EditMessageText(chatID, cb.Message.MessageID, msg, keyboard)
+}
+
+func renderQueuePage(videos []QueuedVideo, page int) (string, *InlineKeyboard, int) {
+	pages := max((len(videos)+queuePageSize-1)/queuePageSize, 1)
+	page = min(max(page, 0), pages-1)
+
+	start := page * queuePageSize
+	end := min(start+queuePageSize, len(videos))
+
+	msg := fmt.Sprintf("*Approval Queue* \\(%d/%d\\)\n\n", len(videos), maxQueueSize)
+	for i := start; i < end; i++ {
+		v := videos[i]
 		age := time.Since(v.AddedAt).Round(time.Minute)
-		msg += fmt.Sprintf("%d. %s (%v ago)\n", i+1, v.Title, age)
+		marker := ""
+		if v.Priority >= priorityRequested {
+			marker = " ⭐"
+		}
+		msg += escapeMarkdown(fmt.Sprintf("%d. %s%s (%v ago)\n", i+1, v.Title, marker, age))
 	}
-	msg += "\nType /review to review."
-	_ = s.client.SendMessage(chat.ID, msg)
+	if pages > 1 {
+		msg += fmt.Sprintf("\nPage %d/%d", page+1, pages)
+	}
+	msg += "\nType /review to review\\."
+
+	var row []InlineButton
+	if page > 0 {
+		row = append(row, InlineButton{Text: "◀ Prev", CallbackData: callbackQueuePrev})
+	}
+	if page < pages-1 {
+		row = append(row, InlineButton{Text: "Next ▶", CallbackData: callbackQueueNext})
+	}
+
+	keyboard := &InlineKeyboard{InlineKeyboard: [][]InlineButton{}}
+	if len(row) > 0 {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
+	}
+	return msg, keyboard, page
 }
 
 func (s *ApprovalService) handleStopCommand(chat *Chat, user *User) {
@@ -402,15 +868,19 @@ func (s *ApprovalService) handleStopCommand(chat *Chat, user *User) {
 	s.saveReviewers()
 
 	slog.Info("Reviewer unregistered", "name", user.FirstName, "chat_id", chat.ID)
-	_ = s.client.SendMessage(chat.ID, "Removed from reviewers.")
+	s.sendPlain(chat.ID, "Removed from reviewers.")
 }
 
 func (s *ApprovalService) WaitForResult(ctx context.Context) (*ApprovalResult, *QueuedVideo, error) {
 	select {
 	case result := <-s.resultChan:
+		if result.video != nil {
+			return result, result.video, nil
+		}
 		s.pendingMu.Lock()
 		video := s.pendingVideo
 		s.pendingVideo = nil
+		s.savePending()
 		s.pendingMu.Unlock()
 		return result, video, nil
 	case <-ctx.Done():
@@ -420,29 +890,45 @@ func (s *ApprovalService) WaitForResult(ctx context.Context) (*ApprovalResult, *
 
 func (s *ApprovalService) RequestApproval(ctx context.Context, request ApprovalRequest) (*ApprovalResult, error) {
 	video := QueuedVideo{
-		VideoPath:   request.VideoPath,
-		PreviewPath: request.PreviewPath,
-		Title:       request.Title,
-		Script:      request.Script,
-		Tags:        request.Tags,
+		VideoPath:     request.VideoPath,
+		PreviewPath:   request.PreviewPath,
+		ThumbnailPath: request.ThumbnailPath,
+		Topic:         request.Topic,
+		Title:         request.Title,
+		Script:        request.Script,
+		Description:   request.Description,
+		Tags:          request.Tags,
+		Priority:      request.Priority,
+		Account:       request.Account,
+		Source:        request.Source,
 	}
 
 	if err := s.QueueVideo(video); err != nil {
 		return nil, err
 	}
+	s.stats.update(func(stats *Stats) { stats.Generated++ })
 
 	return &ApprovalResult{Approved: false, Message: "queued"}, nil
 }
 
 func (s *ApprovalService) NotifyUploadComplete(title, videoURL string, video *QueuedVideo) {
-	caption := fmt.Sprintf("*%s*\n\n✅ Uploaded\n%s", title, videoURL)
-	fallback := fmt.Sprintf("*%s* uploaded\n\n%s", title, videoURL)
+	s.stats.update(func(stats *Stats) { stats.Uploaded++ })
+	caption := fmt.Sprintf("%s\n\n✅ Uploaded\n%s", bold(title), escapeMarkdown(videoURL))
+	fallback := fmt.Sprintf("%s uploaded\n\n%s", bold(title), escapeMarkdown(videoURL))
 	s.notifyResult(video, caption, fallback)
 }
 
 func (s *ApprovalService) NotifyUploadFailed(title string, err error, video *QueuedVideo) {
-	caption := fmt.Sprintf("*%s*\n\n❌ Upload failed: %s", title, err.Error())
-	fallback := fmt.Sprintf("Failed to upload *%s*\n\n%s", title, err.Error())
+	s.stats.update(func(stats *Stats) { stats.UploadFailed++ })
+	caption := fmt.Sprintf("%s\n\n❌ Upload failed: %s", bold(title), escapeMarkdown(err.Error()))
+	fallback := fmt.Sprintf("Failed to upload %s\n\n%s", bold(title), escapeMarkdown(err.Error()))
+	s.notifyResult(video, caption, fallback)
+}
+
+func (s *ApprovalService) NotifyUploadsPaused(title string, until time.Time, video *QueuedVideo) {
+	resume := escapeMarkdown(until.Local().Format("Jan 2 15:04 MST"))
+	caption := fmt.Sprintf("%s\n\n⏸ Upload quota exhausted\\. Uploads resume %s and the video returns to the queue then\\.", bold(title), resume)
+	fallback := fmt.Sprintf("Upload quota exhausted, %s was not uploaded\\. Uploads resume %s\\.", bold(title), resume)
 	s.notifyResult(video, caption, fallback)
 }
 
@@ -481,16 +967,24 @@ func (s *ApprovalService) NotifyGenerating(chatID int64, topic string) {
 	} else {
 		msg = fmt.Sprintf("Generating video...\n\nTopic: %s\n\nThis may take a few minutes.", topic)
 	}
-	_ = s.client.SendMessage(chatID, msg)
+	s.sendPlain(chatID, msg)
 }
 
-func (s *ApprovalService) NotifyGenerationComplete(chatID int64, videoPath, previewPath, title, script string, tags []string) {
-	caption := fmt.Sprintf("*%s*\n\nGenerated successfully.", title)
+func (s *ApprovalService) NotifyGenerationComplete(chatID int64, request ApprovalRequest) {
+	if request.ForReview {
+		request.Priority = priorityRequested
+		if _, err := s.RequestApproval(context.Background(), request); err != nil {
+			slog.Error("Failed to queue regenerated video for approval", "error", err)
+		}
+		return
+	}
+
+	caption := bold(request.Title) + "\n\nGenerated successfully\\."
 
-	videoToSend := videoPath
-	if previewPath != "" {
-		videoToSend = previewPath
-		caption += fmt.Sprintf("\n\n⏱ Preview (%.0fs)", s.previewDuration)
+	videoToSend := request.VideoPath
+	if request.PreviewPath != "" {
+		videoToSend = request.PreviewPath
+		caption += fmt.Sprintf("\n\n⏱ Preview \\(%.0fs\\)", s.previewDuration)
 	}
 
 	_, err := s.client.SendVideo(chatID, videoToSend, caption, nil)
@@ -498,23 +992,20 @@ func (s *ApprovalService) NotifyGenerationComplete(chatID int64, videoPath, prev
 		slog.Error("Failed to send video to requester", "chat_id", chatID, "error", err)
 	}
 
-	if s.defaultChatID != 0 && chatID != s.defaultChatID {
-		video := QueuedVideo{
-			VideoPath:   videoPath,
-			PreviewPath: previewPath,
-			Title:       title,
-			Script:      script,
-			Tags:        tags,
-		}
-		if err := s.QueueVideo(video); err != nil {
-			slog.Error("Failed to queue video for approval", "error", err)
-		}
+	if s.defaultChatID == 0 || chatID == s.defaultChatID {
+		s.stats.update(func(stats *Stats) { stats.Generated++ })
+		return
+	}
+
+	request.Priority = priorityRequested
+	if _, err := s.RequestApproval(context.Background(), request); err != nil {
+		slog.Error("Failed to queue video for approval", "error", err)
 	}
 }
 
 func (s *ApprovalService) NotifyGenerationFailed(chatID int64, errMsg string) {
 	msg := fmt.Sprintf("Generation failed\n\n%s", errMsg)
-	_ = s.client.SendMessage(chatID, msg)
+	s.sendPlain(chatID, msg)
 }
 
 func (s *ApprovalService) CompleteGeneration(chatID int64) {
@@ -525,6 +1016,84 @@ func (s *ApprovalService) FailGeneration(chatID int64) {
 	s.generationQueue.Fail(chatID)
 }
 
+func (s *ApprovalService) savePending() {
+	if s.pendingVideo == nil {
+		_ = os.Remove(s.pendingFile)
+		return
+	}
+
+	data, err := json.MarshalIndent(s.pendingVideo, "", "  ")
+	if err != nil {
+		return
+	}
+
+	_ = os.MkdirAll(filepath.Dir(s.pendingFile), 0755)
+	_ = os.WriteFile(s.pendingFile, data, 0644)
+}
+
+func (s *ApprovalService) restorePending() {
+	data, err := os.ReadFile(s.pendingFile)
+	if err != nil {
+		return
+	}
+
+	var video QueuedVideo
+	if err := json.Unmarshal(data, &video); err != nil {
+		slog.Warn("Failed to parse pending video", "path", s.pendingFile, "error", err)
+		_ = os.Remove(s.pendingFile)
+		return
+	}
+
+	if _, err := os.Stat(video.VideoPath); err != nil {
+		slog.Warn("Dropping pending video: file missing", "title", video.Title, "path", video.VideoPath)
+		_ = os.Remove(s.pendingFile)
+		return
+	}
+
+	s.pendingVideo = &video
+	slog.Info("Restored pending video", "title", video.Title, "chat_id", video.ChatID, "message_id", video.MessageID)
+}
+
+func (s *ApprovalService) resendDeletedPending() {
+	s.pendingMu.Lock()
+	video := s.pendingVideo
+	if video == nil {
+		s.pendingMu.Unlock()
+		return
+	}
+	chatID, messageID := video.ChatID, video.MessageID
+	s.pendingMu.Unlock()
+
+	if chatID != 0 && messageID != 0 {
+		err := s.client.EditMessageReplyMarkup(chatID, messageID, newReviewKeyboard(video.PreviewPath != ""))
+		if err == nil || strings.Contains(err.Error(), "message is not modified") {
+			return
+		}
+		slog.Warn("Review message for pending video is gone, sending it again", "title", video.Title, "error", err)
+	}
+	if chatID == 0 {
+		chatID = s.defaultChatID
+	}
+
+	s.pendingMu.Lock()
+	if s.pendingVideo != video {
+		s.pendingMu.Unlock()
+		return
+	}
+	if chatID == 0 {
+		s.pendingVideo = nil
+		s.savePending()
+		s.pendingMu.Unlock()
+		_ = s.queue.Add(*video)
+		slog.Info("Returned pending video to queue", "title", video.Title)
+		return
+	}
+	video.MessageID, video.ChatID = 0, 0
+	s.pendingMu.Unlock()
+
+	s.sendPending(chatID, video)
+}
+
 func (s *ApprovalService) loadReviewers() {
 	data, err := os.ReadFile(s.dataFile)
 	if err != nil {
@@ -560,3 +1129,23 @@ func (s *ApprovalService) saveReviewers() {
 	_ = os.MkdirAll(filepath.Dir(s.dataFile), 0755)
 	_ = os.WriteFile(s.dataFile, data, 0644)
 }
+
+func newReviewKeyboard(hasPreview bool) *InlineKeyboard {
+	keyboard := NewApprovalKeyboard(callbackApprove, callbackReject)
+	row := []InlineButton{{Text: "🔄 Regenerate", CallbackData: callbackRegenerate}}
+	if hasPreview {
+		row = append(row, InlineButton{Text: "🎬 Full video", CallbackData: callbackFull})
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
+	return keyboard
+}
+
+func parseTags(csv string) []string {
+	var tags []string
+	for _, tag := range strings.Split(csv, ",") {
+		if trimmed := strings.TrimSpace(tag); trimmed != "" {
+			tags = append(tags, trimmed)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,1011 @@
+package telegram
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestApprovalService(t *testing.T) *ApprovalService {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewApprovalService(newTestClient(server), t.TempDir(), 100, 30)
+}
+
+func rejectCallback() Update {
+	return Update{
+		CallbackQuery: &CallbackQuery{
+			ID:      "cb1",
+			From:    &User{ID: 7},
+			Message: &Message{MessageID: 1, Chat: &Chat{ID: 100}},
+			Data:    callbackReject,
+		},
+	}
+}
+
+func textMessage(chatID int64, text string) Update {
+	return Update{
+		Message: &Message{
+			MessageID: 2,
+			From:      &User{ID: 7, FirstName: "Reviewer"},
+			Chat:      &Chat{ID: chatID},
+			Text:      text,
+		},
+	}
+}
+
+func waitForResult(t *testing.T, svc *ApprovalService) *ApprovalResult {
+	t.Helper()
+	select {
+	case result := <-svc.resultChan:
+		return result
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for approval result")
+		return nil
+	}
+}
+
+func TestRejectWithReason(t *testing.T) {
+	svc := newTestApprovalService(t)
+	svc.pendingVideo = &QueuedVideo{Title: "Test Video"}
+
+	svc.handleUpdate(rejectCallback())
+
+	select {
+	case <-svc.resultChan:
+		t.Fatal("result sent before reason was given")
+	default:
+	}
+
+	svc.handleUpdate(textMessage(100, "hook was too slow"))
+
+	result := waitForResult(t, svc)
+	if result.Approved {
+		t.Error("expected rejection")
+	}
+	if result.Message != "hook was too slow" {
+		t.Errorf("Message = %q, want %q", result.Message, "hook was too slow")
+	}
+	if result.ReviewerID != 7 {
+		t.Errorf("ReviewerID = %d, want 7", result.ReviewerID)
+	}
+	if svc.pendingReason != nil {
+		t.Error("expected reason prompt to be cleared")
+	}
+}
+
+func TestRejectReasonTimeout(t *testing.T) {
+	svc := newTestApprovalService(t)
+	svc.reasonTimeout = 10 * time.Millisecond
+	svc.pendingVideo = &QueuedVideo{Title: "Test Video"}
+
+	svc.handleUpdate(rejectCallback())
+
+	result := waitForResult(t, svc)
+	if result.Message != noReasonGiven {
+		t.Errorf("Message = %q, want %q", result.Message, noReasonGiven)
+	}
+
+	svc.handleUpdate(textMessage(100, "late reason"))
+
+	select {
+	case <-svc.resultChan:
+		t.Error("late reason produced a second result")
+	default:
+	}
+}
+
+func TestRejectReasonRace(t *testing.T) {
+	svc := newTestApprovalService(t)
+	svc.reasonTimeout = time.Millisecond
+	svc.pendingVideo = &QueuedVideo{Title: "Test Video"}
+
+	svc.handleUpdate(rejectCallback())
+	svc.pendingMu.Lock()
+	prompt := svc.pendingReason
+	svc.pendingMu.Unlock()
+
+	var wg sync.WaitGroup
+	var completed atomic.Int32
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if svc.completeRejection(prompt, fmt.Sprintf("reason %d", i)) {
+				completed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	time.Sleep(10 * time.Millisecond)
+
+	result, video, err := svc.WaitForResult(t.Context())
+	if err != nil {
+		t.Fatalf("WaitForResult() error = %v", err)
+	}
+	if result.Approved || video == nil || video.Title != "Test Video" {
+		t.Errorf("WaitForResult() = %+v, %+v, want rejected Test Video", result, video)
+	}
+	select {
+	case extra := <-svc.resultChan:
+		t.Errorf("second result sent: %+v", extra)
+	default:
+	}
+	if got := completed.Load(); got > 1 {
+		t.Errorf("completeRejection() succeeded %d times, want at most 1", got)
+	}
+	if svc.pendingVideo != nil {
+		t.Error("expected pending video to be cleared")
+	}
+}
+
+func TestRejectReasonIgnoresOtherChats(t *testing.T) {
+	svc := newTestApprovalService(t)
+	svc.pendingVideo = &QueuedVideo{Title: "Test Video"}
+
+	svc.handleUpdate(rejectCallback())
+	svc.handleUpdate(textMessage(200, "not the reviewer"))
+
+	select {
+	case <-svc.resultChan:
+		t.Fatal("message from another chat completed the rejection")
+	default:
+	}
+
+	svc.handleUpdate(textMessage(100, "bad audio"))
+	if result := waitForResult(t, svc); result.Message != "bad audio" {
+		t.Errorf("Message = %q, want %q", result.Message, "bad audio")
+	}
+}
+
+func TestRegenerateCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		chatID         int64
+		topic          string
+		wantQueued     bool
+		wantFromReddit bool
+	}{
+		{
+			name:       "sameTopic",
+			text:       "/regenerate",
+			chatID:     100,
+			topic:      "Why cats purr",
+			wantQueued: true,
+		},
+		{
+			name:           "newRedditPost",
+			text:           "/regenerate reddit",
+			chatID:         100,
+			topic:          "Why cats purr",
+			wantQueued:     true,
+			wantFromReddit: true,
+		},
+		{
+			name:       "unknownTopic",
+			text:       "/regenerate",
+			chatID:     100,
+			wantQueued: false,
+		},
+		{
+			name:       "notAdminChat",
+			text:       "/regenerate",
+			chatID:     200,
+			topic:      "Why cats purr",
+			wantQueued: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestApprovalService(t)
+			svc.pendingVideo = &QueuedVideo{Title: "Test Video", Topic: tt.topic}
+
+			svc.handleUpdate(textMessage(tt.chatID, tt.text))
+
+			requests := svc.generationQueue.List()
+			if !tt.wantQueued {
+				if len(requests) != 0 {
+					t.Errorf("expected no generation request, got %d", len(requests))
+				}
+				return
+			}
+
+			if len(requests) != 1 {
+				t.Fatalf("expected 1 generation request, got %d", len(requests))
+			}
+			if requests[0].Topic != tt.topic {
+				t.Errorf("Topic = %q, want %q", requests[0].Topic, tt.topic)
+			}
+			if requests[0].FromReddit != tt.wantFromReddit {
+				t.Errorf("FromReddit = %v, want %v", requests[0].FromReddit, tt.wantFromReddit)
+			}
+			if !requests[0].ForReview {
+				t.Error("expected regenerated request to be marked for review")
+			}
+			if svc.pendingVideo != nil {
+				t.Error("expected pending video to be cleared")
+			}
+
+			result, video, err := svc.WaitForResult(t.Context())
+			if err != nil {
+				t.Fatalf("WaitForResult() error = %v", err)
+			}
+			if result.Approved || video == nil || video.Title != "Test Video" {
+				t.Errorf("WaitForResult() = %+v, %+v, want rejected Test Video", result, video)
+			}
+		})
+	}
+}
+
+func TestRegeneratedVideoQueuedForReview(t *testing.T) {
+	svc := newTestApprovalService(t)
+
+	svc.NotifyGenerationComplete(100, ApprovalRequest{VideoPath: "/tmp/regen.mp4", Title: "Regenerated", ForReview: true})
+
+	videos := svc.queue.List()
+	pending := svc.pendingVideo
+	if len(videos) == 0 && pending == nil {
+		t.Fatal("regenerated video from the admin chat was not queued for approval")
+	}
+	if pending != nil && pending.Title != "Regenerated" {
+		t.Errorf("pending video = %q, want %q", pending.Title, "Regenerated")
+	}
+}
+
+func TestRegenerateAfterRejection(t *testing.T) {
+	svc := newTestApprovalService(t)
+	svc.pendingVideo = &QueuedVideo{Title: "Test Video", Topic: "Why cats purr"}
+
+	svc.handleUpdate(rejectCallback())
+	svc.handleUpdate(textMessage(100, "boring"))
+	if _, _, err := svc.WaitForResult(t.Context()); err != nil {
+		t.Fatalf("WaitForResult() error = %v", err)
+	}
+
+	svc.handleUpdate(textMessage(100, "/regenerate"))
+
+	req, err := svc.WaitForGenerationRequest(t.Context())
+	if err != nil {
+		t.Fatalf("WaitForGenerationRequest() error = %v", err)
+	}
+	if req.Topic != "Why cats purr" {
+		t.Errorf("Topic = %q, want %q", req.Topic, "Why cats purr")
+	}
+}
+
+func TestEditPendingMetadata(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		chatID    int64
+		wantTitle string
+		wantTags  []string
+	}{
+		{
+			name:      "setTitle",
+			text:      "/settitle A Better Title",
+			chatID:    100,
+			wantTitle: "A Better Title",
+			wantTags:  []string{"old"},
+		},
+		{
+			name:      "setTags",
+			text:      "/settags cats, facts ,,shorts",
+			chatID:    100,
+			wantTitle: "Original",
+			wantTags:  []string{"cats", "facts", "shorts"},
+		},
+		{
+			name:      "emptyTitleIgnored",
+			text:      "/settitle   ",
+			chatID:    100,
+			wantTitle: "Original",
+			wantTags:  []string{"old"},
+		},
+		{
+			name:      "notAdminChat",
+			text:      "/settitle Hijacked",
+			chatID:    200,
+			wantTitle: "Original",
+			wantTags:  []string{"old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestApprovalService(t)
+			svc.pendingVideo = &QueuedVideo{Title: "Original", Tags: []string{"old"}}
+
+			svc.handleUpdate(textMessage(tt.chatID, tt.text))
+
+			if svc.pendingVideo.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", svc.pendingVideo.Title, tt.wantTitle)
+			}
+			if strings.Join(svc.pendingVideo.Tags, ",") != strings.Join(tt.wantTags, ",") {
+				t.Errorf("Tags = %v, want %v", svc.pendingVideo.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestEditPendingPersistsAcrossRestart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	svc := NewApprovalService(newTestClient(server), dataDir, 100, 30)
+	svc.pendingVideo = &QueuedVideo{Title: "Original", VideoPath: writeTestFile(t, dataDir), MessageID: 5, ChatID: 100}
+
+	svc.handleUpdate(textMessage(100, "/settitle Edited Title"))
+
+	restarted := NewApprovalService(newTestClient(server), dataDir, 100, 30)
+	if restarted.pendingVideo == nil {
+		t.Fatal("expected pending video to be restored")
+	}
+	if restarted.pendingVideo.Title != "Edited Title" {
+		t.Errorf("Title = %q, want %q", restarted.pendingVideo.Title, "Edited Title")
+	}
+}
+
+func TestPendingVideoRestore(t *testing.T) {
+	tests := []struct {
+		name        string
+		video       QueuedVideo
+		missingFile bool
+		wantPending bool
+		wantQueued  int
+	}{
+		{
+			name:        "messageStillValid",
+			video:       QueuedVideo{Title: "Test", MessageID: 5, ChatID: 100},
+			wantPending: true,
+		},
+		{
+			name:        "videoFileDeleted",
+			video:       QueuedVideo{Title: "Test", MessageID: 5, ChatID: 100},
+			missingFile: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataDir := t.TempDir()
+			svc := NewApprovalService(NewClient("test-token"), dataDir, 0, 30)
+
+			video := tt.video
+			video.VideoPath = writeTestFile(t, dataDir)
+			if tt.missingFile {
+				_ = os.Remove(video.VideoPath)
+			}
+			svc.pendingVideo = &video
+			svc.savePending()
+
+			restored := NewApprovalService(NewClient("test-token"), dataDir, 0, 30)
+
+			if (restored.pendingVideo != nil) != tt.wantPending {
+				t.Errorf("pending restored = %v, want %v", restored.pendingVideo != nil, tt.wantPending)
+			}
+			if tt.wantPending && restored.pendingVideo.VideoPath != video.VideoPath {
+				t.Errorf("VideoPath = %q, want %q", restored.pendingVideo.VideoPath, video.VideoPath)
+			}
+			if got := restored.Queue().Len(); got != tt.wantQueued {
+				t.Errorf("queue length = %d, want %d", got, tt.wantQueued)
+			}
+
+			_, err := os.Stat(filepath.Join(dataDir, "pending_video.json"))
+			if tt.wantPending && err != nil {
+				t.Error("expected pending file to be kept")
+			}
+			if !tt.wantPending && err == nil {
+				t.Error("expected pending file to be removed")
+			}
+		})
+	}
+}
+
+func TestResendDeletedPending(t *testing.T) {
+	tests := []struct {
+		name          string
+		video         QueuedVideo
+		defaultChatID int64
+		editResponse  string
+		wantSent      bool
+		wantMessageID int
+		wantQueued    int
+	}{
+		{
+			name:          "messageStillExists",
+			video:         QueuedVideo{Title: "Test", MessageID: 5, ChatID: 100},
+			editResponse:  `{"ok":true,"result":true}`,
+			wantMessageID: 5,
+		},
+		{
+			name:          "messageNotModified",
+			video:         QueuedVideo{Title: "Test", MessageID: 5, ChatID: 100},
+			editResponse:  `{"ok":false,"description":"Bad Request: message is not modified"}`,
+			wantMessageID: 5,
+		},
+		{
+			name:          "messageDeleted",
+			video:         QueuedVideo{Title: "Test", MessageID: 5, ChatID: 100},
+			editResponse:  `{"ok":false,"description":"Bad Request: message to edit not found"}`,
+			wantSent:      true,
+			wantMessageID: 42,
+		},
+		{
+			name:          "neverSent",
+			video:         QueuedVideo{Title: "Test"},
+			defaultChatID: 100,
+			wantSent:      true,
+			wantMessageID: 42,
+		},
+		{
+			name:       "neverSentNoChat",
+			video:      QueuedVideo{Title: "Test"},
+			wantQueued: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:] {
+				case "editMessageReplyMarkup":
+					if strings.Contains(tt.editResponse, `"ok":false`) {
+						w.WriteHeader(http.StatusBadRequest)
+					}
+					_, _ = w.Write([]byte(tt.editResponse))
+				case "sendVideo":
+					sent++
+					_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+				default:
+					_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+				}
+			}))
+			defer server.Close()
+
+			dataDir := t.TempDir()
+			video := tt.video
+			video.VideoPath = writeTestFile(t, dataDir)
+			data, _ := json.Marshal(video)
+			if err := os.WriteFile(filepath.Join(dataDir, "pending_video.json"), data, 0644); err != nil {
+				t.Fatalf("failed to write pending video: %v", err)
+			}
+
+			svc := NewApprovalService(newTestClient(server), dataDir, tt.defaultChatID, 30)
+			svc.resendDeletedPending()
+
+			if (sent > 0) != tt.wantSent {
+				t.Errorf("sendVideo called %d times, want sent %v", sent, tt.wantSent)
+			}
+			if tt.wantQueued > 0 {
+				if svc.pendingVideo != nil {
+					t.Error("pendingVideo should be cleared when it cannot be sent")
+				}
+			} else if svc.pendingVideo == nil || svc.pendingVideo.MessageID != tt.wantMessageID {
+				t.Errorf("pendingVideo = %+v, want message ID %d", svc.pendingVideo, tt.wantMessageID)
+			}
+			if got := svc.Queue().Len(); got != tt.wantQueued {
+				t.Errorf("queue length = %d, want %d", got, tt.wantQueued)
+			}
+		})
+	}
+}
+
+func writeTestFile(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "video.mp4")
+	if err := os.WriteFile(path, []byte("video"), 0644); err != nil {
+		t.Fatalf("failed to write video: %v", err)
+	}
+	return path
+}
+
+func TestEditedMetadataReturnedOnApproval(t *testing.T) {
+	svc := newTestApprovalService(t)
+	svc.pendingVideo = &QueuedVideo{Title: "Original"}
+
+	svc.handleUpdate(textMessage(100, "/settags a,b"))
+	svc.handleUpdate(Update{
+		CallbackQuery: &CallbackQuery{
+			ID:      "cb1",
+			From:    &User{ID: 7},
+			Message: &Message{MessageID: 1, Chat: &Chat{ID: 100}},
+			Data:    callbackApprove,
+		},
+	})
+
+	result, video, err := svc.WaitForResult(t.Context())
+	if err != nil {
+		t.Fatalf("WaitForResult() error = %v", err)
+	}
+	if !result.Approved {
+		t.Error("expected approval")
+	}
+	if strings.Join(video.Tags, ",") != "a,b" {
+		t.Errorf("Tags = %v, want [a b]", video.Tags)
+	}
+}
+
+func TestPersistAcrossRestart(t *testing.T) {
+	dataDir := t.TempDir()
+	svc := NewApprovalService(NewClient("test-token"), dataDir, 0, 30)
+
+	for _, title := range []string{"First", "Second"} {
+		if err := svc.queue.Add(QueuedVideo{Title: title, VideoPath: writeTestFile(t, dataDir)}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	svc.pendingVideo = &QueuedVideo{Title: "Pending", VideoPath: writeTestFile(t, dataDir), MessageID: 5, ChatID: 100}
+	svc.savePending()
+	if err := svc.generationQueue.Add(GenerationRequest{Topic: "Why cats purr", ChatID: 100}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := svc.generationQueue.Pop(); err != nil {
+		t.Fatalf("Pop() error = %v", err)
+	}
+
+	restarted := NewApprovalService(NewClient("test-token"), dataDir, 0, 30)
+	if got := restarted.Queue().Len(); got != 2 {
+		t.Errorf("queue length = %d, want 2", got)
+	}
+	if restarted.pendingVideo == nil || restarted.pendingVideo.Title != "Pending" {
+		t.Errorf("pending video = %+v, want Pending", restarted.pendingVideo)
+	}
+
+	requests := restarted.GenerationQueue().List()
+	if len(requests) != 1 {
+		t.Fatalf("generation queue length = %d, want 1", len(requests))
+	}
+	if requests[0].Status != "pending" || requests[0].Topic != "Why cats purr" {
+		t.Errorf("generation request = %+v, want pending Why cats purr", requests[0])
+	}
+}
+
+func TestRequeue(t *testing.T) {
+	dataDir := t.TempDir()
+	svc := NewApprovalService(NewClient("test-token"), dataDir, 0, 30)
+
+	video := QueuedVideo{Title: "Approved", VideoPath: writeTestFile(t, dataDir), MessageID: 5, ChatID: 100}
+	if err := svc.Requeue(video); err != nil {
+		t.Fatalf("Requeue() error = %v", err)
+	}
+
+	restarted := NewApprovalService(NewClient("test-token"), dataDir, 0, 30)
+	items := restarted.Queue().List()
+	if len(items) != 1 {
+		t.Fatalf("queue length = %d, want 1", len(items))
+	}
+	if items[0].Title != "Approved" || items[0].MessageID != 0 || items[0].ChatID != 0 {
+		t.Errorf("requeued video = %+v, want review state cleared", items[0])
+	}
+}
+
+func TestStatsCounters(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantApproved int
+		wantRejected int
+	}{
+		{name: "approve", data: callbackApprove, wantApproved: 1},
+		{name: "reject", data: callbackReject, wantRejected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestApprovalService(t)
+			svc.pendingVideo = &QueuedVideo{Title: "Test Video"}
+
+			svc.handleUpdate(Update{
+				CallbackQuery: &CallbackQuery{
+					ID:      "cb1",
+					From:    &User{ID: 7},
+					Message: &Message{MessageID: 1, Chat: &Chat{ID: 100}},
+					Data:    tt.data,
+				},
+			})
+
+			stats := svc.Stats()
+			if stats.Approved != tt.wantApproved || stats.Rejected != tt.wantRejected {
+				t.Errorf("stats = %+v, want approved %d rejected %d", stats, tt.wantApproved, tt.wantRejected)
+			}
+		})
+	}
+}
+
+func TestStatsPersistAcrossRestart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	svc := NewApprovalService(newTestClient(server), dataDir, 100, 30)
+	svc.NotifyUploadComplete("Video", "https://youtu.be/abc", nil)
+	svc.NotifyUploadFailed("Video", errors.New("quota"), nil)
+	svc.stats.update(func(stats *Stats) {
+		stats.Approved = 3
+		stats.Rejected = 1
+	})
+
+	restarted := NewApprovalService(newTestClient(server), dataDir, 100, 30)
+	stats := restarted.Stats()
+	if stats.Uploaded != 1 || stats.UploadFailed != 1 {
+		t.Errorf("stats = %+v, want 1 upload and 1 failure", stats)
+	}
+	if rate := stats.ApprovalRate(); rate != 0.75 {
+		t.Errorf("ApprovalRate() = %v, want 0.75", rate)
+	}
+	if !strings.Contains(stats.String(), "Approval rate: 75%") {
+		t.Errorf("String() = %q, want approval rate", stats.String())
+	}
+}
+
+func TestFullVideo(t *testing.T) {
+	tests := []struct {
+		name         string
+		update       func() Update
+		pending      bool
+		size         int64
+		wantEndpoint string
+		wantText     string
+	}{
+		{
+			name:         "commandSendsVideo",
+			update:       func() Update { return textMessage(100, "/full") },
+			pending:      true,
+			size:         1024,
+			wantEndpoint: "sendVideo",
+		},
+		{
+			name: "callbackSendsVideo",
+			update: func() Update {
+				return Update{CallbackQuery: &CallbackQuery{
+					ID:      "cb1",
+					From:    &User{ID: 7},
+					Message: &Message{MessageID: 1, Chat: &Chat{ID: 100}},
+					Data:    callbackFull,
+				}}
+			},
+			pending:      true,
+			size:         1024,
+			wantEndpoint: "sendVideo",
+		},
+		{
+			name:         "tooLarge",
+			update:       func() Update { return textMessage(100, "/full") },
+			pending:      true,
+			size:         maxBotUploadBytes + 1,
+			wantEndpoint: "sendMessage",
+			wantText:     "50 MB bot upload limit",
+		},
+		{
+			name:         "nothingPending",
+			update:       func() Update { return textMessage(100, "/full") },
+			wantEndpoint: "sendMessage",
+			wantText:     "No video pending",
+		},
+		{
+			name:         "notAdminChat",
+			update:       func() Update { return textMessage(200, "/full") },
+			pending:      true,
+			size:         1024,
+			wantEndpoint: "sendMessage",
+			wantText:     "only available in admin chat",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var endpoints, bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				endpoints = append(endpoints, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+			}))
+			defer server.Close()
+
+			dataDir := t.TempDir()
+			svc := NewApprovalService(newTestClient(server), dataDir, 100, 30)
+			if tt.pending {
+				path := writeTestFile(t, dataDir)
+				if err := os.Truncate(path, tt.size); err != nil {
+					t.Fatalf("failed to size video: %v", err)
+				}
+				svc.pendingVideo = &QueuedVideo{Title: "Test Video", VideoPath: path, PreviewPath: path + ".preview"}
+			}
+
+			svc.handleUpdate(tt.update())
+
+			found := false
+			for i, endpoint := range endpoints {
+				if endpoint == tt.wantEndpoint && strings.Contains(bodies[i], tt.wantText) {
+					found = true
+				}
+				if endpoint == "sendVideo" && tt.wantEndpoint != "sendVideo" {
+					t.Errorf("unexpected sendVideo call")
+				}
+			}
+			if !found {
+				t.Errorf("no %s call containing %q, got %v", tt.wantEndpoint, tt.wantText, endpoints)
+			}
+		})
+	}
+}
+
+func TestAttachPreview(t *testing.T) {
+	tests := []struct {
+		name               string
+		queued             bool
+		pending            bool
+		editFails          bool
+		reviewedDuringEdit bool
+		noPreview          bool
+		wantPreview        bool
+		wantEdit           bool
+		wantPreserve       bool
+	}{
+		{name: "queuedVideo", queued: true, wantPreview: true, wantPreserve: true},
+		{name: "pendingReplacedOnReady", pending: true, wantPreview: true, wantEdit: true, wantPreserve: true},
+		{name: "editFailsKeepsFullVideo", pending: true, editFails: true, wantEdit: true},
+		{name: "reviewedDuringEdit", pending: true, reviewedDuringEdit: true, wantEdit: true},
+		{name: "previewFailedKeepsFullVideo", pending: true, noPreview: true, wantPreserve: true},
+		{name: "alreadyReviewed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var endpoints, bodies []string
+			var svc *ApprovalService
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				endpoint := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+				endpoints = append(endpoints, endpoint)
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				if endpoint == "editMessageMedia" && tt.reviewedDuringEdit {
+					svc.pendingMu.Lock()
+					svc.pendingVideo = nil
+					svc.pendingMu.Unlock()
+				}
+				if endpoint == "editMessageMedia" && tt.editFails {
+					_, _ = w.Write([]byte(`{"ok":false,"description":"Bad Request"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+			}))
+			defer server.Close()
+
+			dataDir := t.TempDir()
+			svc = NewApprovalService(newTestClient(server), dataDir, 0, 30)
+			videoPath := writeTestFile(t, dataDir)
+			previewPath := filepath.Join(dataDir, "preview.mp4")
+			if err := os.WriteFile(previewPath, []byte("preview"), 0644); err != nil {
+				t.Fatalf("failed to write preview: %v", err)
+			}
+
+			video := QueuedVideo{Title: "Test Video", VideoPath: videoPath, MessageID: 5, ChatID: 100}
+			if tt.queued {
+				if err := svc.queue.Add(video); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+			if tt.pending {
+				svc.pendingVideo = &video
+			}
+
+			attach := previewPath
+			if tt.noPreview {
+				attach = ""
+			}
+			attached := svc.AttachPreview(videoPath, attach)
+			if attached != tt.wantPreview {
+				t.Errorf("AttachPreview() = %v, want %v", attached, tt.wantPreview)
+			}
+
+			var got string
+			if tt.queued {
+				got = svc.queue.List()[0].PreviewPath
+			}
+			if tt.pending && svc.pendingVideo != nil {
+				got = svc.pendingVideo.PreviewPath
+			}
+			if (got == previewPath) != tt.wantPreview {
+				t.Errorf("PreviewPath = %q, want preview attached %v", got, tt.wantPreview)
+			}
+
+			edited := false
+			for i, endpoint := range endpoints {
+				if endpoint != "editMessageMedia" {
+					continue
+				}
+				edited = true
+				if !strings.Contains(bodies[i], "attach://video") || !strings.Contains(bodies[i], "Preview") {
+					t.Errorf("editMessageMedia body missing preview media: %s", bodies[i])
+				}
+			}
+			if edited != tt.wantEdit {
+				t.Errorf("editMessageMedia called = %v, want %v", edited, tt.wantEdit)
+			}
+
+			if _, err := os.Stat(previewPath); (err == nil) != tt.wantPreserve {
+				t.Errorf("preview file exists = %v, want %v", err == nil, tt.wantPreserve)
+			}
+		})
+	}
+}
+
+func TestReviewKeyboardFullButton(t *testing.T) {
+	for _, hasPreview := range []bool{true, false} {
+		keyboard := newReviewKeyboard(hasPreview)
+		found := false
+		for _, row := range keyboard.InlineKeyboard {
+			for _, button := range row {
+				if button.CallbackData == callbackFull {
+					found = true
+				}
+			}
+		}
+		if found != hasPreview {
+			t.Errorf("newReviewKeyboard(%v) full button = %v, want %v", hasPreview, found, hasPreview)
+		}
+	}
+}
+
+func queuedVideos(n int) []QueuedVideo {
+	videos := make([]QueuedVideo, n)
+	for i := range videos {
+		videos[i] = QueuedVideo{Title: fmt.Sprintf("Video %d", i+1), AddedAt: time.Now()}
+	}
+	return videos
+}
+
+func TestRenderQueuePage(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		page      int
+		wantPage  int
+		wantItems []string
+		wantNot   []string
+		wantPrev  bool
+		wantNext  bool
+	}{
+		{name: "firstPage", count: 5, page: 0, wantPage: 0, wantItems: []string{"1\\. Video 1", "3\\. Video 3", "Page 1/2"}, wantNot: []string{"4\\. Video 4"}, wantNext: true},
+		{name: "lastPage", count: 5, page: 1, wantPage: 1, wantItems: []string{"4\\. Video 4", "5\\. Video 5", "Page 2/2"}, wantNot: []string{"3\\. Video 3"}, wantPrev: true},
+		{name: "pastEndClamped", count: 5, page: 7, wantPage: 1, wantItems: []string{"5\\. Video 5"}, wantPrev: true},
+		{name: "negativeClamped", count: 5, page: -1, wantPage: 0, wantItems: []string{"1\\. Video 1"}, wantNext: true},
+		{name: "exactlyOnePage", count: queuePageSize, page: 0, wantPage: 0, wantItems: []string{"3\\. Video 3"}, wantNot: []string{"Page"}},
+		{name: "empty", count: 0, page: 2, wantPage: 0, wantNot: []string{"1\\."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, keyboard, page := renderQueuePage(queuedVideos(tt.count), tt.page)
+
+			if page != tt.wantPage {
+				t.Errorf("page = %d, want %d", page, tt.wantPage)
+			}
+			for _, want := range tt.wantItems {
+				if !strings.Contains(msg, want) {
+					t.Errorf("message missing %q:\n%s", want, msg)
+				}
+			}
+			for _, notWant := range tt.wantNot {
+				if strings.Contains(msg, notWant) {
+					t.Errorf("message unexpectedly contains %q:\n%s", notWant, msg)
+				}
+			}
+
+			var hasPrev, hasNext bool
+			for _, row := range keyboard.InlineKeyboard {
+				for _, button := range row {
+					hasPrev = hasPrev || button.CallbackData == callbackQueuePrev
+					hasNext = hasNext || button.CallbackData == callbackQueueNext
+				}
+			}
+			if hasPrev != tt.wantPrev || hasNext != tt.wantNext {
+				t.Errorf("buttons prev=%v next=%v, want prev=%v next=%v", hasPrev, hasNext, tt.wantPrev, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestQueuePagingCallback(t *testing.T) {
+	var edits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/editMessageText") {
+			body, _ := io.ReadAll(r.Body)
+			edits = append(edits, string(body))
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	svc := NewApprovalService(newTestClient(server), t.TempDir(), 100, 30)
+	for _, video := range queuedVideos(5) {
+		if err := svc.queue.Add(video); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	page := func(data string) {
+		svc.handleUpdate(Update{CallbackQuery: &CallbackQuery{
+			ID:      "cb1",
+			From:    &User{ID: 7},
+			Message: &Message{MessageID: 9, Chat: &Chat{ID: 300}},
+			Data:    data,
+		}})
+	}
+
+	svc.handleUpdate(textMessage(300, "/queue"))
+	page(callbackQueueNext)
+	if len(edits) != 1 || !strings.Contains(edits[0], "Video 4") {
+		t.Fatalf("next page edit = %v, want items from page 2", edits)
+	}
+
+	for range 3 {
+		if _, err := svc.queue.Pop(); err != nil {
+			t.Fatalf("Pop() error = %v", err)
+		}
+	}
+	page(callbackQueueNext)
+	if len(edits) != 2 || !strings.Contains(edits[1], "Video 4") || strings.Contains(edits[1], "Page 2") {
+		t.Errorf("edit after pops = %v, want clamped single page", edits[1:])
+	}
+}
+
+func TestGenerateCommandAccount(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		wantAccount string
+		wantTopic   string
+	}{
+		{name: "topicOnly", text: "/generate Why cats purr", wantTopic: "Why cats purr"},
+		{name: "accountAndTopic", text: "/generate @gaming Speedrun history", wantAccount: "gaming", wantTopic: "Speedrun history"},
+		{name: "accountOnly", text: "/generate @news", wantAccount: "news"},
+		{name: "empty", text: "/generate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestApprovalService(t)
+			svc.handleUpdate(textMessage(100, tt.text))
+
+			requests := svc.generationQueue.List()
+			if len(requests) != 1 {
+				t.Fatalf("expected 1 generation request, got %d", len(requests))
+			}
+			if requests[0].Account != tt.wantAccount {
+				t.Errorf("Account = %q, want %q", requests[0].Account, tt.wantAccount)
+			}
+			if requests[0].Topic != tt.wantTopic {
+				t.Errorf("Topic = %q, want %q", requests[0].Topic, tt.wantTopic)
+			}
+			if requests[0].FromReddit != (tt.wantTopic == "") {
+				t.Errorf("FromReddit = %v, want %v", requests[0].FromReddit, tt.wantTopic == "")
+			}
+		})
+	}
+}
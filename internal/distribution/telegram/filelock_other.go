@@ -0,0 +1,7 @@
+//go:build !unix
+
+package telegram
+
+func lockFile(string) (func(), error) {
+	return func() {}, nil
+}
@@ -11,6 +11,8 @@ type GenerationRequest struct {
 	Topic      string    `json:"topic"`
 	ChatID     int64     `json:"chat_id"`
 	FromReddit bool      `json:"from_reddit"`
+	Account    string    `json:"account,omitempty"`
+	ForReview  bool      `json:"for_review,omitempty"`
 	AddedAt    time.Time `json:"added_at"`
 	Status     string    `json:"status"`
 }
@@ -45,26 +47,23 @@ func (q *GenerationQueue) Add(request GenerationRequest) error {
 }
 
 func (q *GenerationQueue) Pop() (*GenerationRequest, error) {
-	req := q.FindFirst(func(r GenerationRequest) bool {
-		return r.Status == "pending"
-	})
-	if req == nil {
-		return nil, fmt.Errorf("no pending requests")
-	}
-
+	var popped *GenerationRequest
 	q.Update(func(items []GenerationRequest) []GenerationRequest {
 		for i := range items {
-			if items[i].ChatID == req.ChatID && items[i].Status == "pending" {
+			if items[i].Status == "pending" {
 				items[i].Status = "generating"
+				req := items[i]
+				popped = &req
 				break
 			}
 		}
 		return items
 	})
 
-	return q.FindFirst(func(r GenerationRequest) bool {
-		return r.ChatID == req.ChatID && r.Status == "generating"
-	}), nil
+	if popped == nil {
+		return nil, fmt.Errorf("no pending requests")
+	}
+	return popped, nil
 }
 
 func (q *GenerationQueue) Complete(chatID int64) {
@@ -0,0 +1,23 @@
+package telegram
+
+import "strings"
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+	"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+var codeEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+func escapeCode(s string) string {
+	return codeEscaper.Replace(s)
+}
+
+func bold(s string) string {
+	return "*" + escapeMarkdown(s) + "*"
+}
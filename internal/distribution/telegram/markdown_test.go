@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain", input: "Hello world", want: "Hello world"},
+		{name: "underscore", input: "C_3PO", want: `C\_3PO`},
+		{name: "brackets", input: "[v1.0]", want: `\[v1\.0\]`},
+		{name: "punctuation", input: "Wait - what?!", want: `Wait \- what?\!`},
+		{name: "formatting", input: "*bold* `code` ~strike~", want: "\\*bold\\* \\`code\\` \\~strike\\~"},
+		{name: "backslash", input: `a\b`, want: `a\\b`},
+		{name: "url", input: "https://youtu.be/a-b_c", want: `https://youtu\.be/a\-b\_c`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeMarkdown(tt.input); got != tt.want {
+				t.Errorf("escapeMarkdown(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeCode(t *testing.T) {
+	if got, want := escapeCode("/tmp/my_video [1].mp4"), "/tmp/my_video [1].mp4"; got != want {
+		t.Errorf("escapeCode() = %q, want %q", got, want)
+	}
+	if got, want := escapeCode("a`b\\c"), "a\\`b\\\\c"; got != want {
+		t.Errorf("escapeCode() = %q, want %q", got, want)
+	}
+}
+
+func TestCaptionEscapesTitle(t *testing.T) {
+	title := "C_3PO [v1.0] - wow!"
+	tests := []struct {
+		name   string
+		notify func(svc *ApprovalService, video *QueuedVideo)
+		want   string
+	}{
+		{
+			name: "uploaded",
+			notify: func(svc *ApprovalService, video *QueuedVideo) {
+				svc.NotifyUploadComplete(title, "https://youtu.be/x_y", video)
+			},
+			want: "*C\\_3PO \\[v1\\.0\\] \\- wow\\!*\n\n✅ Uploaded\nhttps://youtu\\.be/x\\_y",
+		},
+		{
+			name: "uploadFailed",
+			notify: func(svc *ApprovalService, video *QueuedVideo) {
+				svc.NotifyUploadFailed(title, errors.New("quota (daily) exceeded."), video)
+			},
+			want: "*C\\_3PO \\[v1\\.0\\] \\- wow\\!*\n\n❌ Upload failed: quota \\(daily\\) exceeded\\.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var captions []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/editMessageCaption") {
+					var payload struct {
+						Caption   string `json:"caption"`
+						ParseMode string `json:"parse_mode"`
+					}
+					body, _ := io.ReadAll(r.Body)
+					_ = json.Unmarshal(body, &payload)
+					if payload.ParseMode != "MarkdownV2" {
+						t.Errorf("parse_mode = %q, want MarkdownV2", payload.ParseMode)
+					}
+					captions = append(captions, payload.Caption)
+				}
+				_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+			}))
+			defer server.Close()
+
+			svc := NewApprovalService(newTestClient(server), t.TempDir(), 100, 30)
+			tt.notify(svc, &QueuedVideo{Title: title, MessageID: 5, ChatID: 100})
+
+			if len(captions) != 1 || captions[0] != tt.want {
+				t.Errorf("captions = %q, want %q", captions, tt.want)
+			}
+		})
+	}
+}
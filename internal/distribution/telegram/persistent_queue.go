@@ -3,14 +3,16 @@ package telegram
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 )
 
 type PersistentQueue[T any] struct {
 	items    []T
-	mu       sync.RWMutex
+	mu       sync.Mutex
 	dataFile string
 	maxSize  int
 }
@@ -28,6 +30,8 @@ func NewPersistentQueue[T any](dataDir, filename string, maxSize int) *Persisten
 func (q *PersistentQueue[T]) Add(item T) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	defer q.lockFile()()
+	q.load()
 
 	if len(q.items) >= q.maxSize {
 		return fmt.Errorf("queue is full (%d/%d)", len(q.items), q.maxSize)
@@ -41,6 +45,8 @@ func (q *PersistentQueue[T]) Add(item T) error {
 func (q *PersistentQueue[T]) Pop() (*T, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	defer q.lockFile()()
+	q.load()
 
 	if len(q.items) == 0 {
 		return nil, fmt.Errorf("queue is empty")
@@ -53,40 +59,38 @@ func (q *PersistentQueue[T]) Pop() (*T, error) {
 }
 
 func (q *PersistentQueue[T]) Peek() (*T, error) {
-	q.mu.RLock()
-	defer q.mu.RUnlock()
+	q.mu.Lock()
+	defer q.mu.Unlock()
 
 	if len(q.items) == 0 {
 		return nil, fmt.Errorf("queue is empty")
 	}
-
-	return &q.items[0], nil
+	item := q.items[0]
+	return &item, nil
 }
 
 func (q *PersistentQueue[T]) Len() int {
-	q.mu.RLock()
-	defer q.mu.RUnlock()
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	return len(q.items)
 }
 
 func (q *PersistentQueue[T]) List() []T {
-	q.mu.RLock()
-	defer q.mu.RUnlock()
-
-	result := make([]T, len(q.items))
-	copy(result, q.items)
-	return result
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Clone(q.items)
 }
 
 func (q *PersistentQueue[T]) IsFull() bool {
-	q.mu.RLock()
-	defer q.mu.RUnlock()
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	return len(q.items) >= q.maxSize
 }
 
 func (q *PersistentQueue[T]) Clear() {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	defer q.lockFile()()
 	q.items = make([]T, 0, q.maxSize)
 	q.save()
 }
@@ -94,6 +98,8 @@ func (q *PersistentQueue[T]) Clear() {
 func (q *PersistentQueue[T]) Update(fn func(items []T) []T) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	defer q.lockFile()()
+	q.load()
 	q.items = fn(q.items)
 	q.save()
 }
@@ -101,6 +107,8 @@ func (q *PersistentQueue[T]) Update(fn func(items []T) []T) {
 func (q *PersistentQueue[T]) FindAndRemove(predicate func(T) bool) *T {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	defer q.lockFile()()
+	q.load()
 
 	for i, item := range q.items {
 		if predicate(item) {
@@ -113,17 +121,27 @@ func (q *PersistentQueue[T]) FindAndRemove(predicate func(T) bool) *T {
 }
 
 func (q *PersistentQueue[T]) FindFirst(predicate func(T) bool) *T {
-	q.mu.RLock()
-	defer q.mu.RUnlock()
+	q.mu.Lock()
+	defer q.mu.Unlock()
 
-	for i := range q.items {
-		if predicate(q.items[i]) {
-			return &q.items[i]
+	for _, item := range q.items {
+		if predicate(item) {
+			return &item
 		}
 	}
 	return nil
 }
 
+func (q *PersistentQueue[T]) lockFile() func() {
+	_ = os.MkdirAll(filepath.Dir(q.dataFile), 0755)
+	unlock, err := lockFile(q.dataFile + ".lock")
+	if err != nil {
+		slog.Warn("Failed to lock queue file", "path", q.dataFile, "error", err)
+		return func() {}
+	}
+	return unlock
+}
+
 func (q *PersistentQueue[T]) load() {
 	data, err := os.ReadFile(q.dataFile)
 	if err != nil {
@@ -145,5 +163,9 @@ func (q *PersistentQueue[T]) save() {
 	}
 
 	_ = os.MkdirAll(filepath.Dir(q.dataFile), 0755)
-	_ = os.WriteFile(q.dataFile, data, 0644)
+	tmp := q.dataFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	_ = os.Rename(tmp, q.dataFile)
 }
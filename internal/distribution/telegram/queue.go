@@ -1,21 +1,31 @@
 package telegram
 
 import (
+	"fmt"
+	"slices"
 	"time"
 )
 
-const maxQueueSize = 5
+const (
+	maxQueueSize      = 5
+	priorityRequested = 1
+)
 
 type QueuedVideo struct {
-	VideoPath   string    `json:"video_path"`
-	PreviewPath string    `json:"preview_path,omitempty"`
-	Title       string    `json:"title"`
-	Script      string    `json:"script"`
-	Tags        []string  `json:"tags,omitempty"`
-	Topic       string    `json:"topic"`
-	AddedAt     time.Time `json:"added_at"`
-	MessageID   int       `json:"message_id,omitempty"`
-	ChatID      int64     `json:"chat_id,omitempty"`
+	VideoPath     string    `json:"video_path"`
+	PreviewPath   string    `json:"preview_path,omitempty"`
+	ThumbnailPath string    `json:"thumbnail_path,omitempty"`
+	Title         string    `json:"title"`
+	Script        string    `json:"script"`
+	Description   string    `json:"description,omitempty"`
+	Tags          []string  `json:"tags,omitempty"`
+	Topic         string    `json:"topic"`
+	Priority      int       `json:"priority"`
+	AddedAt       time.Time `json:"added_at"`
+	MessageID     int       `json:"message_id,omitempty"`
+	ChatID        int64     `json:"chat_id,omitempty"`
+	Account       string    `json:"account,omitempty"`
+	Source        string    `json:"source,omitempty"`
 }
 
 type VideoQueue struct {
@@ -32,3 +42,47 @@ func (q *VideoQueue) Add(video QueuedVideo) error {
 	video.AddedAt = time.Now()
 	return q.PersistentQueue.Add(video)
 }
+
+func (q *VideoQueue) Pop() (*QueuedVideo, error) {
+	var popped *QueuedVideo
+	q.Update(func(items []QueuedVideo) []QueuedVideo {
+		if len(items) == 0 {
+			return items
+		}
+		next := 0
+		for i := range items {
+			if compareReviewOrder(items[i], items[next]) < 0 {
+				next = i
+			}
+		}
+		video := items[next]
+		popped = &video
+		return append(items[:next], items[next+1:]...)
+	})
+
+	if popped == nil {
+		return nil, fmt.Errorf("queue is empty")
+	}
+	return popped, nil
+}
+
+func (q *VideoQueue) Peek() (*QueuedVideo, error) {
+	items := q.List()
+	if len(items) == 0 {
+		return nil, fmt.Errorf("queue is empty")
+	}
+	return &items[0], nil
+}
+
+func (q *VideoQueue) List() []QueuedVideo {
+	items := q.PersistentQueue.List()
+	slices.SortStableFunc(items, compareReviewOrder)
+	return items
+}
+
+func compareReviewOrder(a, b QueuedVideo) int {
+	if a.Priority != b.Priority {
+		return b.Priority - a.Priority
+	}
+	return a.AddedAt.Compare(b.AddedAt)
+}
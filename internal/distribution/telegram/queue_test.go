@@ -0,0 +1,204 @@
+package telegram
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVideoQueuePriorityOrder(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		videos []QueuedVideo
+		want   []string
+	}{
+		{
+			name: "fifoWithinSamePriority",
+			videos: []QueuedVideo{
+				{Title: "second", AddedAt: base.Add(time.Minute)},
+				{Title: "first", AddedAt: base},
+				{Title: "third", AddedAt: base.Add(2 * time.Minute)},
+			},
+			want: []string{"first", "second", "third"},
+		},
+		{
+			name: "requestedBeforeScheduled",
+			videos: []QueuedVideo{
+				{Title: "cron", AddedAt: base},
+				{Title: "requested", Priority: priorityRequested, AddedAt: base.Add(time.Hour)},
+			},
+			want: []string{"requested", "cron"},
+		},
+		{
+			name: "tieBrokenByAddedAt",
+			videos: []QueuedVideo{
+				{Title: "cronOld", AddedAt: base},
+				{Title: "requestedNew", Priority: priorityRequested, AddedAt: base.Add(2 * time.Minute)},
+				{Title: "requestedOld", Priority: priorityRequested, AddedAt: base.Add(time.Minute)},
+				{Title: "cronNew", AddedAt: base.Add(3 * time.Minute)},
+			},
+			want: []string{"requestedOld", "requestedNew", "cronOld", "cronNew"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewVideoQueue(t.TempDir())
+			for _, v := range tt.videos {
+				if err := q.PersistentQueue.Add(v); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+
+			listed := q.List()
+			for i, want := range tt.want {
+				if listed[i].Title != want {
+					t.Errorf("List()[%d] = %q, want %q", i, listed[i].Title, want)
+				}
+			}
+
+			for _, want := range tt.want {
+				video, err := q.Pop()
+				if err != nil {
+					t.Fatalf("Pop() error = %v", err)
+				}
+				if video.Title != want {
+					t.Errorf("Pop() = %q, want %q", video.Title, want)
+				}
+			}
+
+			if _, err := q.Pop(); err == nil {
+				t.Error("Pop() on empty queue should return error")
+			}
+		})
+	}
+}
+
+func TestVideoQueueSharedFile(t *testing.T) {
+	dir := t.TempDir()
+	bot := NewVideoQueue(dir)
+	if err := bot.Add(QueuedVideo{Title: "First", VideoPath: "first.mp4"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	local := NewVideoQueue(dir)
+	if removed := local.FindAndRemove(func(v QueuedVideo) bool { return v.VideoPath == "first.mp4" }); removed == nil {
+		t.Fatal("FindAndRemove() = nil, want First")
+	}
+
+	if err := bot.Add(QueuedVideo{Title: "Second", VideoPath: "second.mp4"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	listed := NewVideoQueue(dir).List()
+	if len(listed) != 1 || listed[0].Title != "Second" {
+		t.Errorf("persisted queue = %+v, want only Second", listed)
+	}
+}
+
+func TestPersistentQueueSharedFileAcrossInstances(t *testing.T) {
+	dir := t.TempDir()
+	const perQueue = 50
+	queues := []*PersistentQueue[int]{
+		NewPersistentQueue[int](dir, "queue.json", 2*perQueue),
+		NewPersistentQueue[int](dir, "queue.json", 2*perQueue),
+	}
+
+	var wg sync.WaitGroup
+	for i, q := range queues {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range perQueue {
+				if err := q.Add(i*perQueue + j); err != nil {
+					t.Errorf("Add() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := NewPersistentQueue[int](dir, "queue.json", 2*perQueue).Len(); got != len(queues)*perQueue {
+		t.Errorf("persisted queue length = %d, want %d", got, len(queues)*perQueue)
+	}
+}
+
+func TestVideoQueueConcurrentAccess(t *testing.T) {
+	q := NewVideoQueue(t.TempDir())
+
+	const workers = 8
+	const perWorker = 20
+
+	var wg sync.WaitGroup
+	var added, popped atomic.Int32
+	for w := range workers {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				if q.Add(QueuedVideo{Title: fmt.Sprintf("video-%d-%d", w, i)}) == nil {
+					added.Add(1)
+				}
+				_ = q.IsFull()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range perWorker {
+				if _, err := q.Pop(); err == nil {
+					popped.Add(1)
+				}
+				_ = q.List()
+				_ = q.Len()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := q.Len(), int(added.Load()-popped.Load()); got != want {
+		t.Errorf("Len() = %d, want %d (added %d, popped %d)", got, want, added.Load(), popped.Load())
+	}
+	if q.Len() > maxQueueSize {
+		t.Errorf("Len() = %d exceeds max size %d", q.Len(), maxQueueSize)
+	}
+}
+
+func TestGenerationQueueConcurrentPop(t *testing.T) {
+	q := NewGenerationQueue(t.TempDir())
+	for i := range maxGenerationQueueSize {
+		if err := q.Add(GenerationRequest{ChatID: int64(i)}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := make(map[int64]int)
+	for range maxGenerationQueueSize * 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := q.Pop()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			seen[req.ChatID]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != maxGenerationQueueSize {
+		t.Errorf("popped %d distinct requests, want %d", len(seen), maxGenerationQueueSize)
+	}
+	for chatID, n := range seen {
+		if n != 1 {
+			t.Errorf("request %d popped %d times, want once", chatID, n)
+		}
+	}
+}
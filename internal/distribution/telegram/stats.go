@@ -0,0 +1,73 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type Stats struct {
+	Generated    int `json:"generated"`
+	Approved     int `json:"approved"`
+	Rejected     int `json:"rejected"`
+	Uploaded     int `json:"uploaded"`
+	UploadFailed int `json:"upload_failed"`
+}
+
+type statsStore struct {
+	mu       sync.Mutex
+	dataFile string
+	stats    Stats
+}
+
+func newStatsStore(dataDir string) *statsStore {
+	store := &statsStore{dataFile: filepath.Join(dataDir, "stats.json")}
+	store.load()
+	return store
+}
+
+func (s *statsStore) update(fn func(stats *Stats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.stats)
+	s.save()
+}
+
+func (s *statsStore) snapshot() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+func (s *statsStore) load() {
+	data, err := os.ReadFile(s.dataFile)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, &s.stats)
+}
+
+func (s *statsStore) save() {
+	data, err := json.MarshalIndent(s.stats, "", "  ")
+	if err != nil {
+		return
+	}
+
+	_ = os.MkdirAll(filepath.Dir(s.dataFile), 0755)
+	_ = os.WriteFile(s.dataFile, data, 0644)
+}
+
+func (st Stats) ApprovalRate() float64 {
+	reviewed := st.Approved + st.Rejected
+	if reviewed == 0 {
+		return 0
+	}
+	return float64(st.Approved) / float64(reviewed)
+}
+
+func (st Stats) String() string {
+	return fmt.Sprintf("*Lifetime Stats*\n\nGenerated: %d\nApproved: %d\nRejected: %d\nUploaded: %d\nUpload failures: %d\n\nApproval rate: %.0f%%",
+		st.Generated, st.Approved, st.Rejected, st.Uploaded, st.UploadFailed, st.ApprovalRate()*100)
+}
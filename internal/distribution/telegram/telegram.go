@@ -34,12 +34,52 @@ func (c *Client) SendMessage(chatID int64, text string) error {
 	payload := map[string]any{
 		"chat_id":    chatID,
 		"text":       text,
-		"parse_mode": "Markdown",
+		"parse_mode": "MarkdownV2",
+	}
+	return c.postJSON("/sendMessage", payload)
+}
+
+func (c *Client) SendMessageWithKeyboard(chatID int64, text string, keyboard *InlineKeyboard) error {
+	payload := map[string]any{
+		"chat_id":    chatID,
+		"text":       text,
+		"parse_mode": "MarkdownV2",
+	}
+	if keyboard != nil {
+		payload["reply_markup"] = keyboard
 	}
 	return c.postJSON("/sendMessage", payload)
 }
 
 func (c *Client) SendVideo(chatID int64, videoPath string, caption string, keyboard *InlineKeyboard) (*MessageResponse, error) {
+	fields := map[string]string{"chat_id": fmt.Sprintf("%d", chatID)}
+	if caption != "" {
+		fields["caption"] = caption
+		fields["parse_mode"] = "MarkdownV2"
+	}
+	return c.postVideo("/sendVideo", fields, videoPath, keyboard)
+}
+
+func (c *Client) EditMessageVideo(chatID int64, messageID int, videoPath string, caption string, keyboard *InlineKeyboard) error {
+	media, err := json.Marshal(map[string]string{
+		"type":       "video",
+		"media":      "attach://video",
+		"caption":    caption,
+		"parse_mode": "MarkdownV2",
+	})
+	if err != nil {
+		return fmt.Errorf("marshal media: %w", err)
+	}
+	fields := map[string]string{
+		"chat_id":    fmt.Sprintf("%d", chatID),
+		"message_id": fmt.Sprintf("%d", messageID),
+		"media":      string(media),
+	}
+	_, err = c.postVideo("/editMessageMedia", fields, videoPath, keyboard)
+	return err
+}
+
+func (c *Client) postVideo(endpoint string, fields map[string]string, videoPath string, keyboard *InlineKeyboard) (*MessageResponse, error) {
 	file, err := os.Open(videoPath)
 	if err != nil {
 		return nil, fmt.Errorf("open video: %w", err)
@@ -49,10 +89,8 @@ func (c *Client) SendVideo(chatID int64, videoPath string, caption string, keybo
 	var buf bytes.Buffer
 	writer := multipart.NewWriter(&buf)
 
-	_ = writer.WriteField("chat_id", fmt.Sprintf("%d", chatID))
-	if caption != "" {
-		_ = writer.WriteField("caption", caption)
-		_ = writer.WriteField("parse_mode", "Markdown")
+	for name, value := range fields {
+		_ = writer.WriteField(name, value)
 	}
 
 	if keyboard != nil {
@@ -76,7 +114,7 @@ func (c *Client) SendVideo(chatID int64, videoPath string, caption string, keybo
 		return nil, fmt.Errorf("close writer: %w", err)
 	}
 
-	resp, err := c.httpClient.Post(c.baseURL+"/sendVideo", writer.FormDataContentType(), &buf)
+	resp, err := c.httpClient.Post(c.baseURL+endpoint, writer.FormDataContentType(), &buf)
 	if err != nil {
 		return nil, fmt.Errorf("send video: %w", err)
 	}
@@ -121,11 +159,24 @@ func (c *Client) EditMessageCaption(chatID int64, messageID int, caption string)
 		"chat_id":    chatID,
 		"message_id": messageID,
 		"caption":    caption,
-		"parse_mode": "Markdown",
+		"parse_mode": "MarkdownV2",
 	}
 	return c.postJSON("/editMessageCaption", payload)
 }
 
+func (c *Client) EditMessageText(chatID int64, messageID int, text string, keyboard *InlineKeyboard) error {
+	payload := map[string]any{
+		"chat_id":    chatID,
+		"message_id": messageID,
+		"text":       text,
+		"parse_mode": "MarkdownV2",
+	}
+	if keyboard != nil {
+		payload["reply_markup"] = keyboard
+	}
+	return c.postJSON("/editMessageText", payload)
+}
+
 func (c *Client) AnswerCallbackQuery(callbackID string, text string) error {
 	payload := map[string]any{
 		"callback_query_id": callbackID,
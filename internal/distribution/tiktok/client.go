@@ -0,0 +1,285 @@
+package tiktok
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"craftstory/internal/distribution"
+)
+
+const (
+	baseURL             = "https://open.tiktokapis.com/v2"
+	defaultTimeout      = 60 * time.Second
+	defaultChunkSize    = 10 * 1024 * 1024
+	defaultPollInterval = 5 * time.Second
+	defaultMaxPolls     = 60
+	defaultPrivacy      = "SELF_ONLY"
+	platform            = "tiktok"
+
+	statusComplete = "PUBLISH_COMPLETE"
+	statusFailed   = "FAILED"
+)
+
+var _ distribution.Uploader = (*Client)(nil)
+
+type Client struct {
+	accessToken  string
+	privacyLevel string
+	httpClient   *http.Client
+	baseURL      string
+	chunkSize    int64
+	pollInterval time.Duration
+	maxPolls     int
+}
+
+type Config struct {
+	AccessToken  string
+	PrivacyLevel string
+	Timeout      time.Duration
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type initRequest struct {
+	PostInfo   postInfo   `json:"post_info"`
+	SourceInfo sourceInfo `json:"source_info"`
+}
+
+type postInfo struct {
+	Title        string `json:"title"`
+	PrivacyLevel string `json:"privacy_level"`
+}
+
+type sourceInfo struct {
+	Source          string `json:"source"`
+	VideoSize       int64  `json:"video_size"`
+	ChunkSize       int64  `json:"chunk_size"`
+	TotalChunkCount int64  `json:"total_chunk_count"`
+}
+
+type initResponse struct {
+	Data struct {
+		PublishID string `json:"publish_id"`
+		UploadURL string `json:"upload_url"`
+	} `json:"data"`
+}
+
+type statusResponse struct {
+	Data struct {
+		Status     string   `json:"status"`
+		FailReason string   `json:"fail_reason"`
+		PostIDs    []string `json:"publicaly_available_post_id"`
+	} `json:"data"`
+}
+
+func NewClient(cfg Config) *Client {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	privacy := cfg.PrivacyLevel
+	if privacy == "" {
+		privacy = defaultPrivacy
+	}
+
+	return &Client{
+		accessToken:  cfg.AccessToken,
+		privacyLevel: privacy,
+		httpClient:   &http.Client{Timeout: timeout},
+		baseURL:      baseURL,
+		chunkSize:    defaultChunkSize,
+		pollInterval: defaultPollInterval,
+		maxPolls:     defaultMaxPolls,
+	}
+}
+
+func (c *Client) Upload(ctx context.Context, req distribution.UploadRequest) (*distribution.UploadResponse, error) {
+	videoFile, err := os.Open(req.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("open video: %w", err)
+	}
+	defer func() { _ = videoFile.Close() }()
+
+	info, err := videoFile.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat video: %w", err)
+	}
+
+	chunkSize, chunkCount := chunkLayout(info.Size(), c.chunkSize)
+
+	initResp, err := c.initUpload(ctx, req.Title, info.Size(), chunkSize, chunkCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.uploadChunks(ctx, initResp.Data.UploadURL, videoFile, info.Size(), chunkSize, chunkCount); err != nil {
+		return nil, err
+	}
+
+	postID, err := c.waitForPublish(ctx, initResp.Data.PublishID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &distribution.UploadResponse{
+		ID:       initResp.Data.PublishID,
+		Platform: platform,
+	}
+	if postID != "" {
+		resp.ID = postID
+		resp.URL = fmt.Sprintf("https://www.tiktok.com/video/%s", postID)
+	}
+	return resp, nil
+}
+
+func (c *Client) SetPrivacy(ctx context.Context, videoID, privacy string) error {
+	return fmt.Errorf("tiktok: changing privacy after publish is not supported")
+}
+
+func (c *Client) Platform() string {
+	return platform
+}
+
+func (c *Client) initUpload(ctx context.Context, title string, size, chunkSize, chunkCount int64) (*initResponse, error) {
+	payload := initRequest{
+		PostInfo: postInfo{
+			Title:        title,
+			PrivacyLevel: c.privacyLevel,
+		},
+		SourceInfo: sourceInfo{
+			Source:          "FILE_UPLOAD",
+			VideoSize:       size,
+			ChunkSize:       chunkSize,
+			TotalChunkCount: chunkCount,
+		},
+	}
+
+	var resp initResponse
+	if err := c.postJSON(ctx, "/post/publish/video/init/", payload, &resp); err != nil {
+		return nil, fmt.Errorf("init upload: %w", err)
+	}
+	if resp.Data.UploadURL == "" {
+		return nil, fmt.Errorf("init upload: no upload url returned")
+	}
+	return &resp, nil
+}
+
+func (c *Client) uploadChunks(ctx context.Context, uploadURL string, file io.ReaderAt, size, chunkSize, chunkCount int64) error {
+	for i := range chunkCount {
+		start := i * chunkSize
+		end := start + chunkSize - 1
+		if i == chunkCount-1 {
+			end = size - 1
+		}
+
+		chunk := make([]byte, end-start+1)
+		if _, err := file.ReadAt(chunk, start); err != nil && err != io.EOF {
+			return fmt.Errorf("read chunk %d: %w", i+1, err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, bytes.NewReader(chunk))
+		if err != nil {
+			return fmt.Errorf("create chunk request: %w", err)
+		}
+		req.Header.Set("Content-Type", "video/mp4")
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("upload chunk %d: %w", i+1, err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("upload chunk %d: %s", i+1, resp.Status)
+		}
+	}
+	return nil
+}
+
+func (c *Client) waitForPublish(ctx context.Context, publishID string) (string, error) {
+	for range c.maxPolls {
+		var resp statusResponse
+		if err := c.postJSON(ctx, "/post/publish/status/fetch/", map[string]string{"publish_id": publishID}, &resp); err != nil {
+			return "", fmt.Errorf("fetch status: %w", err)
+		}
+
+		switch resp.Data.Status {
+		case statusComplete:
+			if len(resp.Data.PostIDs) > 0 {
+				return resp.Data.PostIDs[0], nil
+			}
+			return "", nil
+		case statusFailed:
+			return "", fmt.Errorf("publish failed: %s", resp.Data.FailReason)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+	}
+	return "", fmt.Errorf("publish did not complete after %d status checks", c.maxPolls)
+}
+
+func (c *Client) postJSON(ctx context.Context, endpoint string, payload, out any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tiktok api error: %s - %s", resp.Status, string(body))
+	}
+
+	var envelope struct {
+		Error apiError `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if envelope.Error.Code != "" && envelope.Error.Code != "ok" {
+		return fmt.Errorf("tiktok api error: %s - %s", envelope.Error.Code, envelope.Error.Message)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+func chunkLayout(size, chunkSize int64) (int64, int64) {
+	if size <= chunkSize {
+		return size, 1
+	}
+	return chunkSize, size / chunkSize
+}
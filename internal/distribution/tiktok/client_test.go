@@ -0,0 +1,189 @@
+package tiktok
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"craftstory/internal/distribution"
+)
+
+func newTestClient(server *httptest.Server) *Client {
+	return &Client{
+		accessToken:  "test-token",
+		privacyLevel: defaultPrivacy,
+		httpClient:   server.Client(),
+		baseURL:      server.URL,
+		chunkSize:    4,
+		pollInterval: time.Millisecond,
+		maxPolls:     5,
+	}
+}
+
+func writeTestVideo(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write video: %v", err)
+	}
+	return path
+}
+
+func TestClientUpload(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		ranges   []string
+		received []byte
+		polls    int
+		initReq  initRequest
+	)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/post/publish/video/init/":
+			if r.Header.Get("Authorization") != "Bearer test-token" {
+				t.Errorf("expected bearer token, got %q", r.Header.Get("Authorization"))
+			}
+			if err := json.NewDecoder(r.Body).Decode(&initReq); err != nil {
+				t.Errorf("failed to decode init request: %v", err)
+			}
+			_, _ = fmt.Fprintf(w, `{"data":{"publish_id":"pub-1","upload_url":"%s/upload"},"error":{"code":"ok"}}`, server.URL)
+		case "/upload":
+			if r.Method != http.MethodPut {
+				t.Errorf("expected PUT, got %s", r.Method)
+			}
+			ranges = append(ranges, r.Header.Get("Content-Range"))
+			body, _ := io.ReadAll(r.Body)
+			received = append(received, body...)
+			w.WriteHeader(http.StatusPartialContent)
+		case "/post/publish/status/fetch/":
+			polls++
+			if polls < 2 {
+				_, _ = w.Write([]byte(`{"data":{"status":"PROCESSING_UPLOAD"},"error":{"code":"ok"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"status":"PUBLISH_COMPLETE","publicaly_available_post_id":["7300"]},"error":{"code":"ok"}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	path := writeTestVideo(t, "0123456789")
+
+	resp, err := client.Upload(context.Background(), distribution.UploadRequest{
+		FilePath: path,
+		Title:    "Test Video",
+	})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+
+	if resp.ID != "7300" {
+		t.Errorf("ID = %q, want %q", resp.ID, "7300")
+	}
+	if resp.URL != "https://www.tiktok.com/video/7300" {
+		t.Errorf("URL = %q", resp.URL)
+	}
+	if resp.Platform != "tiktok" {
+		t.Errorf("Platform = %q, want tiktok", resp.Platform)
+	}
+
+	if initReq.PostInfo.Title != "Test Video" {
+		t.Errorf("init title = %q, want %q", initReq.PostInfo.Title, "Test Video")
+	}
+	if initReq.SourceInfo.VideoSize != 10 || initReq.SourceInfo.TotalChunkCount != 2 {
+		t.Errorf("init source = %+v, want size 10 in 2 chunks", initReq.SourceInfo)
+	}
+
+	wantRanges := []string{"bytes 0-3/10", "bytes 4-9/10"}
+	if len(ranges) != len(wantRanges) {
+		t.Fatalf("got %d chunks, want %d", len(ranges), len(wantRanges))
+	}
+	for i, want := range wantRanges {
+		if ranges[i] != want {
+			t.Errorf("chunk %d Content-Range = %q, want %q", i, ranges[i], want)
+		}
+	}
+	if string(received) != "0123456789" {
+		t.Errorf("received %q, want %q", received, "0123456789")
+	}
+	if polls != 2 {
+		t.Errorf("polls = %d, want 2", polls)
+	}
+}
+
+func TestClientUploadPublishFailed(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/post/publish/video/init/":
+			_, _ = fmt.Fprintf(w, `{"data":{"publish_id":"pub-1","upload_url":"%s/upload"},"error":{"code":"ok"}}`, server.URL)
+		case "/upload":
+			w.WriteHeader(http.StatusCreated)
+		case "/post/publish/status/fetch/":
+			_, _ = w.Write([]byte(`{"data":{"status":"FAILED","fail_reason":"file_format_check_failed"},"error":{"code":"ok"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	path := writeTestVideo(t, "abc")
+
+	_, err := client.Upload(context.Background(), distribution.UploadRequest{FilePath: path, Title: "Test"})
+	if err == nil {
+		t.Fatal("expected error for failed publish")
+	}
+}
+
+func TestClientUploadInitError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{},"error":{"code":"access_token_invalid","message":"token expired"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	path := writeTestVideo(t, "abc")
+
+	_, err := client.Upload(context.Background(), distribution.UploadRequest{FilePath: path, Title: "Test"})
+	if err == nil {
+		t.Fatal("expected error for invalid token")
+	}
+}
+
+func TestChunkLayout(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int64
+		chunkSize int64
+		wantSize  int64
+		wantCount int64
+	}{
+		{name: "smallerThanChunk", size: 3, chunkSize: 10, wantSize: 3, wantCount: 1},
+		{name: "exactMultiple", size: 20, chunkSize: 10, wantSize: 10, wantCount: 2},
+		{name: "remainderMergedIntoLast", size: 25, chunkSize: 10, wantSize: 10, wantCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, count := chunkLayout(tt.size, tt.chunkSize)
+			if size != tt.wantSize || count != tt.wantCount {
+				t.Errorf("chunkLayout(%d, %d) = (%d, %d), want (%d, %d)", tt.size, tt.chunkSize, size, count, tt.wantSize, tt.wantCount)
+			}
+		})
+	}
+}
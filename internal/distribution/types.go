@@ -1,6 +1,12 @@
 package distribution
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrQuotaExceeded = errors.New("upload quota exceeded")
 
 type UploadRequest struct {
 	FilePath    string
@@ -8,6 +14,9 @@ type UploadRequest struct {
 	Description string
 	Tags        []string
 	Privacy     string
+	Thumbnail   string
+	PlaylistID  string
+	PublishAt   time.Time
 }
 
 type UploadResponse struct {
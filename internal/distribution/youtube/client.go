@@ -4,12 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"log/slog"
 	"net/http"
 	"os"
-	"path/filepath"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -18,16 +19,37 @@ import (
 )
 
 const (
-	uploadURL  = "https://www.googleapis.com/upload/youtube/v3/videos"
-	videosURL  = "https://www.googleapis.com/youtube/v3/videos"
-	categoryID = "22"
-	platform   = "youtube"
+	uploadURL        = "https://www.googleapis.com/upload/youtube/v3/videos"
+	videosURL        = "https://www.googleapis.com/youtube/v3/videos"
+	thumbnailsURL    = "https://www.googleapis.com/upload/youtube/v3/thumbnails/set"
+	playlistItemsURL = "https://www.googleapis.com/youtube/v3/playlistItems"
+	channelsURL      = "https://www.googleapis.com/youtube/v3/channels"
+	categoryID       = "22"
+	platform         = "youtube"
 )
 
 var _ distribution.Uploader = (*Client)(nil)
 
+var (
+	ErrThumbnailForbidden = errors.New("custom thumbnails not allowed (channel may not be verified)")
+	ErrQuotaExceeded      = fmt.Errorf("youtube %w", distribution.ErrQuotaExceeded)
+)
+
 type Client struct {
-	auth *Auth
+	auth             *Auth
+	uploadURL        string
+	videosURL        string
+	thumbnailsURL    string
+	playlistItemsURL string
+	channelsURL      string
+	uploadRetries    int
+	retryDelay       time.Duration
+	chunkSize        int64
+}
+
+type Config struct {
+	Auth          *Auth
+	UploadRetries int
 }
 
 type Auth struct {
@@ -50,6 +72,7 @@ type videoSnippet struct {
 
 type videoStatus struct {
 	PrivacyStatus string `json:"privacyStatus"`
+	PublishAt     string `json:"publishAt,omitempty"`
 }
 
 type videoMetadata struct {
@@ -75,8 +98,18 @@ func NewAuth(clientID, clientSecret, tokenPath string) *Auth {
 	}
 }
 
-func NewClient(auth *Auth) *Client {
-	return &Client{auth: auth}
+func NewClient(cfg Config) *Client {
+	return &Client{
+		auth:             cfg.Auth,
+		uploadURL:        uploadURL,
+		videosURL:        videosURL,
+		thumbnailsURL:    thumbnailsURL,
+		playlistItemsURL: playlistItemsURL,
+		channelsURL:      channelsURL,
+		uploadRetries:    max(cfg.UploadRetries, 0),
+		retryDelay:       defaultRetryDelay,
+		chunkSize:        defaultChunkSize,
+	}
 }
 
 func (c *Client) Upload(ctx context.Context, req distribution.UploadRequest) (*distribution.UploadResponse, error) {
@@ -85,97 +118,163 @@ func (c *Client) Upload(ctx context.Context, req distribution.UploadRequest) (*d
 		return nil, fmt.Errorf("failed to get auth client: %w", err)
 	}
 
-	metadata := videoMetadata{
+	metadataJSON, err := json.Marshal(buildMetadata(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	videoFile, err := os.Open(req.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open video file: %w", err)
+	}
+	defer func() { _ = videoFile.Close() }()
+
+	info, err := videoFile.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat video file: %w", err)
+	}
+	if info.Size() == 0 {
+		return nil, errors.New("video file is empty")
+	}
+
+	uploadResp, err := c.uploadResumable(ctx, httpClient, metadataJSON, videoFile, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Thumbnail != "" {
+		if err := c.setThumbnail(ctx, httpClient, uploadResp.ID, req.Thumbnail); err != nil {
+			slog.Warn("Failed to set thumbnail", "video_id", uploadResp.ID, "error", err)
+		}
+	}
+
+	if req.PlaylistID != "" {
+		if err := c.addToPlaylist(ctx, httpClient, uploadResp.ID, req.PlaylistID); err != nil {
+			slog.Warn("Failed to add video to playlist", "video_id", uploadResp.ID, "playlist_id", req.PlaylistID, "error", err)
+		}
+	}
+
+	return &distribution.UploadResponse{
+		ID:       uploadResp.ID,
+		URL:      fmt.Sprintf("https://youtube.com/watch?v=%s", uploadResp.ID),
+		Platform: platform,
+	}, nil
+}
+
+func buildMetadata(req distribution.UploadRequest) videoMetadata {
+	status := videoStatus{PrivacyStatus: req.Privacy}
+	if !req.PublishAt.IsZero() {
+		status.PrivacyStatus = "private"
+		status.PublishAt = req.PublishAt.UTC().Format(time.RFC3339)
+	}
+
+	return videoMetadata{
 		Snippet: videoSnippet{
 			Title:       req.Title,
 			Description: req.Description,
 			Tags:        req.Tags,
 			CategoryID:  categoryID,
 		},
-		Status: videoStatus{
-			PrivacyStatus: req.Privacy,
+		Status: status,
+	}
+}
+
+func (c *Client) SetPrivacy(ctx context.Context, videoID, privacy string) error {
+	httpClient, err := c.auth.Client(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get auth client: %w", err)
+	}
+
+	body := map[string]any{
+		"id": videoID,
+		"status": map[string]string{
+			"privacyStatus": privacy,
 		},
 	}
 
-	metadataJSON, err := json.Marshal(metadata)
+	bodyJSON, err := json.Marshal(body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		return fmt.Errorf("failed to marshal body: %w", err)
 	}
 
-	videoFile, err := os.Open(req.FilePath)
+	url := fmt.Sprintf("%s?part=status", c.videosURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewBuffer(bodyJSON))
 	if err != nil {
-		return nil, fmt.Errorf("failed to open video file: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
-	defer func() { _ = videoFile.Close() }()
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	req.Header.Set("Content-Type", "application/json")
 
-	metadataPart, err := writer.CreateFormField("snippet")
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create metadata part: %w", err)
+		return fmt.Errorf("failed to update video: %w", err)
 	}
-	if _, err := metadataPart.Write(metadataJSON); err != nil {
-		return nil, fmt.Errorf("failed to write metadata: %w", err)
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("update failed: %s", string(respBody))
 	}
 
-	videoPart, err := writer.CreateFormFile("file", filepath.Base(req.FilePath))
+	return nil
+}
+
+func (c *Client) SetThumbnail(ctx context.Context, videoID, thumbnailPath string) error {
+	httpClient, err := c.auth.Client(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create video part: %w", err)
-	}
-	if _, err := io.Copy(videoPart, videoFile); err != nil {
-		return nil, fmt.Errorf("failed to copy video: %w", err)
+		return fmt.Errorf("failed to get auth client: %w", err)
 	}
+	return c.setThumbnail(ctx, httpClient, videoID, thumbnailPath)
+}
 
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close writer: %w", err)
+func (c *Client) setThumbnail(ctx context.Context, httpClient *http.Client, videoID, thumbnailPath string) error {
+	data, err := os.ReadFile(thumbnailPath)
+	if err != nil {
+		return fmt.Errorf("failed to read thumbnail: %w", err)
 	}
 
-	url := fmt.Sprintf("%s?uploadType=multipart&part=snippet,status", uploadURL)
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	url := fmt.Sprintf("%s?videoId=%s&uploadType=media", c.thumbnailsURL, videoID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Content-Type", "image/jpeg")
 
-	resp, err := httpClient.Do(httpReq)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload video: %w", err)
+		return fmt.Errorf("failed to upload thumbnail: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	if resp.StatusCode == http.StatusForbidden {
+		return ErrThumbnailForbidden
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("upload failed: %s", string(respBody))
-	}
-
-	var uploadResp uploadResponse
-	if err := json.Unmarshal(respBody, &uploadResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("thumbnail upload failed: %s", string(respBody))
 	}
 
-	return &distribution.UploadResponse{
-		ID:       uploadResp.ID,
-		URL:      fmt.Sprintf("https://youtube.com/watch?v=%s", uploadResp.ID),
-		Platform: platform,
-	}, nil
+	return nil
 }
 
-func (c *Client) SetPrivacy(ctx context.Context, videoID, privacy string) error {
+func (c *Client) AddToPlaylist(ctx context.Context, videoID, playlistID string) error {
 	httpClient, err := c.auth.Client(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get auth client: %w", err)
 	}
+	return c.addToPlaylist(ctx, httpClient, videoID, playlistID)
+}
 
+func (c *Client) addToPlaylist(ctx context.Context, httpClient *http.Client, videoID, playlistID string) error {
 	body := map[string]any{
-		"id": videoID,
-		"status": map[string]string{
-			"privacyStatus": privacy,
+		"snippet": map[string]any{
+			"playlistId": playlistID,
+			"resourceId": map[string]string{
+				"kind":    "youtube#video",
+				"videoId": videoID,
+			},
 		},
 	}
 
@@ -184,8 +283,8 @@ func (c *Client) SetPrivacy(ctx context.Context, videoID, privacy string) error
 		return fmt.Errorf("failed to marshal body: %w", err)
 	}
 
-	url := fmt.Sprintf("%s?part=status", videosURL)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewBuffer(bodyJSON))
+	url := fmt.Sprintf("%s?part=snippet", c.playlistItemsURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(bodyJSON))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -194,13 +293,13 @@ func (c *Client) SetPrivacy(ctx context.Context, videoID, privacy string) error
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to update video: %w", err)
+		return fmt.Errorf("failed to add to playlist: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("update failed: %s", string(respBody))
+		return fmt.Errorf("playlist insert failed: %s", string(respBody))
 	}
 
 	return nil
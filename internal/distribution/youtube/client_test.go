@@ -3,6 +3,12 @@ package youtube
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -32,7 +38,7 @@ func TestNewAuth(t *testing.T) {
 
 func TestNewClient(t *testing.T) {
 	auth := NewAuth("id", "secret", "/tmp/token.json")
-	client := NewClient(auth)
+	client := NewClient(Config{Auth: auth})
 
 	if client == nil {
 		t.Fatal("NewClient() returned nil")
@@ -43,7 +49,7 @@ func TestNewClient(t *testing.T) {
 }
 
 func TestPlatform(t *testing.T) {
-	client := NewClient(nil)
+	client := NewClient(Config{})
 	if got := client.Platform(); got != platform {
 		t.Errorf("Platform() = %q, want %q", got, platform)
 	}
@@ -51,7 +57,7 @@ func TestPlatform(t *testing.T) {
 
 func TestClientAuth(t *testing.T) {
 	auth := NewAuth("id", "secret", "/tmp/token.json")
-	client := NewClient(auth)
+	client := NewClient(Config{Auth: auth})
 
 	if client.Auth() != auth {
 		t.Error("Auth() did not return the correct auth")
@@ -305,7 +311,7 @@ func TestClientUploadNoAuth(t *testing.T) {
 	tokenPath := filepath.Join(tmpDir, "token.json")
 
 	auth := NewAuth("id", "secret", tokenPath)
-	client := NewClient(auth)
+	client := NewClient(Config{Auth: auth})
 
 	ctx := context.Background()
 	_, err := client.Upload(ctx, distribution.UploadRequest{
@@ -330,7 +336,7 @@ func TestClientUploadBadFile(t *testing.T) {
 	_ = os.WriteFile(tokenPath, tokenData, 0600)
 
 	auth := NewAuth("id", "secret", tokenPath)
-	client := NewClient(auth)
+	client := NewClient(Config{Auth: auth})
 
 	ctx := context.Background()
 	_, err := client.Upload(ctx, distribution.UploadRequest{
@@ -348,7 +354,7 @@ func TestClientSetPrivacyNoAuth(t *testing.T) {
 	tokenPath := filepath.Join(tmpDir, "token.json")
 
 	auth := NewAuth("id", "secret", tokenPath)
-	client := NewClient(auth)
+	client := NewClient(Config{Auth: auth})
 
 	ctx := context.Background()
 	err := client.SetPrivacy(ctx, "video-id", "public")
@@ -357,3 +363,467 @@ func TestClientSetPrivacyNoAuth(t *testing.T) {
 		t.Error("SetPrivacy() should fail without auth")
 	}
 }
+
+func newTestClient(t *testing.T, server *httptest.Server) *Client {
+	t.Helper()
+
+	auth := NewAuth("id", "secret", filepath.Join(t.TempDir(), "token.json"))
+	auth.token = &oauth2.Token{
+		AccessToken: "test-token",
+		Expiry:      time.Now().Add(time.Hour),
+	}
+
+	client := NewClient(Config{Auth: auth})
+	client.uploadURL = server.URL + "/upload"
+	client.videosURL = server.URL + "/videos"
+	client.thumbnailsURL = server.URL + "/thumbnails/set"
+	client.playlistItemsURL = server.URL + "/playlistItems"
+	return client
+}
+
+func TestClientUploadWithThumbnail(t *testing.T) {
+	tests := []struct {
+		name            string
+		thumbnailStatus int
+		withThumbnail   bool
+		wantThumbnail   bool
+	}{
+		{
+			name:            "thumbnailSet",
+			thumbnailStatus: http.StatusOK,
+			withThumbnail:   true,
+			wantThumbnail:   true,
+		},
+		{
+			name:            "channelNotVerified",
+			thumbnailStatus: http.StatusForbidden,
+			withThumbnail:   true,
+			wantThumbnail:   true,
+		},
+		{
+			name:          "noThumbnail",
+			withThumbnail: false,
+			wantThumbnail: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var thumbnailCalled bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/upload":
+					w.Header().Set("Location", "http://"+r.Host+"/session")
+				case "/session":
+					_, _ = w.Write([]byte(`{"id":"abc123","kind":"youtube#video"}`))
+				case "/thumbnails/set":
+					thumbnailCalled = true
+					if got := r.URL.Query().Get("videoId"); got != "abc123" {
+						t.Errorf("videoId = %q, want %q", got, "abc123")
+					}
+					if got := r.Header.Get("Content-Type"); got != "image/jpeg" {
+						t.Errorf("Content-Type = %q, want %q", got, "image/jpeg")
+					}
+					body, _ := io.ReadAll(r.Body)
+					if string(body) != "jpeg-data" {
+						t.Errorf("body = %q, want %q", body, "jpeg-data")
+					}
+					w.WriteHeader(tt.thumbnailStatus)
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			tmpDir := t.TempDir()
+			videoPath := filepath.Join(tmpDir, "video.mp4")
+			_ = os.WriteFile(videoPath, []byte("video-data"), 0644)
+
+			req := distribution.UploadRequest{
+				FilePath: videoPath,
+				Title:    "Test",
+			}
+			if tt.withThumbnail {
+				req.Thumbnail = filepath.Join(tmpDir, "thumb.jpg")
+				_ = os.WriteFile(req.Thumbnail, []byte("jpeg-data"), 0644)
+			}
+
+			client := newTestClient(t, server)
+			resp, err := client.Upload(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+			if resp.ID != "abc123" {
+				t.Errorf("ID = %q, want %q", resp.ID, "abc123")
+			}
+			if thumbnailCalled != tt.wantThumbnail {
+				t.Errorf("thumbnail called = %v, want %v", thumbnailCalled, tt.wantThumbnail)
+			}
+		})
+	}
+}
+
+func TestClientSetThumbnailForbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	thumbPath := filepath.Join(t.TempDir(), "thumb.jpg")
+	_ = os.WriteFile(thumbPath, []byte("jpeg-data"), 0644)
+
+	client := newTestClient(t, server)
+	err := client.SetThumbnail(context.Background(), "abc123", thumbPath)
+	if !errors.Is(err, ErrThumbnailForbidden) {
+		t.Errorf("SetThumbnail() error = %v, want %v", err, ErrThumbnailForbidden)
+	}
+}
+
+func TestClientUploadWithPlaylist(t *testing.T) {
+	tests := []struct {
+		name           string
+		playlistID     string
+		playlistStatus int
+		wantPlaylist   bool
+	}{
+		{
+			name:           "addedToPlaylist",
+			playlistID:     "PL123",
+			playlistStatus: http.StatusOK,
+			wantPlaylist:   true,
+		},
+		{
+			name:           "invalidPlaylistDoesNotFail",
+			playlistID:     "PLbad",
+			playlistStatus: http.StatusNotFound,
+			wantPlaylist:   true,
+		},
+		{
+			name:         "noPlaylist",
+			playlistID:   "",
+			wantPlaylist: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var playlistCalled bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/upload":
+					w.Header().Set("Location", "http://"+r.Host+"/session")
+				case "/session":
+					_, _ = w.Write([]byte(`{"id":"abc123","kind":"youtube#video"}`))
+				case "/playlistItems":
+					playlistCalled = true
+					var body struct {
+						Snippet struct {
+							PlaylistID string `json:"playlistId"`
+							ResourceID struct {
+								Kind    string `json:"kind"`
+								VideoID string `json:"videoId"`
+							} `json:"resourceId"`
+						} `json:"snippet"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("failed to decode body: %v", err)
+					}
+					if body.Snippet.PlaylistID != tt.playlistID {
+						t.Errorf("playlistId = %q, want %q", body.Snippet.PlaylistID, tt.playlistID)
+					}
+					if body.Snippet.ResourceID.VideoID != "abc123" {
+						t.Errorf("videoId = %q, want %q", body.Snippet.ResourceID.VideoID, "abc123")
+					}
+					w.WriteHeader(tt.playlistStatus)
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			videoPath := filepath.Join(t.TempDir(), "video.mp4")
+			_ = os.WriteFile(videoPath, []byte("video-data"), 0644)
+
+			client := newTestClient(t, server)
+			_, err := client.Upload(context.Background(), distribution.UploadRequest{
+				FilePath:   videoPath,
+				Title:      "Test",
+				PlaylistID: tt.playlistID,
+			})
+			if err != nil {
+				t.Fatalf("Upload() error = %v", err)
+			}
+			if playlistCalled != tt.wantPlaylist {
+				t.Errorf("playlist called = %v, want %v", playlistCalled, tt.wantPlaylist)
+			}
+		})
+	}
+}
+
+func TestBuildMetadataPublishAt(t *testing.T) {
+	publishAt := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		req           distribution.UploadRequest
+		wantPrivacy   string
+		wantPublishAt string
+	}{
+		{
+			name:          "scheduled",
+			req:           distribution.UploadRequest{Title: "Test", Privacy: "unlisted", PublishAt: publishAt},
+			wantPrivacy:   "private",
+			wantPublishAt: "2030-01-02T15:04:05Z",
+		},
+		{
+			name:          "scheduledConvertsToUTC",
+			req:           distribution.UploadRequest{Title: "Test", Privacy: "private", PublishAt: publishAt.In(time.FixedZone("EET", 2*3600))},
+			wantPrivacy:   "private",
+			wantPublishAt: "2030-01-02T15:04:05Z",
+		},
+		{
+			name:          "immediate",
+			req:           distribution.UploadRequest{Title: "Test", Privacy: "public"},
+			wantPrivacy:   "public",
+			wantPublishAt: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := buildMetadata(tt.req)
+
+			if metadata.Status.PrivacyStatus != tt.wantPrivacy {
+				t.Errorf("PrivacyStatus = %q, want %q", metadata.Status.PrivacyStatus, tt.wantPrivacy)
+			}
+			if metadata.Status.PublishAt != tt.wantPublishAt {
+				t.Errorf("PublishAt = %q, want %q", metadata.Status.PublishAt, tt.wantPublishAt)
+			}
+
+			data, _ := json.Marshal(metadata)
+			var raw map[string]map[string]any
+			_ = json.Unmarshal(data, &raw)
+			_, hasPublishAt := raw["status"]["publishAt"]
+			if hasPublishAt != (tt.wantPublishAt != "") {
+				t.Errorf("status JSON publishAt present = %v, want %v", hasPublishAt, tt.wantPublishAt != "")
+			}
+		})
+	}
+}
+
+func TestClientUploadResume(t *testing.T) {
+	tests := []struct {
+		name      string
+		failAt    int
+		failWith  int
+		retries   int
+		wantErr   bool
+		wantPuts  int
+		wantRange string
+	}{
+		{
+			name:     "noInterruption",
+			failAt:   -1,
+			retries:  3,
+			wantPuts: 3,
+		},
+		{
+			name:      "resumesAfterServerError",
+			failAt:    1,
+			failWith:  http.StatusServiceUnavailable,
+			retries:   3,
+			wantPuts:  5,
+			wantRange: "bytes 4-7/10",
+		},
+		{
+			name:     "serverErrorWithoutRetries",
+			failAt:   1,
+			failWith: http.StatusServiceUnavailable,
+			retries:  0,
+			wantErr:  true,
+			wantPuts: 2,
+		},
+		{
+			name:     "badRequestNotRetried",
+			failAt:   1,
+			failWith: http.StatusBadRequest,
+			retries:  3,
+			wantErr:  true,
+			wantPuts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []byte
+			var puts int
+			var resumedRange string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/upload":
+					if got := r.URL.Query().Get("uploadType"); got != "resumable" {
+						t.Errorf("uploadType = %q, want resumable", got)
+					}
+					if got := r.Header.Get("X-Upload-Content-Length"); got != "10" {
+						t.Errorf("X-Upload-Content-Length = %q, want 10", got)
+					}
+					w.Header().Set("Location", "http://"+r.Host+"/session")
+				case "/session":
+					puts++
+					contentRange := r.Header.Get("Content-Range")
+					if contentRange == "bytes */10" {
+						w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(received)-1))
+						w.WriteHeader(statusResume)
+						return
+					}
+
+					chunk, _ := io.ReadAll(r.Body)
+					if puts-1 == tt.failAt {
+						w.WriteHeader(tt.failWith)
+						return
+					}
+					if resumedRange == "" && tt.failAt >= 0 && puts > tt.failAt+2 {
+						resumedRange = contentRange
+					}
+
+					received = append(received, chunk...)
+					if len(received) == 10 {
+						_, _ = w.Write([]byte(`{"id":"abc123","kind":"youtube#video"}`))
+						return
+					}
+					w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(received)-1))
+					w.WriteHeader(statusResume)
+				default:
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			videoPath := filepath.Join(t.TempDir(), "video.mp4")
+			_ = os.WriteFile(videoPath, []byte("0123456789"), 0644)
+
+			client := newTestClient(t, server)
+			client.uploadRetries = tt.retries
+			client.retryDelay = time.Millisecond
+			client.chunkSize = 4
+
+			resp, err := client.Upload(context.Background(), distribution.UploadRequest{FilePath: videoPath, Title: "Test"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Upload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if puts != tt.wantPuts {
+				t.Errorf("session requests = %d, want %d", puts, tt.wantPuts)
+			}
+			if tt.wantErr {
+				return
+			}
+			if resp.ID != "abc123" {
+				t.Errorf("ID = %q, want %q", resp.ID, "abc123")
+			}
+			if string(received) != "0123456789" {
+				t.Errorf("received = %q, want %q", received, "0123456789")
+			}
+			if resumedRange != tt.wantRange {
+				t.Errorf("resumed Content-Range = %q, want %q", resumedRange, tt.wantRange)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "serverError", err: &statusError{op: "upload", status: http.StatusServiceUnavailable}, want: true},
+		{name: "clientError", err: &statusError{op: "upload", status: http.StatusBadRequest}, want: false},
+		{name: "networkError", err: fmt.Errorf("failed to upload video: %w", &net.OpError{Op: "read", Err: errors.New("connection reset")}), want: true},
+		{name: "unexpectedEOF", err: fmt.Errorf("failed to upload video: %w", io.ErrUnexpectedEOF), want: true},
+		{name: "canceled", err: fmt.Errorf("failed to upload video: %w", context.Canceled), want: false},
+		{name: "deadlineExceeded", err: context.DeadlineExceeded, want: false},
+		{name: "noLocation", err: errNoLocation, want: false},
+		{name: "invalidResponse", err: fmt.Errorf("%w: %v", errInvalidResponse, io.ErrUnexpectedEOF), want: false},
+		{name: "fileError", err: fmt.Errorf("failed to read video: %w", os.ErrNotExist), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   int64
+	}{
+		{name: "empty", header: "", want: 0},
+		{name: "partial", header: "bytes=0-1023", want: 1024},
+		{name: "malformed", header: "bytes=0-abc", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextOffset(tt.header); got != tt.want {
+				t.Errorf("nextOffset(%q) = %d, want %d", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientUploadQuotaExceeded(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantQuota bool
+	}{
+		{
+			name:      "quotaExceeded",
+			body:      `{"error":{"code":403,"errors":[{"reason":"quotaExceeded","domain":"youtube.quota"}]}}`,
+			wantQuota: true,
+		},
+		{
+			name:      "otherForbidden",
+			body:      `{"error":{"code":403,"errors":[{"reason":"forbidden"}]}}`,
+			wantQuota: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			videoPath := filepath.Join(t.TempDir(), "video.mp4")
+			_ = os.WriteFile(videoPath, []byte("video-data"), 0644)
+
+			client := newTestClient(t, server)
+			client.uploadRetries = 3
+			client.retryDelay = time.Millisecond
+
+			_, err := client.Upload(context.Background(), distribution.UploadRequest{FilePath: videoPath, Title: "Test"})
+			if err == nil {
+				t.Fatal("Upload() should fail on 403")
+			}
+			if got := errors.Is(err, ErrQuotaExceeded); got != tt.wantQuota {
+				t.Errorf("errors.Is(ErrQuotaExceeded) = %v, want %v (err: %v)", got, tt.wantQuota, err)
+			}
+			if got := errors.Is(err, distribution.ErrQuotaExceeded); got != tt.wantQuota {
+				t.Errorf("errors.Is(distribution.ErrQuotaExceeded) = %v, want %v", got, tt.wantQuota)
+			}
+			if calls != 1 {
+				t.Errorf("requests = %d, want 1 (403 is not retried)", calls)
+			}
+		})
+	}
+}
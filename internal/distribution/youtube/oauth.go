@@ -0,0 +1,156 @@
+package youtube
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	callbackPath = "/callback"
+	authTimeout  = 5 * time.Minute
+)
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+type LocalFlow struct {
+	OpenBrowser func(url string) error
+	PromptCode  func(authURL string) (string, error)
+	Timeout     time.Duration
+}
+
+func (a *Auth) AuthorizeLocal(ctx context.Context, flow LocalFlow) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to start callback server: %w", err)
+	}
+
+	state, err := randomState()
+	if err != nil {
+		_ = listener.Close()
+		return err
+	}
+
+	results := make(chan callbackResult, 2)
+	server := &http.Server{
+		Handler:           callbackHandler(state, results),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			results <- callbackResult{err: err}
+		}
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	a.config.RedirectURL = fmt.Sprintf("http://%s%s", listener.Addr().String(), callbackPath)
+	authURL := a.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+
+	if flow.OpenBrowser == nil || flow.OpenBrowser(authURL) != nil {
+		if flow.PromptCode == nil {
+			return fmt.Errorf("could not open a browser, visit %s", authURL)
+		}
+		go func() {
+			input, err := flow.PromptCode(authURL)
+			if err != nil {
+				results <- callbackResult{err: err}
+				return
+			}
+			code, err := parseManualCode(input, state)
+			results <- callbackResult{code: code, err: err}
+		}()
+	}
+
+	timeout := flow.Timeout
+	if timeout <= 0 {
+		timeout = authTimeout
+	}
+
+	select {
+	case result := <-results:
+		if result.err != nil {
+			return result.err
+		}
+		return a.Exchange(ctx, result.code)
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(timeout):
+		return errors.New("authentication timed out")
+	}
+}
+
+func callbackHandler(state string, results chan<- callbackResult) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != callbackPath {
+			http.NotFound(w, r)
+			return
+		}
+
+		code, err := codeFromQuery(r.URL.Query(), state)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, "<html><body><h1>Error</h1><p>%s</p></body></html>", err)
+		} else {
+			_, _ = fmt.Fprint(w, "<html><body><h1>Success!</h1><p>You can close this window and return to the terminal.</p></body></html>")
+		}
+
+		select {
+		case results <- callbackResult{code: code, err: err}:
+		default:
+		}
+	})
+}
+
+func codeFromQuery(query url.Values, state string) (string, error) {
+	if reason := query.Get("error"); reason != "" {
+		return "", fmt.Errorf("authorization denied: %s", reason)
+	}
+	if query.Get("state") != state {
+		return "", errors.New("state mismatch in callback")
+	}
+	code := query.Get("code")
+	if code == "" {
+		return "", errors.New("no authorization code received")
+	}
+	return code, nil
+}
+
+func parseManualCode(input, state string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", errors.New("no authorization code entered")
+	}
+	if !strings.Contains(input, "://") {
+		return input, nil
+	}
+
+	parsed, err := url.Parse(input)
+	if err != nil {
+		return "", fmt.Errorf("invalid redirect url: %w", err)
+	}
+	return codeFromQuery(parsed.Query(), state)
+}
+
+func randomState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate state: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
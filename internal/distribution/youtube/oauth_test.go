@@ -0,0 +1,170 @@
+package youtube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestCallbackHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		wantCode   string
+		wantErr    bool
+		wantStatus int
+	}{
+		{name: "extractsCode", target: "/callback?code=abc123&state=s1", wantCode: "abc123", wantStatus: http.StatusOK},
+		{name: "missingCode", target: "/callback?state=s1", wantErr: true, wantStatus: http.StatusBadRequest},
+		{name: "stateMismatch", target: "/callback?code=abc123&state=other", wantErr: true, wantStatus: http.StatusBadRequest},
+		{name: "accessDenied", target: "/callback?error=access_denied&state=s1", wantErr: true, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make(chan callbackResult, 1)
+			rec := httptest.NewRecorder()
+			callbackHandler("s1", results).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			result := <-results
+			if (result.err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", result.err, tt.wantErr)
+			}
+			if result.code != tt.wantCode {
+				t.Errorf("code = %q, want %q", result.code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCallbackHandlerIgnoresOtherPaths(t *testing.T) {
+	results := make(chan callbackResult, 1)
+	rec := httptest.NewRecorder()
+	callbackHandler("s1", results).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/favicon.ico", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if len(results) != 0 {
+		t.Error("non-callback request should not produce a result")
+	}
+}
+
+func TestParseManualCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "rawCode", input: " abc123\n", want: "abc123"},
+		{name: "redirectURL", input: "http://127.0.0.1:5555/callback?code=abc123&state=s1", want: "abc123"},
+		{name: "redirectURLWrongState", input: "http://127.0.0.1:5555/callback?code=abc123&state=x", wantErr: true},
+		{name: "empty", input: "\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseManualCode(tt.input, "s1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseManualCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseManualCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizeLocal(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if got := r.Form.Get("code"); got != "abc123" {
+			t.Errorf("exchanged code = %q, want abc123", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"tok","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	tests := []struct {
+		name   string
+		flow   func(t *testing.T) LocalFlow
+		wantOK bool
+	}{
+		{
+			name: "browserCallback",
+			flow: func(t *testing.T) LocalFlow {
+				return LocalFlow{OpenBrowser: func(authURL string) error {
+					redirect, state := authParams(t, authURL)
+					go func() {
+						resp, err := http.Get(redirect + "?code=abc123&state=" + state)
+						if err == nil {
+							_ = resp.Body.Close()
+						}
+					}()
+					return nil
+				}}
+			},
+			wantOK: true,
+		},
+		{
+			name: "manualFallback",
+			flow: func(t *testing.T) LocalFlow {
+				return LocalFlow{
+					OpenBrowser: func(string) error { return os.ErrNotExist },
+					PromptCode: func(authURL string) (string, error) {
+						redirect, state := authParams(t, authURL)
+						return redirect + "?code=abc123&state=" + state, nil
+					},
+				}
+			},
+			wantOK: true,
+		},
+		{
+			name: "timesOut",
+			flow: func(t *testing.T) LocalFlow {
+				return LocalFlow{OpenBrowser: func(string) error { return nil }, Timeout: 50 * time.Millisecond}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenPath := filepath.Join(t.TempDir(), "token.json")
+			auth := NewAuth("id", "secret", tokenPath)
+			auth.config.Endpoint = oauth2.Endpoint{AuthURL: "https://accounts.example.com/auth", TokenURL: tokenServer.URL}
+
+			err := auth.AuthorizeLocal(context.Background(), tt.flow(t))
+			if (err == nil) != tt.wantOK {
+				t.Fatalf("AuthorizeLocal() error = %v, wantOK %v", err, tt.wantOK)
+			}
+			if !tt.wantOK {
+				return
+			}
+			if _, err := os.Stat(tokenPath); err != nil {
+				t.Errorf("token not saved: %v", err)
+			}
+		})
+	}
+}
+
+func authParams(t *testing.T, authURL string) (string, string) {
+	t.Helper()
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse auth url: %v", err)
+	}
+	query := parsed.Query()
+	return query.Get("redirect_uri"), query.Get("state")
+}
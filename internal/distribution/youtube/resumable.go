@@ -0,0 +1,230 @@
+package youtube
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultChunkSize  = 8 * 1024 * 1024
+	defaultRetryDelay = 2 * time.Second
+	maxRetryDelay     = 30 * time.Second
+	statusResume      = 308
+)
+
+var (
+	errNoLocation      = errors.New("upload session response has no location")
+	errInvalidResponse = errors.New("invalid upload response")
+)
+
+type statusError struct {
+	op     string
+	status int
+	body   string
+	err    error
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s failed (%d): %s", e.op, e.status, e.body)
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+func newStatusError(op string, resp *http.Response) *statusError {
+	body, _ := io.ReadAll(resp.Body)
+	statusErr := &statusError{op: op, status: resp.StatusCode, body: strings.TrimSpace(string(body))}
+	if resp.StatusCode == http.StatusForbidden && strings.Contains(statusErr.body, "quotaExceeded") {
+		statusErr.err = ErrQuotaExceeded
+	}
+	return statusErr
+}
+
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, errNoLocation) || errors.Is(err, errInvalidResponse) {
+		return false
+	}
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.status >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (c *Client) retry(ctx context.Context, op string, fn func(attempt int) error) error {
+	var err error
+	for attempt := 0; attempt <= c.uploadRetries; attempt++ {
+		if attempt > 0 {
+			delay := min(c.retryDelay<<(attempt-1), maxRetryDelay)
+			slog.Warn("Retrying YouTube "+op, "attempt", attempt, "delay", delay, "error", err)
+			if sleepErr := sleep(ctx, delay); sleepErr != nil {
+				return sleepErr
+			}
+		}
+		if err = fn(attempt); err == nil || !isRetryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *Client) uploadResumable(ctx context.Context, httpClient *http.Client, metadata []byte, file io.ReaderAt, size int64) (*uploadResponse, error) {
+	var sessionURL string
+	err := c.retry(ctx, "upload session", func(int) error {
+		var err error
+		sessionURL, err = c.startSession(ctx, httpClient, metadata, size)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result *uploadResponse
+	var offset int64
+	err = c.retry(ctx, "upload", func(attempt int) error {
+		if attempt > 0 {
+			done, next, err := c.uploadStatus(ctx, httpClient, sessionURL, size)
+			if err != nil {
+				return err
+			}
+			if done != nil {
+				result = done
+				return nil
+			}
+			offset = next
+			slog.Info("Resuming YouTube upload", "offset", offset, "size", size)
+		}
+
+		var err error
+		result, err = c.sendChunks(ctx, httpClient, sessionURL, file, offset, size)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) startSession(ctx context.Context, httpClient *http.Client, metadata []byte, size int64) (string, error) {
+	url := fmt.Sprintf("%s?uploadType=resumable&part=snippet,status", c.uploadURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(metadata))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Type", "video/*")
+	req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to start upload: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newStatusError("upload session", resp)
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", errNoLocation
+	}
+	return location, nil
+}
+
+func (c *Client) sendChunks(ctx context.Context, httpClient *http.Client, sessionURL string, file io.ReaderAt, offset, size int64) (*uploadResponse, error) {
+	for offset < size {
+		end := min(offset+c.chunkSize, size) - 1
+		chunk := make([]byte, end-offset+1)
+		if _, err := file.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read video: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURL, bytes.NewReader(chunk))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, end, size))
+
+		done, next, err := c.doSessionRequest(httpClient, req, "upload")
+		if err != nil {
+			return nil, err
+		}
+		if done != nil {
+			return done, nil
+		}
+		offset = next
+	}
+	return nil, errors.New("upload incomplete: server did not finalize the video")
+}
+
+func (c *Client) uploadStatus(ctx context.Context, httpClient *http.Client, sessionURL string, size int64) (*uploadResponse, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+
+	return c.doSessionRequest(httpClient, req, "upload status")
+}
+
+func (c *Client) doSessionRequest(httpClient *http.Client, req *http.Request, op string) (*uploadResponse, int64, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to upload video: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		var result uploadResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", errInvalidResponse, err)
+		}
+		return &result, 0, nil
+	case statusResume:
+		return nil, nextOffset(resp.Header.Get("Range")), nil
+	default:
+		return nil, 0, newStatusError(op, resp)
+	}
+}
+
+func nextOffset(rangeHeader string) int64 {
+	_, last, ok := strings.Cut(strings.TrimPrefix(rangeHeader, "bytes="), "-")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n + 1
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type AuthStatus struct {
+	Authenticated   bool
+	Expiry          time.Time
+	HasRefreshToken bool
+	Channel         string
+}
+
+type channelsResponse struct {
+	Items []struct {
+		Snippet struct {
+			Title string `json:"title"`
+		} `json:"snippet"`
+	} `json:"items"`
+}
+
+func (c *Client) Status(ctx context.Context) (*AuthStatus, error) {
+	if err := c.auth.LoadToken(); err != nil {
+		return nil, err
+	}
+
+	status := &AuthStatus{
+		Authenticated:   c.auth.IsAuthenticated(),
+		Expiry:          c.auth.token.Expiry,
+		HasRefreshToken: c.auth.token.RefreshToken != "",
+	}
+
+	channel, err := c.channelName(ctx)
+	if err != nil {
+		return status, err
+	}
+	status.Channel = channel
+	return status, nil
+}
+
+func (c *Client) channelName(ctx context.Context) (string, error) {
+	httpClient, err := c.auth.Client(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get auth client: %w", err)
+	}
+
+	url := fmt.Sprintf("%s?part=snippet&mine=true", c.channelsURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch channel: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("channel lookup failed: %s", string(respBody))
+	}
+
+	var channels channelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&channels); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(channels.Items) == 0 {
+		return "", errors.New("no channel found for this account")
+	}
+	return channels.Items[0].Snippet.Title, nil
+}
@@ -0,0 +1,104 @@
+package youtube
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestClientStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       oauth2.Token
+		body        string
+		wantAuth    bool
+		wantRefresh bool
+		wantChannel string
+		wantErr     bool
+	}{
+		{
+			name:        "validWithRefresh",
+			token:       oauth2.Token{AccessToken: "tok", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)},
+			body:        `{"items":[{"snippet":{"title":"Craft Stories"}}]}`,
+			wantAuth:    true,
+			wantRefresh: true,
+			wantChannel: "Craft Stories",
+		},
+		{
+			name:        "validWithoutRefresh",
+			token:       oauth2.Token{AccessToken: "tok", Expiry: time.Now().Add(time.Hour)},
+			body:        `{"items":[{"snippet":{"title":"Craft Stories"}}]}`,
+			wantAuth:    true,
+			wantChannel: "Craft Stories",
+		},
+		{
+			name:     "noChannel",
+			token:    oauth2.Token{AccessToken: "tok", Expiry: time.Now().Add(time.Hour)},
+			body:     `{"items":[]}`,
+			wantAuth: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/channels" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if got := r.URL.Query().Get("mine"); got != "true" {
+					t.Errorf("mine = %q, want true", got)
+				}
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server)
+			client.channelsURL = server.URL + "/channels"
+			writeToken(t, client.auth.tokenPath, tt.token)
+
+			status, err := client.Status(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Status() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if status.Authenticated != tt.wantAuth {
+				t.Errorf("Authenticated = %v, want %v", status.Authenticated, tt.wantAuth)
+			}
+			if status.HasRefreshToken != tt.wantRefresh {
+				t.Errorf("HasRefreshToken = %v, want %v", status.HasRefreshToken, tt.wantRefresh)
+			}
+			if !status.Expiry.Equal(tt.token.Expiry) {
+				t.Errorf("Expiry = %v, want %v", status.Expiry, tt.token.Expiry)
+			}
+			if status.Channel != tt.wantChannel {
+				t.Errorf("Channel = %q, want %q", status.Channel, tt.wantChannel)
+			}
+		})
+	}
+}
+
+func TestClientStatusNoToken(t *testing.T) {
+	auth := NewAuth("id", "secret", filepath.Join(t.TempDir(), "missing.json"))
+	status, err := NewClient(Config{Auth: auth}).Status(context.Background())
+	if err == nil || status != nil {
+		t.Errorf("Status() = %v, %v, want nil status and error", status, err)
+	}
+}
+
+func writeToken(t *testing.T, path string, token oauth2.Token) {
+	t.Helper()
+	data, err := json.Marshal(token)
+	if err != nil {
+		t.Fatalf("marshal token: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("write token: %v", err)
+	}
+}
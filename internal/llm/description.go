@@ -0,0 +1,29 @@
+package llm
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const MaxDescriptionLength = 5000
+
+var descriptionReplacer = strings.NewReplacer("<", "", ">", "")
+
+func CleanDescription(raw string) string {
+	description := strings.TrimSpace(raw)
+	description = strings.Trim(description, "\"'")
+	description = descriptionReplacer.Replace(description)
+
+	return TruncateDescription(description, MaxDescriptionLength)
+}
+
+func TruncateDescription(description string, limit int) string {
+	if len(description) <= limit {
+		return description
+	}
+	cut := max(limit, 0)
+	for cut > 0 && !utf8.RuneStart(description[cut]) {
+		cut--
+	}
+	return strings.TrimSpace(description[:cut])
+}
@@ -0,0 +1,53 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCleanDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "trimmed", raw: "  \"A story <b>about</b> cats\"  ", want: "A story babout/b cats"},
+		{name: "asciiOverLimit", raw: strings.Repeat("a", MaxDescriptionLength+10), want: strings.Repeat("a", MaxDescriptionLength)},
+		{name: "multiByteCutOnRuneBoundary", raw: strings.Repeat("é", MaxDescriptionLength), want: strings.Repeat("é", MaxDescriptionLength/2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CleanDescription(tt.raw)
+			if got != tt.want {
+				t.Errorf("CleanDescription() = %q (%d bytes), want %q (%d bytes)", got, len(got), tt.want, len(tt.want))
+			}
+			if len(got) > MaxDescriptionLength || !utf8.ValidString(got) {
+				t.Errorf("CleanDescription() returned %d bytes, valid UTF-8 = %v", len(got), utf8.ValidString(got))
+			}
+		})
+	}
+}
+
+func TestTruncateDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		limit       int
+		want        string
+	}{
+		{name: "withinLimit", description: "short", limit: 10, want: "short"},
+		{name: "cutAtLimit", description: "hello world", limit: 5, want: "hello"},
+		{name: "backsUpToRuneStart", description: "ab😀cd", limit: 4, want: "ab"},
+		{name: "zeroLimit", description: "hello", limit: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateDescription(tt.description, tt.limit); got != tt.want {
+				t.Errorf("TruncateDescription() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
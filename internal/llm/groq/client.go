@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/conneroisu/groq-go"
+	"golang.org/x/time/rate"
 
 	"craftstory/internal/llm"
 	"craftstory/pkg/prompts"
@@ -16,28 +19,45 @@ import (
 var _ llm.Client = (*Client)(nil)
 
 type Client struct {
-	client  *groq.Client
-	model   groq.ChatModel
-	prompts *prompts.Prompts
+	client   *groq.Client
+	model    groq.ChatModel
+	prompts  *prompts.Prompts
+	limiter  *rate.Limiter
+	language string
 }
 
-func NewClient(apiKey, model string, p *prompts.Prompts) (*Client, error) {
-	client, err := groq.NewClient(apiKey)
+func NewClient(apiKey, model, language string, requestsPerMinute int, p *prompts.Prompts, transport http.RoundTripper) (*Client, error) {
+	var opts []groq.Opts
+	if transport != nil {
+		opts = append(opts, groq.WithClient(&http.Client{Transport: transport}))
+	}
+
+	client, err := groq.NewClient(apiKey, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("create groq client: %w", err)
 	}
 
 	return &Client{
-		client:  client,
-		model:   groq.ChatModel(model),
-		prompts: p,
+		client:   client,
+		model:    groq.ChatModel(model),
+		prompts:  p,
+		limiter:  newLimiter(requestsPerMinute),
+		language: prompts.LanguageName(language),
 	}, nil
 }
 
+func newLimiter(requestsPerMinute int) *rate.Limiter {
+	if requestsPerMinute <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	return rate.NewLimiter(rate.Every(time.Minute/time.Duration(requestsPerMinute)), 1)
+}
+
 func (c *Client) GenerateScript(ctx context.Context, topic string, wordCount int) (string, error) {
 	prompt, err := c.prompts.RenderScript(prompts.ScriptParams{
 		Topic:     topic,
 		WordCount: wordCount,
+		Language:  c.language,
 	})
 	if err != nil {
 		return "", fmt.Errorf("render prompt: %w", err)
@@ -52,6 +72,7 @@ func (c *Client) GenerateConversation(ctx context.Context, topic string, speaker
 		SpeakerList:  strings.Join(speakers, ", "),
 		FirstSpeaker: speakers[0],
 		LastSpeaker:  speakers[len(speakers)-1],
+		Language:     c.language,
 	})
 	if err != nil {
 		return "", fmt.Errorf("render prompt: %w", err)
@@ -128,6 +149,20 @@ func cleanTitle(raw string) string {
 	return title
 }
 
+func (c *Client) GenerateDescription(ctx context.Context, script string) (string, error) {
+	prompt, err := c.prompts.RenderDescription(prompts.DescriptionParams{Script: script})
+	if err != nil {
+		return "", fmt.Errorf("render prompt: %w", err)
+	}
+
+	content, err := c.generate(ctx, c.prompts.System.Description, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	return llm.CleanDescription(content), nil
+}
+
 func (c *Client) GenerateTags(ctx context.Context, script string, count int) ([]string, error) {
 	prompt, err := c.prompts.RenderTags(prompts.TagsParams{Script: script, Count: count})
 	if err != nil {
@@ -148,6 +183,8 @@ func (c *Client) GenerateTags(ctx context.Context, script string, count int) ([]
 }
 
 func parseJSONArray[T any](content string, keys []string) ([]T, error) {
+	content = cleanJSONResponse(content)
+
 	var direct []T
 	if err := json.Unmarshal([]byte(content), &direct); err == nil && len(direct) > 0 {
 		return direct, nil
@@ -170,7 +207,19 @@ func parseJSONArray[T any](content string, keys []string) ([]T, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("no items found in response")
+	return nil, fmt.Errorf("no items found in response: %w", llm.ErrEmptyResponse)
+}
+
+func cleanJSONResponse(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") {
+		return content
+	}
+
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimPrefix(content, "json")
+	content = strings.TrimSuffix(strings.TrimSpace(content), "```")
+	return strings.TrimSpace(content)
 }
 
 func cleanTags(tags []string) []string {
@@ -201,6 +250,12 @@ func (c *Client) generateJSONContent(ctx context.Context, systemPrompt, userProm
 }
 
 func (c *Client) doGenerate(ctx context.Context, systemPrompt, userPrompt string, jsonMode bool) (string, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return "", fmt.Errorf("wait for rate limit: %w", err)
+		}
+	}
+
 	req := groq.ChatCompletionRequest{
 		Model: c.model,
 		Messages: []groq.ChatCompletionMessage{
@@ -219,12 +274,12 @@ func (c *Client) doGenerate(ctx context.Context, systemPrompt, userPrompt string
 	}
 
 	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response")
+		return "", fmt.Errorf("no response: %w", llm.ErrEmptyResponse)
 	}
 
 	content := resp.Choices[0].Message.Content
 	if content == "" {
-		return "", fmt.Errorf("empty response")
+		return "", llm.ErrEmptyResponse
 	}
 
 	return content, nil
@@ -3,10 +3,14 @@ package groq
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/conneroisu/groq-go"
 
@@ -41,6 +45,7 @@ func testPrompts() *prompts.Prompts {
 			Conversation: "You are a conversation writer.",
 			Visuals:      "You generate visual cues as JSON.",
 			Title:        "You generate titles.",
+			Description:  "You write descriptions.",
 		},
 		Script: prompts.ScriptPrompts{
 			Single:       "Write about {{.Topic}} in {{.WordCount}} words.",
@@ -50,6 +55,9 @@ func testPrompts() *prompts.Prompts {
 		Title: prompts.TitlePrompts{
 			Generate: "Generate a title for: {{.Script}}",
 		},
+		Description: prompts.DescriptionPrompts{
+			Generate: "Describe: {{.Script}}",
+		},
 	}
 }
 
@@ -502,6 +510,159 @@ func TestGenerateTitle(t *testing.T) {
 	}
 }
 
+func TestGenerateDescription(t *testing.T) {
+	tests := []struct {
+		name            string
+		script          string
+		responseBody    string
+		statusCode      int
+		wantErr         bool
+		wantErrContain  string
+		wantDescription string
+	}{
+		{
+			name:            "successfulDescription",
+			script:          "This is a story about adventure and discovery.",
+			responseBody:    mustJSON(makeGroqResponse("An epic journey you won't forget.\n\n#shorts #adventure")),
+			statusCode:      http.StatusOK,
+			wantDescription: "An epic journey you won't forget.\n\n#shorts #adventure",
+		},
+		{
+			name:            "stripsQuotesAndAngleBrackets",
+			script:          "A tale of love and loss.",
+			responseBody:    mustJSON(makeGroqResponse(`"Love <3 conquers all"`)),
+			statusCode:      http.StatusOK,
+			wantDescription: "Love 3 conquers all",
+		},
+		{
+			name:            "truncatesToLimit",
+			script:          "A very long story.",
+			responseBody:    mustJSON(makeGroqResponse(strings.Repeat("é", llm.MaxDescriptionLength+100))),
+			statusCode:      http.StatusOK,
+			wantDescription: strings.Repeat("é", llm.MaxDescriptionLength/2),
+		},
+		{
+			name:           "emptyResponse",
+			script:         "test script",
+			responseBody:   mustJSON(makeGroqResponse("")),
+			statusCode:     http.StatusOK,
+			wantErr:        true,
+			wantErrContain: "empty response",
+		},
+		{
+			name:           "httpErrorNotFound",
+			script:         "test script",
+			responseBody:   `{"error": {"message": "not found", "type": "not_found_error"}}`,
+			statusCode:     http.StatusNotFound,
+			wantErr:        true,
+			wantErrContain: "generate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server.URL)
+			got, err := client.GenerateDescription(context.Background(), tt.script)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("GenerateDescription() expected error containing %q, got nil", tt.wantErrContain)
+					return
+				}
+				if !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("GenerateDescription() error = %v, want error containing %q", err, tt.wantErrContain)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("GenerateDescription() unexpected error: %v", err)
+				return
+			}
+
+			if got != tt.wantDescription {
+				t.Errorf("GenerateDescription() = %q, want %q", got, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestGenerateTags(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		wantErr        bool
+		wantErrContain string
+		wantTags       []string
+	}{
+		{
+			name:     "directArray",
+			content:  `["space", "nasa", "mars"]`,
+			wantTags: []string{"space", "nasa", "mars"},
+		},
+		{
+			name:     "wrappedObject",
+			content:  `{"tags": ["space", "nasa"]}`,
+			wantTags: []string{"space", "nasa"},
+		},
+		{
+			name:     "markdownWrapped",
+			content:  "```json\n{\"tags\": [\"space\", \"nasa\"]}\n```",
+			wantTags: []string{"space", "nasa"},
+		},
+		{
+			name:     "markdownWrappedWithoutLanguage",
+			content:  "```\n[\"space\"]\n```",
+			wantTags: []string{"space"},
+		},
+		{
+			name:     "cleansHashtagsAndDuplicates",
+			content:  `["#Space", "space", " NASA ", ""]`,
+			wantTags: []string{"space", "nasa"},
+		},
+		{
+			name:           "invalidJSON",
+			content:        "space, nasa",
+			wantErr:        true,
+			wantErrContain: "parse response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(mustJSON(makeGroqResponse(tt.content))))
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server.URL)
+			got, err := client.GenerateTags(context.Background(), "script", 5)
+
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrContain) {
+					t.Errorf("GenerateTags() error = %v, want error containing %q", err, tt.wantErrContain)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GenerateTags() unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.wantTags) {
+				t.Errorf("GenerateTags() = %v, want %v", got, tt.wantTags)
+			}
+		})
+	}
+}
+
 func TestRequestValidation(t *testing.T) {
 	t.Run("verifiesRequestBody", func(t *testing.T) {
 		var receivedBody map[string]any
@@ -589,6 +750,69 @@ func TestRateLimitError(t *testing.T) {
 	})
 }
 
+func TestRateLimiter(t *testing.T) {
+	t.Run("throttlesConcurrentCalls", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(mustJSON(makeGroqResponse("script"))))
+		}))
+		defer server.Close()
+
+		client := newTestClient(t, server.URL)
+		client.limiter = newLimiter(1200)
+
+		const calls = 4
+		start := time.Now()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, calls)
+		for range calls {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.GenerateScript(context.Background(), "test", 100)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("GenerateScript() error = %v", err)
+			}
+		}
+
+		minElapsed := (calls - 1) * 50 * time.Millisecond
+		if elapsed := time.Since(start); elapsed < minElapsed {
+			t.Errorf("%d calls took %v, want at least %v at 1200 requests/min", calls, elapsed, minElapsed)
+		}
+	})
+
+	t.Run("respectsContextWhileWaiting", func(t *testing.T) {
+		client := newTestClient(t, "http://127.0.0.1:0")
+		client.limiter = newLimiter(1)
+		client.limiter.Allow()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GenerateScript(ctx, "test", 100)
+		if err == nil || !strings.Contains(err.Error(), "rate limit") {
+			t.Errorf("expected rate limit wait error, got %v", err)
+		}
+	})
+
+	t.Run("unlimitedWhenNotConfigured", func(t *testing.T) {
+		limiter := newLimiter(0)
+		for range 100 {
+			if !limiter.Allow() {
+				t.Fatal("expected unlimited limiter to allow all requests")
+			}
+		}
+	})
+}
+
 func mustJSON(v any) string {
 	b, err := json.Marshal(v)
 	if err != nil {
@@ -596,3 +820,26 @@ func mustJSON(v any) string {
 	}
 	return string(b)
 }
+
+func TestGenerateScriptLanguage(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(mustJSON(makeGroqResponse("Hola mundo."))))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	client.prompts.Script.Single = "Write about {{.Topic}} in {{.Language}}."
+	client.language = prompts.LanguageName("es")
+
+	if _, err := client.GenerateScript(context.Background(), "space", 100); err != nil {
+		t.Fatalf("GenerateScript() error = %v", err)
+	}
+
+	if !strings.Contains(body, "Write about space in Spanish.") {
+		t.Errorf("request body missing rendered language, got %s", body)
+	}
+}
@@ -1,6 +1,14 @@
 package llm
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrEmptyResponse = errors.New("llm returned an empty response")
+	ErrNoProviders   = errors.New("no llm providers configured")
+)
 
 type VisualCue struct {
 	Keyword     string `json:"keyword"`
@@ -13,5 +21,6 @@ type Client interface {
 	GenerateConversation(ctx context.Context, topic string, speakers []string, wordCount int) (string, error)
 	GenerateVisuals(ctx context.Context, script string, count int) ([]VisualCue, error)
 	GenerateTitle(ctx context.Context, script string) (string, error)
+	GenerateDescription(ctx context.Context, script string) (string, error)
 	GenerateTags(ctx context.Context, script string, count int) ([]string, error)
 }
@@ -0,0 +1,182 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+var stageBuckets = []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+type Collector struct {
+	mu         sync.Mutex
+	attempted  int
+	succeeded  int
+	failed     int
+	uploads    map[uploadKey]int
+	stages     map[string]*histogram
+	queueDepth func() int
+}
+
+type uploadKey struct {
+	platform string
+	result   string
+}
+
+type histogram struct {
+	counts []int
+	sum    float64
+	count  int
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		uploads: make(map[uploadKey]int),
+		stages:  make(map[string]*histogram),
+	}
+}
+
+func (c *Collector) SetQueueDepth(fn func() int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queueDepth = fn
+}
+
+func (c *Collector) ObserveGeneration(stages map[string]time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.attempted++
+	if err != nil {
+		c.failed++
+		return
+	}
+	c.succeeded++
+
+	for stage, d := range stages {
+		h, ok := c.stages[stage]
+		if !ok {
+			h = &histogram{counts: make([]int, len(stageBuckets))}
+			c.stages[stage] = h
+		}
+		h.observe(d.Seconds())
+	}
+}
+
+func (c *Collector) ObserveUpload(platform string, success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if platform == "" {
+		platform = "unknown"
+	}
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	c.uploads[uploadKey{platform: platform, result: result}]++
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range stageBuckets {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	c.write(w)
+}
+
+func (c *Collector) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeCounter(w, "craftstory_generations_attempted_total", "Video generations attempted.", c.attempted)
+	writeCounter(w, "craftstory_generations_succeeded_total", "Video generations that produced a video.", c.succeeded)
+	writeCounter(w, "craftstory_generations_failed_total", "Video generations that failed.", c.failed)
+
+	fmt.Fprintln(w, "# HELP craftstory_uploads_total Video uploads by platform and result.")
+	fmt.Fprintln(w, "# TYPE craftstory_uploads_total counter")
+	keys := make([]uploadKey, 0, len(c.uploads))
+	for key := range c.uploads {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b uploadKey) int {
+		return strings.Compare(a.platform+a.result, b.platform+b.result)
+	})
+	for _, key := range keys {
+		fmt.Fprintf(w, "craftstory_uploads_total{platform=%q,result=%q} %d\n", key.platform, key.result, c.uploads[key])
+	}
+
+	if c.queueDepth != nil {
+		fmt.Fprintln(w, "# HELP craftstory_queue_depth Videos waiting for approval.")
+		fmt.Fprintln(w, "# TYPE craftstory_queue_depth gauge")
+		fmt.Fprintf(w, "craftstory_queue_depth %d\n", c.queueDepth())
+	}
+
+	fmt.Fprintln(w, "# HELP craftstory_stage_duration_seconds Duration of each generation stage.")
+	fmt.Fprintln(w, "# TYPE craftstory_stage_duration_seconds histogram")
+	stages := make([]string, 0, len(c.stages))
+	for stage := range c.stages {
+		stages = append(stages, stage)
+	}
+	slices.Sort(stages)
+	for _, stage := range stages {
+		h := c.stages[stage]
+		for i, bound := range stageBuckets {
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			fmt.Fprintf(w, "craftstory_stage_duration_seconds_bucket{stage=%q,le=%q} %d\n", stage, le, h.counts[i])
+		}
+		fmt.Fprintf(w, "craftstory_stage_duration_seconds_bucket{stage=%q,le=\"+Inf\"} %d\n", stage, h.count)
+		fmt.Fprintf(w, "craftstory_stage_duration_seconds_sum{stage=%q} %g\n", stage, h.sum)
+		fmt.Fprintf(w, "craftstory_stage_duration_seconds_count{stage=%q} %d\n", stage, h.count)
+	}
+}
+
+func writeCounter(w io.Writer, name, help string, value int) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	fmt.Fprintf(w, "%s %d\n", name, value)
+}
+
+func (c *Collector) Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", c)
+
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("metrics server: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Failed to shut down metrics server", "error", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics server: %w", err)
+	}
+	return nil
+}
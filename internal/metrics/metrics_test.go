@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("scrape failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("scrape status = %d", resp.StatusCode)
+	}
+	return string(body)
+}
+
+func TestCollectorEndpoint(t *testing.T) {
+	collector := NewCollector()
+	collector.SetQueueDepth(func() int { return 3 })
+
+	collector.ObserveGeneration(map[string]time.Duration{
+		"script":   800 * time.Millisecond,
+		"assembly": 45 * time.Second,
+	}, nil)
+	collector.ObserveGeneration(nil, errors.New("tts failed"))
+	collector.ObserveUpload("youtube", true)
+	collector.ObserveUpload("tiktok", false)
+	collector.ObserveUpload("", false)
+
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	body := scrape(t, server.URL)
+
+	want := []string{
+		"craftstory_generations_attempted_total 2",
+		"craftstory_generations_succeeded_total 1",
+		"craftstory_generations_failed_total 1",
+		`craftstory_uploads_total{platform="youtube",result="success"} 1`,
+		`craftstory_uploads_total{platform="tiktok",result="failure"} 1`,
+		`craftstory_uploads_total{platform="unknown",result="failure"} 1`,
+		"craftstory_queue_depth 3",
+		"# TYPE craftstory_stage_duration_seconds histogram",
+		`craftstory_stage_duration_seconds_bucket{stage="script",le="0.5"} 0`,
+		`craftstory_stage_duration_seconds_bucket{stage="script",le="1"} 1`,
+		`craftstory_stage_duration_seconds_bucket{stage="assembly",le="30"} 0`,
+		`craftstory_stage_duration_seconds_bucket{stage="assembly",le="60"} 1`,
+		`craftstory_stage_duration_seconds_bucket{stage="assembly",le="+Inf"} 1`,
+		`craftstory_stage_duration_seconds_sum{stage="assembly"} 45`,
+		`craftstory_stage_duration_seconds_count{stage="script"} 1`,
+	}
+	for _, line := range want {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, body)
+		}
+	}
+}
+
+func TestServeShutsDownOnCancel(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewCollector().Serve(ctx, addr) }()
+
+	var body string
+	for range 50 {
+		resp, err := http.Get("http://" + addr + "/metrics")
+		if err == nil {
+			data, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			body = string(data)
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !strings.Contains(body, "craftstory_generations_attempted_total 0") {
+		t.Errorf("unexpected metrics body:\n%s", body)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Serve() did not return after cancel")
+	}
+}
@@ -0,0 +1,109 @@
+package review
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+
+	"craftstory/internal/distribution/telegram"
+)
+
+var ErrEmptyQueue = errors.New("queue is empty")
+
+type Browser struct {
+	dataDir string
+	queue   *telegram.VideoQueue
+	items   []telegram.QueuedVideo
+	cursor  int
+}
+
+func NewBrowser(dataDir string) *Browser {
+	b := &Browser{dataDir: dataDir}
+	b.Refresh()
+	return b
+}
+
+func (b *Browser) Refresh() {
+	b.queue = telegram.NewVideoQueue(b.dataDir)
+	b.items = b.queue.List()
+	b.clamp()
+}
+
+func (b *Browser) Items() []telegram.QueuedVideo {
+	return b.items
+}
+
+func (b *Browser) Cursor() int {
+	return b.cursor
+}
+
+func (b *Browser) Select(i int) {
+	b.cursor = i
+	b.clamp()
+}
+
+func (b *Browser) Next() {
+	b.Select(b.cursor + 1)
+}
+
+func (b *Browser) Prev() {
+	b.Select(b.cursor - 1)
+}
+
+func (b *Browser) Selected() (telegram.QueuedVideo, error) {
+	if len(b.items) == 0 {
+		return telegram.QueuedVideo{}, ErrEmptyQueue
+	}
+	return b.items[b.cursor], nil
+}
+
+func (b *Browser) Approve() (telegram.QueuedVideo, error) {
+	return b.remove()
+}
+
+func (b *Browser) Reject() (telegram.QueuedVideo, error) {
+	video, err := b.remove()
+	if err != nil {
+		return video, err
+	}
+	if video.PreviewPath != "" {
+		if err := os.Remove(video.PreviewPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Failed to cleanup preview file", "path", video.PreviewPath, "error", err)
+		}
+	}
+	return video, nil
+}
+
+func (b *Browser) Requeue(video telegram.QueuedVideo) error {
+	video.MessageID = 0
+	video.ChatID = 0
+	if err := b.queue.Add(video); err != nil {
+		return err
+	}
+	b.items = b.queue.List()
+	b.clamp()
+	return nil
+}
+
+func (b *Browser) remove() (telegram.QueuedVideo, error) {
+	selected, err := b.Selected()
+	if err != nil {
+		return selected, err
+	}
+
+	b.queue = telegram.NewVideoQueue(b.dataDir)
+	removed := b.queue.FindAndRemove(func(v telegram.QueuedVideo) bool {
+		return v.VideoPath == selected.VideoPath
+	})
+	b.items = b.queue.List()
+	b.clamp()
+
+	if removed == nil {
+		return selected, errors.New("video is no longer in the queue")
+	}
+	return *removed, nil
+}
+
+func (b *Browser) clamp() {
+	b.cursor = max(min(b.cursor, len(b.items)-1), 0)
+}
@@ -0,0 +1,189 @@
+package review
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"craftstory/internal/distribution/telegram"
+)
+
+func seedQueue(t *testing.T, dir string, videos ...telegram.QueuedVideo) {
+	t.Helper()
+	queue := telegram.NewVideoQueue(dir)
+	for _, v := range videos {
+		if err := queue.Add(v); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func titles(videos []telegram.QueuedVideo) []string {
+	result := make([]string, len(videos))
+	for i, v := range videos {
+		result[i] = v.Title
+	}
+	return result
+}
+
+func TestBrowserTransitions(t *testing.T) {
+	tests := []struct {
+		name       string
+		cursor     int
+		action     func(*Browser) (telegram.QueuedVideo, error)
+		wantTitle  string
+		wantItems  []string
+		wantCursor int
+	}{
+		{
+			name:       "approveRemovesSelected",
+			cursor:     1,
+			action:     (*Browser).Approve,
+			wantTitle:  "Second",
+			wantItems:  []string{"First", "Third"},
+			wantCursor: 1,
+		},
+		{
+			name:       "rejectRemovesSelected",
+			action:     (*Browser).Reject,
+			wantTitle:  "First",
+			wantItems:  []string{"Second", "Third"},
+			wantCursor: 0,
+		},
+		{
+			name:       "removingLastItemMovesCursorBack",
+			cursor:     2,
+			action:     (*Browser).Approve,
+			wantTitle:  "Third",
+			wantItems:  []string{"First", "Second"},
+			wantCursor: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			seedQueue(t, dir,
+				telegram.QueuedVideo{Title: "First", VideoPath: "first.mp4"},
+				telegram.QueuedVideo{Title: "Second", VideoPath: "second.mp4"},
+				telegram.QueuedVideo{Title: "Third", VideoPath: "third.mp4"},
+			)
+
+			browser := NewBrowser(dir)
+			browser.Select(tt.cursor)
+
+			video, err := tt.action(browser)
+			if err != nil {
+				t.Fatalf("action error = %v", err)
+			}
+			if video.Title != tt.wantTitle {
+				t.Errorf("removed %q, want %q", video.Title, tt.wantTitle)
+			}
+			if got := titles(browser.Items()); !slices.Equal(got, tt.wantItems) {
+				t.Errorf("Items() = %v, want %v", got, tt.wantItems)
+			}
+			if browser.Cursor() != tt.wantCursor {
+				t.Errorf("Cursor() = %d, want %d", browser.Cursor(), tt.wantCursor)
+			}
+
+			persisted := titles(telegram.NewVideoQueue(dir).List())
+			if !slices.Equal(persisted, tt.wantItems) {
+				t.Errorf("persisted queue = %v, want %v", persisted, tt.wantItems)
+			}
+		})
+	}
+}
+
+func TestBrowserNavigation(t *testing.T) {
+	dir := t.TempDir()
+	seedQueue(t, dir,
+		telegram.QueuedVideo{Title: "First", VideoPath: "first.mp4"},
+		telegram.QueuedVideo{Title: "Second", VideoPath: "second.mp4"},
+	)
+	browser := NewBrowser(dir)
+
+	steps := []struct {
+		name string
+		move func()
+		want int
+	}{
+		{name: "prevAtStartStays", move: browser.Prev, want: 0},
+		{name: "next", move: browser.Next, want: 1},
+		{name: "nextAtEndStays", move: browser.Next, want: 1},
+		{name: "prev", move: browser.Prev, want: 0},
+	}
+
+	for _, step := range steps {
+		step.move()
+		if got := browser.Cursor(); got != step.want {
+			t.Errorf("%s: Cursor() = %d, want %d", step.name, got, step.want)
+		}
+	}
+}
+
+func TestBrowserEmptyQueue(t *testing.T) {
+	browser := NewBrowser(t.TempDir())
+
+	if _, err := browser.Approve(); !errors.Is(err, ErrEmptyQueue) {
+		t.Errorf("Approve() error = %v, want ErrEmptyQueue", err)
+	}
+	if _, err := browser.Reject(); !errors.Is(err, ErrEmptyQueue) {
+		t.Errorf("Reject() error = %v, want ErrEmptyQueue", err)
+	}
+}
+
+func TestBrowserSeesBotChanges(t *testing.T) {
+	dir := t.TempDir()
+	seedQueue(t, dir, telegram.QueuedVideo{Title: "First", VideoPath: "first.mp4"})
+	browser := NewBrowser(dir)
+
+	bot := telegram.NewVideoQueue(dir)
+	bot.FindAndRemove(func(v telegram.QueuedVideo) bool { return v.VideoPath == "first.mp4" })
+
+	if _, err := browser.Approve(); err == nil {
+		t.Error("Approve() expected error for a video the bot already handled")
+	}
+	if len(browser.Items()) != 0 {
+		t.Errorf("Items() = %v, want empty after refresh", titles(browser.Items()))
+	}
+}
+
+func TestBrowserRejectRemovesPreview(t *testing.T) {
+	dir := t.TempDir()
+	preview := filepath.Join(dir, "preview.mp4")
+	if err := os.WriteFile(preview, []byte("preview"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	seedQueue(t, dir, telegram.QueuedVideo{Title: "First", VideoPath: "first.mp4", PreviewPath: preview})
+
+	if _, err := NewBrowser(dir).Reject(); err != nil {
+		t.Fatalf("Reject() error = %v", err)
+	}
+	if _, err := os.Stat(preview); !os.IsNotExist(err) {
+		t.Errorf("preview file still exists after reject")
+	}
+}
+
+func TestBrowserRequeue(t *testing.T) {
+	dir := t.TempDir()
+	seedQueue(t, dir, telegram.QueuedVideo{Title: "First", VideoPath: "first.mp4"})
+	browser := NewBrowser(dir)
+
+	video, err := browser.Approve()
+	if err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	video.MessageID = 42
+	if err := browser.Requeue(video); err != nil {
+		t.Fatalf("Requeue() error = %v", err)
+	}
+
+	items := telegram.NewVideoQueue(dir).List()
+	if len(items) != 1 || items[0].Title != "First" || items[0].MessageID != 0 {
+		t.Errorf("persisted queue = %+v, want First requeued without message ID", items)
+	}
+}
@@ -0,0 +1,202 @@
+package duckduckgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	baseURL        = "https://duckduckgo.com"
+	defaultTimeout = 15 * time.Second
+	minImgWidth    = 400
+	minImgHeight   = 300
+	browserAgent   = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+var vqdPattern = regexp.MustCompile(`vqd=["']?([\w-]+)["']?`)
+
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+type Config struct {
+	Timeout   time.Duration
+	Transport http.RoundTripper
+}
+
+type Result struct {
+	Title    string
+	ImageURL string
+	ThumbURL string
+	Width    int
+	Height   int
+}
+
+type searchResponse struct {
+	Results []searchItem `json:"results"`
+}
+
+type searchItem struct {
+	Title     string `json:"title"`
+	Image     string `json:"image"`
+	Thumbnail string `json:"thumbnail"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+}
+
+func NewClient(cfg Config) *Client {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Client{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: cfg.Transport,
+		},
+		baseURL: baseURL,
+	}
+}
+
+func (c *Client) Search(ctx context.Context, query string, count int) ([]Result, error) {
+	vqd, err := c.token(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("l", "us-en")
+	params.Set("o", "json")
+	params.Set("q", query)
+	params.Set("vqd", vqd)
+	params.Set("f", ",,,,,")
+	params.Set("p", "1")
+
+	body, err := c.get(ctx, c.baseURL+"/i.js?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResp searchResponse
+	if err := json.Unmarshal(body, &searchResp); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+
+	return filterResults(searchResp.Results, count), nil
+}
+
+func (c *Client) SearchImages(ctx context.Context, query string, count int) ([]string, error) {
+	results, err := c.Search(ctx, query, count)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, len(results))
+	for i, result := range results {
+		urls[i] = result.ImageURL
+	}
+	return urls, nil
+}
+
+func (c *Client) DownloadImage(ctx context.Context, imageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", browserAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download image: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download image: %s", resp.Status)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(strings.ToLower(contentType), "image/") {
+		return nil, fmt.Errorf("invalid content type: %s", contentType)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read image data: %w", err)
+	}
+
+	return data, nil
+}
+
+func (c *Client) token(ctx context.Context, query string) (string, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("ia", "images")
+	params.Set("iax", "images")
+
+	body, err := c.get(ctx, c.baseURL+"/?"+params.Encode())
+	if err != nil {
+		return "", err
+	}
+
+	match := vqdPattern.FindSubmatch(body)
+	if match == nil {
+		return "", fmt.Errorf("search token not found")
+	}
+	return string(match[1]), nil
+}
+
+func (c *Client) get(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("User-Agent", browserAgent)
+	req.Header.Set("Referer", c.baseURL+"/")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("search error: %s, body: %s", resp.Status, string(body))
+	}
+
+	return body, nil
+}
+
+func filterResults(items []searchItem, count int) []Result {
+	results := make([]Result, 0, count)
+	for _, item := range items {
+		if item.Image == "" || item.Width < minImgWidth || item.Height < minImgHeight {
+			continue
+		}
+		results = append(results, Result{
+			Title:    item.Title,
+			ImageURL: item.Image,
+			ThumbURL: item.Thumbnail,
+			Width:    item.Width,
+			Height:   item.Height,
+		})
+		if len(results) >= count {
+			break
+		}
+	}
+	return results
+}
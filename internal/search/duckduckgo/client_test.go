@@ -0,0 +1,133 @@
+package duckduckgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestClient(server *httptest.Server) *Client {
+	client := NewClient(Config{})
+	client.baseURL = server.URL
+	return client
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name        string
+		page        string
+		items       []searchItem
+		count       int
+		wantErr     bool
+		wantResults []string
+	}{
+		{
+			name: "filtersSmallImages",
+			page: `<script>vqd="4-12345";</script>`,
+			items: []searchItem{
+				{Title: "Small", Image: "http://example.com/small.jpg", Width: 100, Height: 100},
+				{Title: "Cat 1", Image: "http://example.com/cat1.jpg", Width: 1200, Height: 800},
+				{Title: "Missing", Width: 1200, Height: 800},
+				{Title: "Cat 2", Image: "http://example.com/cat2.jpg", Width: 800, Height: 600},
+			},
+			count:       5,
+			wantResults: []string{"http://example.com/cat1.jpg", "http://example.com/cat2.jpg"},
+		},
+		{
+			name: "respectsCount",
+			page: `vqd='4-12345'&`,
+			items: []searchItem{
+				{Image: "http://example.com/cat1.jpg", Width: 1200, Height: 800},
+				{Image: "http://example.com/cat2.jpg", Width: 1200, Height: 800},
+			},
+			count:       1,
+			wantResults: []string{"http://example.com/cat1.jpg"},
+		},
+		{
+			name:    "missingToken",
+			page:    `<html>blocked</html>`,
+			count:   5,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/":
+					if r.URL.Query().Get("q") != "cute cats" {
+						t.Errorf("token query = %q, want %q", r.URL.Query().Get("q"), "cute cats")
+					}
+					_, _ = w.Write([]byte(tt.page))
+				case "/i.js":
+					if r.URL.Query().Get("vqd") != "4-12345" {
+						t.Errorf("vqd = %q, want %q", r.URL.Query().Get("vqd"), "4-12345")
+					}
+					_ = json.NewEncoder(w).Encode(searchResponse{Results: tt.items})
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			urls, err := newTestClient(server).SearchImages(context.Background(), "cute cats", tt.count)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SearchImages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(urls) != len(tt.wantResults) {
+				t.Fatalf("SearchImages() = %v, want %v", urls, tt.wantResults)
+			}
+			for i, want := range tt.wantResults {
+				if urls[i] != want {
+					t.Errorf("SearchImages()[%d] = %q, want %q", i, urls[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	if _, err := newTestClient(server).Search(context.Background(), "cats", 5); err == nil {
+		t.Error("Search() expected error for forbidden response")
+	}
+}
+
+func TestDownloadImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		statusCode  int
+		wantErr     bool
+	}{
+		{name: "validImage", contentType: "image/jpeg", statusCode: http.StatusOK},
+		{name: "htmlPage", contentType: "text/html", statusCode: http.StatusOK, wantErr: true},
+		{name: "notFound", contentType: "image/jpeg", statusCode: http.StatusNotFound, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte("image-data"))
+			}))
+			defer server.Close()
+
+			data, err := newTestClient(server).DownloadImage(context.Background(), server.URL+"/img.jpg")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DownloadImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(data) != "image-data" {
+				t.Errorf("DownloadImage() = %q, want %q", data, "image-data")
+			}
+		})
+	}
+}
@@ -14,6 +14,12 @@ type FetcherConfig struct {
 	ImageWidth     int
 	ImageHeight    int
 	MinGap         float64
+	MinDuration    float64
+	MaxDuration    float64
+	MaxCount       int
+	MinWidth       int
+	MinHeight      int
+	Orientation    string
 }
 
 type FetchRequest struct {
@@ -24,21 +30,21 @@ type FetchRequest struct {
 }
 
 type Fetcher struct {
-	imageSearch ImageSearcher
-	gifSearch   GIFSearcher
-	cfg         FetcherConfig
+	images    ImageSource
+	gifSearch GIFSearcher
+	cfg       FetcherConfig
 }
 
-func NewFetcher(imageSearch ImageSearcher, gifSearch GIFSearcher, cfg FetcherConfig) *Fetcher {
+func NewFetcher(images ImageSource, gifSearch GIFSearcher, cfg FetcherConfig) *Fetcher {
 	return &Fetcher{
-		imageSearch: imageSearch,
-		gifSearch:   gifSearch,
-		cfg:         cfg,
+		images:    images,
+		gifSearch: gifSearch,
+		cfg:       cfg,
 	}
 }
 
 func (f *Fetcher) Fetch(ctx context.Context, req FetchRequest) []video.ImageOverlay {
-	if f.imageSearch == nil && f.gifSearch == nil {
+	if f.images == nil && f.gifSearch == nil {
 		slog.Warn("No search clients configured")
 		return nil
 	}
@@ -145,43 +151,79 @@ func (f *Fetcher) fetchGIF(ctx context.Context, query string) ([]byte, string) {
 }
 
 func (f *Fetcher) fetchImage(ctx context.Context, query string) ([]byte, string) {
-	if f.imageSearch == nil {
+	if f.images == nil {
 		slog.Debug("Image search not configured")
 		return nil, ""
 	}
 
-	results, err := f.imageSearch.Search(ctx, query, 5)
+	urls, err := f.images.SearchImages(ctx, query, 5)
 	if err != nil {
 		slog.Warn("Image search failed", "query", query, "error", err)
 		return nil, ""
 	}
-	if len(results) == 0 {
+	if len(urls) == 0 {
 		slog.Debug("No images found", "query", query)
 		return nil, ""
 	}
 
-	for _, result := range results {
-		data, err := f.imageSearch.DownloadImage(ctx, result.ImageURL)
+	var fallback []byte
+	var fallbackExt string
+	for _, imageURL := range urls {
+		data, err := f.images.DownloadImage(ctx, imageURL)
 		if err != nil {
-			slog.Debug("Image download failed", "url", result.ImageURL, "error", err)
+			slog.Debug("Image download failed", "url", imageURL, "error", err)
 			continue
 		}
 		if !isValidImage(data) || len(data) < 10000 {
 			continue
 		}
 
+		width, height, known := imageDimensions(data)
+		if known && (width < f.cfg.MinWidth || height < f.cfg.MinHeight) {
+			slog.Debug("Image below minimum resolution", "url", imageURL, "width", width, "height", height)
+			continue
+		}
+
 		ext := detectImageFormat(data)
 		if ext == "" {
 			ext = ".jpg"
 		}
+		if known && !matchesOrientation(width, height, f.cfg.Orientation) {
+			if fallback == nil {
+				fallback, fallbackExt = data, ext
+			}
+			continue
+		}
 		return data, ext
 	}
 
+	if fallback != nil {
+		slog.Debug("No image matched preferred orientation", "query", query, "orientation", f.cfg.Orientation)
+		return fallback, fallbackExt
+	}
+
 	slog.Debug("All image downloads failed", "query", query)
 	return nil, ""
 }
 
 func (f *Fetcher) enforceConstraints(overlays []video.ImageOverlay) []video.ImageOverlay {
+	if f.cfg.MaxCount > 0 && len(overlays) > f.cfg.MaxCount {
+		slog.Debug("Dropping excess overlays", "count", len(overlays), "max", f.cfg.MaxCount)
+		overlays = overlays[:f.cfg.MaxCount]
+	}
+
+	for i := range overlays {
+		duration := overlays[i].EndTime - overlays[i].StartTime
+		switch {
+		case f.cfg.MinDuration > 0 && duration < f.cfg.MinDuration:
+			slog.Debug("Extending overlay", "index", i, "duration", duration, "min", f.cfg.MinDuration)
+			overlays[i].EndTime = overlays[i].StartTime + f.cfg.MinDuration
+		case f.cfg.MaxDuration > 0 && duration > f.cfg.MaxDuration:
+			slog.Debug("Trimming overlay", "index", i, "duration", duration, "max", f.cfg.MaxDuration)
+			overlays[i].EndTime = overlays[i].StartTime + f.cfg.MaxDuration
+		}
+	}
+
 	if len(overlays) <= 1 {
 		return overlays
 	}
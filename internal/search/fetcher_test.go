@@ -1,6 +1,12 @@
 package search
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"os"
 	"testing"
 
 	"craftstory/internal/speech"
@@ -12,6 +18,9 @@ func TestEnforceConstraints(t *testing.T) {
 		name        string
 		overlays    []video.ImageOverlay
 		minGap      float64
+		minDuration float64
+		maxDuration float64
+		maxCount    int
 		wantCount   int
 		wantEndTime float64
 	}{
@@ -72,11 +81,59 @@ func TestEnforceConstraints(t *testing.T) {
 			wantCount:   2,
 			wantEndTime: 0.5,
 		},
+		{
+			name: "extendsShortOverlay",
+			overlays: []video.ImageOverlay{
+				{ImagePath: "img1.jpg", StartTime: 1, EndTime: 1.5},
+			},
+			minDuration: 2.0,
+			wantCount:   1,
+			wantEndTime: 3,
+		},
+		{
+			name: "trimsLongOverlay",
+			overlays: []video.ImageOverlay{
+				{ImagePath: "img1.jpg", StartTime: 1, EndTime: 9},
+				{ImagePath: "img2.jpg", StartTime: 10, EndTime: 12},
+			},
+			minGap:      1.0,
+			maxDuration: 4.0,
+			wantCount:   2,
+			wantEndTime: 5,
+		},
+		{
+			name: "extensionYieldsToGap",
+			overlays: []video.ImageOverlay{
+				{ImagePath: "img1.jpg", StartTime: 0, EndTime: 1},
+				{ImagePath: "img2.jpg", StartTime: 2.5, EndTime: 5},
+			},
+			minGap:      1.0,
+			minDuration: 3.0,
+			wantCount:   2,
+			wantEndTime: 1.5,
+		},
+		{
+			name: "dropsExcessOverlays",
+			overlays: []video.ImageOverlay{
+				{ImagePath: "img1.jpg", StartTime: 0, EndTime: 2},
+				{ImagePath: "img2.jpg", StartTime: 4, EndTime: 6},
+				{ImagePath: "img3.jpg", StartTime: 8, EndTime: 10},
+			},
+			minGap:      1.0,
+			maxCount:    2,
+			wantCount:   2,
+			wantEndTime: 2,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			f := &Fetcher{cfg: FetcherConfig{MinGap: tt.minGap}}
+			f := &Fetcher{cfg: FetcherConfig{
+				MinGap:      tt.minGap,
+				MinDuration: tt.minDuration,
+				MaxDuration: tt.maxDuration,
+				MaxCount:    tt.maxCount,
+			}}
 			got := f.enforceConstraints(tt.overlays)
 			if len(got) != tt.wantCount {
 				t.Errorf("enforceConstraints() returned %d overlays, want %d", len(got), tt.wantCount)
@@ -88,6 +145,165 @@ func TestEnforceConstraints(t *testing.T) {
 	}
 }
 
+type mockImageSource struct {
+	urls      []string
+	searchErr error
+	images    map[string][]byte
+	queries   []string
+}
+
+func (m *mockImageSource) SearchImages(_ context.Context, query string, _ int) ([]string, error) {
+	m.queries = append(m.queries, query)
+	return m.urls, m.searchErr
+}
+
+func (m *mockImageSource) DownloadImage(_ context.Context, imageURL string) ([]byte, error) {
+	data, ok := m.images[imageURL]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func TestFetchWithImageSource(t *testing.T) {
+	png := append([]byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}, bytes.Repeat([]byte{0}, 20000)...)
+	timings := []speech.WordTiming{
+		{Word: "The", StartTime: 0, EndTime: 0.5},
+		{Word: "dragon", StartTime: 0.5, EndTime: 1.0},
+		{Word: "flew", StartTime: 1.0, EndTime: 1.5},
+		{Word: "home.", StartTime: 1.5, EndTime: 2.0},
+	}
+
+	tests := []struct {
+		name      string
+		source    *mockImageSource
+		wantCount int
+	}{
+		{
+			name: "skipsFailedDownloads",
+			source: &mockImageSource{
+				urls:   []string{"http://a/missing.png", "http://a/tiny.png", "http://a/dragon.png"},
+				images: map[string][]byte{"http://a/tiny.png": png[:500], "http://a/dragon.png": png},
+			},
+			wantCount: 1,
+		},
+		{
+			name:      "searchError",
+			source:    &mockImageSource{searchErr: errors.New("quota exceeded")},
+			wantCount: 0,
+		},
+		{
+			name:      "noResults",
+			source:    &mockImageSource{},
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFetcher(tt.source, nil, FetcherConfig{ImageWidth: 800, ImageHeight: 600, MaxDuration: 1.0})
+			got := f.Fetch(context.Background(), FetchRequest{
+				Visuals:  []VisualCue{{Keyword: "dragon", SearchQuery: "red dragon", Type: "image"}},
+				Timings:  timings,
+				ImageDir: t.TempDir(),
+			})
+
+			if len(tt.source.queries) != 1 || tt.source.queries[0] != "red dragon" {
+				t.Errorf("queries = %v, want [red dragon]", tt.source.queries)
+			}
+			if len(got) != tt.wantCount {
+				t.Fatalf("Fetch() returned %d overlays, want %d", len(got), tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+
+			overlay := got[0]
+			if overlay.StartTime != 0.5 || overlay.EndTime != 1.5 {
+				t.Errorf("overlay time = %.1f-%.1f, want 0.5-1.5", overlay.StartTime, overlay.EndTime)
+			}
+			if overlay.Width != 800 || overlay.Height != 600 || overlay.IsGif {
+				t.Errorf("overlay = %+v, want 800x600 image", overlay)
+			}
+			data, err := os.ReadFile(overlay.ImagePath)
+			if err != nil || !bytes.Equal(data, png) {
+				t.Errorf("overlay file not written correctly: %v", err)
+			}
+		})
+	}
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	if buf.Len() < 20000 {
+		buf.Write(make([]byte, 20000-buf.Len()))
+	}
+	return buf.Bytes()
+}
+
+func TestFetchImageDimensions(t *testing.T) {
+	small := testPNG(t, 200, 300)
+	landscape := testPNG(t, 1200, 800)
+	portrait := testPNG(t, 800, 1200)
+
+	tests := []struct {
+		name        string
+		urls        []string
+		orientation string
+		want        []byte
+	}{
+		{name: "rejectsBelowMinimum", urls: []string{"small", "landscape"}, want: landscape},
+		{name: "onlyTooSmall", urls: []string{"small"}},
+		{name: "prefersOrientation", urls: []string{"landscape", "portrait"}, orientation: "portrait", want: portrait},
+		{name: "fallsBackWithoutOrientationMatch", urls: []string{"small", "landscape"}, orientation: "portrait", want: landscape},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &mockImageSource{
+				urls:   tt.urls,
+				images: map[string][]byte{"small": small, "landscape": landscape, "portrait": portrait},
+			}
+			f := NewFetcher(source, nil, FetcherConfig{MinWidth: 400, MinHeight: 400, Orientation: tt.orientation})
+
+			data, ext := f.fetchImage(context.Background(), "query")
+			if !bytes.Equal(data, tt.want) {
+				t.Errorf("fetchImage() returned %d bytes, want %d", len(data), len(tt.want))
+			}
+			if tt.want != nil && ext != ".png" {
+				t.Errorf("fetchImage() ext = %q, want .png", ext)
+			}
+		})
+	}
+}
+
+func TestMatchesOrientation(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		orientation   string
+		want          bool
+	}{
+		{name: "anyOrientation", width: 1200, height: 800, want: true},
+		{name: "portraitMatch", width: 800, height: 1200, orientation: "portrait", want: true},
+		{name: "portraitMismatch", width: 1200, height: 800, orientation: "portrait", want: false},
+		{name: "landscapeMatch", width: 1200, height: 800, orientation: "landscape", want: true},
+		{name: "squareMismatch", width: 800, height: 801, orientation: "square", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesOrientation(tt.width, tt.height, tt.orientation); got != tt.want {
+				t.Errorf("matchesOrientation(%d, %d, %q) = %v, want %v", tt.width, tt.height, tt.orientation, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFindKeywordInTimings(t *testing.T) {
 	timings := []speech.WordTiming{
 		{Word: "The", StartTime: 0, EndTime: 0.2},
@@ -223,9 +439,9 @@ func TestCleanWord(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			got := cleanWord(tt.input)
+			got := CleanWord(tt.input)
 			if got != tt.want {
-				t.Errorf("cleanWord(%q) = %q, want %q", tt.input, got, tt.want)
+				t.Errorf("CleanWord(%q) = %q, want %q", tt.input, got, tt.want)
 			}
 		})
 	}
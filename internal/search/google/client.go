@@ -16,6 +16,8 @@ const (
 	defaultTimeout = 15 * time.Second
 	minImgWidth    = 400
 	minImgHeight   = 300
+	defaultImgSize = "xlarge"
+	defaultImgType = "photo"
 )
 
 type Client struct {
@@ -23,12 +25,21 @@ type Client struct {
 	engineID   string
 	httpClient *http.Client
 	baseURL    string
+	minWidth   int
+	minHeight  int
+	imgSize    string
+	imgType    string
 }
 
 type Config struct {
-	APIKey   string
-	EngineID string
-	Timeout  time.Duration
+	APIKey    string
+	EngineID  string
+	Timeout   time.Duration
+	Transport http.RoundTripper
+	MinWidth  int
+	MinHeight int
+	ImgSize   string
+	ImgType   string
 }
 
 type Result struct {
@@ -80,13 +91,35 @@ func NewClient(cfg Config) *Client {
 		timeout = defaultTimeout
 	}
 
+	minWidth := cfg.MinWidth
+	if minWidth == 0 {
+		minWidth = minImgWidth
+	}
+	minHeight := cfg.MinHeight
+	if minHeight == 0 {
+		minHeight = minImgHeight
+	}
+	imgSize := cfg.ImgSize
+	if imgSize == "" {
+		imgSize = defaultImgSize
+	}
+	imgType := cfg.ImgType
+	if imgType == "" {
+		imgType = defaultImgType
+	}
+
 	return &Client{
 		apiKey:   cfg.APIKey,
 		engineID: cfg.EngineID,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: cfg.Transport,
 		},
-		baseURL: baseURL,
+		baseURL:   baseURL,
+		minWidth:  minWidth,
+		minHeight: minHeight,
+		imgSize:   imgSize,
+		imgType:   imgType,
 	}
 }
 
@@ -112,6 +145,19 @@ func (c *Client) Search(ctx context.Context, query string, count int) ([]Result,
 	return c.parseSearchResponse(resp.Body, count)
 }
 
+func (c *Client) SearchImages(ctx context.Context, query string, count int) ([]string, error) {
+	results, err := c.Search(ctx, query, count)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, len(results))
+	for i, result := range results {
+		urls[i] = result.ImageURL
+	}
+	return urls, nil
+}
+
 func (c *Client) DownloadImage(ctx context.Context, imageURL string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
 	if err != nil {
@@ -155,8 +201,8 @@ func (c *Client) buildSearchURL(query string, count int) string {
 	params.Set("searchType", "image")
 	params.Set("num", fmt.Sprintf("%d", requestCount))
 	params.Set("safe", "active")
-	params.Set("imgSize", "xlarge")
-	params.Set("imgType", "photo")
+	params.Set("imgSize", c.imgSize)
+	params.Set("imgType", c.imgType)
 
 	return fmt.Sprintf("%s?%s", c.baseURL, params.Encode())
 }
@@ -172,7 +218,7 @@ func (c *Client) parseSearchResponse(body io.Reader, count int) ([]Result, error
 		return nil, fmt.Errorf("parse response: %w", err)
 	}
 
-	results := filterResults(searchResp.Items, count)
+	results := filterResults(searchResp.Items, count, c.minWidth, c.minHeight)
 	if len(results) == 0 {
 		results = filterResultsNoSize(searchResp.Items, count)
 	}
@@ -180,13 +226,13 @@ func (c *Client) parseSearchResponse(body io.Reader, count int) ([]Result, error
 	return results, nil
 }
 
-func filterResults(items []searchItem, count int) []Result {
+func filterResults(items []searchItem, count, minWidth, minHeight int) []Result {
 	results := make([]Result, 0, count)
 	for _, item := range items {
 		if isBlockedDomain(item.Link) {
 			continue
 		}
-		if item.Image.Width < minImgWidth || item.Image.Height < minImgHeight {
+		if item.Image.Width < minWidth || item.Image.Height < minHeight {
 			continue
 		}
 		results = append(results, toResult(item))
@@ -244,3 +244,47 @@ func TestSearchContextCancellation(t *testing.T) {
 		t.Error("expected error for cancelled context")
 	}
 }
+
+func TestSearchFilters(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		wantSize    string
+		wantType    string
+		wantResults int
+	}{
+		{name: "defaults", cfg: Config{}, wantSize: "xlarge", wantType: "photo", wantResults: 2},
+		{name: "custom", cfg: Config{MinWidth: 1000, MinHeight: 1000, ImgSize: "huge", ImgType: "clipart"}, wantSize: "huge", wantType: "clipart", wantResults: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("imgSize"); got != tt.wantSize {
+					t.Errorf("imgSize = %q, want %q", got, tt.wantSize)
+				}
+				if got := r.URL.Query().Get("imgType"); got != tt.wantType {
+					t.Errorf("imgType = %q, want %q", got, tt.wantType)
+				}
+				_ = json.NewEncoder(w).Encode(searchResponse{Items: []searchItem{
+					{Link: "http://example.com/big.jpg", Image: imageInfo{Width: 1080, Height: 1920}},
+					{Link: "http://example.com/medium.jpg", Image: imageInfo{Width: 800, Height: 600}},
+					{Link: "http://example.com/tiny.jpg", Image: imageInfo{Width: 100, Height: 100}},
+				}})
+			}))
+			defer server.Close()
+
+			tt.cfg.APIKey, tt.cfg.EngineID = "key", "engine"
+			client := NewClient(tt.cfg)
+			client.baseURL = server.URL
+
+			results, err := client.Search(context.Background(), "test", 5)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(results) != tt.wantResults {
+				t.Errorf("Search() returned %d results, want %d", len(results), tt.wantResults)
+			}
+		})
+	}
+}
@@ -23,8 +23,9 @@ type Client struct {
 }
 
 type Config struct {
-	APIKey  string
-	Timeout time.Duration
+	APIKey    string
+	Timeout   time.Duration
+	Transport http.RoundTripper
 }
 
 type GIF struct {
@@ -66,7 +67,8 @@ func NewClient(cfg Config) *Client {
 		apiKey:  cfg.APIKey,
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: cfg.Transport,
 		},
 	}
 }
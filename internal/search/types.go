@@ -11,15 +11,14 @@ import (
 	"strings"
 
 	"craftstory/internal/llm"
-	"craftstory/internal/search/google"
 	"craftstory/internal/search/tenor"
 	"craftstory/internal/speech"
 )
 
 type VisualCue = llm.VisualCue
 
-type ImageSearcher interface {
-	Search(ctx context.Context, query string, count int) ([]google.Result, error)
+type ImageSource interface {
+	SearchImages(ctx context.Context, query string, count int) ([]string, error)
 	DownloadImage(ctx context.Context, imageURL string) ([]byte, error)
 }
 
@@ -41,18 +40,18 @@ func findKeywordInTimings(timings []speech.WordTiming, keyword string, startFrom
 
 	if len(keywordWords) == 1 {
 		for i := startFrom; i < len(timings); i++ {
-			if cleanWord(timings[i].Word) == keywordLower {
+			if CleanWord(timings[i].Word) == keywordLower {
 				return i
 			}
 		}
 		for i := startFrom; i < len(timings); i++ {
-			cleaned := cleanWord(timings[i].Word)
+			cleaned := CleanWord(timings[i].Word)
 			if strings.Contains(cleaned, keywordLower) || strings.Contains(keywordLower, cleaned) {
 				return i
 			}
 		}
 		for i := startFrom; i < len(timings); i++ {
-			cleaned := cleanWord(timings[i].Word)
+			cleaned := CleanWord(timings[i].Word)
 			if len(cleaned) > 3 && len(keywordLower) > 3 {
 				if strings.HasPrefix(cleaned, keywordLower[:len(keywordLower)-1]) ||
 					strings.HasPrefix(keywordLower, cleaned[:len(cleaned)-1]) {
@@ -66,7 +65,7 @@ func findKeywordInTimings(timings []speech.WordTiming, keyword string, startFrom
 	for i := startFrom; i <= len(timings)-len(keywordWords); i++ {
 		match := true
 		for j, kw := range keywordWords {
-			if cleanWord(timings[i+j].Word) != kw {
+			if CleanWord(timings[i+j].Word) != kw {
 				match = false
 				break
 			}
@@ -78,7 +77,7 @@ func findKeywordInTimings(timings []speech.WordTiming, keyword string, startFrom
 
 	firstWord := keywordWords[0]
 	for i := startFrom; i < len(timings); i++ {
-		if cleanWord(timings[i].Word) == firstWord {
+		if CleanWord(timings[i].Word) == firstWord {
 			return i
 		}
 	}
@@ -104,7 +103,7 @@ func findSpeakerSegmentEnd(timings []speech.WordTiming, startIndex int) float64
 	return lastEndTime
 }
 
-func cleanWord(word string) string {
+func CleanWord(word string) string {
 	return strings.ToLower(strings.Trim(word, ".,!?;:'\"()[]{}"))
 }
 
@@ -139,6 +138,27 @@ func isValidImage(data []byte) bool {
 	return err == nil
 }
 
+func imageDimensions(data []byte) (int, int, bool) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, false
+	}
+	return cfg.Width, cfg.Height, true
+}
+
+func matchesOrientation(width, height int, orientation string) bool {
+	switch orientation {
+	case "portrait":
+		return height > width
+	case "landscape":
+		return width > height
+	case "square":
+		return width == height
+	default:
+		return true
+	}
+}
+
 func isValidGif(data []byte) bool {
 	if len(data) < 100 {
 		return false
@@ -5,12 +5,14 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"craftstory/internal/speech"
 )
@@ -21,23 +23,54 @@ const (
 	model   = "eleven_multilingual_v2"
 )
 
+var _ speech.Provider = (*Client)(nil)
+
+var ErrMissingAPIKey = errors.New("elevenlabs: missing API key (set ELEVENLABS_API_KEY)")
+
+var languageCodeModels = map[string]bool{
+	"eleven_turbo_v2_5": true,
+	"eleven_flash_v2_5": true,
+}
+
 type Client struct {
-	apiKeys    []string
-	keyIndex   uint64
-	httpClient *http.Client
-	voiceID    string
-	baseURL    string
-	speed      float64
-	stability  float64
-	similarity float64
+	apiKeys        []string
+	keyIndex       uint64
+	httpClient     *http.Client
+	voiceID        string
+	baseURL        string
+	speed          float64
+	stability      float64
+	similarity     float64
+	model          string
+	language       string
+	wordsPerMinute float64
+}
+
+type Voice struct {
+	ID       string `json:"voice_id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+type voicesResponse struct {
+	Voices []Voice `json:"voices"`
+}
+
+type voiceSettings struct {
+	Stability       float64 `json:"stability"`
+	SimilarityBoost float64 `json:"similarity_boost"`
+	Speed           float64 `json:"speed"`
 }
 
 type Config struct {
-	APIKeys    []string
-	VoiceID    string
-	Speed      float64
-	Stability  float64
-	Similarity float64
+	APIKeys        []string
+	VoiceID        string
+	Speed          float64
+	Stability      float64
+	Similarity     float64
+	Model          string
+	Language       string
+	WordsPerMinute float64
 }
 
 type option func(*Client)
@@ -65,20 +98,8 @@ func withHTTPClient(client *http.Client) option {
 	}
 }
 
-func NewClient(cfg Config) speech.Provider {
-	keys := cfg.APIKeys
-	if len(keys) == 0 {
-		keys = []string{""}
-	}
-
-	return &Client{
-		apiKeys:    keys,
-		httpClient: &http.Client{Timeout: timeout},
-		voiceID:    cfg.VoiceID,
-		speed:      cfg.Speed,
-		stability:  cfg.Stability,
-		similarity: cfg.Similarity,
-	}
+func NewClient(cfg Config) *Client {
+	return newClient(cfg)
 }
 
 func newClient(cfg Config, opts ...option) *Client {
@@ -88,12 +109,18 @@ func newClient(cfg Config, opts ...option) *Client {
 	}
 
 	c := &Client{
-		apiKeys:    keys,
-		httpClient: &http.Client{Timeout: timeout},
-		voiceID:    cfg.VoiceID,
-		speed:      cfg.Speed,
-		stability:  cfg.Stability,
-		similarity: cfg.Similarity,
+		apiKeys:        keys,
+		httpClient:     &http.Client{Timeout: timeout},
+		voiceID:        cfg.VoiceID,
+		speed:          cfg.Speed,
+		stability:      cfg.Stability,
+		similarity:     cfg.Similarity,
+		model:          cfg.Model,
+		language:       cfg.Language,
+		wordsPerMinute: cfg.WordsPerMinute,
+	}
+	if c.model == "" {
+		c.model = model
 	}
 
 	for _, opt := range opts {
@@ -104,7 +131,7 @@ func newClient(cfg Config, opts ...option) *Client {
 }
 
 func (c *Client) GenerateSpeech(ctx context.Context, text string) ([]byte, error) {
-	result, err := c.generateWithTimestamps(ctx, text, c.voiceID)
+	result, err := c.generateWithTimestamps(ctx, text, speech.VoiceConfig{})
 	if err != nil {
 		return nil, err
 	}
@@ -112,15 +139,66 @@ func (c *Client) GenerateSpeech(ctx context.Context, text string) ([]byte, error
 }
 
 func (c *Client) GenerateSpeechWithTimings(ctx context.Context, text string) (*speech.SpeechResult, error) {
-	return c.generateWithTimestamps(ctx, text, c.voiceID)
+	return c.generateWithTimestamps(ctx, text, speech.VoiceConfig{})
 }
 
 func (c *Client) GenerateSpeechWithVoice(ctx context.Context, text string, voice speech.VoiceConfig) (*speech.SpeechResult, error) {
-	voiceID := voice.ID
-	if voiceID == "" {
-		voiceID = c.voiceID
+	return c.generateWithTimestamps(ctx, text, voice)
+}
+
+func (c *Client) settingsFor(voice speech.VoiceConfig) voiceSettings {
+	settings := voiceSettings{
+		Stability:       c.stability,
+		SimilarityBoost: c.similarity,
+		Speed:           c.speed,
+	}
+	if voice.Stability > 0 {
+		settings.Stability = voice.Stability
+	}
+	if voice.Similarity > 0 {
+		settings.SimilarityBoost = voice.Similarity
+	}
+	if voice.Speed > 0 {
+		settings.Speed = voice.Speed
+	}
+	return settings
+}
+
+func (c *Client) ListVoices(ctx context.Context) ([]Voice, error) {
+	apiKey := c.nextAPIKey()
+	if apiKey == "" {
+		return nil, ErrMissingAPIKey
+	}
+
+	base := c.baseURL
+	if base == "" {
+		base = baseURL
 	}
-	return c.generateWithTimestamps(ctx, text, voiceID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/voices", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("xi-api-key", apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("elevenlabs: %s - %s", resp.Status, string(body))
+	}
+
+	var voicesResp voicesResponse
+	if err := json.Unmarshal(body, &voicesResp); err != nil {
+		return nil, fmt.Errorf("parse voices: %w", err)
+	}
+	return voicesResp.Voices, nil
 }
 
 func (c *Client) nextAPIKey() string {
@@ -136,11 +214,16 @@ func (c *Client) getKeyAtOffset(offset int) string {
 	return c.apiKeys[(idx+uint64(offset))%uint64(len(c.apiKeys))]
 }
 
-func (c *Client) generateWithTimestamps(ctx context.Context, text, voiceID string) (*speech.SpeechResult, error) {
+func (c *Client) generateWithTimestamps(ctx context.Context, text string, voice speech.VoiceConfig) (*speech.SpeechResult, error) {
+	voiceID := voice.ID
+	if voiceID == "" {
+		voiceID = c.voiceID
+	}
 	url := c.buildURL(voiceID)
+	settings := c.settingsFor(voice)
 
 	startKey := c.nextAPIKey()
-	result, err := c.doRequestWithKey(ctx, url, text, startKey)
+	result, err := c.doRequestWithKey(ctx, url, text, startKey, settings)
 	if err == nil {
 		return result, nil
 	}
@@ -153,7 +236,7 @@ func (c *Client) generateWithTimestamps(ctx context.Context, text, voiceID strin
 		if key == startKey {
 			continue
 		}
-		result, err = c.doRequestWithKey(ctx, url, text, key)
+		result, err = c.doRequestWithKey(ctx, url, text, key, settings)
 		if err == nil {
 			return result, nil
 		}
@@ -165,8 +248,8 @@ func (c *Client) generateWithTimestamps(ctx context.Context, text, voiceID strin
 	return nil, fmt.Errorf("all API keys exhausted: %w", err)
 }
 
-func (c *Client) doRequestWithKey(ctx context.Context, url, text, apiKey string) (*speech.SpeechResult, error) {
-	req, err := c.buildRequestWithKey(ctx, url, text, apiKey)
+func (c *Client) doRequestWithKey(ctx context.Context, url, text, apiKey string, settings voiceSettings) (*speech.SpeechResult, error) {
+	req, err := c.buildRequestWithKey(ctx, url, text, apiKey, settings)
 	if err != nil {
 		return nil, err
 	}
@@ -207,15 +290,14 @@ func (c *Client) buildURL(voiceID string) string {
 	return fmt.Sprintf("%s/text-to-speech/%s/with-timestamps", base, voiceID)
 }
 
-func (c *Client) buildRequestWithKey(ctx context.Context, url, text, apiKey string) (*http.Request, error) {
+func (c *Client) buildRequestWithKey(ctx context.Context, url, text, apiKey string, settings voiceSettings) (*http.Request, error) {
 	payload := map[string]any{
-		"text":     text,
-		"model_id": model,
-		"voice_settings": map[string]any{
-			"stability":        c.stability,
-			"similarity_boost": c.similarity,
-			"speed":            c.speed,
-		},
+		"text":           text,
+		"model_id":       c.model,
+		"voice_settings": settings,
+	}
+	if c.language != "" && languageCodeModels[c.model] {
+		payload["language_code"] = c.language
 	}
 
 	data, err := json.Marshal(payload)
@@ -247,16 +329,16 @@ func (c *Client) parseResponse(text string, body []byte) (*speech.SpeechResult,
 
 	return &speech.SpeechResult{
 		Audio:   audio,
-		Timings: parseTimings(text, tsResp.Alignment),
+		Timings: parseTimings(text, tsResp.Alignment, c.wordsPerMinute),
 	}, nil
 }
 
-func parseTimings(text string, align *alignment) []speech.WordTiming {
+func parseTimings(text string, align *alignment, wordsPerMinute float64) []speech.WordTiming {
 	if align == nil || len(align.Characters) == 0 {
-		return speech.EstimateTimings(text, nil)
+		return speech.EstimateTimingsFromWPM(text, wordsPerMinute)
 	}
 
-	words := strings.Fields(text)
+	words := speech.SplitWords(text)
 	if len(words) == 0 {
 		return nil
 	}
@@ -274,7 +356,7 @@ func parseTimings(text string, align *alignment) []speech.WordTiming {
 		}
 
 		startIdx := charIdx
-		wordLen := len(word)
+		wordLen := utf8.RuneCountInString(word)
 		endIdx := startIdx
 		matchedChars := 0
 		for endIdx < len(align.Characters) && matchedChars < wordLen {
@@ -296,7 +378,7 @@ func parseTimings(text string, align *alignment) []speech.WordTiming {
 	}
 
 	if len(timings) == 0 {
-		return speech.EstimateTimings(text, nil)
+		return speech.EstimateTimingsFromWPM(text, wordsPerMinute)
 	}
 
 	return timings
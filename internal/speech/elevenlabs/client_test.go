@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"craftstory/internal/speech"
@@ -133,6 +135,60 @@ func TestGenerateSpeechWithVoice(t *testing.T) {
 	}
 }
 
+func TestGenerateSpeechWithVoiceSettings(t *testing.T) {
+	tests := []struct {
+		name  string
+		voice speech.VoiceConfig
+		want  voiceSettings
+	}{
+		{
+			name:  "perVoiceOverrides",
+			voice: speech.VoiceConfig{ID: "host", Stability: 0.9, Similarity: 0.6, Speed: 0.95},
+			want:  voiceSettings{Stability: 0.9, SimilarityBoost: 0.6, Speed: 0.95},
+		},
+		{
+			name:  "partialOverride",
+			voice: speech.VoiceConfig{ID: "guest", Speed: 1.15},
+			want:  voiceSettings{Stability: 0.5, SimilarityBoost: 0.75, Speed: 1.15},
+		},
+		{
+			name:  "clientDefaults",
+			voice: speech.VoiceConfig{ID: "guest"},
+			want:  voiceSettings{Stability: 0.5, SimilarityBoost: 0.75, Speed: 1.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				VoiceSettings voiceSettings `json:"voice_settings"`
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(mockTimestampResponse([]byte("audio")))
+			}))
+			defer server.Close()
+
+			client := newTestClient(Config{
+				APIKeys:    []string{"test-key"},
+				Stability:  0.5,
+				Similarity: 0.75,
+				Speed:      1.0,
+			}, withBaseURL(server.URL), withHTTPClient(server.Client()))
+
+			if _, err := client.GenerateSpeechWithVoice(context.Background(), "Hello", tt.voice); err != nil {
+				t.Fatalf("GenerateSpeechWithVoice() error = %v", err)
+			}
+			if got.VoiceSettings != tt.want {
+				t.Errorf("voice_settings = %+v, want %+v", got.VoiceSettings, tt.want)
+			}
+		})
+	}
+}
+
 func TestGenerateSpeechWithVoiceDefaultFallback(t *testing.T) {
 	fakeAudio := []byte("fake audio data")
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -180,12 +236,51 @@ func TestAPIError(t *testing.T) {
 }
 
 func TestParseTimingsNoAlignment(t *testing.T) {
-	timings := parseTimings("Hello world", nil)
+	timings := parseTimings("Hello world", nil, speech.DefaultWordsPerMinute)
 	if len(timings) != 2 {
 		t.Errorf("got %d timings, want 2", len(timings))
 	}
 }
 
+func TestParseTimingsAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantWords []string
+	}{
+		{name: "detachedEllipsis", text: "Hi ... you", wantWords: []string{"Hi...", "you"}},
+		{name: "decimal", text: "It is 3.5", wantWords: []string{"It", "is", "3.5"}},
+		{name: "multibyte", text: "Ačiū žmonės", wantWords: []string{"Ačiū", "žmonės"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			align := &alignment{}
+			for i, r := range []rune(tt.text) {
+				align.Characters = append(align.Characters, string(r))
+				align.CharacterStartTimes = append(align.CharacterStartTimes, float64(i)*0.1)
+				align.CharacterEndTimes = append(align.CharacterEndTimes, float64(i+1)*0.1)
+			}
+
+			timings := parseTimings(tt.text, align, speech.DefaultWordsPerMinute)
+			if len(timings) != len(tt.wantWords) {
+				t.Fatalf("got %d timings, want %d", len(timings), len(tt.wantWords))
+			}
+			for i, want := range tt.wantWords {
+				if timings[i].Word != want {
+					t.Errorf("timings[%d].Word = %q, want %q", i, timings[i].Word, want)
+				}
+			}
+
+			last := timings[len(timings)-1]
+			wantEnd := align.CharacterEndTimes[len(align.CharacterEndTimes)-1]
+			if last.EndTime != wantEnd {
+				t.Errorf("last EndTime = %v, want %v", last.EndTime, wantEnd)
+			}
+		})
+	}
+}
+
 func TestGenerateSpeech(t *testing.T) {
 	fakeAudio := []byte("fake audio data")
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -240,3 +335,130 @@ func TestKeyRotationSingleKey(t *testing.T) {
 func newTestClient(cfg Config, opts ...option) *Client {
 	return newClient(cfg, opts...)
 }
+
+func TestRequestModelAndLanguage(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantModel    string
+		wantLanguage string
+	}{
+		{
+			name:      "defaultModel",
+			cfg:       Config{Language: "es"},
+			wantModel: "eleven_multilingual_v2",
+		},
+		{
+			name:         "turboModelSendsLanguage",
+			cfg:          Config{Model: "eleven_turbo_v2_5", Language: "es"},
+			wantModel:    "eleven_turbo_v2_5",
+			wantLanguage: "es",
+		},
+		{
+			name:      "turboModelWithoutLanguage",
+			cfg:       Config{Model: "eleven_turbo_v2_5"},
+			wantModel: "eleven_turbo_v2_5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(tt.cfg)
+
+			req, err := client.buildRequestWithKey(context.Background(), "http://example.com", "Hola", "key", client.settingsFor(speech.VoiceConfig{}))
+			if err != nil {
+				t.Fatalf("buildRequestWithKey() error = %v", err)
+			}
+
+			var payload map[string]any
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+
+			if payload["model_id"] != tt.wantModel {
+				t.Errorf("model_id = %v, want %q", payload["model_id"], tt.wantModel)
+			}
+			language, _ := payload["language_code"].(string)
+			if language != tt.wantLanguage {
+				t.Errorf("language_code = %q, want %q", language, tt.wantLanguage)
+			}
+		})
+	}
+}
+
+func TestListVoices(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiKeys    []string
+		statusCode int
+		body       string
+		want       []Voice
+		wantErr    error
+	}{
+		{
+			name:       "success",
+			apiKeys:    []string{"test-key"},
+			statusCode: http.StatusOK,
+			body:       `{"voices":[{"voice_id":"abc123","name":"Adam","category":"premade","labels":{"accent":"american"}},{"voice_id":"def456","name":"My Clone","category":"cloned"}]}`,
+			want: []Voice{
+				{ID: "abc123", Name: "Adam", Category: "premade"},
+				{ID: "def456", Name: "My Clone", Category: "cloned"},
+			},
+		},
+		{
+			name:       "empty",
+			apiKeys:    []string{"test-key"},
+			statusCode: http.StatusOK,
+			body:       `{"voices":[]}`,
+			want:       []Voice{},
+		},
+		{
+			name:    "missingAPIKey",
+			wantErr: ErrMissingAPIKey,
+		},
+		{
+			name:       "unauthorized",
+			apiKeys:    []string{"bad-key"},
+			statusCode: http.StatusUnauthorized,
+			body:       `{"detail":{"status":"invalid_api_key"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/voices" {
+					t.Errorf("path = %s, want /voices", r.URL.Path)
+				}
+				if r.Header.Get("xi-api-key") != tt.apiKeys[0] {
+					t.Errorf("xi-api-key = %q, want %q", r.Header.Get("xi-api-key"), tt.apiKeys[0])
+				}
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := newTestClient(Config{APIKeys: tt.apiKeys}, withBaseURL(server.URL), withHTTPClient(server.Client()))
+			got, err := client.ListVoices(context.Background())
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ListVoices() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if tt.statusCode != http.StatusOK {
+				if err == nil {
+					t.Error("ListVoices() expected error for non-200 response")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListVoices() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListVoices() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
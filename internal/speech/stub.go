@@ -3,7 +3,6 @@ package speech
 import (
 	"context"
 	"encoding/binary"
-	"strings"
 )
 
 const (
@@ -45,8 +44,7 @@ func (s *StubProvider) GenerateSpeechWithVoice(ctx context.Context, text string,
 }
 
 func (s *StubProvider) estimateDuration(text string) float64 {
-	wordCount := len(strings.Fields(text))
-	return float64(wordCount) / s.wordsPerMinute * 60.0
+	return EstimateDuration(text, s.wordsPerMinute)
 }
 
 func generateSilentWAV(durationSec float64) []byte {
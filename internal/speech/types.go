@@ -3,6 +3,7 @@ package speech
 import (
 	"context"
 	"strings"
+	"unicode"
 )
 
 const DefaultWordsPerMinute = 150.0
@@ -23,6 +24,9 @@ type VoiceConfig struct {
 	ID            string
 	Name          string
 	SubtitleColor string
+	Stability     float64
+	Similarity    float64
+	Speed         float64
 }
 
 type Provider interface {
@@ -31,8 +35,38 @@ type Provider interface {
 	GenerateSpeechWithVoice(ctx context.Context, text string, voice VoiceConfig) (*SpeechResult, error)
 }
 
+func SplitWords(text string) []string {
+	var words []string
+	pending := ""
+	for _, field := range strings.Fields(text) {
+		if !isPunctuation(field) {
+			words = append(words, pending+field)
+			pending = ""
+			continue
+		}
+		if len(words) == 0 {
+			pending += field
+			continue
+		}
+		words[len(words)-1] += field
+	}
+	if pending != "" {
+		words = append(words, pending)
+	}
+	return words
+}
+
+func isPunctuation(token string) bool {
+	for _, r := range token {
+		if !unicode.IsPunct(r) && !unicode.IsSymbol(r) {
+			return false
+		}
+	}
+	return true
+}
+
 func EstimateTimingsFromDuration(text string, duration float64) []WordTiming {
-	words := strings.Fields(text)
+	words := SplitWords(text)
 	if len(words) == 0 {
 		return nil
 	}
@@ -62,6 +96,17 @@ func EstimateTimingsFromDuration(text string, duration float64) []WordTiming {
 	return timings
 }
 
+func EstimateDuration(text string, wordsPerMinute float64) float64 {
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = DefaultWordsPerMinute
+	}
+	return float64(len(SplitWords(text))) / wordsPerMinute * 60.0
+}
+
+func EstimateTimingsFromWPM(text string, wordsPerMinute float64) []WordTiming {
+	return EstimateTimingsFromDuration(text, EstimateDuration(text, wordsPerMinute))
+}
+
 func EstimateTimings(text string, audio []byte) []WordTiming {
 	duration := EstimateAudioDuration(audio)
 	return EstimateTimingsFromDuration(text, duration)
@@ -1,6 +1,8 @@
 package speech
 
 import (
+	"math"
+	"slices"
 	"testing"
 )
 
@@ -191,6 +193,32 @@ func TestEstimateTimings(t *testing.T) {
 	}
 }
 
+func TestEstimateTimingsFromWPM(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog"
+
+	base := EstimateTimingsFromWPM(text, 150)
+	fast := EstimateTimingsFromWPM(text, 300)
+	fallback := EstimateTimingsFromWPM(text, 0)
+
+	if len(base) != 9 || len(fast) != 9 {
+		t.Fatalf("got %d and %d timings, want 9", len(base), len(fast))
+	}
+
+	if got, want := Duration(base), 9.0/150*60; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Duration at 150 WPM = %v, want %v", got, want)
+	}
+	for i := range base {
+		baseDur := base[i].EndTime - base[i].StartTime
+		fastDur := fast[i].EndTime - fast[i].StartTime
+		if math.Abs(baseDur-2*fastDur) > 1e-9 {
+			t.Errorf("word %d: duration at 150 WPM = %v, at 300 WPM = %v, want half", i, baseDur, fastDur)
+		}
+		if fallback[i] != base[i] {
+			t.Errorf("word %d: zero WPM = %+v, want default %+v", i, fallback[i], base[i])
+		}
+	}
+}
+
 func TestAddPauses(t *testing.T) {
 	tests := []struct {
 		input string
@@ -213,6 +241,43 @@ func TestAddPauses(t *testing.T) {
 	}
 }
 
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "plain", input: "Hello world", want: []string{"Hello", "world"}},
+		{name: "contractions", input: "Don't stop, it's fine", want: []string{"Don't", "stop,", "it's", "fine"}},
+		{name: "decimals", input: "Pi is 3.14 roughly", want: []string{"Pi", "is", "3.14", "roughly"}},
+		{name: "attachedEllipsis", input: "Hello... world", want: []string{"Hello...", "world"}},
+		{name: "detachedEllipsis", input: "Wait ... what", want: []string{"Wait...", "what"}},
+		{name: "detachedDash", input: "He left - quickly", want: []string{"He", "left-", "quickly"}},
+		{name: "hyphenatedCompound", input: "A well-known fact", want: []string{"A", "well-known", "fact"}},
+		{name: "leadingPunctuation", input: "... and then", want: []string{"...and", "then"}},
+		{name: "onlyPunctuation", input: " ... ", want: []string{"..."}},
+		{name: "extraWhitespace", input: "  one \n\t two  ", want: []string{"one", "two"}},
+		{name: "empty", input: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitWords(tt.input)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SplitWords(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitWordsAfterPauses(t *testing.T) {
+	words := SplitWords(AddPauses("Hello. World! Really? Yes"))
+	want := []string{"Hello...", "World!..", "Really?..", "Yes"}
+	if !slices.Equal(words, want) {
+		t.Errorf("SplitWords(AddPauses()) = %q, want %q", words, want)
+	}
+}
+
 func TestWordTimingFields(t *testing.T) {
 	timing := WordTiming{
 		Word:      "test",
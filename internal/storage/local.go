@@ -20,7 +20,7 @@ func NewLocalStorage(backgroundDir, outputDir string) *LocalStorage {
 	}
 }
 
-func (s *LocalStorage) RandomBackgroundClip(ctx context.Context) (string, error) {
+func (s *LocalStorage) RandomBackgroundClip(ctx context.Context, rng *rand.Rand) (string, error) {
 	clips, err := s.ListBackgroundClips()
 	if err != nil {
 		return "", err
@@ -30,7 +30,7 @@ func (s *LocalStorage) RandomBackgroundClip(ctx context.Context) (string, error)
 		return "", fmt.Errorf("no video clips found in %s", s.backgroundDir)
 	}
 
-	return clips[rand.Intn(len(clips))], nil
+	return clips[rng.Intn(len(clips))], nil
 }
 
 func (s *LocalStorage) SaveAudio(data []byte, filename string) (string, error) {
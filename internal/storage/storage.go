@@ -1,7 +1,10 @@
 package storage
 
-import "context"
+import (
+	"context"
+	"math/rand"
+)
 
 type BackgroundProvider interface {
-	RandomBackgroundClip(ctx context.Context) (string, error)
+	RandomBackgroundClip(ctx context.Context, rng *rand.Rand) (string, error)
 }
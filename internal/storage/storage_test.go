@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
@@ -83,7 +84,7 @@ func TestLocalStorageRandomBackgroundClip(t *testing.T) {
 			dir := tt.setupFunc(t)
 			s := NewLocalStorage(dir, "/tmp")
 
-			clip, err := s.RandomBackgroundClip(context.Background())
+			clip, err := s.RandomBackgroundClip(context.Background(), rand.New(rand.NewSource(1)))
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("RandomBackgroundClip() error = %v, wantErr %v", err, tt.wantErr)
@@ -1,13 +1,18 @@
 package video
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,28 +29,88 @@ const (
 	defaultWidth   = 1080
 	defaultHeight  = 1920
 	maxOverlays    = 6
+	kenBurnsFPS    = 30
+	kenBurnsZoom   = 0.15
+	kenBurnsRefDur = 5.0
+	thumbnailChars = 18
+	thumbnailFont  = 96
+	hookDuration   = 2.5
+	hookFade       = 0.5
+	hookFontScale  = 1.5
+	hookChars      = 16
+	subscribeDur   = 3.0
+	subscribeScale = 0.6
+	crossfadeFPS   = 30
+	duckThreshold  = 0.05
+	duckRatio      = 8.0
+	duckAttackMs   = 20
+	duckReleaseMs  = 300
+	defaultLUFS    = -14.0
+	defaultPeak    = -1.5
+	loudnessRange  = 11.0
+	encoderAuto    = "auto"
+	defaultCRF     = 20
+	defaultPreset  = "medium"
+	minFFmpegMajor = 4
+	ffmpegInstall  = "https://ffmpeg.org/download.html"
+)
+
+var (
+	ffmpegVersionRe = regexp.MustCompile(`ffmpeg version n?(\d+)\.`)
+	tempFileRe      = regexp.MustCompile(`^(subs_\d+\.ass|main_\d+\.mp4|concat_\d+\.txt|intro_\d+\.mp4|outro_\d+\.mp4|title_\d+\.txt|hook_\d+\.txt|chapters_\d+\.(txt|mp4)|gif_\d+\.mp4|pass_\d+-\d+\.log(\.mbtree|\.temp|\.mbtree\.temp)?)$`)
 )
 
 type Assembler struct {
 	ffmpeg      string
 	ffprobe     string
 	outputDir   string
+	cacheDir    string
 	width       int
 	height      int
 	threads     int
 	subtitleGen *SubtitleGenerator
 	bgProvider  storage.BackgroundProvider
 	music       musicConfig
+	loudness    loudnessConfig
 	intro       clipConfig
 	outro       clipConfig
+	subscribe   clipConfig
+	watermark   Watermark
+	crossfade   float64
+	kenBurns    bool
+	hookLength  float64
+	safeZone    int
+	exportSRT   bool
+	encoder     string
+	software    softwareConfig
+	twoPass     bool
+	mirror      bool
+	progress    func(percent float64)
 	verbose     bool
+	verifyOnce  sync.Once
+	verifyErr   error
 }
 
 type musicConfig struct {
-	dir     string
-	volume  float64
-	fadeIn  float64
-	fadeOut float64
+	dir           string
+	volume        float64
+	fadeIn        float64
+	fadeOut       float64
+	ducking       bool
+	duckThreshold float64
+	duckRatio     float64
+}
+
+type loudnessConfig struct {
+	enabled  bool
+	lufs     float64
+	truePeak float64
+}
+
+type softwareConfig struct {
+	crf         *int
+	preset      string
+	bitrateKbps int
 }
 
 type clipConfig struct {
@@ -53,21 +118,48 @@ type clipConfig struct {
 	duration float64
 }
 
+type segment struct {
+	path     string
+	duration float64
+}
+
 type AssemblerOptions struct {
-	OutputDir     string
-	Resolution    string
-	Threads       int
-	SubtitleGen   *SubtitleGenerator
-	BgProvider    storage.BackgroundProvider
-	MusicDir      string
-	MusicVolume   float64
-	MusicFadeIn   float64
-	MusicFadeOut  float64
-	IntroPath     string
-	OutroPath     string
-	IntroDuration float64
-	OutroDuration float64
-	Verbose       bool
+	OutputDir      string
+	CacheDir       string
+	Resolution     string
+	Threads        int
+	SubtitleGen    *SubtitleGenerator
+	BgProvider     storage.BackgroundProvider
+	MusicDir       string
+	MusicVolume    float64
+	MusicFadeIn    float64
+	MusicFadeOut   float64
+	Ducking        bool
+	DuckThreshold  float64
+	DuckRatio      float64
+	Normalize      bool
+	TargetLUFS     float64
+	TruePeak       float64
+	IntroPath      string
+	OutroPath      string
+	IntroDuration  float64
+	OutroDuration  float64
+	SubscribePath  string
+	SubscribeDur   float64
+	Crossfade      float64
+	Watermark      Watermark
+	KenBurns       bool
+	HookDuration   float64
+	SafeZoneBottom int
+	ExportSRT      bool
+	Encoder        string
+	CRF            *int
+	Preset         string
+	BitrateKbps    int
+	TwoPass        bool
+	Mirror         bool
+	ProgressFunc   func(percent float64)
+	Verbose        bool
 }
 
 type ImageOverlay struct {
@@ -77,6 +169,7 @@ type ImageOverlay struct {
 	Width     int
 	Height    int
 	IsGif     bool
+	Delayed   bool
 }
 
 type AssembleRequest struct {
@@ -87,11 +180,24 @@ type AssembleRequest struct {
 	WordTimings   []speech.WordTiming
 	ImageOverlays []ImageOverlay
 	SpeakerColors map[string]string
+	MusicMood     string
+	HookText      string
+	Chapters      []Chapter
+	Rand          *rand.Rand
+}
+
+type ClipInfo struct {
+	Path     string
+	Duration float64
+	Width    int
+	Height   int
 }
 
 type AssembleResult struct {
-	OutputPath string
-	Duration   float64
+	OutputPath   string
+	CaptionsPath string
+	Duration     float64
+	Chapters     []Chapter
 }
 
 type encoder struct {
@@ -105,6 +211,7 @@ type encoder struct {
 var (
 	encoderOnce   sync.Once
 	encoderCached encoder
+	detectEncoder = getEncoder
 )
 
 var encoders = []encoder{
@@ -135,7 +242,7 @@ var encoders = []encoder{
 
 var softwareEncoder = encoder{
 	name: "libx264",
-	args: []string{"-c:v", "libx264", "-preset", "medium", "-crf", "20", "-b:v", "8M", "-maxrate", "12M", "-bufsize", "16M", "-pix_fmt", "yuv420p"},
+	args: []string{"-c:v", "libx264", "-preset", defaultPreset, "-crf", strconv.Itoa(defaultCRF), "-b:v", "8M", "-maxrate", "12M", "-bufsize", "16M", "-pix_fmt", "yuv420p"},
 }
 
 func NewAssembler(outputDir string, subtitleGen *SubtitleGenerator, bgProvider storage.BackgroundProvider) *Assembler {
@@ -160,20 +267,45 @@ func NewAssemblerWithOptions(opts AssemblerOptions) *Assembler {
 		ffmpeg:      ffmpegBin,
 		ffprobe:     ffprobeBin,
 		outputDir:   opts.OutputDir,
+		cacheDir:    opts.CacheDir,
 		width:       w,
 		height:      h,
 		threads:     threads,
 		subtitleGen: opts.SubtitleGen,
 		bgProvider:  opts.BgProvider,
 		music: musicConfig{
-			dir:     opts.MusicDir,
-			volume:  orDefault(opts.MusicVolume, 0.15),
-			fadeIn:  orDefault(opts.MusicFadeIn, 1.0),
-			fadeOut: orDefault(opts.MusicFadeOut, 2.0),
+			dir:           opts.MusicDir,
+			volume:        orDefault(opts.MusicVolume, 0.15),
+			fadeIn:        orDefault(opts.MusicFadeIn, 1.0),
+			fadeOut:       orDefault(opts.MusicFadeOut, 2.0),
+			ducking:       opts.Ducking,
+			duckThreshold: orDefault(opts.DuckThreshold, duckThreshold),
+			duckRatio:     orDefault(opts.DuckRatio, duckRatio),
+		},
+		loudness: loudnessConfig{
+			enabled:  opts.Normalize,
+			lufs:     orDefault(opts.TargetLUFS, defaultLUFS),
+			truePeak: orDefault(opts.TruePeak, defaultPeak),
+		},
+		intro:      clipConfig{path: opts.IntroPath, duration: opts.IntroDuration},
+		outro:      clipConfig{path: opts.OutroPath, duration: opts.OutroDuration},
+		subscribe:  clipConfig{path: opts.SubscribePath, duration: orDefault(opts.SubscribeDur, subscribeDur)},
+		crossfade:  max(opts.Crossfade, 0),
+		watermark:  opts.Watermark,
+		kenBurns:   opts.KenBurns,
+		hookLength: orDefault(opts.HookDuration, hookDuration),
+		safeZone:   max(opts.SafeZoneBottom, 0) * h / playResY,
+		exportSRT:  opts.ExportSRT,
+		encoder:    opts.Encoder,
+		software: softwareConfig{
+			crf:         opts.CRF,
+			preset:      opts.Preset,
+			bitrateKbps: opts.BitrateKbps,
 		},
-		intro:   clipConfig{path: opts.IntroPath, duration: opts.IntroDuration},
-		outro:   clipConfig{path: opts.OutroPath, duration: opts.OutroDuration},
-		verbose: opts.Verbose,
+		twoPass:  opts.TwoPass,
+		mirror:   opts.Mirror,
+		progress: opts.ProgressFunc,
+		verbose:  opts.Verbose,
 	}
 }
 
@@ -184,9 +316,25 @@ func (a *Assembler) log(msg string, args ...any) {
 	slog.Debug(msg, args...)
 }
 
+func (a *Assembler) Verify() error {
+	a.verifyOnce.Do(func() {
+		a.verifyErr = verifyTools(a.ffmpeg, a.ffprobe)
+	})
+	return a.verifyErr
+}
+
 func (a *Assembler) Assemble(ctx context.Context, req AssembleRequest) (*AssembleResult, error) {
+	if err := a.Verify(); err != nil {
+		return nil, err
+	}
+
+	rng := req.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
 	a.log("selecting background clip")
-	bgClip, err := a.bgProvider.RandomBackgroundClip(ctx)
+	bgClip, err := a.bgProvider.RandomBackgroundClip(ctx, rng)
 	if err != nil {
 		return nil, fmt.Errorf("select background: %w", err)
 	}
@@ -198,8 +346,14 @@ func (a *Assembler) Assemble(ctx context.Context, req AssembleRequest) (*Assembl
 	}
 	a.log("clip duration", "seconds", clipDur)
 
-	startTime := randomStart(clipDur, req.AudioDuration)
-	a.log("random start time", "seconds", startTime)
+	loopBackground := clipDur < req.AudioDuration+videoEndBuffer
+	var startTime float64
+	if loopBackground {
+		a.log("background shorter than audio, looping", "clip", clipDur, "needed", req.AudioDuration+videoEndBuffer)
+	} else {
+		startTime = randomStart(rng, clipDur, req.AudioDuration+videoEndBuffer)
+		a.log("random start time", "seconds", startTime)
+	}
 
 	a.log("generating subtitles")
 	subtitles := a.generateSubtitles(req)
@@ -213,30 +367,50 @@ func (a *Assembler) Assemble(ctx context.Context, req AssembleRequest) (*Assembl
 	a.log("wrote subtitle file", "path", assPath)
 
 	outputPath := a.resolveOutputPath(req.OutputPath)
-	musicPath := a.selectMusicTrack()
+	musicPath := a.selectMusicTrack(rng, req.MusicMood)
 	a.log("selected music", "path", musicPath)
 
+	hookPath, cleanupHook, err := a.writeHookFile(outputPath, req.HookText)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanupHook()
+
+	overlays := a.convertGifs(ctx, a.prepareOverlays(req.ImageOverlays, req.AudioDuration))
+
 	a.log("building filter complex")
-	filterComplex := a.buildFilterComplex(assPath, req.ImageOverlays, musicPath, req.AudioDuration)
+	filterComplex := a.buildFilterComplex(assPath, hookPath, overlays, musicPath, req.AudioDuration)
 	a.log("filter complex", "filter", filterComplex)
 
 	mainPath, cleanupMain := a.prepareMainPath(outputPath)
 	defer cleanupMain()
 
 	a.log("building ffmpeg args")
-	args := a.buildFFmpegArgs(bgClip, req.AudioPath, musicPath, startTime, req.AudioDuration, filterComplex, req.ImageOverlays, mainPath)
+	args := a.buildFFmpegArgs(bgClip, req.AudioPath, musicPath, startTime, loopBackground, req.AudioDuration, filterComplex, overlays, mainPath)
 	a.log("ffmpeg command", "args", strings.Join(args, " "))
 
 	a.log("running ffmpeg", "output", mainPath)
-	if err := a.runFFmpeg(ctx, args); err != nil {
+	if a.usesTwoPass(overlays) {
+		err = a.runTwoPass(ctx, args, req.AudioDuration+videoEndBuffer)
+	} else {
+		err = a.runFFmpegWithProgress(ctx, args, req.AudioDuration+videoEndBuffer)
+	}
+	if err != nil {
 		return nil, err
 	}
 	a.log("ffmpeg completed")
 
 	totalDur := req.AudioDuration
+	var introDur float64
 	if a.hasIntroOutro() {
 		a.log("concatenating intro/outro")
-		introDur, outroDur, err := a.concatIntroOutro(ctx, mainPath, outputPath)
+		mainDur, err := a.videoDuration(ctx, mainPath)
+		if err != nil {
+			slog.Warn("Failed to probe rendered video duration, using estimate", "path", mainPath, "error", err)
+			mainDur = req.AudioDuration + videoEndBuffer
+		}
+		var outroDur float64
+		introDur, outroDur, err = a.concatIntroOutro(ctx, mainPath, mainDur, outputPath)
 		if err != nil {
 			return nil, fmt.Errorf("concat intro/outro: %w", err)
 		}
@@ -244,8 +418,74 @@ func (a *Assembler) Assemble(ctx context.Context, req AssembleRequest) (*Assembl
 		a.log("concat completed", "introDur", introDur, "outroDur", outroDur)
 	}
 
+	var captionsPath string
+	if a.exportSRT {
+		captionsPath, err = a.writeCaptions(outputPath, subtitles, introDur)
+		if err != nil {
+			slog.Warn("Failed to write captions", "error", err)
+		}
+	}
+
+	var chapters []Chapter
+	if len(req.Chapters) > 0 {
+		chapters = shiftChapters(req.Chapters, introDur)
+		if err := a.embedChapters(ctx, outputPath, chapters, totalDur+videoEndBuffer); err != nil {
+			slog.Warn("Failed to embed chapters", "error", err)
+		}
+	}
+
 	a.log("assembly completed", "output", outputPath, "duration", totalDur)
-	return &AssembleResult{OutputPath: outputPath, Duration: totalDur}, nil
+	return &AssembleResult{OutputPath: outputPath, CaptionsPath: captionsPath, Duration: totalDur, Chapters: chapters}, nil
+}
+
+func (a *Assembler) CleanupTemps(olderThan time.Duration) (int, error) {
+	if a.outputDir == "" {
+		return 0, nil
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+	err := filepath.WalkDir(a.outputDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !tempFileRe.MatchString(d.Name()) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			slog.Warn("Failed to remove temp file", "path", path, "error", err)
+			return nil
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("cleanup temps: %w", err)
+	}
+	return removed, nil
+}
+
+func (a *Assembler) writeCaptions(videoPath string, subs []Subtitle, offset float64) (string, error) {
+	shifted := make([]Subtitle, len(subs))
+	for i, sub := range subs {
+		sub.StartTime += offset
+		sub.EndTime += offset
+		shifted[i] = sub
+	}
+
+	path := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + ".srt"
+	if err := os.WriteFile(path, []byte(a.subtitleGen.ToSRT(shifted)), 0644); err != nil {
+		return "", fmt.Errorf("write captions: %w", err)
+	}
+	return path, nil
 }
 
 func (a *Assembler) generateSubtitles(req AssembleRequest) []Subtitle {
@@ -266,6 +506,19 @@ func (a *Assembler) writeSubtitleFile(outputPath string, subs []Subtitle) (strin
 	return path, func() { _ = os.Remove(path) }, nil
 }
 
+func (a *Assembler) writeHookFile(outputPath, text string) (string, func(), error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", func() {}, nil
+	}
+
+	path := filepath.Join(filepath.Dir(outputPath), fmt.Sprintf("hook_%d.txt", time.Now().UnixNano()))
+	if err := os.WriteFile(path, []byte(wrapText(text, hookChars)), 0644); err != nil {
+		return "", func() {}, fmt.Errorf("write hook file: %w", err)
+	}
+	return path, func() { _ = os.Remove(path) }, nil
+}
+
 func (a *Assembler) resolveOutputPath(path string) string {
 	if path != "" {
 		return path
@@ -285,19 +538,21 @@ func (a *Assembler) prepareMainPath(outputPath string) (string, func()) {
 	return mainPath, func() { _ = os.Remove(mainPath) }
 }
 
-func (a *Assembler) buildFilterComplex(assPath string, overlays []ImageOverlay, musicPath string, duration float64) string {
+func (a *Assembler) buildFilterComplex(assPath, hookPath string, overlays []ImageOverlay, musicPath string, duration float64) string {
 	scale := fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d", a.width, a.height, a.width, a.height)
+	if a.mirror {
+		scale += ",hflip"
+	}
 	audio := a.buildAudioFilter(musicPath, duration)
+	hook := a.buildHookFilter(hookPath)
 
 	hwSuffix := ""
-	if len(overlays) == 0 {
-		hwSuffix = getEncoder().filterSuffix
-		return fmt.Sprintf("[0:v]%s,ass=%s%s[v];%s", scale, assPath, hwSuffix, audio)
-	}
-
-	if len(overlays) > maxOverlays {
-		slog.Info("Limiting overlays", "from", len(overlays), "to", maxOverlays)
-		overlays = overlays[:maxOverlays]
+	if !a.hasOverlayInputs(overlays) {
+		hwSuffix = a.selectEncoder().filterSuffix
+		if hook != "" {
+			hook = "," + hook
+		}
+		return fmt.Sprintf("[0:v]%s,ass=%s%s%s[v];%s", scale, assPath, hook, hwSuffix, audio)
 	}
 
 	inputOffset := 2
@@ -307,7 +562,12 @@ func (a *Assembler) buildFilterComplex(assPath string, overlays []ImageOverlay,
 
 	slog.Info("Building overlay filters", "overlay_count", len(overlays), "input_offset", inputOffset)
 
-	filters := []string{fmt.Sprintf("[0:v]%s,ass=%s[base]", scale, assPath)}
+	subtitles := "ass=" + assPath
+	base := scale + "," + subtitles
+	if a.watermarkBelowSubtitles() {
+		base = scale
+	}
+	filters := []string{fmt.Sprintf("[0:v]%s[base]", base)}
 	lastOut := "base"
 
 	for i, ov := range overlays {
@@ -315,8 +575,8 @@ func (a *Assembler) buildFilterComplex(assPath string, overlays []ImageOverlay,
 		out := fmt.Sprintf("v%d", i)
 
 		inputIdx := inputOffset + i
-		scaleFilter := fmt.Sprintf("[%d:v]scale=%d:%d,format=rgba[%s]", inputIdx, ov.Width, ov.Height, img)
-		overlayFilter := fmt.Sprintf("[%s][%s]overlay=(W-w)/2:100:enable='between(t,%.2f,%.2f)'[%s]", lastOut, img, ov.StartTime, ov.EndTime, out)
+		scaleFilter := fmt.Sprintf("[%d:v]%s,format=rgba[%s]", inputIdx, a.overlayInputFilter(ov), img)
+		overlayFilter := fmt.Sprintf("[%s][%s]overlay=(W-w)/2:%s:enable='between(t,%.2f,%.2f)'[%s]", lastOut, img, a.overlayY(), ov.StartTime, ov.EndTime, out)
 
 		slog.Info("Overlay filter",
 			"index", i,
@@ -332,33 +592,143 @@ func (a *Assembler) buildFilterComplex(assPath string, overlays []ImageOverlay,
 		lastOut = out
 	}
 
-	filters = append(filters, fmt.Sprintf("[%s]null[v]", lastOut))
+	if a.watermark.Path != "" {
+		filters = append(filters, a.watermarkFilters(inputOffset+len(overlays), lastOut)...)
+		lastOut = "wmv"
+	}
+
+	if a.watermarkBelowSubtitles() {
+		hook = strings.Trim(subtitles+","+hook, ",")
+	}
+	if hook == "" {
+		hook = "null"
+	}
+	filters = append(filters, fmt.Sprintf("[%s]%s[v]", lastOut, hook))
 	filters = append(filters, audio)
 	return strings.Join(filters, ";")
 }
 
-func (a *Assembler) buildAudioFilter(musicPath string, duration float64) string {
-	if musicPath == "" {
-		return "[0:a]volume=0.1[bga];[1:a]volume=1.0[voice];[bga][voice]amix=inputs=2:duration=longest[a]"
+func (a *Assembler) prepareOverlays(overlays []ImageOverlay, duration float64) []ImageOverlay {
+	if len(overlays) > maxOverlays {
+		slog.Info("Limiting overlays", "from", len(overlays), "to", maxOverlays)
+		overlays = overlays[:maxOverlays]
+	}
+	if a.subscribe.path == "" {
+		return overlays
+	}
+
+	return append(overlays[:len(overlays):len(overlays)], ImageOverlay{
+		ImagePath: a.subscribe.path,
+		StartTime: max(duration-a.subscribe.duration, 0),
+		EndTime:   duration + videoEndBuffer,
+		Width:     int(float64(a.width) * subscribeScale),
+		Height:    -1,
+		IsGif:     strings.EqualFold(filepath.Ext(a.subscribe.path), ".gif"),
+		Delayed:   true,
+	})
+}
+
+func (a *Assembler) overlayInputFilter(ov ImageOverlay) string {
+	if ov.Delayed {
+		return fmt.Sprintf("scale=%d:%d,setpts=PTS-STARTPTS+%.2f/TB", ov.Width, ov.Height, ov.StartTime)
+	}
+	return fmt.Sprintf("scale=%d:%d%s", ov.Width, ov.Height, a.kenBurnsFilter(ov))
+}
+
+func (a *Assembler) overlayY() string {
+	if a.safeZone == 0 {
+		return "100"
+	}
+	return fmt.Sprintf("'min(100,H-h-%d)'", a.safeZone)
+}
+
+func (a *Assembler) buildHookFilter(hookPath string) string {
+	if hookPath == "" {
+		return ""
+	}
+
+	fontSize := thumbnailFont
+	borderWidth := 6
+	font := ""
+	if a.subtitleGen != nil {
+		if a.subtitleGen.fontSize > 0 {
+			fontSize = int(float64(a.subtitleGen.fontSize) * hookFontScale)
+		}
+		borderWidth = a.subtitleGen.outlineSize
+		font = a.subtitleGen.fontName
+	}
+
+	end := a.hookLength
+	fadeStart := max(end-hookFade, 0)
+	text := fmt.Sprintf("drawtext=textfile='%s':fontsize=%d:fontcolor=white:borderw=%d:bordercolor=black:line_spacing=10:x=(w-text_w)/2:y=(h-text_h)/2:enable='lt(t,%.2f)':alpha='if(lt(t,%.2f),1,(%.2f-t)/%.2f)'",
+		hookPath, fontSize, borderWidth, end, fadeStart, end, end-fadeStart)
+	if font != "" {
+		text += fmt.Sprintf(":font='%s'", font)
 	}
 
-	fadeOut := max(duration-a.music.fadeOut, 0)
+	card := fmt.Sprintf("drawbox=x=0:y=0:w=iw:h=ih:color=black@0.6:t=fill:enable='lt(t,%.2f)'", fadeStart)
+	return card + "," + text
+}
+
+func (a *Assembler) kenBurnsFilter(ov ImageOverlay) string {
+	if !a.kenBurns || ov.IsGif {
+		return ""
+	}
+
+	duration := max(ov.EndTime-ov.StartTime, 0.1)
+	maxZoom := 1 + kenBurnsZoom*min(duration/kenBurnsRefDur, 1)
+	step := (maxZoom - 1) / (duration * kenBurnsFPS)
+
 	return fmt.Sprintf(
-		"[0:a]volume=0.1[bga];[1:a]volume=1.0[voice];[2:a]volume=%.2f,afade=t=in:st=0:d=%.2f,afade=t=out:st=%.2f:d=%.2f[music];[bga][voice][music]amix=inputs=3:duration=longest:normalize=0[a]",
-		a.music.volume, a.music.fadeIn, fadeOut, a.music.fadeOut,
+		",zoompan=z='min(zoom+%.5f,%.3f)':x='iw/2-(iw/zoom/2)':y='ih/2-(ih/zoom/2)':d=1:s=%dx%d:fps=%d,setpts=PTS-STARTPTS+%.2f/TB",
+		step, maxZoom, ov.Width, ov.Height, kenBurnsFPS, ov.StartTime,
 	)
 }
 
-func (a *Assembler) buildFFmpegArgs(bgClip, audioPath, musicPath string, startTime, duration float64, filterComplex string, overlays []ImageOverlay, outputPath string) []string {
-	enc := getEncoder()
-	if len(overlays) > 0 {
-		enc = softwareEncoder
+func (a *Assembler) buildAudioFilter(musicPath string, duration float64) string {
+	mixOut := "[a]"
+	if a.loudness.enabled {
+		mixOut = "[mix]"
+	}
+
+	var mix string
+	if musicPath == "" {
+		mix = "[0:a]volume=0.1[bga];[1:a]volume=1.0[voice];[bga][voice]amix=inputs=2:duration=longest" + mixOut
+	} else if a.music.ducking {
+		fadeOut := max(duration-a.music.fadeOut, 0)
+		mix = fmt.Sprintf(
+			"[0:a]volume=0.1[bga];[1:a]volume=1.0,asplit=2[voice][sidechain];[2:a]volume=%.2f,afade=t=in:st=0:d=%.2f,afade=t=out:st=%.2f:d=%.2f[musicraw];[musicraw][sidechain]sidechaincompress=threshold=%.3f:ratio=%.1f:attack=%d:release=%d[music];[bga][voice][music]amix=inputs=3:duration=longest:normalize=0%s",
+			a.music.volume, a.music.fadeIn, fadeOut, a.music.fadeOut, a.music.duckThreshold, a.music.duckRatio, duckAttackMs, duckReleaseMs, mixOut,
+		)
+	} else {
+		fadeOut := max(duration-a.music.fadeOut, 0)
+		mix = fmt.Sprintf(
+			"[0:a]volume=0.1[bga];[1:a]volume=1.0[voice];[2:a]volume=%.2f,afade=t=in:st=0:d=%.2f,afade=t=out:st=%.2f:d=%.2f[music];[bga][voice][music]amix=inputs=3:duration=longest:normalize=0%s",
+			a.music.volume, a.music.fadeIn, fadeOut, a.music.fadeOut, mixOut,
+		)
+	}
+
+	if !a.loudness.enabled {
+		return mix
+	}
+	return fmt.Sprintf("%s;[mix]loudnorm=I=%.1f:TP=%.1f:LRA=%.0f[a]", mix, a.loudness.lufs, a.loudness.truePeak, loudnessRange)
+}
+
+func (a *Assembler) buildFFmpegArgs(bgClip, audioPath, musicPath string, startTime float64, loopBackground bool, duration float64, filterComplex string, overlays []ImageOverlay, outputPath string) []string {
+	enc := a.selectEncoder()
+	if a.hasOverlayInputs(overlays) {
+		enc = a.applySoftwareSettings(softwareEncoder)
 	}
 	videoDur := duration + videoEndBuffer
 
 	args := []string{"-y", "-threads", strconv.Itoa(a.threads)}
 	args = append(args, enc.inputArgs...)
-	args = append(args, "-ss", fmt.Sprintf("%.2f", startTime), "-t", fmt.Sprintf("%.2f", videoDur), "-i", bgClip, "-i", audioPath)
+	if loopBackground {
+		args = append(args, "-stream_loop", "-1")
+	} else {
+		args = append(args, "-ss", fmt.Sprintf("%.2f", startTime))
+	}
+	args = append(args, "-t", fmt.Sprintf("%.2f", videoDur), "-i", bgClip, "-i", audioPath)
 
 	if musicPath != "" {
 		args = append(args, "-i", musicPath)
@@ -366,12 +736,17 @@ func (a *Assembler) buildFFmpegArgs(bgClip, audioPath, musicPath string, startTi
 
 	for _, ov := range overlays {
 		displayDuration := ov.EndTime - ov.StartTime + 0.5
-		if ov.IsGif {
+		if ov.IsGif && isGifFile(ov.ImagePath) {
 			args = append(args, "-t", fmt.Sprintf("%.2f", displayDuration), "-i", ov.ImagePath)
+		} else if ov.IsGif {
+			args = append(args, "-stream_loop", "-1", "-t", fmt.Sprintf("%.2f", displayDuration), "-i", ov.ImagePath)
 		} else {
 			args = append(args, "-loop", "1", "-t", fmt.Sprintf("%.2f", displayDuration), "-i", ov.ImagePath)
 		}
 	}
+	if a.watermark.Path != "" {
+		args = append(args, "-loop", "1", "-t", fmt.Sprintf("%.2f", videoDur), "-i", a.watermark.Path)
+	}
 
 	args = append(args, "-filter_complex", filterComplex, "-map", "[v]", "-map", "[a]")
 	args = append(args, enc.args...)
@@ -393,15 +768,101 @@ func (a *Assembler) runFFmpeg(ctx context.Context, args []string) error {
 	return nil
 }
 
-func (a *Assembler) selectMusicTrack() string {
+func (a *Assembler) runFFmpegWithProgress(ctx context.Context, args []string, duration float64) error {
+	if a.progress == nil {
+		return a.runFFmpeg(ctx, args)
+	}
+
+	args = append([]string{"-progress", "pipe:1", "-nostats"}, args...)
+	cmd := exec.CommandContext(ctx, a.ffmpeg, args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if a.verbose {
+		cmd.Stderr = io.MultiWriter(&stderr, os.Stderr)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("ffmpeg stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ffmpeg: %w", err)
+	}
+
+	parseProgress(stdout, duration, a.progress)
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("ffmpeg: %w, output: %s", err, stderr.String())
+	}
+	return nil
+}
+
+func parseProgress(r io.Reader, duration float64, report func(percent float64)) {
+	if report == nil || duration <= 0 {
+		_, _ = io.Copy(io.Discard, r)
+		return
+	}
+
+	last := -1.0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+
+		var percent float64
+		switch key {
+		case "out_time_us", "out_time_ms":
+			us, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || us < 0 {
+				continue
+			}
+			percent = float64(us) / 1e6 / duration * 100
+		case "progress":
+			if value != "end" {
+				continue
+			}
+			percent = 100
+		default:
+			continue
+		}
+
+		percent = min(percent, 100)
+		if percent <= last {
+			continue
+		}
+		last = percent
+		report(percent)
+	}
+	_, _ = io.Copy(io.Discard, r)
+}
+
+func (a *Assembler) selectMusicTrack(rng *rand.Rand, mood string) string {
 	if a.music.dir == "" {
 		return ""
 	}
 
-	entries, err := os.ReadDir(a.music.dir)
-	if err != nil {
+	if mood != "" {
+		if tracks := listTracks(filepath.Join(a.music.dir, mood)); len(tracks) > 0 {
+			return tracks[rng.Intn(len(tracks))]
+		}
+		a.log("no music for mood, using any track", "mood", mood)
+	}
+
+	tracks := listTracks(a.music.dir)
+	if len(tracks) == 0 {
 		return ""
 	}
+	return tracks[rng.Intn(len(tracks))]
+}
+
+func listTracks(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
 
 	var tracks []string
 	for _, e := range entries {
@@ -410,14 +871,10 @@ func (a *Assembler) selectMusicTrack() string {
 		}
 		name := strings.ToLower(e.Name())
 		if strings.HasSuffix(name, ".mp3") || strings.HasSuffix(name, ".wav") || strings.HasSuffix(name, ".m4a") {
-			tracks = append(tracks, filepath.Join(a.music.dir, e.Name()))
+			tracks = append(tracks, filepath.Join(dir, e.Name()))
 		}
 	}
-
-	if len(tracks) == 0 {
-		return ""
-	}
-	return tracks[rand.Intn(len(tracks))]
+	return tracks
 }
 
 func (a *Assembler) videoDuration(ctx context.Context, path string) (float64, error) {
@@ -434,49 +891,155 @@ func (a *Assembler) videoDuration(ctx context.Context, path string) (float64, er
 	return dur, nil
 }
 
-func (a *Assembler) concatIntroOutro(ctx context.Context, mainPath, outputPath string) (float64, float64, error) {
+func (a *Assembler) hasAudioStream(ctx context.Context, path string) (bool, error) {
+	cmd := exec.CommandContext(ctx, a.ffprobe, "-v", "error", "-select_streams", "a", "-show_entries", "stream=index", "-of", "csv=p=0", path)
+	out, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("ffprobe: %w", err)
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
+
+func (a *Assembler) ProbeClip(ctx context.Context, path string) (*ClipInfo, error) {
+	cmd := exec.CommandContext(ctx, a.ffprobe, "-v", "error", "-select_streams", "v:0", "-show_entries", "stream=width,height:format=duration", "-of", "default=noprint_wrappers=1", path)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe: %w", err)
+	}
+
+	info, err := parseProbeOutput(string(out))
+	if err != nil {
+		return nil, err
+	}
+	info.Path = path
+	return info, nil
+}
+
+func parseProbeOutput(out string) (*ClipInfo, error) {
+	info := &ClipInfo{}
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "width":
+			info.Width, _ = strconv.Atoi(value)
+		case "height":
+			info.Height, _ = strconv.Atoi(value)
+		case "duration":
+			info.Duration, _ = strconv.ParseFloat(value, 64)
+		}
+	}
+
+	if info.Duration <= 0 {
+		return nil, fmt.Errorf("parse duration from ffprobe output: %q", out)
+	}
+	return info, nil
+}
+
+func (a *Assembler) concatIntroOutro(ctx context.Context, mainPath string, mainDur float64, outputPath string) (float64, float64, error) {
 	dir := filepath.Dir(outputPath)
-	var clips []string
+	var segments []segment
 	var introDur, outroDur float64
 
 	if clip, dur, err := a.prepareClip(ctx, a.intro, dir, "intro"); err == nil && clip != "" {
-		clips = append(clips, clip)
+		segments = append(segments, segment{path: clip, duration: dur})
 		introDur = dur
 		defer func() { _ = os.Remove(clip) }()
 	}
 
-	clips = append(clips, mainPath)
+	segments = append(segments, segment{path: mainPath, duration: mainDur})
 
 	if clip, dur, err := a.prepareClip(ctx, a.outro, dir, "outro"); err == nil && clip != "" {
-		clips = append(clips, clip)
+		segments = append(segments, segment{path: clip, duration: dur})
 		outroDur = dur
 		defer func() { _ = os.Remove(clip) }()
 	}
 
-	if len(clips) == 1 {
+	if len(segments) == 1 {
 		return 0, 0, nil
 	}
 
+	if a.crossfade == 0 {
+		return introDur, outroDur, a.concatCopy(ctx, segments, outputPath)
+	}
+
+	args, fades := a.buildCrossfadeArgs(segments, outputPath)
+	if err := a.runFFmpeg(ctx, args); err != nil {
+		return 0, 0, err
+	}
+	if introDur > 0 {
+		introDur -= fades[0]
+		fades = fades[1:]
+	}
+	if outroDur > 0 {
+		outroDur -= fades[0]
+	}
+	return introDur, outroDur, nil
+}
+
+func (a *Assembler) concatCopy(ctx context.Context, segments []segment, outputPath string) error {
+	dir := filepath.Dir(outputPath)
 	listPath := filepath.Join(dir, fmt.Sprintf("concat_%d.txt", time.Now().UnixNano()))
 	defer func() { _ = os.Remove(listPath) }()
 
 	var content strings.Builder
-	for _, c := range clips {
-		abs, err := filepath.Abs(c)
+	for _, s := range segments {
+		abs, err := filepath.Abs(s.path)
 		if err != nil {
-			return 0, 0, fmt.Errorf("abs path: %w", err)
+			return fmt.Errorf("abs path: %w", err)
 		}
 		content.WriteString(fmt.Sprintf("file '%s'\n", abs))
 	}
 
 	if err := os.WriteFile(listPath, []byte(content.String()), 0644); err != nil {
-		return 0, 0, fmt.Errorf("write concat list: %w", err)
+		return fmt.Errorf("write concat list: %w", err)
 	}
 
-	if err := a.runFFmpeg(ctx, []string{"-y", "-f", "concat", "-safe", "0", "-i", listPath, "-c", "copy", outputPath}); err != nil {
-		return 0, 0, err
+	return a.runFFmpeg(ctx, []string{"-y", "-f", "concat", "-safe", "0", "-i", listPath, "-c", "copy", outputPath})
+}
+
+func (a *Assembler) buildCrossfadeArgs(segments []segment, outputPath string) ([]string, []float64) {
+	args := []string{"-y", "-threads", strconv.Itoa(a.threads)}
+	var filters []string
+	for i, s := range segments {
+		args = append(args, "-i", s.path)
+		filters = append(filters,
+			fmt.Sprintf("[%d:v]fps=%d,format=yuv420p,settb=AVTB[v%d]", i, crossfadeFPS, i),
+			fmt.Sprintf("[%d:a]aresample=48000[a%d]", i, i),
+		)
 	}
-	return introDur, outroDur, nil
+
+	fades := make([]float64, 0, len(segments)-1)
+	lastV, lastA := "v0", "a0"
+	var offset float64
+	for i := 1; i < len(segments); i++ {
+		fade := crossfadeFor(a.crossfade, segments[i-1], segments[i])
+		fades = append(fades, fade)
+		offset += segments[i-1].duration - fade
+
+		outV, outA := fmt.Sprintf("xv%d", i), fmt.Sprintf("xa%d", i)
+		filters = append(filters,
+			fmt.Sprintf("[%s][v%d]xfade=transition=fade:duration=%.2f:offset=%.2f[%s]", lastV, i, fade, offset, outV),
+			fmt.Sprintf("[%s][a%d]acrossfade=d=%.2f[%s]", lastA, i, fade, outA),
+		)
+		lastV, lastA = outV, outA
+	}
+
+	args = append(args, "-filter_complex", strings.Join(filters, ";"), "-map", "["+lastV+"]", "-map", "["+lastA+"]")
+	args = append(args, a.software.args()...)
+	args = append(args, "-c:a", "aac", "-b:a", "192k", "-ar", "48000", "-movflags", "+faststart", outputPath)
+	return args, fades
+}
+
+func crossfadeFor(fade float64, from, to segment) float64 {
+	limit := min(from.duration, to.duration) / 2
+	if fade > limit {
+		slog.Warn("Clip shorter than crossfade, shortening transition", "from", from.path, "to", to.path, "crossfade", fade, "used", limit)
+		return limit
+	}
+	return fade
 }
 
 func (a *Assembler) prepareClip(ctx context.Context, cfg clipConfig, dir, prefix string) (string, float64, error) {
@@ -497,16 +1060,83 @@ func (a *Assembler) prepareClip(ctx context.Context, cfg clipConfig, dir, prefix
 		targetDur = cfg.duration
 	}
 
-	out := filepath.Join(dir, fmt.Sprintf("%s_%d.mp4", prefix, time.Now().UnixNano()))
-	vf := fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d", a.width, a.height, a.width, a.height)
-	args := []string{"-y", "-i", cfg.path, "-t", fmt.Sprintf("%.2f", targetDur), "-vf", vf, "-c:v", "libx264", "-preset", "ultrafast", "-threads", strconv.Itoa(a.threads), "-c:a", "aac", "-ar", "44100", out}
+	hasAudio, err := a.hasAudioStream(ctx, cfg.path)
+	if err != nil {
+		slog.Warn("Failed to probe clip audio, assuming it has audio", "path", cfg.path, "error", err)
+		hasAudio = true
+	}
 
-	if err := a.runFFmpeg(ctx, args); err != nil {
+	out := filepath.Join(dir, fmt.Sprintf("%s_%d.mp4", prefix, time.Now().UnixNano()))
+	if err := a.runFFmpeg(ctx, a.clipArgs(cfg.path, targetDur, hasAudio, out)); err != nil {
 		return "", 0, err
 	}
 	return out, targetDur, nil
 }
 
+func (a *Assembler) clipArgs(path string, duration float64, hasAudio bool, out string) []string {
+	args := []string{"-y", "-i", path}
+	if !hasAudio {
+		args = append(args, "-f", "lavfi", "-i", "anullsrc=r=44100:cl=stereo")
+	}
+	vf := fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d", a.width, a.height, a.width, a.height)
+	args = append(args, "-t", fmt.Sprintf("%.2f", duration), "-vf", vf)
+	if !hasAudio {
+		args = append(args, "-map", "0:v:0", "-map", "1:a:0")
+	}
+	return append(args, "-c:v", "libx264", "-preset", "ultrafast", "-threads", strconv.Itoa(a.threads), "-c:a", "aac", "-ar", "44100", out)
+}
+
+func (a *Assembler) selectEncoder() encoder {
+	return a.applySoftwareSettings(a.pickEncoder())
+}
+
+func (a *Assembler) applySoftwareSettings(enc encoder) encoder {
+	if enc.name == softwareEncoder.name {
+		enc.args = a.software.args()
+	}
+	return enc
+}
+
+func (a *Assembler) pickEncoder() encoder {
+	if a.encoder == "" || a.encoder == encoderAuto {
+		return detectEncoder()
+	}
+	if a.encoder == softwareEncoder.name {
+		return softwareEncoder
+	}
+	for _, e := range encoders {
+		if e.name == a.encoder {
+			return e
+		}
+	}
+
+	slog.Warn("Unknown encoder, falling back to auto-detection", "encoder", a.encoder)
+	return detectEncoder()
+}
+
+func (s softwareConfig) args() []string {
+	preset := s.preset
+	if preset == "" {
+		preset = defaultPreset
+	}
+	args := []string{"-c:v", "libx264", "-preset", preset}
+
+	if s.bitrateKbps > 0 {
+		args = append(args,
+			"-b:v", fmt.Sprintf("%dk", s.bitrateKbps),
+			"-maxrate", fmt.Sprintf("%dk", s.bitrateKbps*3/2),
+			"-bufsize", fmt.Sprintf("%dk", s.bitrateKbps*2),
+		)
+	} else {
+		crf := defaultCRF
+		if s.crf != nil {
+			crf = *s.crf
+		}
+		args = append(args, "-crf", strconv.Itoa(crf), "-b:v", "8M", "-maxrate", "12M", "-bufsize", "16M")
+	}
+	return append(args, "-pix_fmt", "yuv420p")
+}
+
 func getEncoder() encoder {
 	encoderOnce.Do(func() {
 		for _, e := range encoders {
@@ -520,6 +1150,36 @@ func getEncoder() encoder {
 	return encoderCached
 }
 
+func verifyTools(ffmpeg, ffprobe string) error {
+	for _, bin := range []string{ffmpeg, ffprobe} {
+		if _, err := exec.LookPath(bin); err != nil {
+			return fmt.Errorf("%s not found in PATH, install ffmpeg from %s: %w", bin, ffmpegInstall, err)
+		}
+	}
+
+	out, err := exec.Command(ffmpeg, "-version").Output()
+	if err != nil {
+		return fmt.Errorf("run %s -version: %w", ffmpeg, err)
+	}
+
+	if major, ok := parseFFmpegMajor(string(out)); ok && major < minFFmpegMajor {
+		return fmt.Errorf("ffmpeg %d is too old, version %d or newer is required, see %s", major, minFFmpegMajor, ffmpegInstall)
+	}
+	return nil
+}
+
+func parseFFmpegMajor(versionOutput string) (int, bool) {
+	match := ffmpegVersionRe.FindStringSubmatch(versionOutput)
+	if match == nil {
+		return 0, false
+	}
+	major, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return major, true
+}
+
 func testEnc(codec string) bool {
 	return exec.Command(ffmpegBin, "-hide_banner", "-loglevel", "error", "-f", "lavfi", "-i", "nullsrc=s=256x256:d=1", "-c:v", codec, "-frames:v", "1", "-f", "null", "-").Run() == nil
 }
@@ -542,11 +1202,11 @@ func parseResolution(res string) (int, int) {
 	return w, h
 }
 
-func randomStart(clipDur, needed float64) float64 {
+func randomStart(rng *rand.Rand, clipDur, needed float64) float64 {
 	if clipDur <= needed {
 		return 0
 	}
-	return rand.Float64() * (clipDur - needed)
+	return rng.Float64() * (clipDur - needed)
 }
 
 func orDefault(val, def float64) float64 {
@@ -584,3 +1244,79 @@ func (a *Assembler) CreatePreview(ctx context.Context, videoPath string, duratio
 
 	return previewPath, nil
 }
+
+func (a *Assembler) GenerateThumbnail(ctx context.Context, videoPath string, atSeconds float64) (string, error) {
+	return a.GenerateThumbnailWithTitle(ctx, videoPath, atSeconds, "")
+}
+
+func (a *Assembler) GenerateThumbnailWithTitle(ctx context.Context, videoPath string, atSeconds float64, title string) (string, error) {
+	thumbnailPath := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + "_thumbnail.jpg"
+
+	var textPath string
+	if title != "" {
+		textPath = filepath.Join(filepath.Dir(videoPath), fmt.Sprintf("title_%d.txt", time.Now().UnixNano()))
+		if err := os.WriteFile(textPath, []byte(wrapText(title, thumbnailChars)), 0644); err != nil {
+			return "", fmt.Errorf("write title file: %w", err)
+		}
+		defer func() { _ = os.Remove(textPath) }()
+	}
+
+	args := a.buildThumbnailArgs(videoPath, thumbnailPath, atSeconds, textPath)
+	if err := a.runFFmpeg(ctx, args); err != nil {
+		return "", fmt.Errorf("create thumbnail: %w", err)
+	}
+
+	return thumbnailPath, nil
+}
+
+func (a *Assembler) buildThumbnailArgs(videoPath, outputPath string, atSeconds float64, textPath string) []string {
+	args := []string{
+		"-y",
+		"-ss", fmt.Sprintf("%.2f", max(atSeconds, 0)),
+		"-i", videoPath,
+		"-frames:v", "1",
+	}
+
+	if textPath != "" {
+		args = append(args, "-vf", a.buildTitleFilter(textPath))
+	}
+
+	return append(args, "-q:v", "2", outputPath)
+}
+
+func (a *Assembler) buildTitleFilter(textPath string) string {
+	fontSize := thumbnailFont
+	borderWidth := 6
+	font := ""
+	if a.subtitleGen != nil {
+		font = a.subtitleGen.fontName
+		borderWidth = a.subtitleGen.outlineSize
+	}
+
+	filter := fmt.Sprintf("drawtext=textfile='%s':fontsize=%d:fontcolor=white:borderw=%d:bordercolor=black:line_spacing=10:x=(w-text_w)/2:y=(h-text_h)/2",
+		textPath, fontSize, borderWidth)
+	if font != "" {
+		filter += fmt.Sprintf(":font='%s'", font)
+	}
+	return filter
+}
+
+func wrapText(text string, width int) string {
+	var lines []string
+	var current string
+	for _, word := range strings.Fields(strings.ToUpper(text)) {
+		if current != "" && len(current)+1+len(word) > width {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		if current != "" {
+			current += " "
+		}
+		current += word
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return strings.Join(lines, "\n")
+}
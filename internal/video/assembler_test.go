@@ -1,8 +1,17 @@
 package video
 
 import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
 func TestNewAssembler(t *testing.T) {
@@ -105,7 +114,7 @@ func TestBuildFilterComplex(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := assembler.buildFilterComplex(tt.assPath, tt.overlays, tt.musicPath, tt.duration)
+			result := assembler.buildFilterComplex(tt.assPath, "", tt.overlays, tt.musicPath, tt.duration)
 
 			for _, want := range tt.wantContains {
 				if !strings.Contains(result, want) {
@@ -122,19 +131,96 @@ func TestBuildFilterComplex(t *testing.T) {
 	}
 }
 
+func TestBuildFilterComplexKenBurns(t *testing.T) {
+	subGen := NewSubtitleGenerator(SubtitleOptions{FontName: "Arial", FontSize: 48})
+	overlays := []ImageOverlay{
+		{ImagePath: "/tmp/img1.png", StartTime: 1.0, EndTime: 5.0, Width: 400, Height: 300},
+		{ImagePath: "/tmp/anim.gif", StartTime: 6.0, EndTime: 8.0, Width: 400, Height: 300, IsGif: true},
+	}
+
+	tests := []struct {
+		name        string
+		kenBurns    bool
+		wantImageZP bool
+	}{
+		{
+			name:        "enabled",
+			kenBurns:    true,
+			wantImageZP: true,
+		},
+		{
+			name:        "disabled",
+			kenBurns:    false,
+			wantImageZP: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(AssemblerOptions{
+				OutputDir:   "/output",
+				Resolution:  "1080x1920",
+				SubtitleGen: subGen,
+				KenBurns:    tt.kenBurns,
+			})
+
+			result := assembler.buildFilterComplex("/tmp/subs.ass", "", overlays, "", 30.0)
+
+			var imageFilter, gifFilter string
+			for _, f := range strings.Split(result, ";") {
+				switch {
+				case strings.HasPrefix(f, "[2:v]"):
+					imageFilter = f
+				case strings.HasPrefix(f, "[3:v]"):
+					gifFilter = f
+				}
+			}
+
+			if got := strings.Contains(imageFilter, "zoompan"); got != tt.wantImageZP {
+				t.Errorf("image filter zoompan = %v, want %v\ngot: %s", got, tt.wantImageZP, imageFilter)
+			}
+			if strings.Contains(gifFilter, "zoompan") {
+				t.Errorf("gif filter should not contain zoompan\ngot: %s", gifFilter)
+			}
+		})
+	}
+}
+
+func TestKenBurnsFilterScalesWithDuration(t *testing.T) {
+	subGen := NewSubtitleGenerator(SubtitleOptions{FontName: "Arial", FontSize: 48})
+	assembler := NewAssemblerWithOptions(AssemblerOptions{
+		OutputDir:   "/output",
+		Resolution:  "1080x1920",
+		SubtitleGen: subGen,
+		KenBurns:    true,
+	})
+
+	short := assembler.kenBurnsFilter(ImageOverlay{StartTime: 0, EndTime: 1, Width: 400, Height: 300})
+	long := assembler.kenBurnsFilter(ImageOverlay{StartTime: 0, EndTime: 5, Width: 400, Height: 300})
+
+	if !strings.Contains(short, ",1.030)") {
+		t.Errorf("short overlay max zoom mismatch\ngot: %s", short)
+	}
+	if !strings.Contains(long, ",1.150)") {
+		t.Errorf("long overlay max zoom mismatch\ngot: %s", long)
+	}
+}
+
 func TestBuildFFmpegArgs(t *testing.T) {
 	subGen := NewSubtitleGenerator(SubtitleOptions{FontName: "Arial", FontSize: 48})
 	assembler := NewAssembler("/output", subGen, nil)
 
 	tests := []struct {
-		name         string
-		bgClip       string
-		audioPath    string
-		musicPath    string
-		startTime    float64
-		duration     float64
-		overlays     []ImageOverlay
-		wantContains []string
+		name            string
+		bgClip          string
+		audioPath       string
+		musicPath       string
+		startTime       float64
+		loop            bool
+		duration        float64
+		overlays        []ImageOverlay
+		wantContains    []string
+		wantNotContains []string
 	}{
 		{
 			name:      "basicArgs",
@@ -155,6 +241,22 @@ func TestBuildFFmpegArgs(t *testing.T) {
 				"-c:v",
 				"-c:a", "aac",
 			},
+			wantNotContains: []string{
+				"-stream_loop",
+			},
+		},
+		{
+			name:      "shortClipLoops",
+			bgClip:    "/bg/short.mp4",
+			audioPath: "/audio/voice.mp3",
+			loop:      true,
+			duration:  45.0,
+			wantContains: []string{
+				"-stream_loop -1 -t 46.50 -i /bg/short.mp4",
+			},
+			wantNotContains: []string{
+				"-ss",
+			},
 		},
 		{
 			name:      "withOverlays",
@@ -188,9 +290,9 @@ func TestBuildFFmpegArgs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			filterComplex := assembler.buildFilterComplex("/tmp/subs.ass", tt.overlays, tt.musicPath, tt.duration)
+			filterComplex := assembler.buildFilterComplex("/tmp/subs.ass", "", tt.overlays, tt.musicPath, tt.duration)
 			args := assembler.buildFFmpegArgs(
-				tt.bgClip, tt.audioPath, tt.musicPath, tt.startTime, tt.duration,
+				tt.bgClip, tt.audioPath, tt.musicPath, tt.startTime, tt.loop, tt.duration,
 				filterComplex, tt.overlays, "/output/out.mp4",
 			)
 
@@ -200,6 +302,11 @@ func TestBuildFFmpegArgs(t *testing.T) {
 					t.Errorf("buildFFmpegArgs() missing %q\ngot: %v", want, args)
 				}
 			}
+			for _, unwanted := range tt.wantNotContains {
+				if strings.Contains(argsStr, unwanted) {
+					t.Errorf("buildFFmpegArgs() should not contain %q\ngot: %v", unwanted, args)
+				}
+			}
 		})
 	}
 }
@@ -234,7 +341,7 @@ func TestRandomStartTime(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			for i := 0; i < 10; i++ {
-				result := randomStart(tt.clipDuration, tt.neededDuration)
+				result := randomStart(rand.New(rand.NewSource(1)), tt.clipDuration, tt.neededDuration)
 
 				if tt.wantZero && result != 0 {
 					t.Errorf("randomStart() = %v, want 0", result)
@@ -412,7 +519,7 @@ func TestSelectMusicTrack(t *testing.T) {
 			SubtitleGen: subGen,
 			MusicDir:    "",
 		})
-		result := assembler.selectMusicTrack()
+		result := assembler.selectMusicTrack(rand.New(rand.NewSource(1)), "")
 		if result != "" {
 			t.Errorf("selectMusicTrack() = %q, want empty string", result)
 		}
@@ -425,9 +532,960 @@ func TestSelectMusicTrack(t *testing.T) {
 			SubtitleGen: subGen,
 			MusicDir:    "/nonexistent/path",
 		})
-		result := assembler.selectMusicTrack()
+		result := assembler.selectMusicTrack(rand.New(rand.NewSource(1)), "")
 		if result != "" {
 			t.Errorf("selectMusicTrack() = %q, want empty string", result)
 		}
 	})
 }
+
+func TestSelectMusicTrackMood(t *testing.T) {
+	musicDir := t.TempDir()
+	writeTrack := func(path string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("audio"), 0644); err != nil {
+			t.Fatalf("failed to write track: %v", err)
+		}
+	}
+
+	topLevel := filepath.Join(musicDir, "upbeat.mp3")
+	dark := filepath.Join(musicDir, "dark", "suspense.mp3")
+	writeTrack(topLevel)
+	writeTrack(dark)
+	writeTrack(filepath.Join(musicDir, "calm", "notes.txt"))
+
+	tests := []struct {
+		name string
+		mood string
+		want string
+	}{
+		{name: "moodSubdirectory", mood: "dark", want: dark},
+		{name: "noMood", mood: "", want: topLevel},
+		{name: "missingMoodFallsBack", mood: "epic", want: topLevel},
+		{name: "emptyMoodFallsBack", mood: "calm", want: topLevel},
+	}
+
+	assembler := NewAssemblerWithOptions(AssemblerOptions{MusicDir: musicDir})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := assembler.selectMusicTrack(rand.New(rand.NewSource(1)), tt.mood); got != tt.want {
+				t.Errorf("selectMusicTrack(%q) = %q, want %q", tt.mood, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeededSelectionIsReproducible(t *testing.T) {
+	musicDir := t.TempDir()
+	for i := range 10 {
+		if err := os.WriteFile(filepath.Join(musicDir, fmt.Sprintf("track%d.mp3", i)), []byte("audio"), 0644); err != nil {
+			t.Fatalf("failed to write track: %v", err)
+		}
+	}
+	assembler := NewAssemblerWithOptions(AssemblerOptions{MusicDir: musicDir})
+
+	pick := func(seed int64) (float64, string) {
+		rng := rand.New(rand.NewSource(seed))
+		return randomStart(rng, 600, 60), assembler.selectMusicTrack(rng, "")
+	}
+
+	start1, track1 := pick(42)
+	start2, track2 := pick(42)
+	if start1 != start2 || track1 != track2 {
+		t.Errorf("same seed picked (%v, %q) and (%v, %q)", start1, track1, start2, track2)
+	}
+
+	start3, _ := pick(7)
+	if start3 == start1 {
+		t.Errorf("different seeds picked the same start %v", start1)
+	}
+}
+
+func TestBuildThumbnailArgs(t *testing.T) {
+	subGen := NewSubtitleGenerator(SubtitleOptions{FontName: "Montserrat Black", FontSize: 48})
+	assembler := NewAssembler("/output", subGen, nil)
+
+	tests := []struct {
+		name            string
+		atSeconds       float64
+		textPath        string
+		wantContains    []string
+		wantNotContains []string
+	}{
+		{
+			name:      "withTitle",
+			atSeconds: 2.5,
+			textPath:  "/output/title.txt",
+			wantContains: []string{
+				"-ss 2.50",
+				"-i /output/video.mp4",
+				"-frames:v 1",
+				"drawtext=textfile='/output/title.txt'",
+				"font='Montserrat Black'",
+				"/output/video_thumbnail.jpg",
+			},
+		},
+		{
+			name:      "withoutTitle",
+			atSeconds: 1.0,
+			wantContains: []string{
+				"-ss 1.00",
+				"-frames:v 1",
+			},
+			wantNotContains: []string{
+				"drawtext",
+			},
+		},
+		{
+			name:      "negativeTimestamp",
+			atSeconds: -3,
+			wantContains: []string{
+				"-ss 0.00",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := assembler.buildThumbnailArgs("/output/video.mp4", "/output/video_thumbnail.jpg", tt.atSeconds, tt.textPath)
+			argsStr := strings.Join(args, " ")
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(argsStr, want) {
+					t.Errorf("buildThumbnailArgs() missing %q\ngot: %v", want, args)
+				}
+			}
+			for _, notWant := range tt.wantNotContains {
+				if strings.Contains(argsStr, notWant) {
+					t.Errorf("buildThumbnailArgs() should not contain %q\ngot: %v", notWant, args)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}{
+		{
+			name:  "shortText",
+			text:  "Hello world",
+			width: 18,
+			want:  "HELLO WORLD",
+		},
+		{
+			name:  "wrapsLongText",
+			text:  "What Elon Musk Said to Zuckerberg",
+			width: 18,
+			want:  "WHAT ELON MUSK\nSAID TO ZUCKERBERG",
+		},
+		{
+			name:  "emptyText",
+			text:  "",
+			width: 18,
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapText(tt.text, tt.width); got != tt.want {
+				t.Errorf("wrapText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssemblerVerify(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries use shell scripts")
+	}
+
+	tests := []struct {
+		name     string
+		binaries map[string]string
+		wantErr  string
+	}{
+		{
+			name:    "ffmpegMissing",
+			wantErr: "ffmpeg not found in PATH",
+		},
+		{
+			name:     "ffprobeMissing",
+			binaries: map[string]string{"ffmpeg": "ffmpeg version 6.1.1"},
+			wantErr:  "ffprobe not found in PATH",
+		},
+		{
+			name:     "outdatedVersion",
+			binaries: map[string]string{"ffmpeg": "ffmpeg version 3.4.8", "ffprobe": ""},
+			wantErr:  "too old",
+		},
+		{
+			name:     "supportedVersion",
+			binaries: map[string]string{"ffmpeg": "ffmpeg version n6.1.1", "ffprobe": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, output := range tt.binaries {
+				script := fmt.Sprintf("#!/bin/sh\necho '%s'\n", output)
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+					t.Fatalf("failed to write %s: %v", name, err)
+				}
+			}
+			t.Setenv("PATH", dir)
+
+			assembler := NewAssembler("/output", nil, nil)
+			err := assembler.Verify()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want containing %q", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), ffmpegInstall) {
+				t.Errorf("Verify() error = %v, want install hint", err)
+			}
+		})
+	}
+}
+
+func TestParseFFmpegMajor(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   int
+		wantOK bool
+	}{
+		{name: "release", output: "ffmpeg version 6.1.1-3ubuntu5 Copyright (c)", want: 6, wantOK: true},
+		{name: "prefixedRelease", output: "ffmpeg version n7.0 Copyright (c)", want: 7, wantOK: true},
+		{name: "gitBuild", output: "ffmpeg version N-112345-gabcdef Copyright (c)"},
+		{name: "garbage", output: "command not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseFFmpegMajor(tt.output)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseFFmpegMajor() = (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSelectEncoder(t *testing.T) {
+	tests := []struct {
+		name      string
+		encoder   string
+		want      string
+		wantProbe bool
+	}{
+		{name: "autoDetects", encoder: "auto", want: "nvenc", wantProbe: true},
+		{name: "emptyDetects", encoder: "", want: "nvenc", wantProbe: true},
+		{name: "forceSoftware", encoder: "libx264", want: "libx264"},
+		{name: "forceVAAPI", encoder: "vaapi", want: "vaapi"},
+		{name: "unknownFallsBack", encoder: "quicksync", want: "nvenc", wantProbe: true},
+	}
+
+	original := detectEncoder
+	t.Cleanup(func() { detectEncoder = original })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probed := false
+			detectEncoder = func() encoder {
+				probed = true
+				return encoders[0]
+			}
+
+			assembler := NewAssemblerWithOptions(AssemblerOptions{Encoder: tt.encoder})
+			if got := assembler.selectEncoder(); got.name != tt.want {
+				t.Errorf("selectEncoder() = %q, want %q", got.name, tt.want)
+			}
+			if probed != tt.wantProbe {
+				t.Errorf("probed = %v, want %v", probed, tt.wantProbe)
+			}
+		})
+	}
+}
+
+func TestSoftwareEncoderArgs(t *testing.T) {
+	crf, lossless := 18, 0
+
+	tests := []struct {
+		name            string
+		opts            AssemblerOptions
+		overlays        []ImageOverlay
+		wantContains    []string
+		wantNotContains []string
+	}{
+		{
+			name:         "defaults",
+			opts:         AssemblerOptions{Encoder: "libx264"},
+			wantContains: []string{"-preset medium", "-crf 20", "-maxrate 12M"},
+		},
+		{
+			name:            "configuredCRFAndPreset",
+			opts:            AssemblerOptions{Encoder: "libx264", CRF: &crf, Preset: "slow"},
+			wantContains:    []string{"-preset slow", "-crf 18"},
+			wantNotContains: []string{"-crf 20", "-preset medium"},
+		},
+		{
+			name:            "explicitZeroCRF",
+			opts:            AssemblerOptions{Encoder: "libx264", CRF: &lossless},
+			wantContains:    []string{"-crf 0"},
+			wantNotContains: []string{"-crf 20"},
+		},
+		{
+			name:            "targetBitrateReplacesCRF",
+			opts:            AssemblerOptions{Encoder: "libx264", Preset: "fast", BitrateKbps: 6000},
+			wantContains:    []string{"-preset fast", "-b:v 6000k", "-maxrate 9000k", "-bufsize 12000k"},
+			wantNotContains: []string{"-crf"},
+		},
+		{
+			name:         "overlaysUseConfiguredSoftwareEncoder",
+			opts:         AssemblerOptions{Encoder: "nvenc", CRF: &crf, Preset: "slow"},
+			overlays:     []ImageOverlay{{ImagePath: "image.jpg", EndTime: 2}},
+			wantContains: []string{"libx264", "-preset slow", "-crf 18"},
+		},
+		{
+			name:            "hardwareEncoderKeepsPresets",
+			opts:            AssemblerOptions{Encoder: "nvenc", CRF: &crf, Preset: "slow"},
+			wantContains:    []string{"h264_nvenc", "-preset p4"},
+			wantNotContains: []string{"-crf 18", "-preset slow"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(tt.opts)
+			args := strings.Join(assembler.buildFFmpegArgs("bg.mp4", "audio.mp3", "", 0, false, 10, "[v]", tt.overlays, "out.mp4"), " ")
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(args, want) {
+					t.Errorf("args missing %q: %s", want, args)
+				}
+			}
+			for _, notWant := range tt.wantNotContains {
+				if strings.Contains(args, notWant) {
+					t.Errorf("args unexpectedly contain %q: %s", notWant, args)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildFFmpegArgsForcedEncoder(t *testing.T) {
+	original := detectEncoder
+	t.Cleanup(func() { detectEncoder = original })
+	detectEncoder = func() encoder {
+		t.Fatal("encoder probe should be skipped")
+		return softwareEncoder
+	}
+
+	assembler := NewAssemblerWithOptions(AssemblerOptions{Encoder: "vaapi"})
+	args := strings.Join(assembler.buildFFmpegArgs("bg.mp4", "audio.mp3", "", 0, false, 10, "[v]", nil, "out.mp4"), " ")
+
+	if !strings.Contains(args, "-vaapi_device /dev/dri/renderD128") {
+		t.Errorf("args missing vaapi input args: %s", args)
+	}
+	if !strings.Contains(args, "h264_vaapi") {
+		t.Errorf("args missing vaapi codec: %s", args)
+	}
+}
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		duration float64
+		want     []float64
+	}{
+		{
+			name:     "reportsOutTime",
+			input:    "frame=10\nout_time_us=2500000\nprogress=continue\nframe=20\nout_time_us=5000000\nprogress=continue\n",
+			duration: 10,
+			want:     []float64{25, 50},
+		},
+		{
+			name:     "endReportsComplete",
+			input:    "out_time_us=9000000\nprogress=end\n",
+			duration: 10,
+			want:     []float64{90, 100},
+		},
+		{
+			name:     "ignoresUnavailableAndDuplicates",
+			input:    "out_time_us=N/A\nout_time_ms=1000000\nout_time_us=1000000\nout_time=00:00:01.000000\n",
+			duration: 4,
+			want:     []float64{25},
+		},
+		{
+			name:     "clampsOvershoot",
+			input:    "out_time_us=12000000\nprogress=end\n",
+			duration: 10,
+			want:     []float64{100},
+		},
+		{
+			name:     "partialTrailingLine",
+			input:    "out_time_us=3000000\nout_time_us=60",
+			duration: 10,
+			want:     []float64{30},
+		},
+		{
+			name:     "unknownDuration",
+			input:    "out_time_us=3000000\n",
+			duration: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []float64
+			parseProgress(iotest.OneByteReader(strings.NewReader(tt.input)), tt.duration, func(p float64) {
+				got = append(got, p)
+			})
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("reported %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("report[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseProgressNilCallback(t *testing.T) {
+	parseProgress(strings.NewReader("out_time_us=1000000\nprogress=end\n"), 10, nil)
+}
+
+func TestCleanupTemps(t *testing.T) {
+	outputDir := t.TempDir()
+	sessionDir := filepath.Join(outputDir, "20240501_120000_cats")
+	if err := os.MkdirAll(sessionDir, 0755); err != nil {
+		t.Fatalf("failed to create session dir: %v", err)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	fixtures := []struct {
+		path        string
+		modTime     time.Time
+		wantRemoved bool
+	}{
+		{path: filepath.Join(outputDir, "subs_1714564800000000000.ass"), modTime: old, wantRemoved: true},
+		{path: filepath.Join(sessionDir, "main_1714564800000000000.mp4"), modTime: old, wantRemoved: true},
+		{path: filepath.Join(sessionDir, "concat_1714564800000000000.txt"), modTime: old, wantRemoved: true},
+		{path: filepath.Join(sessionDir, "intro_1714564800000000000.mp4"), modTime: old, wantRemoved: true},
+		{path: filepath.Join(sessionDir, "title_1714564800000000000.txt"), modTime: old, wantRemoved: true},
+		{path: filepath.Join(sessionDir, "pass_1714564800000000000-0.log"), modTime: old, wantRemoved: true},
+		{path: filepath.Join(sessionDir, "pass_1714564800000000000-0.log.mbtree"), modTime: old, wantRemoved: true},
+		{path: filepath.Join(sessionDir, "main_1714564900000000000.mp4"), modTime: time.Now()},
+		{path: filepath.Join(outputDir, "video_1714564800.mp4"), modTime: old},
+		{path: filepath.Join(sessionDir, "video.mp4"), modTime: old},
+		{path: filepath.Join(sessionDir, "preview_1714564800000000000.mp4"), modTime: old},
+		{path: filepath.Join(sessionDir, "subs_final.ass"), modTime: old},
+	}
+
+	for _, f := range fixtures {
+		if err := os.WriteFile(f.path, []byte("x"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", f.path, err)
+		}
+		if err := os.Chtimes(f.path, f.modTime, f.modTime); err != nil {
+			t.Fatalf("failed to set mod time: %v", err)
+		}
+	}
+
+	assembler := NewAssembler(outputDir, nil, nil)
+	removed, err := assembler.CleanupTemps(time.Hour)
+	if err != nil {
+		t.Fatalf("CleanupTemps() error = %v", err)
+	}
+	if removed != 7 {
+		t.Errorf("removed = %d, want 7", removed)
+	}
+
+	for _, f := range fixtures {
+		_, err := os.Stat(f.path)
+		if exists := err == nil; exists == f.wantRemoved {
+			t.Errorf("%s exists = %v, want removed = %v", filepath.Base(f.path), exists, f.wantRemoved)
+		}
+	}
+}
+
+func TestCleanupTempsMissingDir(t *testing.T) {
+	assembler := NewAssembler(filepath.Join(t.TempDir(), "missing"), nil, nil)
+	if removed, err := assembler.CleanupTemps(time.Hour); err != nil || removed != 0 {
+		t.Errorf("CleanupTemps() = (%d, %v), want (0, nil)", removed, err)
+	}
+}
+
+func TestBuildAudioFilterLoudness(t *testing.T) {
+	tests := []struct {
+		name         string
+		normalize    bool
+		musicPath    string
+		wantContains []string
+	}{
+		{
+			name:         "disabled",
+			musicPath:    "/music/track.mp3",
+			wantContains: []string{"normalize=0[a]"},
+		},
+		{
+			name:         "enabledNoMusic",
+			normalize:    true,
+			wantContains: []string{"amix=inputs=2:duration=longest[mix]", ";[mix]loudnorm=I=-14.0:TP=-1.5:LRA=11[a]"},
+		},
+		{
+			name:      "enabledWithMusic",
+			normalize: true,
+			musicPath: "/music/track.mp3",
+			wantContains: []string{
+				"afade=t=out:st=28.00:d=2.00[music]",
+				"normalize=0[mix];[mix]loudnorm=I=-14.0:TP=-1.5:LRA=11[a]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(AssemblerOptions{Normalize: tt.normalize})
+			result := assembler.buildAudioFilter(tt.musicPath, 30.0)
+
+			if got := strings.Contains(result, "loudnorm"); got != tt.normalize {
+				t.Errorf("loudnorm present = %v, want %v\ngot: %s", got, tt.normalize, result)
+			}
+			if strings.Count(result, "[a]") != 1 {
+				t.Errorf("expected exactly one [a] output\ngot: %s", result)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(result, want) {
+					t.Errorf("buildAudioFilter() missing %q\ngot: %s", want, result)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildAudioFilterDucking(t *testing.T) {
+	tests := []struct {
+		name          string
+		ducking       bool
+		musicPath     string
+		wantSidechain bool
+	}{
+		{name: "enabledWithMusic", ducking: true, musicPath: "/music/track.mp3", wantSidechain: true},
+		{name: "enabledNoMusic", ducking: true},
+		{name: "disabled", musicPath: "/music/track.mp3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(AssemblerOptions{
+				MusicVolume: 0.15,
+				Ducking:     tt.ducking,
+				DuckRatio:   6,
+			})
+			result := assembler.buildAudioFilter(tt.musicPath, 30.0)
+
+			if got := strings.Contains(result, "sidechaincompress"); got != tt.wantSidechain {
+				t.Fatalf("sidechaincompress present = %v, want %v\ngot: %s", got, tt.wantSidechain, result)
+			}
+			if !tt.wantSidechain {
+				if tt.musicPath != "" && !strings.Contains(result, "volume=0.15") {
+					t.Errorf("expected static music volume\ngot: %s", result)
+				}
+				return
+			}
+
+			for _, want := range []string{
+				"[1:a]volume=1.0,asplit=2[voice][sidechain]",
+				"[musicraw][sidechain]sidechaincompress=threshold=0.050:ratio=6.0",
+				"[music];[bga][voice][music]amix=inputs=3",
+			} {
+				if !strings.Contains(result, want) {
+					t.Errorf("buildAudioFilter() missing %q\ngot: %s", want, result)
+				}
+			}
+		})
+	}
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    ClipInfo
+		wantErr bool
+	}{
+		{
+			name:   "fullOutput",
+			output: "width=1080\nheight=1920\nduration=42.500000\n",
+			want:   ClipInfo{Width: 1080, Height: 1920, Duration: 42.5},
+		},
+		{
+			name:   "missingStream",
+			output: "duration=12.0\n",
+			want:   ClipInfo{Duration: 12},
+		},
+		{
+			name:    "noDuration",
+			output:  "width=1080\nheight=1920\nduration=N/A\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProbeOutput(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseProbeOutput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && *got != tt.want {
+				t.Errorf("parseProbeOutput() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeClip(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries use shell scripts")
+	}
+
+	binDir := t.TempDir()
+	script := "#!/bin/sh\nfor last; do :; done\ncase \"$last\" in\n*short.mp4) echo 'width=720'; echo 'height=1280'; echo 'duration=15.0' ;;\n*long.mp4) echo 'width=1080'; echo 'height=1920'; echo 'duration=120.0' ;;\n*) exit 1 ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(binDir, "ffprobe"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write ffprobe: %v", err)
+	}
+	t.Setenv("PATH", binDir)
+
+	clipDir := t.TempDir()
+	for _, name := range []string{"short.mp4", "long.mp4", "broken.mp4"} {
+		if err := os.WriteFile(filepath.Join(clipDir, name), []byte("video"), 0644); err != nil {
+			t.Fatalf("failed to write clip: %v", err)
+		}
+	}
+
+	tests := []struct {
+		clip    string
+		want    ClipInfo
+		wantErr bool
+	}{
+		{clip: "short.mp4", want: ClipInfo{Duration: 15, Width: 720, Height: 1280}},
+		{clip: "long.mp4", want: ClipInfo{Duration: 120, Width: 1080, Height: 1920}},
+		{clip: "broken.mp4", wantErr: true},
+	}
+
+	assembler := NewAssembler("/output", nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.clip, func(t *testing.T) {
+			path := filepath.Join(clipDir, tt.clip)
+			got, err := assembler.ProbeClip(context.Background(), path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProbeClip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			tt.want.Path = path
+			if *got != tt.want {
+				t.Errorf("ProbeClip() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFilterComplexHook(t *testing.T) {
+	subGen := NewSubtitleGenerator(SubtitleOptions{FontName: "Montserrat Black", FontSize: 60, OutlineSize: 5})
+	assembler := NewAssemblerWithOptions(AssemblerOptions{
+		Resolution:   "1080x1920",
+		SubtitleGen:  subGen,
+		HookDuration: 3,
+	})
+
+	tests := []struct {
+		name     string
+		hookPath string
+		overlays []ImageOverlay
+		wantHook bool
+	}{
+		{name: "noHook"},
+		{name: "hookWithoutOverlays", hookPath: "/tmp/hook.txt", wantHook: true},
+		{
+			name:     "hookWithOverlays",
+			hookPath: "/tmp/hook.txt",
+			overlays: []ImageOverlay{{ImagePath: "/tmp/img.png", StartTime: 0, EndTime: 2, Width: 400, Height: 300}},
+			wantHook: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := assembler.buildFilterComplex("/tmp/subs.ass", tt.hookPath, tt.overlays, "", 30.0)
+
+			if got := strings.Contains(result, "drawtext="); got != tt.wantHook {
+				t.Fatalf("drawtext present = %v, want %v\ngot: %s", got, tt.wantHook, result)
+			}
+			if !tt.wantHook {
+				return
+			}
+
+			for _, want := range []string{
+				"drawtext=textfile='/tmp/hook.txt'",
+				"fontsize=90",
+				"borderw=5",
+				"font='Montserrat Black'",
+				"enable='lt(t,3.00)'",
+				"alpha='if(lt(t,2.50),1,(3.00-t)/0.50)'",
+				"drawbox=",
+			} {
+				if !strings.Contains(result, want) {
+					t.Errorf("buildFilterComplex() missing %q\ngot: %s", want, result)
+				}
+			}
+
+			videoChain := strings.Split(result, "[v]")[0]
+			if strings.Index(videoChain, "drawtext") < strings.Index(videoChain, "ass=") {
+				t.Errorf("hook should render after subtitles\ngot: %s", videoChain)
+			}
+		})
+	}
+}
+
+func TestWriteHookFile(t *testing.T) {
+	assembler := NewAssembler("/output", nil, nil)
+	outputPath := filepath.Join(t.TempDir(), "video.mp4")
+
+	path, cleanup, err := assembler.writeHookFile(outputPath, "  ")
+	if err != nil || path != "" {
+		t.Fatalf("writeHookFile() with empty text = (%q, %v), want no file", path, err)
+	}
+	cleanup()
+
+	path, cleanup, err = assembler.writeHookFile(outputPath, "You won't believe this fact")
+	if err != nil {
+		t.Fatalf("writeHookFile() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read hook file: %v", err)
+	}
+	if string(content) != "YOU WON'T\nBELIEVE THIS\nFACT" {
+		t.Errorf("hook file = %q", content)
+	}
+
+	cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected hook file to be removed")
+	}
+}
+
+func TestBuildFilterComplexSafeZone(t *testing.T) {
+	overlays := []ImageOverlay{{ImagePath: "/tmp/img.png", StartTime: 1, EndTime: 3, Width: 800, Height: 1600}}
+
+	tests := []struct {
+		name       string
+		resolution string
+		safeZone   int
+		want       string
+	}{
+		{name: "noSafeZone", resolution: "1080x1920", want: "overlay=(W-w)/2:100:"},
+		{name: "safeZone", resolution: "1080x1920", safeZone: 300, want: "overlay=(W-w)/2:'min(100,H-h-300)':"},
+		{name: "scaledToResolution", resolution: "720x1280", safeZone: 300, want: "overlay=(W-w)/2:'min(100,H-h-200)':"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(AssemblerOptions{Resolution: tt.resolution, SafeZoneBottom: tt.safeZone})
+			result := assembler.buildFilterComplex("/tmp/subs.ass", "", overlays, "", 30.0)
+			if !strings.Contains(result, tt.want) {
+				t.Errorf("buildFilterComplex() missing %q\ngot: %s", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestBuildFilterComplexMirror(t *testing.T) {
+	overlays := []ImageOverlay{{ImagePath: "/tmp/img.png", StartTime: 1, EndTime: 3, Width: 800, Height: 1600}}
+
+	tests := []struct {
+		name     string
+		mirror   bool
+		overlays []ImageOverlay
+		want     bool
+	}{
+		{name: "disabled", want: false},
+		{name: "enabled", mirror: true, want: true},
+		{name: "enabledWithOverlays", mirror: true, overlays: overlays, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(AssemblerOptions{Resolution: "1080x1920", Mirror: tt.mirror})
+			result := assembler.buildFilterComplex("/tmp/subs.ass", "", tt.overlays, "", 30.0)
+			if got := strings.Contains(result, "crop=1080:1920,hflip,ass="); got != tt.want {
+				t.Errorf("hflip present = %v, want %v\ngot: %s", got, tt.want, result)
+			}
+		})
+	}
+}
+
+func TestBuildFilterComplexSubscribeOverlay(t *testing.T) {
+	tests := []struct {
+		name      string
+		overlays  []ImageOverlay
+		duration  float64
+		wantInput int
+		wantStart float64
+	}{
+		{name: "noOtherOverlays", duration: 30, wantInput: 2, wantStart: 27},
+		{name: "afterOverlays", overlays: []ImageOverlay{{ImagePath: "/tmp/img.png", StartTime: 1, EndTime: 3, Width: 800, Height: 1600}}, duration: 30, wantInput: 3, wantStart: 27},
+		{name: "shortVideo", duration: 2, wantInput: 2, wantStart: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(AssemblerOptions{Resolution: "1080x1920", SubscribePath: "/tmp/subscribe.gif"})
+			overlays := assembler.prepareOverlays(tt.overlays, tt.duration)
+
+			last := overlays[len(overlays)-1]
+			if !last.IsGif || last.ImagePath != "/tmp/subscribe.gif" {
+				t.Fatalf("last overlay = %+v, want subscribe gif", last)
+			}
+
+			result := assembler.buildFilterComplex("/tmp/subs.ass", "", overlays, "", tt.duration)
+			wantScale := fmt.Sprintf("[%d:v]scale=648:-1,setpts=PTS-STARTPTS+%.2f/TB", tt.wantInput, tt.wantStart)
+			if !strings.Contains(result, wantScale) {
+				t.Errorf("missing %q\ngot: %s", wantScale, result)
+			}
+			wantEnable := fmt.Sprintf("enable='between(t,%.2f,%.2f)'", tt.wantStart, tt.duration+videoEndBuffer)
+			if !strings.Contains(result, wantEnable) {
+				t.Errorf("missing %q\ngot: %s", wantEnable, result)
+			}
+		})
+	}
+}
+
+func TestPrepareOverlaysKeepsSubscribe(t *testing.T) {
+	overlays := make([]ImageOverlay, maxOverlays+2)
+	for i := range overlays {
+		overlays[i] = ImageOverlay{ImagePath: fmt.Sprintf("/tmp/img%d.png", i), Width: 800, Height: 1600}
+	}
+
+	assembler := NewAssemblerWithOptions(AssemblerOptions{SubscribePath: "/tmp/subscribe.png", SubscribeDur: 4})
+	got := assembler.prepareOverlays(overlays, 20)
+	if len(got) != maxOverlays+1 {
+		t.Fatalf("len = %d, want %d", len(got), maxOverlays+1)
+	}
+	if last := got[len(got)-1]; last.ImagePath != "/tmp/subscribe.png" || last.StartTime != 16 || last.IsGif {
+		t.Errorf("last overlay = %+v", last)
+	}
+	if overlays[maxOverlays].ImagePath != fmt.Sprintf("/tmp/img%d.png", maxOverlays) {
+		t.Error("prepareOverlays modified the input slice")
+	}
+}
+
+func TestClipArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		hasAudio bool
+		want     string
+		wantNot  string
+	}{
+		{
+			name:     "withAudio",
+			hasAudio: true,
+			want:     "-y -i intro.mp4 -t 3.00",
+			wantNot:  "anullsrc",
+		},
+		{
+			name:     "silentClipGetsSilence",
+			hasAudio: false,
+			want:     "-y -i intro.mp4 -f lavfi -i anullsrc=r=44100:cl=stereo -t 3.00 -vf scale=1080:1920:force_original_aspect_ratio=increase,crop=1080:1920 -map 0:v:0 -map 1:a:0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(AssemblerOptions{})
+			joined := strings.Join(assembler.clipArgs("intro.mp4", 3, tt.hasAudio, "out.mp4"), " ")
+			if !strings.Contains(joined, tt.want) {
+				t.Errorf("clipArgs() missing %q\ngot: %s", tt.want, joined)
+			}
+			if tt.wantNot != "" && strings.Contains(joined, tt.wantNot) {
+				t.Errorf("clipArgs() contains %q\ngot: %s", tt.wantNot, joined)
+			}
+			if !strings.HasSuffix(joined, "-c:a aac -ar 44100 out.mp4") {
+				t.Errorf("clipArgs() must encode audio, got: %s", joined)
+			}
+		})
+	}
+}
+
+func TestBuildCrossfadeArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		segments  []segment
+		wantFades []float64
+		want      []string
+	}{
+		{
+			name:      "introMainOutro",
+			segments:  []segment{{path: "intro.mp4", duration: 3}, {path: "main.mp4", duration: 31.5}, {path: "outro.mp4", duration: 4}},
+			wantFades: []float64{0.5, 0.5},
+			want: []string{
+				"[v0][v1]xfade=transition=fade:duration=0.50:offset=2.50[xv1]",
+				"[xv1][v2]xfade=transition=fade:duration=0.50:offset=33.50[xv2]",
+				"[a0][a1]acrossfade=d=0.50[xa1]",
+				"[xa1][a2]acrossfade=d=0.50[xa2]",
+			},
+		},
+		{
+			name:      "shortIntro",
+			segments:  []segment{{path: "intro.mp4", duration: 0.6}, {path: "main.mp4", duration: 31.5}},
+			wantFades: []float64{0.3},
+			want:      []string{"[v0][v1]xfade=transition=fade:duration=0.30:offset=0.30[xv1]"},
+		},
+		{
+			name:      "probedMainDuration",
+			segments:  []segment{{path: "main.mp4", duration: 32.04}, {path: "outro.mp4", duration: 4}},
+			wantFades: []float64{0.5},
+			want:      []string{"[v0][v1]xfade=transition=fade:duration=0.50:offset=31.54[xv1]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(AssemblerOptions{Crossfade: 0.5})
+			args, fades := assembler.buildCrossfadeArgs(tt.segments, "out.mp4")
+
+			if !slices.Equal(fades, tt.wantFades) {
+				t.Errorf("fades = %v, want %v", fades, tt.wantFades)
+			}
+			joined := strings.Join(args, " ")
+			for _, want := range tt.want {
+				if !strings.Contains(joined, want) {
+					t.Errorf("args missing %q\ngot: %s", want, joined)
+				}
+			}
+			if strings.Contains(joined, "-c copy") {
+				t.Errorf("crossfade must re-encode, got: %s", joined)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Chapter struct {
+	Title string
+	Start float64
+}
+
+var metadataEscaper = strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", " ")
+
+func NormalizeChapters(chapters []Chapter) []Chapter {
+	if len(chapters) == 0 {
+		return nil
+	}
+
+	sorted := make([]Chapter, len(chapters))
+	copy(sorted, chapters)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	normalized := make([]Chapter, 0, len(sorted))
+	for _, chapter := range sorted {
+		chapter.Start = max(chapter.Start, 0)
+		if len(normalized) > 0 && int(chapter.Start) <= int(normalized[len(normalized)-1].Start) {
+			continue
+		}
+		normalized = append(normalized, chapter)
+	}
+	normalized[0].Start = 0
+	return normalized
+}
+
+func FormatChapters(chapters []Chapter) string {
+	var lines []string
+	for _, chapter := range NormalizeChapters(chapters) {
+		lines = append(lines, formatTimestamp(chapter.Start)+" "+chapter.Title)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatTimestamp(seconds float64) string {
+	total := int(seconds)
+	h, m, s := total/3600, total/60%60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+func chapterMetadata(chapters []Chapter, duration float64) string {
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+	for i, chapter := range chapters {
+		end := duration
+		if i+1 < len(chapters) {
+			end = chapters[i+1].Start
+		}
+		fmt.Fprintf(&b, "[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			int64(chapter.Start*1000), int64(end*1000), metadataEscaper.Replace(chapter.Title))
+	}
+	return b.String()
+}
+
+func shiftChapters(chapters []Chapter, offset float64) []Chapter {
+	shifted := make([]Chapter, len(chapters))
+	for i, chapter := range chapters {
+		chapter.Start += offset
+		shifted[i] = chapter
+	}
+	return NormalizeChapters(shifted)
+}
+
+func (a *Assembler) embedChapters(ctx context.Context, videoPath string, chapters []Chapter, duration float64) error {
+	dir := filepath.Dir(videoPath)
+	stamp := time.Now().UnixNano()
+	metaPath := filepath.Join(dir, fmt.Sprintf("chapters_%d.txt", stamp))
+	tmpPath := filepath.Join(dir, fmt.Sprintf("chapters_%d.mp4", stamp))
+	defer func() { _ = os.Remove(metaPath) }()
+
+	if err := os.WriteFile(metaPath, []byte(chapterMetadata(chapters, duration)), 0644); err != nil {
+		return fmt.Errorf("write chapter metadata: %w", err)
+	}
+
+	args := []string{"-y", "-i", videoPath, "-f", "ffmetadata", "-i", metaPath, "-map", "0", "-map_metadata", "1", "-map_chapters", "1", "-c", "copy", "-movflags", "+faststart", tmpPath}
+	if err := a.runFFmpeg(ctx, args); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, videoPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("replace video: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package video
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFormatChapters(t *testing.T) {
+	tests := []struct {
+		name     string
+		chapters []Chapter
+		want     string
+	}{
+		{
+			name:     "firstStartsAtZero",
+			chapters: []Chapter{{Title: "Cats", Start: 1.4}, {Title: "Dogs", Start: 15.2}, {Title: "Birds", Start: 75}},
+			want:     "0:00 Cats\n0:15 Dogs\n1:15 Birds",
+		},
+		{
+			name:     "unsorted",
+			chapters: []Chapter{{Title: "Dogs", Start: 20}, {Title: "Cats", Start: 0}},
+			want:     "0:00 Cats\n0:20 Dogs",
+		},
+		{
+			name:     "hours",
+			chapters: []Chapter{{Title: "Start", Start: 0}, {Title: "Late", Start: 3725}},
+			want:     "0:00 Start\n1:02:05 Late",
+		},
+		{
+			name:     "duplicateSecond",
+			chapters: []Chapter{{Title: "A", Start: 0}, {Title: "B", Start: 0.6}},
+			want:     "0:00 A",
+		},
+		{name: "empty", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatChapters(tt.chapters); got != tt.want {
+				t.Errorf("FormatChapters() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChapterMetadata(t *testing.T) {
+	chapters := []Chapter{{Title: "Intro", Start: 0}, {Title: "a=b; #1", Start: 12.5}}
+
+	want := ";FFMETADATA1\n" +
+		"[CHAPTER]\nTIMEBASE=1/1000\nSTART=0\nEND=12500\ntitle=Intro\n" +
+		"[CHAPTER]\nTIMEBASE=1/1000\nSTART=12500\nEND=30000\ntitle=a\\=b\\; \\#1\n"
+	if got := chapterMetadata(chapters, 30); got != want {
+		t.Errorf("chapterMetadata() = %q, want %q", got, want)
+	}
+}
+
+func TestShiftChapters(t *testing.T) {
+	got := shiftChapters([]Chapter{{Title: "A", Start: 0}, {Title: "B", Start: 10}}, 2.5)
+	want := []Chapter{{Title: "A", Start: 0}, {Title: "B", Start: 12.5}}
+	if !slices.Equal(got, want) {
+		t.Errorf("shiftChapters() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package video
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const gifCacheDir = "gifs"
+
+func isGifFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".gif")
+}
+
+func (a *Assembler) convertGifs(ctx context.Context, overlays []ImageOverlay) []ImageOverlay {
+	converted := make([]ImageOverlay, len(overlays))
+	for i, ov := range overlays {
+		if ov.IsGif && isGifFile(ov.ImagePath) {
+			path, err := a.gifToMP4(ctx, ov.ImagePath)
+			if err != nil {
+				slog.Warn("Failed to convert GIF overlay, using it directly", "path", ov.ImagePath, "error", err)
+			} else {
+				ov.ImagePath = path
+			}
+		}
+		converted[i] = ov
+	}
+	return converted
+}
+
+func (a *Assembler) gifToMP4(ctx context.Context, gifPath string) (string, error) {
+	outPath, err := a.gifCachePath(gifPath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(outPath); err == nil {
+		a.log("using cached GIF conversion", "gif", gifPath, "mp4", outPath)
+		return outPath, nil
+	}
+
+	tmpPath := filepath.Join(filepath.Dir(outPath), fmt.Sprintf("gif_%d.mp4", time.Now().UnixNano()))
+	if err := a.runFFmpeg(ctx, gifConvertArgs(gifPath, tmpPath)); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("convert gif: %w", err)
+	}
+	if err := os.Rename(tmpPath, outPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("cache converted gif: %w", err)
+	}
+	return outPath, nil
+}
+
+func (a *Assembler) gifCachePath(gifPath string) (string, error) {
+	f, err := os.Open(gifPath)
+	if err != nil {
+		return "", fmt.Errorf("open gif: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("hash gif: %w", err)
+	}
+
+	dir := filepath.Dir(gifPath)
+	if a.cacheDir != "" {
+		dir = filepath.Join(a.cacheDir, gifCacheDir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create gif cache: %w", err)
+	}
+	return filepath.Join(dir, hex.EncodeToString(hash.Sum(nil))[:16]+".mp4"), nil
+}
+
+func gifConvertArgs(gifPath, outPath string) []string {
+	return []string{
+		"-y",
+		"-i", gifPath,
+		"-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2",
+		"-c:v", "libx264",
+		"-preset", "ultrafast",
+		"-pix_fmt", "yuv420p",
+		"-movflags", "+faststart",
+		"-an",
+		outPath,
+	}
+}
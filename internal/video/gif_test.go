@@ -0,0 +1,89 @@
+package video
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvertGifs(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls.txt")
+	fakeFFmpeg := filepath.Join(dir, "ffmpeg")
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" >> " + calls + "\n" +
+		"for last; do :; done\n" +
+		"touch \"$last\"\n"
+	if err := os.WriteFile(fakeFFmpeg, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	gifPath := filepath.Join(dir, "cat.gif")
+	if err := os.WriteFile(gifPath, []byte("GIF89a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheDir := filepath.Join(dir, "cache")
+	assembler := NewAssemblerWithOptions(AssemblerOptions{CacheDir: cacheDir})
+	assembler.ffmpeg = fakeFFmpeg
+
+	overlays := []ImageOverlay{
+		{ImagePath: gifPath, IsGif: true},
+		{ImagePath: filepath.Join(dir, "dog.png")},
+	}
+	for range 2 {
+		got := assembler.convertGifs(context.Background(), overlays)
+
+		if !strings.HasPrefix(got[0].ImagePath, filepath.Join(cacheDir, gifCacheDir)) || filepath.Ext(got[0].ImagePath) != ".mp4" {
+			t.Errorf("gif overlay path = %q, want cached mp4", got[0].ImagePath)
+		}
+		if !got[0].IsGif {
+			t.Error("converted overlay should stay animated")
+		}
+		if got[1].ImagePath != overlays[1].ImagePath {
+			t.Errorf("png overlay path = %q, want unchanged", got[1].ImagePath)
+		}
+	}
+
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invocations := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(invocations) != 1 {
+		t.Fatalf("ffmpeg invoked %d times, want 1 (second run should hit the cache)", len(invocations))
+	}
+	for _, want := range []string{"-i " + gifPath, "-c:v libx264", "-pix_fmt yuv420p"} {
+		if !strings.Contains(invocations[0], want) {
+			t.Errorf("conversion args missing %q: %s", want, invocations[0])
+		}
+	}
+}
+
+func TestBuildFFmpegArgsAnimatedOverlay(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		want     string
+		wantLoop bool
+	}{
+		{name: "convertedGif", path: "/cache/gifs/abc.mp4", want: "-t 2.50 -i /cache/gifs/abc.mp4", wantLoop: true},
+		{name: "unconvertedGif", path: "/tmp/cat.gif", want: "-t 2.50 -i /tmp/cat.gif"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(AssemblerOptions{})
+			overlays := []ImageOverlay{{ImagePath: tt.path, StartTime: 1, EndTime: 3, IsGif: true}}
+			args := strings.Join(assembler.buildFFmpegArgs("bg.mp4", "audio.mp3", "", 0, false, 10, "[v]", overlays, "out.mp4"), " ")
+			if !strings.Contains(args, tt.want) {
+				t.Errorf("args missing %q\ngot: %s", tt.want, args)
+			}
+			if got := strings.Contains(args, "-stream_loop -1"); got != tt.wantLoop {
+				t.Errorf("stream loop = %v, want %v\ngot: %s", got, tt.wantLoop, args)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	reencodeAudioBitrate = 128_000
+	minVideoBitrate      = 300_000
+	reencodeHeadroom     = 0.95
+)
+
+func (a *Assembler) Reencode(ctx context.Context, videoPath string, maxBytes int64) (string, error) {
+	duration, err := a.videoDuration(ctx, videoPath)
+	if err != nil {
+		return "", fmt.Errorf("probe video: %w", err)
+	}
+
+	bitrate, err := reencodeBitrate(maxBytes, duration)
+	if err != nil {
+		return "", err
+	}
+
+	outputPath := strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + "_reencoded.mp4"
+	args := []string{
+		"-y",
+		"-i", videoPath,
+		"-c:v", "libx264",
+		"-preset", "medium",
+		"-b:v", fmt.Sprint(bitrate),
+		"-maxrate", fmt.Sprint(bitrate),
+		"-bufsize", fmt.Sprint(bitrate * 2),
+		"-pix_fmt", "yuv420p",
+		"-c:a", "aac",
+		"-b:a", fmt.Sprint(reencodeAudioBitrate),
+		"-movflags", "+faststart",
+		outputPath,
+	}
+
+	a.log("Re-encoding to fit upload limit", "bitrate", bitrate, "max_bytes", maxBytes)
+	if err := a.runFFmpeg(ctx, args); err != nil {
+		return "", fmt.Errorf("reencode video: %w", err)
+	}
+	return outputPath, nil
+}
+
+func reencodeBitrate(maxBytes int64, duration float64) (int64, error) {
+	if duration <= 0 {
+		return 0, fmt.Errorf("invalid video duration %.2fs", duration)
+	}
+
+	total := float64(maxBytes) * 8 * reencodeHeadroom / duration
+	bitrate := int64(total) - reencodeAudioBitrate
+	if bitrate < minVideoBitrate {
+		return 0, fmt.Errorf("a %.0fs video cannot fit in %d bytes at an acceptable bitrate", duration, maxBytes)
+	}
+	return bitrate, nil
+}
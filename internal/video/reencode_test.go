@@ -0,0 +1,29 @@
+package video
+
+import "testing"
+
+func TestReencodeBitrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxBytes int64
+		duration float64
+		want     int64
+		wantErr  bool
+	}{
+		{name: "fitsLimit", maxBytes: 10_000_000, duration: 60, want: 1_138_666},
+		{name: "longVideoTooSmallLimit", maxBytes: 1_000_000, duration: 600, wantErr: true},
+		{name: "zeroDuration", maxBytes: 10_000_000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reencodeBitrate(tt.maxBytes, tt.duration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reencodeBitrate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("reencodeBitrate() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"craftstory/internal/speech"
+)
+
+const (
+	silenceThreshold   = "-50dB"
+	silenceMinDuration = 0.1
+	silencePadding     = 0.05
+	minTrim            = 0.01
+)
+
+var (
+	silenceStartRe = regexp.MustCompile(`silence_start: (-?[\d.]+)`)
+	silenceEndRe   = regexp.MustCompile(`silence_end: (-?[\d.]+)`)
+)
+
+type silenceInterval struct {
+	Start float64
+	End   float64
+}
+
+func (s *AudioStitcher) TrimSilence(ctx context.Context, seg AudioSegment) (AudioSegment, error) {
+	if len(seg.Audio) == 0 || len(seg.Timings) == 0 {
+		return seg, nil
+	}
+
+	input, err := os.CreateTemp(s.tempDir, "trim_in_*"+DetectAudioFormat(seg.Audio))
+	if err != nil {
+		return seg, fmt.Errorf("create temp file: %w", err)
+	}
+	inputPath := input.Name()
+	defer func() { _ = os.Remove(inputPath) }()
+
+	if _, err := input.Write(seg.Audio); err != nil {
+		_ = input.Close()
+		return seg, fmt.Errorf("write audio: %w", err)
+	}
+	if err := input.Close(); err != nil {
+		return seg, fmt.Errorf("write audio: %w", err)
+	}
+
+	silences, err := s.detectSilence(ctx, inputPath)
+	if err != nil {
+		return seg, err
+	}
+
+	start, end := trimWindow(silences, seg.Timings)
+	if start == 0 && end == 0 {
+		return seg, nil
+	}
+
+	outputPath := inputPath + ".trimmed.mp3"
+	defer func() { _ = os.Remove(outputPath) }()
+
+	filter := fmt.Sprintf("atrim=start=%.3f", start)
+	if end > 0 {
+		filter += fmt.Sprintf(":end=%.3f", end)
+	}
+	filter += ",asetpts=PTS-STARTPTS"
+
+	args := []string{
+		"-y",
+		"-i", inputPath,
+		"-af", filter,
+		"-acodec", "libmp3lame",
+		"-q:a", "2",
+		outputPath,
+	}
+	cmd := exec.CommandContext(ctx, s.ffmpegPath, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return seg, fmt.Errorf("ffmpeg trim failed: %w, output: %s", err, string(output))
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return seg, fmt.Errorf("read trimmed audio: %w", err)
+	}
+
+	return AudioSegment{
+		Audio:   data,
+		Timings: shiftTimings(seg.Timings, start),
+		Speaker: seg.Speaker,
+	}, nil
+}
+
+func (s *AudioStitcher) detectSilence(ctx context.Context, inputPath string) ([]silenceInterval, error) {
+	args := []string{
+		"-i", inputPath,
+		"-af", fmt.Sprintf("silencedetect=noise=%s:d=%g", silenceThreshold, silenceMinDuration),
+		"-f", "null",
+		"-",
+	}
+	cmd := exec.CommandContext(ctx, s.ffmpegPath, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg silencedetect failed: %w, output: %s", err, string(output))
+	}
+	return parseSilences(string(output)), nil
+}
+
+func parseSilences(output string) []silenceInterval {
+	starts := silenceStartRe.FindAllStringSubmatch(output, -1)
+	ends := silenceEndRe.FindAllStringSubmatch(output, -1)
+
+	intervals := make([]silenceInterval, 0, len(starts))
+	for i, match := range starts {
+		start, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			continue
+		}
+		interval := silenceInterval{Start: max(start, 0), End: -1}
+		if i < len(ends) {
+			if end, err := strconv.ParseFloat(ends[i][1], 64); err == nil {
+				interval.End = end
+			}
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals
+}
+
+func trimWindow(silences []silenceInterval, timings []speech.WordTiming) (float64, float64) {
+	if len(silences) == 0 || len(timings) == 0 {
+		return 0, 0
+	}
+
+	firstWord := timings[0].StartTime
+	lastWord := timings[len(timings)-1].EndTime
+
+	var start, end float64
+
+	leading := silences[0]
+	if leading.Start <= minTrim && leading.End > 0 {
+		start = min(leading.End, firstWord) - silencePadding
+		if start < minTrim {
+			start = 0
+		}
+	}
+
+	trailing := silences[len(silences)-1]
+	if trailing.Start >= lastWord-silencePadding && (trailing.End < 0 || trailing.End > trailing.Start) {
+		end = max(trailing.Start, lastWord) + silencePadding
+	}
+
+	return start, end
+}
+
+func shiftTimings(timings []speech.WordTiming, offset float64) []speech.WordTiming {
+	shifted := make([]speech.WordTiming, len(timings))
+	for i, t := range timings {
+		t.StartTime = max(t.StartTime-offset, 0)
+		t.EndTime = max(t.EndTime-offset, 0)
+		shifted[i] = t
+	}
+	return shifted
+}
@@ -0,0 +1,120 @@
+package video
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"craftstory/internal/speech"
+)
+
+func TestParseSilences(t *testing.T) {
+	output := `[silencedetect @ 0x1] silence_start: 0
+[silencedetect @ 0x1] silence_end: 0.412 | silence_duration: 0.412
+size=N/A time=00:00:02.10 bitrate=N/A
+[silencedetect @ 0x1] silence_start: 1.873`
+
+	got := parseSilences(output)
+	want := []silenceInterval{{Start: 0, End: 0.412}, {Start: 1.873, End: -1}}
+
+	if len(got) != len(want) {
+		t.Fatalf("parseSilences() returned %d intervals, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("interval[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTrimWindow(t *testing.T) {
+	timings := []speech.WordTiming{
+		{Word: "Hello", StartTime: 0.4, EndTime: 0.8},
+		{Word: "world", StartTime: 0.9, EndTime: 1.5},
+	}
+
+	tests := []struct {
+		name      string
+		silences  []silenceInterval
+		wantStart float64
+		wantEnd   float64
+	}{
+		{
+			name:      "leadingAndTrailing",
+			silences:  []silenceInterval{{Start: 0, End: 0.4}, {Start: 1.5, End: -1}},
+			wantStart: 0.35,
+			wantEnd:   1.55,
+		},
+		{
+			name:      "leadingOnly",
+			silences:  []silenceInterval{{Start: 0, End: 0.4}},
+			wantStart: 0.35,
+		},
+		{
+			name:      "softOnsetKeepsWordStart",
+			silences:  []silenceInterval{{Start: 0, End: 0.6}},
+			wantStart: 0.35,
+		},
+		{
+			name:     "midSpeechPauseIgnored",
+			silences: []silenceInterval{{Start: 0.8, End: 0.9}},
+		},
+		{
+			name:     "shortLeadingSilence",
+			silences: []silenceInterval{{Start: 0, End: 0.05}},
+		},
+		{
+			name: "noSilence",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := trimWindow(tt.silences, timings)
+			if math.Abs(start-tt.wantStart) > 1e-9 {
+				t.Errorf("start = %v, want %v", start, tt.wantStart)
+			}
+			if math.Abs(end-tt.wantEnd) > 1e-9 {
+				t.Errorf("end = %v, want %v", end, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestShiftTimings(t *testing.T) {
+	timings := []speech.WordTiming{
+		{Word: "Hello", StartTime: 0.5, EndTime: 0.9, Speaker: "Host"},
+		{Word: "world", StartTime: 1.0, EndTime: 1.4, Speaker: "Host"},
+	}
+
+	shifted := shiftTimings(timings, 0.5)
+
+	want := []speech.WordTiming{
+		{Word: "Hello", StartTime: 0, EndTime: 0.4, Speaker: "Host"},
+		{Word: "world", StartTime: 0.5, EndTime: 0.9, Speaker: "Host"},
+	}
+	for i := range want {
+		if shifted[i].Word != want[i].Word || shifted[i].Speaker != want[i].Speaker {
+			t.Errorf("shifted[%d] = %+v, want %+v", i, shifted[i], want[i])
+		}
+		if math.Abs(shifted[i].StartTime-want[i].StartTime) > 1e-9 || math.Abs(shifted[i].EndTime-want[i].EndTime) > 1e-9 {
+			t.Errorf("shifted[%d] = %+v, want %+v", i, shifted[i], want[i])
+		}
+	}
+	if timings[0].StartTime != 0.5 {
+		t.Error("shiftTimings() modified the input timings")
+	}
+}
+
+func TestTrimSilenceNoTimings(t *testing.T) {
+	stitcher := NewAudioStitcher(t.TempDir())
+	seg := AudioSegment{Audio: []byte("audio")}
+
+	got, err := stitcher.TrimSilence(context.Background(), seg)
+	if err != nil {
+		t.Fatalf("TrimSilence() error = %v", err)
+	}
+	if string(got.Audio) != "audio" {
+		t.Errorf("TrimSilence() changed audio without timings")
+	}
+}
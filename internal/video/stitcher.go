@@ -75,7 +75,7 @@ func (s *AudioStitcher) Stitch(ctx context.Context, segments []AudioSegment) (*S
 	tempFiles = append(tempFiles, silencePath)
 
 	for i, seg := range segments {
-		ext := detectAudioFormat(seg.Audio)
+		ext := DetectAudioFormat(seg.Audio)
 		tempPath := filepath.Join(s.tempDir, fmt.Sprintf("seg_%d%s", i, ext))
 		if err := os.WriteFile(tempPath, seg.Audio, 0644); err != nil {
 			return nil, fmt.Errorf("failed to write segment %d: %w", i, err)
@@ -182,7 +182,7 @@ func (s *AudioStitcher) adjustTimings(segments []AudioSegment) ([]speech.WordTim
 	return allTimings, offset, segmentInfos
 }
 
-func detectAudioFormat(data []byte) string {
+func DetectAudioFormat(data []byte) string {
 	if len(data) < 4 {
 		return ".bin"
 	}
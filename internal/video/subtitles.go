@@ -2,16 +2,29 @@ package video
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"craftstory/internal/speech"
 )
 
+const (
+	captionMaxWords    = 7
+	captionMaxDuration = 3.0
+	emphasisScale      = 1.3
+	playResX           = 1080
+	playResY           = 1920
+	defaultMarginV     = 50
+	defaultBoxColor    = "&H80000000"
+)
+
 type Subtitle struct {
-	Word      string
-	StartTime float64
-	EndTime   float64
-	Color     string
+	Word          string
+	StartTime     float64
+	EndTime       float64
+	Color         string
+	EmphasisColor string
+	Words         []Subtitle
 }
 
 type SubtitleGenerator struct {
@@ -23,17 +36,29 @@ type SubtitleGenerator struct {
 	shadowSize   int
 	bold         bool
 	offset       float64
+	emphasis     map[string]string
+	safeZone     int
+	minDuration  float64
+	wordsPerCue  int
+	box          bool
+	boxColor     string
 }
 
 type SubtitleOptions struct {
-	FontName     string
-	FontSize     int
-	PrimaryColor string
-	OutlineColor string
-	OutlineSize  int
-	ShadowSize   int
-	Bold         bool
-	Offset       float64
+	FontName        string
+	FontSize        int
+	PrimaryColor    string
+	OutlineColor    string
+	OutlineSize     int
+	ShadowSize      int
+	Bold            bool
+	Offset          float64
+	EmphasisWords   map[string]string
+	SafeZoneBottom  int
+	MinWordDuration float64
+	WordsPerCue     int
+	BackgroundBox   bool
+	BoxColor        string
 }
 
 func NewSubtitleGenerator(opts SubtitleOptions) *SubtitleGenerator {
@@ -57,6 +82,16 @@ func NewSubtitleGenerator(opts SubtitleOptions) *SubtitleGenerator {
 		shadowSize = opts.ShadowSize
 	}
 
+	boxColor := defaultBoxColor
+	if opts.BoxColor != "" {
+		boxColor = toASSColor(opts.BoxColor)
+	}
+
+	emphasis := make(map[string]string, len(opts.EmphasisWords))
+	for word, color := range opts.EmphasisWords {
+		emphasis[normalizeSubtitleWord(word)] = color
+	}
+
 	return &SubtitleGenerator{
 		fontName:     opts.FontName,
 		fontSize:     opts.FontSize,
@@ -66,6 +101,12 @@ func NewSubtitleGenerator(opts SubtitleOptions) *SubtitleGenerator {
 		shadowSize:   shadowSize,
 		bold:         opts.Bold,
 		offset:       opts.Offset,
+		emphasis:     emphasis,
+		safeZone:     max(opts.SafeZoneBottom, 0),
+		minDuration:  max(opts.MinWordDuration, 0),
+		wordsPerCue:  max(opts.WordsPerCue, 1),
+		box:          opts.BackgroundBox,
+		boxColor:     boxColor,
 	}
 }
 
@@ -89,6 +130,7 @@ func (g *SubtitleGenerator) GenerateFromTimings(timings []speech.WordTiming) []S
 
 func (g *SubtitleGenerator) GenerateFromTimingsWithColors(timings []speech.WordTiming, speakerColors map[string]string) []Subtitle {
 	subtitles := make([]Subtitle, 0, len(timings))
+	speakers := make([]string, 0, len(timings))
 	for _, t := range timings {
 		startTime := t.StartTime + g.offset
 		endTime := t.EndTime + g.offset
@@ -105,17 +147,87 @@ func (g *SubtitleGenerator) GenerateFromTimingsWithColors(timings []speech.WordT
 		}
 
 		subtitles = append(subtitles, Subtitle{
-			Word:      t.Word,
-			StartTime: startTime,
-			EndTime:   endTime,
-			Color:     color,
+			Word:          t.Word,
+			StartTime:     startTime,
+			EndTime:       endTime,
+			Color:         color,
+			EmphasisColor: g.emphasisColor(t.Word),
 		})
+		speakers = append(speakers, t.Speaker)
+	}
+	subtitles = enforceMinDuration(subtitles, g.minDuration)
+	return groupWords(subtitles, speakers, g.wordsPerCue)
+}
+
+func groupWords(subtitles []Subtitle, speakers []string, wordsPerCue int) []Subtitle {
+	if wordsPerCue <= 1 {
+		return subtitles
+	}
+
+	grouped := make([]Subtitle, 0, (len(subtitles)+wordsPerCue-1)/wordsPerCue)
+	for i := 0; i < len(subtitles); {
+		end := i + 1
+		for end < len(subtitles) && end-i < wordsPerCue && speakers[end] == speakers[i] {
+			end++
+		}
+		grouped = append(grouped, newCue(subtitles[i:end]))
+		i = end
+	}
+	return grouped
+}
+
+func newCue(words []Subtitle) Subtitle {
+	if len(words) == 1 {
+		return words[0]
+	}
+
+	text := make([]string, len(words))
+	for i, word := range words {
+		text[i] = word.Word
+	}
+	return Subtitle{
+		Word:      strings.Join(text, " "),
+		StartTime: words[0].StartTime,
+		EndTime:   words[len(words)-1].EndTime,
+		Color:     words[0].Color,
+		Words:     words,
+	}
+}
+
+func enforceMinDuration(subtitles []Subtitle, minDuration float64) []Subtitle {
+	if minDuration <= 0 {
+		return subtitles
+	}
+
+	last := len(subtitles) - 1
+	for i := range subtitles {
+		sub := &subtitles[i]
+		need := minDuration - (sub.EndTime - sub.StartTime)
+		if need <= 0 {
+			continue
+		}
+
+		if i < last {
+			gap := max(subtitles[i+1].StartTime-sub.EndTime, 0)
+			take := min(need, gap)
+			sub.EndTime += take
+			need -= take
+		}
+
+		if need > 0 {
+			prevEnd := 0.0
+			if i > 0 {
+				prevEnd = subtitles[i-1].EndTime
+			}
+			gap := max(sub.StartTime-prevEnd, 0)
+			sub.StartTime -= min(need, gap)
+		}
 	}
 	return subtitles
 }
 
 func (g *SubtitleGenerator) Generate(text string, audioDuration float64) []Subtitle {
-	words := strings.Fields(text)
+	words := speech.SplitWords(text)
 	if len(words) == 0 {
 		return nil
 	}
@@ -128,9 +240,10 @@ func (g *SubtitleGenerator) Generate(text string, audioDuration float64) []Subti
 		endTime := startTime + timePerWord
 
 		subtitles = append(subtitles, Subtitle{
-			Word:      word,
-			StartTime: startTime,
-			EndTime:   endTime,
+			Word:          word,
+			StartTime:     startTime,
+			EndTime:       endTime,
+			EmphasisColor: g.emphasisColor(word),
 		})
 	}
 
@@ -143,8 +256,8 @@ func (g *SubtitleGenerator) ToASS(subtitles []Subtitle) string {
 	sb.WriteString("[Script Info]\n")
 	sb.WriteString("Title: Generated Subtitles\n")
 	sb.WriteString("ScriptType: v4.00+\n")
-	sb.WriteString("PlayResX: 1080\n")
-	sb.WriteString("PlayResY: 1920\n")
+	sb.WriteString(fmt.Sprintf("PlayResX: %d\n", playResX))
+	sb.WriteString(fmt.Sprintf("PlayResY: %d\n", playResY))
 	sb.WriteString("\n")
 
 	boldVal := 0
@@ -152,10 +265,15 @@ func (g *SubtitleGenerator) ToASS(subtitles []Subtitle) string {
 		boldVal = -1
 	}
 
+	borderStyle, outlineColor, backColor := 1, g.outlineColor, defaultBoxColor
+	if g.box {
+		borderStyle, outlineColor, backColor = 3, g.boxColor, g.boxColor
+	}
+
 	sb.WriteString("[V4+ Styles]\n")
 	sb.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
-	sb.WriteString(fmt.Sprintf("Style: Default,%s,%d,%s,%s,%s,&H80000000,%d,0,0,0,100,100,0,0,1,%d,%d,5,10,10,50,1\n",
-		g.fontName, g.fontSize, g.primaryColor, g.primaryColor, g.outlineColor, boldVal, g.outlineSize, g.shadowSize))
+	sb.WriteString(fmt.Sprintf("Style: Default,%s,%d,%s,%s,%s,%s,%d,0,0,0,100,100,0,0,%d,%d,%d,5,10,10,%d,1\n",
+		g.fontName, g.fontSize, g.primaryColor, g.secondaryColor(), outlineColor, backColor, boldVal, borderStyle, g.outlineSize, g.shadowSize, defaultMarginV+g.safeZone))
 	sb.WriteString("\n")
 
 	sb.WriteString("[Events]\n")
@@ -165,7 +283,7 @@ func (g *SubtitleGenerator) ToASS(subtitles []Subtitle) string {
 		start := formatASSTime(sub.StartTime)
 		end := formatASSTime(sub.EndTime)
 
-		text := g.buildAnimatedText(sub)
+		text := g.positionTag() + g.buildAnimatedText(sub)
 
 		sb.WriteString(fmt.Sprintf("Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", start, end, text))
 	}
@@ -173,15 +291,92 @@ func (g *SubtitleGenerator) ToASS(subtitles []Subtitle) string {
 	return sb.String()
 }
 
+func (g *SubtitleGenerator) positionTag() string {
+	if g.safeZone == 0 {
+		return ""
+	}
+	return fmt.Sprintf("{\\pos(%d,%d)}", playResX/2, (playResY-g.safeZone)/2)
+}
+
+func (g *SubtitleGenerator) secondaryColor() string {
+	if g.wordsPerCue <= 1 || len(g.primaryColor) != 10 {
+		return g.primaryColor
+	}
+	return "&H80" + g.primaryColor[4:]
+}
+
 func (g *SubtitleGenerator) buildAnimatedText(sub Subtitle) string {
 	popIn := "{\\fscx50\\fscy50\\t(0,80,\\fscx115\\fscy115)\\t(80,120,\\fscx100\\fscy100)}"
+	if len(sub.Words) > 0 {
+		return popIn + g.buildKaraokeText(sub)
+	}
+	return popIn + wrapTag(g.colorTags(sub)) + escapeASSText(sub.Word)
+}
+
+func (g *SubtitleGenerator) buildKaraokeText(cue Subtitle) string {
+	var sb strings.Builder
+	overridden := false
+	for i, word := range cue.Words {
+		next := cue.EndTime
+		if i < len(cue.Words)-1 {
+			next = cue.Words[i+1].StartTime
+		}
+		tags := fmt.Sprintf("\\k%d", int(math.Round(max(next-word.StartTime, 0)*100)))
+
+		if color := g.colorTags(word); color != "" {
+			tags += color
+			overridden = true
+		} else if overridden {
+			tags += fmt.Sprintf("\\c%s\\fs%d", g.primaryColor, g.fontSize)
+			overridden = false
+		}
 
-	colorTag := ""
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(wrapTag(tags))
+		sb.WriteString(escapeASSText(word.Word))
+	}
+	return sb.String()
+}
+
+func (g *SubtitleGenerator) colorTags(sub Subtitle) string {
+	if sub.EmphasisColor != "" {
+		return fmt.Sprintf("\\c%s\\fs%d", toASSColor(sub.EmphasisColor), int(float64(g.fontSize)*emphasisScale))
+	}
 	if sub.Color != "" {
-		colorTag = fmt.Sprintf("{\\c%s}", toASSColor(sub.Color))
+		return fmt.Sprintf("\\c%s", toASSColor(sub.Color))
 	}
+	return ""
+}
+
+func wrapTag(tags string) string {
+	if tags == "" {
+		return ""
+	}
+	return "{" + tags + "}"
+}
 
-	return fmt.Sprintf("%s%s%s", popIn, colorTag, sub.Word)
+func escapeASSText(text string) string {
+	return assTextEscaper.Replace(text)
+}
+
+func (g *SubtitleGenerator) emphasisColor(word string) string {
+	if len(g.emphasis) == 0 {
+		return ""
+	}
+	return g.emphasis[normalizeSubtitleWord(word)]
+}
+
+var assTextEscaper = strings.NewReplacer(
+	"\\", "\u29f5",
+	"{", "\\{",
+	"}", "\\}",
+	"\n", " ",
+)
+
+func normalizeSubtitleWord(word string) string {
+	return strings.ToLower(strings.Trim(word, ".,!?;:'\"()[]{}"))
 }
 
 func formatASSTime(seconds float64) string {
@@ -192,3 +387,92 @@ func formatASSTime(seconds float64) string {
 
 	return fmt.Sprintf("%d:%02d:%02d.%02d", hours, minutes, secs, centis)
 }
+
+type captionCue struct {
+	start float64
+	end   float64
+	text  string
+}
+
+func (g *SubtitleGenerator) ToSRT(subtitles []Subtitle) string {
+	var sb strings.Builder
+	for i, cue := range groupCues(subtitles) {
+		sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", i+1, formatSRTTime(cue.start), formatSRTTime(cue.end), cue.text))
+	}
+	return sb.String()
+}
+
+func (g *SubtitleGenerator) ToVTT(subtitles []Subtitle) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n\n")
+	for _, cue := range groupCues(subtitles) {
+		sb.WriteString(fmt.Sprintf("%s --> %s\n%s\n\n", formatVTTTime(cue.start), formatVTTTime(cue.end), cue.text))
+	}
+	return sb.String()
+}
+
+func groupCues(subtitles []Subtitle) []captionCue {
+	var cues []captionCue
+	var words []string
+	var start, end float64
+
+	flush := func() {
+		if len(words) == 0 {
+			return
+		}
+		cues = append(cues, captionCue{start: start, end: end, text: strings.Join(words, " ")})
+		words = nil
+	}
+
+	for _, sub := range flattenWords(subtitles) {
+		if len(words) > 0 && sub.EndTime-start > captionMaxDuration {
+			flush()
+		}
+		if len(words) == 0 {
+			start = sub.StartTime
+		}
+		words = append(words, sub.Word)
+		end = sub.EndTime
+
+		if len(words) >= captionMaxWords || endsSentence(sub.Word) {
+			flush()
+		}
+	}
+	flush()
+
+	return cues
+}
+
+func flattenWords(subtitles []Subtitle) []Subtitle {
+	flat := make([]Subtitle, 0, len(subtitles))
+	for _, sub := range subtitles {
+		if len(sub.Words) > 0 {
+			flat = append(flat, sub.Words...)
+		} else {
+			flat = append(flat, sub)
+		}
+	}
+	return flat
+}
+
+func endsSentence(word string) bool {
+	return strings.HasSuffix(word, ".") || strings.HasSuffix(word, "!") || strings.HasSuffix(word, "?")
+}
+
+func formatSRTTime(seconds float64) string {
+	return formatCaptionTime(seconds, ",")
+}
+
+func formatVTTTime(seconds float64) string {
+	return formatCaptionTime(seconds, ".")
+}
+
+func formatCaptionTime(seconds float64, separator string) string {
+	totalMillis := int(math.Round(seconds * 1000))
+	hours := totalMillis / 3600000
+	minutes := (totalMillis % 3600000) / 60000
+	secs := (totalMillis % 60000) / 1000
+	millis := totalMillis % 1000
+
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, secs, separator, millis)
+}
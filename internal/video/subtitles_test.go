@@ -1,6 +1,7 @@
 package video
 
 import (
+	"math"
 	"strings"
 	"testing"
 
@@ -38,6 +39,12 @@ func TestGenerate(t *testing.T) {
 			audioDuration: 1.0,
 			wantCount:     1,
 		},
+		{
+			name:          "detachedPunctuation",
+			text:          "Wait ... it costs 3.50 - don't ask",
+			audioDuration: 3.0,
+			wantCount:     6,
+		},
 		{
 			name:          "multipleSpaces",
 			text:          "Hello    world",
@@ -140,6 +147,52 @@ func TestToASS(t *testing.T) {
 	}
 }
 
+func TestToASSNonLatin(t *testing.T) {
+	gen := NewSubtitleGenerator(SubtitleOptions{FontName: "Noto Sans", FontSize: 72})
+
+	tests := []struct {
+		name  string
+		text  string
+		words []string
+	}{
+		{name: "cyrillic", text: "Привет мир", words: []string{"Привет", "мир"}},
+		{name: "arabic", text: "مرحبا بالعالم", words: []string{"مرحبا", "بالعالم"}},
+		{name: "lithuanian", text: "Ačiū žmonės", words: []string{"Ačiū", "žmonės"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subs := gen.Generate(tt.text, 2.0)
+			if len(subs) != len(tt.words) {
+				t.Fatalf("Generate() returned %d subtitles, want %d", len(subs), len(tt.words))
+			}
+
+			ass := gen.ToASS(subs)
+			for _, word := range tt.words {
+				if !strings.Contains(ass, word) {
+					t.Errorf("ASS output missing %q", word)
+				}
+			}
+		})
+	}
+}
+
+func TestToASSEscapesText(t *testing.T) {
+	gen := NewSubtitleGenerator(SubtitleOptions{FontName: "Arial", FontSize: 48})
+
+	ass := gen.ToASS([]Subtitle{{Word: "{\\b1}bold\\N", StartTime: 0, EndTime: 1}})
+
+	if strings.Contains(ass, "{\\b1}") {
+		t.Error("ASS output contains unescaped override block from subtitle text")
+	}
+	if strings.Contains(ass, "bold\\N") {
+		t.Error("ASS output contains unescaped line break from subtitle text")
+	}
+	if !strings.Contains(ass, "\\{") {
+		t.Error("ASS output missing escaped brace")
+	}
+}
+
 func TestToASSEmpty(t *testing.T) {
 	gen := NewSubtitleGenerator(SubtitleOptions{FontName: "Arial", FontSize: 48})
 
@@ -176,6 +229,108 @@ func TestFormatASSTime(t *testing.T) {
 	}
 }
 
+func TestFormatCaptionTime(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		wantSRT string
+		wantVTT string
+	}{
+		{0.0, "00:00:00,000", "00:00:00.000"},
+		{1.5, "00:00:01,500", "00:00:01.500"},
+		{90.25, "00:01:30,250", "00:01:30.250"},
+		{3661.999, "01:01:01,999", "01:01:01.999"},
+		{59.9996, "00:01:00,000", "00:01:00.000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantSRT, func(t *testing.T) {
+			if got := formatSRTTime(tt.seconds); got != tt.wantSRT {
+				t.Errorf("formatSRTTime(%v) = %q, want %q", tt.seconds, got, tt.wantSRT)
+			}
+			if got := formatVTTTime(tt.seconds); got != tt.wantVTT {
+				t.Errorf("formatVTTTime(%v) = %q, want %q", tt.seconds, got, tt.wantVTT)
+			}
+		})
+	}
+}
+
+func TestToSRT(t *testing.T) {
+	gen := NewSubtitleGenerator(SubtitleOptions{FontName: "Arial", FontSize: 48})
+	subs := []Subtitle{
+		{Word: "Hello", StartTime: 0.0, EndTime: 0.4},
+		{Word: "world.", StartTime: 0.4, EndTime: 0.9},
+		{Word: "Next", StartTime: 1.0, EndTime: 1.3},
+		{Word: "cue", StartTime: 1.3, EndTime: 1.6},
+	}
+
+	want := "1\n00:00:00,000 --> 00:00:00,900\nHello world.\n\n" +
+		"2\n00:00:01,000 --> 00:00:01,600\nNext cue\n\n"
+	if got := gen.ToSRT(subs); got != want {
+		t.Errorf("ToSRT() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestToVTT(t *testing.T) {
+	gen := NewSubtitleGenerator(SubtitleOptions{FontName: "Arial", FontSize: 48})
+	subs := []Subtitle{
+		{Word: "Hello", StartTime: 0.0, EndTime: 0.4},
+		{Word: "world", StartTime: 0.4, EndTime: 0.9},
+	}
+
+	want := "WEBVTT\n\n00:00:00.000 --> 00:00:00.900\nHello world\n\n"
+	if got := gen.ToVTT(subs); got != want {
+		t.Errorf("ToVTT() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGroupCues(t *testing.T) {
+	tests := []struct {
+		name      string
+		subs      []Subtitle
+		wantCues  int
+		wantFirst string
+	}{
+		{
+			name:     "empty",
+			subs:     nil,
+			wantCues: 0,
+		},
+		{
+			name: "splitsAtMaxWords",
+			subs: func() []Subtitle {
+				subs := make([]Subtitle, 10)
+				for i := range subs {
+					subs[i] = Subtitle{Word: "w", StartTime: float64(i) * 0.1, EndTime: float64(i+1) * 0.1}
+				}
+				return subs
+			}(),
+			wantCues:  2,
+			wantFirst: "w w w w w w w",
+		},
+		{
+			name: "splitsAtMaxDuration",
+			subs: []Subtitle{
+				{Word: "slow", StartTime: 0, EndTime: 2},
+				{Word: "speech", StartTime: 2, EndTime: 4},
+			},
+			wantCues:  2,
+			wantFirst: "slow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cues := groupCues(tt.subs)
+			if len(cues) != tt.wantCues {
+				t.Fatalf("groupCues() returned %d cues, want %d", len(cues), tt.wantCues)
+			}
+			if tt.wantCues > 0 && cues[0].text != tt.wantFirst {
+				t.Errorf("first cue = %q, want %q", cues[0].text, tt.wantFirst)
+			}
+		})
+	}
+}
+
 func TestSubtitleWords(t *testing.T) {
 	gen := NewSubtitleGenerator(SubtitleOptions{FontName: "Arial", FontSize: 48})
 
@@ -226,6 +381,208 @@ func TestGenerateFromTimings(t *testing.T) {
 	}
 }
 
+func TestGenerateFromTimingsMinWordDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		timings []speech.WordTiming
+		want    [][2]float64
+	}{
+		{
+			name: "extendsIntoFollowingGap",
+			timings: []speech.WordTiming{
+				{Word: "I", StartTime: 0.0, EndTime: 0.05},
+				{Word: "think", StartTime: 0.4, EndTime: 0.8},
+			},
+			want: [][2]float64{{0.0, 0.2}, {0.4, 0.8}},
+		},
+		{
+			name: "borrowsPrecedingGapWhenFollowingIsTight",
+			timings: []speech.WordTiming{
+				{Word: "so", StartTime: 0.0, EndTime: 0.3},
+				{Word: "a", StartTime: 0.5, EndTime: 0.55},
+				{Word: "cat", StartTime: 0.6, EndTime: 1.0},
+			},
+			want: [][2]float64{{0.0, 0.3}, {0.4, 0.6}, {0.6, 1.0}},
+		},
+		{
+			name: "noGapsLeavesWordShort",
+			timings: []speech.WordTiming{
+				{Word: "so", StartTime: 0.0, EndTime: 0.3},
+				{Word: "a", StartTime: 0.3, EndTime: 0.35},
+				{Word: "cat", StartTime: 0.35, EndTime: 0.8},
+			},
+			want: [][2]float64{{0.0, 0.3}, {0.3, 0.35}, {0.35, 0.8}},
+		},
+		{
+			name: "lastWordKeepsAudioEnd",
+			timings: []speech.WordTiming{
+				{Word: "hello", StartTime: 0.0, EndTime: 0.5},
+				{Word: "I", StartTime: 0.9, EndTime: 0.95},
+			},
+			want: [][2]float64{{0.0, 0.5}, {0.75, 0.95}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewSubtitleGenerator(SubtitleOptions{MinWordDuration: 0.2})
+			subs := gen.GenerateFromTimings(tt.timings)
+
+			for i, sub := range subs {
+				if math.Abs(sub.StartTime-tt.want[i][0]) > 1e-9 || math.Abs(sub.EndTime-tt.want[i][1]) > 1e-9 {
+					t.Errorf("subs[%d] %q = [%v, %v], want %v", i, sub.Word, sub.StartTime, sub.EndTime, tt.want[i])
+				}
+				if sub.EndTime < sub.StartTime {
+					t.Errorf("subs[%d] ends before it starts", i)
+				}
+				if i > 0 && sub.StartTime < subs[i-1].EndTime {
+					t.Errorf("subs[%d] overlaps previous word", i)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateFromTimingsWordsPerCue(t *testing.T) {
+	tests := []struct {
+		name    string
+		timings []speech.WordTiming
+		want    []string
+	}{
+		{
+			name: "groupsConsecutiveWords",
+			timings: []speech.WordTiming{
+				{Word: "one", StartTime: 0.0, EndTime: 0.2},
+				{Word: "two", StartTime: 0.2, EndTime: 0.4},
+				{Word: "three", StartTime: 0.4, EndTime: 0.6},
+				{Word: "four", StartTime: 0.6, EndTime: 0.8},
+			},
+			want: []string{"one two three", "four"},
+		},
+		{
+			name: "splitsAtSpeakerBoundary",
+			timings: []speech.WordTiming{
+				{Word: "hey", StartTime: 0.0, EndTime: 0.2, Speaker: "Adam"},
+				{Word: "there", StartTime: 0.2, EndTime: 0.4, Speaker: "Adam"},
+				{Word: "hi", StartTime: 0.5, EndTime: 0.7, Speaker: "Bella"},
+				{Word: "back", StartTime: 0.7, EndTime: 0.9, Speaker: "Bella"},
+			},
+			want: []string{"hey there", "hi back"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewSubtitleGenerator(SubtitleOptions{WordsPerCue: 3})
+			subs := gen.GenerateFromTimings(tt.timings)
+
+			if len(subs) != len(tt.want) {
+				t.Fatalf("len(subs) = %d, want %d", len(subs), len(tt.want))
+			}
+			for i, sub := range subs {
+				if sub.Word != tt.want[i] {
+					t.Errorf("subs[%d].Word = %q, want %q", i, sub.Word, tt.want[i])
+				}
+				if len(sub.Words) > 1 {
+					first, last := sub.Words[0], sub.Words[len(sub.Words)-1]
+					if sub.StartTime != first.StartTime || sub.EndTime != last.EndTime {
+						t.Errorf("subs[%d] = [%v, %v], want [%v, %v]", i, sub.StartTime, sub.EndTime, first.StartTime, last.EndTime)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateFromTimingsSingleWordPerCue(t *testing.T) {
+	timings := []speech.WordTiming{
+		{Word: "one", StartTime: 0.0, EndTime: 0.2},
+		{Word: "two", StartTime: 0.2, EndTime: 0.4},
+	}
+
+	subs := NewSubtitleGenerator(SubtitleOptions{}).GenerateFromTimings(timings)
+	if len(subs) != 2 {
+		t.Fatalf("len(subs) = %d, want 2", len(subs))
+	}
+	for i, sub := range subs {
+		if sub.Words != nil {
+			t.Errorf("subs[%d].Words = %v, want nil", i, sub.Words)
+		}
+	}
+}
+
+func TestToASSKaraokeCue(t *testing.T) {
+	gen := NewSubtitleGenerator(SubtitleOptions{FontSize: 48, WordsPerCue: 3})
+	subs := gen.GenerateFromTimings([]speech.WordTiming{
+		{Word: "one", StartTime: 0.0, EndTime: 0.2},
+		{Word: "two", StartTime: 0.3, EndTime: 0.5},
+		{Word: "three", StartTime: 0.5, EndTime: 0.9},
+	})
+
+	ass := gen.ToASS(subs)
+	if got := strings.Count(ass, "Dialogue:"); got != 1 {
+		t.Fatalf("Dialogue count = %d, want 1", got)
+	}
+	if !strings.Contains(ass, "Dialogue: 0,0:00:00.00,0:00:00.90,") {
+		t.Error("cue should span the combined word timings")
+	}
+	if !strings.Contains(ass, "{\\k30}one {\\k20}two {\\k40}three") {
+		t.Errorf("missing karaoke timings in %q", ass)
+	}
+	if !strings.Contains(ass, ",&H00FFFFFF,&H80FFFFFF,") {
+		t.Error("upcoming words should use a dimmed secondary colour")
+	}
+}
+
+func TestSRTFlattensGroupedCues(t *testing.T) {
+	gen := NewSubtitleGenerator(SubtitleOptions{WordsPerCue: 2})
+	timings := make([]speech.WordTiming, 9)
+	for i := range timings {
+		timings[i] = speech.WordTiming{Word: "w", StartTime: float64(i) * 0.1, EndTime: float64(i+1) * 0.1}
+	}
+
+	cues := groupCues(gen.GenerateFromTimings(timings))
+	if len(cues) != 2 {
+		t.Fatalf("len(cues) = %d, want 2", len(cues))
+	}
+	if got := len(strings.Fields(cues[0].text)); got != captionMaxWords {
+		t.Errorf("first cue words = %d, want %d", got, captionMaxWords)
+	}
+}
+
+func TestToASSBackgroundBox(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SubtitleOptions
+		want string
+	}{
+		{
+			name: "disabled",
+			opts: SubtitleOptions{OutlineSize: 6, ShadowSize: 2},
+			want: ",&H00000000,&H80000000,0,0,0,0,100,100,0,0,1,6,2,",
+		},
+		{
+			name: "defaultBoxColor",
+			opts: SubtitleOptions{OutlineSize: 6, ShadowSize: 2, BackgroundBox: true},
+			want: ",&H80000000,&H80000000,0,0,0,0,100,100,0,0,3,6,2,",
+		},
+		{
+			name: "customBoxColor",
+			opts: SubtitleOptions{OutlineSize: 6, ShadowSize: 0, BackgroundBox: true, BoxColor: "#102030"},
+			want: ",&H00302010,&H00302010,0,0,0,0,100,100,0,0,3,6,0,",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ass := NewSubtitleGenerator(tt.opts).ToASS(nil)
+			if !strings.Contains(ass, tt.want) {
+				t.Errorf("style line missing %q in:\n%s", tt.want, ass)
+			}
+		})
+	}
+}
+
 func TestToASSCenterAligned(t *testing.T) {
 	gen := NewSubtitleGenerator(SubtitleOptions{FontName: "Arial", FontSize: 48})
 
@@ -240,6 +597,36 @@ func TestToASSCenterAligned(t *testing.T) {
 	}
 }
 
+func TestToASSSafeZone(t *testing.T) {
+	tests := []struct {
+		name        string
+		safeZone    int
+		wantMarginV string
+		wantPos     string
+	}{
+		{name: "noSafeZone", safeZone: 0, wantMarginV: ",5,10,10,50,1"},
+		{name: "reservedBottomBand", safeZone: 300, wantMarginV: ",5,10,10,350,1", wantPos: "{\\pos(540,810)}"},
+		{name: "negativeIgnored", safeZone: -100, wantMarginV: ",5,10,10,50,1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewSubtitleGenerator(SubtitleOptions{FontName: "Arial", FontSize: 48, SafeZoneBottom: tt.safeZone})
+			ass := gen.ToASS([]Subtitle{{Word: "Test", StartTime: 0.0, EndTime: 1.0}})
+
+			if !strings.Contains(ass, tt.wantMarginV) {
+				t.Errorf("style line missing margin %q\ngot: %s", tt.wantMarginV, ass)
+			}
+			if got := strings.Contains(ass, "\\pos("); got != (tt.wantPos != "") {
+				t.Errorf("pos tag present = %v, want %v", got, tt.wantPos != "")
+			}
+			if tt.wantPos != "" && !strings.Contains(ass, tt.wantPos) {
+				t.Errorf("dialogue missing %q\ngot: %s", tt.wantPos, ass)
+			}
+		})
+	}
+}
+
 func TestGenerateFromTimingsWithOffset(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -592,3 +979,61 @@ func TestBuildSpeakerColors(t *testing.T) {
 		t.Errorf("Bella color = %q, want #FF69B4", speakerColors["Bella"])
 	}
 }
+
+func TestGenerateFromTimingsWithEmphasis(t *testing.T) {
+	gen := NewSubtitleGenerator(SubtitleOptions{
+		FontName:      "Arial",
+		FontSize:      100,
+		EmphasisWords: map[string]string{"Money": "#FFD700", "crazy": "#FF0000"},
+	})
+
+	timings := []speech.WordTiming{
+		{Word: "The", StartTime: 0.0, EndTime: 0.2},
+		{Word: "MONEY", StartTime: 0.2, EndTime: 0.5},
+		{Word: "was", StartTime: 0.5, EndTime: 0.7},
+		{Word: "crazy!", StartTime: 0.7, EndTime: 1.0},
+		{Word: "crazyness", StartTime: 1.0, EndTime: 1.3},
+	}
+
+	subs := gen.GenerateFromTimings(timings)
+	want := []string{"", "#FFD700", "", "#FF0000", ""}
+	for i, sub := range subs {
+		if sub.EmphasisColor != want[i] {
+			t.Errorf("subs[%d] (%q) EmphasisColor = %q, want %q", i, sub.Word, sub.EmphasisColor, want[i])
+		}
+	}
+
+	ass := gen.ToASS(subs)
+	if !strings.Contains(ass, "{\\c&H0000D7FF\\fs130}MONEY") {
+		t.Error("expected emphasized word to have color and size override")
+	}
+	if !strings.Contains(ass, "{\\c&H000000FF\\fs130}crazy!") {
+		t.Error("expected punctuated word to have emphasis override")
+	}
+	for _, plain := range []string{"The", "was", "crazyness"} {
+		line := "\\fscy100)}" + plain + "\n"
+		if !strings.Contains(ass, line) {
+			t.Errorf("expected %q to keep the default style", plain)
+		}
+	}
+}
+
+func TestEmphasisOverridesSpeakerColor(t *testing.T) {
+	gen := NewSubtitleGenerator(SubtitleOptions{
+		FontName:      "Arial",
+		FontSize:      100,
+		EmphasisWords: map[string]string{"wow": "#00FF00"},
+	})
+
+	subs := gen.GenerateFromTimingsWithColors([]speech.WordTiming{
+		{Word: "Wow", StartTime: 0, EndTime: 0.5, Speaker: "Adam"},
+	}, map[string]string{"Adam": "#FF0000"})
+
+	text := gen.buildAnimatedText(subs[0])
+	if !strings.Contains(text, "\\c&H0000FF00\\fs130") {
+		t.Errorf("expected emphasis color to win over speaker color, got %q", text)
+	}
+	if strings.Contains(text, "&H000000FF") {
+		t.Errorf("speaker color should not be applied to emphasized word, got %q", text)
+	}
+}
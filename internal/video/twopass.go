@@ -0,0 +1,52 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+func (a *Assembler) usesTwoPass(overlays []ImageOverlay) bool {
+	if !a.twoPass || a.software.bitrateKbps <= 0 {
+		return false
+	}
+	return a.hasOverlayInputs(overlays) || a.selectEncoder().name == softwareEncoder.name
+}
+
+func (a *Assembler) runTwoPass(ctx context.Context, args []string, duration float64) error {
+	outputPath := args[len(args)-1]
+	logPrefix := filepath.Join(filepath.Dir(outputPath), fmt.Sprintf("pass_%d", time.Now().UnixNano()))
+	defer removePassLogs(logPrefix)
+
+	first, second := twoPassArgs(args, logPrefix)
+
+	a.log("running first pass", "log", logPrefix)
+	if err := a.runFFmpeg(ctx, first); err != nil {
+		return fmt.Errorf("first pass: %w", err)
+	}
+
+	a.log("running second pass", "output", outputPath)
+	if err := a.runFFmpegWithProgress(ctx, second, duration); err != nil {
+		return fmt.Errorf("second pass: %w", err)
+	}
+	return nil
+}
+
+func twoPassArgs(args []string, logPrefix string) ([]string, []string) {
+	base := args[:len(args)-1]
+	output := args[len(args)-1]
+
+	first := append(slices.Clone(base), "-pass", "1", "-passlogfile", logPrefix, "-f", "null", os.DevNull)
+	second := append(slices.Clone(base), "-pass", "2", "-passlogfile", logPrefix, output)
+	return first, second
+}
+
+func removePassLogs(logPrefix string) {
+	matches, _ := filepath.Glob(logPrefix + "*")
+	for _, path := range matches {
+		_ = os.Remove(path)
+	}
+}
@@ -0,0 +1,94 @@
+package video
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunTwoPass(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls.txt")
+	fakeFFmpeg := filepath.Join(dir, "ffmpeg")
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" >> " + calls + "\n" +
+		"for arg in \"$@\"; do\n" +
+		"  if [ \"$prev\" = \"-passlogfile\" ]; then touch \"$arg-0.log\" \"$arg-0.log.mbtree\"; fi\n" +
+		"  prev=$arg\n" +
+		"done\n"
+	if err := os.WriteFile(fakeFFmpeg, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	assembler := NewAssemblerWithOptions(AssemblerOptions{Encoder: "libx264", BitrateKbps: 6000, TwoPass: true})
+	assembler.ffmpeg = fakeFFmpeg
+
+	outputPath := filepath.Join(dir, "main.mp4")
+	args := assembler.buildFFmpegArgs("bg.mp4", "audio.mp3", "", 0, false, 10, "[v]", nil, outputPath)
+	if err := assembler.runTwoPass(context.Background(), args, 11.5); err != nil {
+		t.Fatalf("runTwoPass() error = %v", err)
+	}
+
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passes := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(passes) != 2 {
+		t.Fatalf("ffmpeg invoked %d times, want 2", len(passes))
+	}
+
+	if !strings.Contains(passes[0], "-pass 1") || !strings.Contains(passes[0], "-f null "+os.DevNull) {
+		t.Errorf("first pass args = %s, want -pass 1 writing to null", passes[0])
+	}
+	if strings.Contains(passes[0], outputPath) {
+		t.Errorf("first pass should not write the output file: %s", passes[0])
+	}
+	if !strings.Contains(passes[1], "-pass 2") || !strings.HasSuffix(passes[1], outputPath) {
+		t.Errorf("second pass args = %s, want -pass 2 writing %s", passes[1], outputPath)
+	}
+	for _, pass := range passes {
+		if !strings.Contains(pass, "-b:v 6000k") || strings.Contains(pass, "-crf") {
+			t.Errorf("pass args should use target bitrate without CRF: %s", pass)
+		}
+	}
+
+	logs, _ := filepath.Glob(filepath.Join(dir, "pass_*"))
+	if len(logs) != 0 {
+		t.Errorf("pass logs not cleaned up: %v", logs)
+	}
+}
+
+func TestUsesTwoPass(t *testing.T) {
+	original := detectEncoder
+	t.Cleanup(func() { detectEncoder = original })
+	detectEncoder = func() encoder { return encoders[0] }
+
+	tests := []struct {
+		name     string
+		opts     AssemblerOptions
+		overlays []ImageOverlay
+		want     bool
+	}{
+		{name: "softwareWithBitrate", opts: AssemblerOptions{Encoder: "libx264", BitrateKbps: 6000, TwoPass: true}, want: true},
+		{name: "disabled", opts: AssemblerOptions{Encoder: "libx264", BitrateKbps: 6000}},
+		{name: "noBitrate", opts: AssemblerOptions{Encoder: "libx264", TwoPass: true}},
+		{name: "hardwareEncoder", opts: AssemblerOptions{Encoder: "auto", BitrateKbps: 6000, TwoPass: true}},
+		{
+			name:     "overlaysForceSoftware",
+			opts:     AssemblerOptions{Encoder: "auto", BitrateKbps: 6000, TwoPass: true},
+			overlays: []ImageOverlay{{ImagePath: "image.jpg"}},
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewAssemblerWithOptions(tt.opts).usesTwoPass(tt.overlays); got != tt.want {
+				t.Errorf("usesTwoPass() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package video
+
+import "fmt"
+
+const (
+	WatermarkTopLeft     = "top_left"
+	WatermarkTopRight    = "top_right"
+	WatermarkBottomLeft  = "bottom_left"
+	WatermarkBottomRight = "bottom_right"
+	WatermarkCustom      = "custom"
+
+	WatermarkBelowSubtitles = "below_subtitles"
+	WatermarkAboveSubtitles = "above_subtitles"
+
+	watermarkMargin  = 40
+	watermarkOpacity = 0.8
+	watermarkScale   = 0.15
+)
+
+type Watermark struct {
+	Path     string
+	Position string
+	X        int
+	Y        int
+	Opacity  float64
+	Scale    float64
+	Layer    string
+}
+
+func (a *Assembler) hasOverlayInputs(overlays []ImageOverlay) bool {
+	return len(overlays) > 0 || a.watermark.Path != ""
+}
+
+func (a *Assembler) watermarkBelowSubtitles() bool {
+	return a.watermark.Path != "" && a.watermark.Layer != WatermarkAboveSubtitles
+}
+
+func (a *Assembler) watermarkFilters(inputIdx int, lastOut string) []string {
+	wm := a.watermark
+	width := int(float64(a.width) * orDefault(wm.Scale, watermarkScale))
+	opacity := min(orDefault(wm.Opacity, watermarkOpacity), 1)
+
+	return []string{
+		fmt.Sprintf("[%d:v]scale=%d:-1,format=rgba,colorchannelmixer=aa=%.2f[wm]", inputIdx, width, opacity),
+		fmt.Sprintf("[%s][wm]overlay=%s[wmv]", lastOut, a.watermarkPosition()),
+	}
+}
+
+func (a *Assembler) watermarkPosition() string {
+	bottom := fmt.Sprintf("H-h-%d", watermarkMargin+a.safeZone)
+	switch a.watermark.Position {
+	case WatermarkTopLeft:
+		return fmt.Sprintf("%d:%d", watermarkMargin, watermarkMargin)
+	case WatermarkBottomLeft:
+		return fmt.Sprintf("%d:%s", watermarkMargin, bottom)
+	case WatermarkBottomRight:
+		return fmt.Sprintf("W-w-%d:%s", watermarkMargin, bottom)
+	case WatermarkCustom:
+		return fmt.Sprintf("%d:%d", a.watermark.X, a.watermark.Y)
+	default:
+		return fmt.Sprintf("W-w-%d:%d", watermarkMargin, watermarkMargin)
+	}
+}
@@ -0,0 +1,84 @@
+package video
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildFilterComplexWatermark(t *testing.T) {
+	overlays := []ImageOverlay{{ImagePath: "/tmp/img.png", StartTime: 1, EndTime: 3, Width: 800, Height: 1600}}
+
+	tests := []struct {
+		name      string
+		watermark Watermark
+		overlays  []ImageOverlay
+		want      []string
+		wantOrder []string
+	}{
+		{
+			name:      "defaultCorner",
+			watermark: Watermark{Path: "/tmp/logo.png"},
+			want: []string{
+				"[0:v]scale=1080:1920:force_original_aspect_ratio=increase,crop=1080:1920[base]",
+				"[2:v]scale=162:-1,format=rgba,colorchannelmixer=aa=0.80[wm]",
+				"[base][wm]overlay=W-w-40:40[wmv]",
+				"[wmv]ass=/tmp/subs.ass[v]",
+			},
+		},
+		{
+			name:      "bottomLeftAboveImages",
+			watermark: Watermark{Path: "/tmp/logo.png", Position: WatermarkBottomLeft, Opacity: 0.5, Scale: 0.2},
+			overlays:  overlays,
+			want: []string{
+				"[3:v]scale=216:-1,format=rgba,colorchannelmixer=aa=0.50[wm]",
+				"[v0][wm]overlay=40:H-h-40[wmv]",
+			},
+			wantOrder: []string{"[img0]overlay", "[wm]overlay", "ass="},
+		},
+		{
+			name:      "customAboveSubtitles",
+			watermark: Watermark{Path: "/tmp/logo.png", Position: WatermarkCustom, X: 12, Y: 34, Layer: WatermarkAboveSubtitles},
+			want: []string{
+				"[0:v]scale=1080:1920:force_original_aspect_ratio=increase,crop=1080:1920,ass=/tmp/subs.ass[base]",
+				"[base][wm]overlay=12:34[wmv]",
+				"[wmv]null[v]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assembler := NewAssemblerWithOptions(AssemblerOptions{Resolution: "1080x1920", Watermark: tt.watermark})
+			result := assembler.buildFilterComplex("/tmp/subs.ass", "", tt.overlays, "", 30.0)
+
+			for _, want := range tt.want {
+				if !strings.Contains(result, want) {
+					t.Errorf("missing %q\ngot: %s", want, result)
+				}
+			}
+			if strings.Contains(result, "[wm]overlay=") && strings.Contains(result[strings.Index(result, "[wm]overlay="):], "enable=") {
+				t.Errorf("watermark must not be time-gated\ngot: %s", result)
+			}
+			last := -1
+			for _, part := range tt.wantOrder {
+				idx := strings.Index(result, part)
+				if idx <= last {
+					t.Errorf("%q out of order\ngot: %s", part, result)
+				}
+				last = idx
+			}
+		})
+	}
+}
+
+func TestBuildFFmpegArgsWatermark(t *testing.T) {
+	assembler := NewAssemblerWithOptions(AssemblerOptions{Watermark: Watermark{Path: "/tmp/logo.png"}})
+	args := strings.Join(assembler.buildFFmpegArgs("bg.mp4", "audio.mp3", "", 0, false, 30, "filter", nil, "out.mp4"), " ")
+
+	if !strings.Contains(args, "-loop 1 -t 31.50 -i /tmp/logo.png") {
+		t.Errorf("watermark input missing\ngot: %s", args)
+	}
+	if !strings.Contains(args, "libx264") {
+		t.Errorf("watermark requires software encoding\ngot: %s", args)
+	}
+}
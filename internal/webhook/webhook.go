@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"craftstory/pkg/httputil"
+)
+
+const (
+	defaultTimeout  = 10 * time.Second
+	signatureHeader = "X-Craftstory-Signature"
+
+	EventGeneration = "generation"
+	EventUpload     = "upload"
+	EventPreview    = "preview"
+)
+
+type Client struct {
+	url        string
+	secret     string
+	httpClient *httputil.RetryClient
+}
+
+type Config struct {
+	URL       string
+	Secret    string
+	Transport http.RoundTripper
+}
+
+type Event struct {
+	Event         string    `json:"event"`
+	Success       bool      `json:"success"`
+	Error         string    `json:"error,omitempty"`
+	Topic         string    `json:"topic,omitempty"`
+	Title         string    `json:"title,omitempty"`
+	VideoPath     string    `json:"video_path,omitempty"`
+	PreviewPath   string    `json:"preview_path,omitempty"`
+	ThumbnailPath string    `json:"thumbnail_path,omitempty"`
+	Duration      float64   `json:"duration,omitempty"`
+	Platform      string    `json:"platform,omitempty"`
+	URL           string    `json:"url,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.URL == "" {
+		return nil
+	}
+
+	return &Client{
+		url:    cfg.URL,
+		secret: cfg.Secret,
+		httpClient: httputil.NewRetryClient(&http.Client{
+			Timeout:   defaultTimeout,
+			Transport: cfg.Transport,
+		}, httputil.DefaultRetryConfig()),
+	}
+}
+
+func (c *Client) Send(ctx context.Context, event Event) error {
+	if c == nil {
+		return nil
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.secret != "" {
+		req.Header.Set(signatureHeader, "sha256="+Sign(body, c.secret))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook error: %s", resp.Status)
+	}
+	return nil
+}
+
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"craftstory/pkg/httputil"
+)
+
+func newTestClient(server *httptest.Server, secret string) *Client {
+	return &Client{
+		url:    server.URL,
+		secret: secret,
+		httpClient: httputil.NewRetryClient(server.Client(), httputil.RetryConfig{
+			MaxRetries:   1,
+			InitialDelay: time.Millisecond,
+			MaxDelay:     time.Millisecond,
+		}),
+	}
+}
+
+func TestSend(t *testing.T) {
+	tests := []struct {
+		name          string
+		secret        string
+		wantSignature bool
+	}{
+		{name: "signed", secret: "s3cret", wantSignature: true},
+		{name: "unsigned"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				body      []byte
+				signature string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("expected POST, got %s", r.Method)
+				}
+				if r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+				}
+				body, _ = io.ReadAll(r.Body)
+				signature = r.Header.Get(signatureHeader)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			err := newTestClient(server, tt.secret).Send(context.Background(), Event{
+				Event:     EventUpload,
+				Success:   true,
+				Title:     "Why cats purr",
+				VideoPath: "/out/video.mp4",
+				Duration:  42.5,
+				Platform:  "youtube",
+				URL:       "https://youtu.be/abc",
+			})
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			var got Event
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("invalid payload: %v", err)
+			}
+			if got.Title != "Why cats purr" || got.URL != "https://youtu.be/abc" || got.Duration != 42.5 || !got.Success {
+				t.Errorf("payload = %+v", got)
+			}
+			if got.Timestamp.IsZero() {
+				t.Error("expected timestamp to be set")
+			}
+
+			if !tt.wantSignature {
+				if signature != "" {
+					t.Errorf("unexpected signature %q", signature)
+				}
+				return
+			}
+			if want := "sha256=" + Sign(body, tt.secret); signature != want {
+				t.Errorf("signature = %q, want %q", signature, want)
+			}
+		})
+	}
+}
+
+func TestSendServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := newTestClient(server, "").Send(context.Background(), Event{Event: EventGeneration}); err == nil {
+		t.Error("expected error for server failure")
+	}
+}
+
+func TestNilClient(t *testing.T) {
+	client := NewClient(Config{})
+	if client != nil {
+		t.Fatal("expected nil client without url")
+	}
+	if err := client.Send(context.Background(), Event{Event: EventGeneration}); err != nil {
+		t.Errorf("Send() on nil client = %v, want nil", err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	got := Sign([]byte(`{"event":"upload"}`), "key")
+	if len(got) != 64 {
+		t.Fatalf("Sign() length = %d, want 64 hex chars", len(got))
+	}
+	if got != Sign([]byte(`{"event":"upload"}`), "key") {
+		t.Error("Sign() is not deterministic")
+	}
+	if got == Sign([]byte(`{"event":"upload"}`), "other") {
+		t.Error("Sign() ignores the secret")
+	}
+}
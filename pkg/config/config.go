@@ -4,7 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
@@ -13,6 +18,19 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	DefaultPath = "config.yaml"
+	envPrefix   = "CRAFTSTORY"
+)
+
+var x264Presets = []string{"ultrafast", "superfast", "veryfast", "faster", "fast", "medium", "slow", "slower", "veryslow", "placebo"}
+
+var privacyStatuses = []string{"public", "unlisted", "private"}
+
+var watermarkPositions = []string{"top_left", "top_right", "bottom_left", "bottom_right", "custom"}
+
+var watermarkLayers = []string{"below_subtitles", "above_subtitles"}
+
 type Config struct {
 	GCPProject           string
 	GroqAPIKey           string
@@ -25,37 +43,60 @@ type Config struct {
 	ElevenLabsAPIKey     string
 	ElevenLabsAPIKeys    []string
 	TenorAPIKey          string
+	TikTokAccessToken    string
+	WebhookSecret        string
 
 	Groq       GroqConfig       `yaml:"groq"`
 	ElevenLabs ElevenLabsConfig `yaml:"elevenlabs"`
 	Content    ContentConfig    `yaml:"content"`
 	Video      VideoConfig      `yaml:"video"`
 	Music      MusicConfig      `yaml:"music"`
+	Audio      AudioConfig      `yaml:"audio"`
 	Subtitles  SubtitlesConfig  `yaml:"subtitles"`
 	YouTube    YouTubeConfig    `yaml:"youtube"`
+	TikTok     TikTokConfig     `yaml:"tiktok"`
+	Upload     UploadConfig     `yaml:"upload"`
 	Visuals    VisualsConfig    `yaml:"visuals"`
 	Reddit     RedditConfig     `yaml:"reddit"`
+	HackerNews HackerNewsConfig `yaml:"hackernews"`
 	Telegram   TelegramConfig   `yaml:"telegram"`
+	HTTP       HTTPConfig       `yaml:"http"`
+	Timeouts   TimeoutsConfig   `yaml:"timeouts"`
+	WebhookURL string           `yaml:"webhook_url"`
+	Metrics    MetricsConfig    `yaml:"metrics"`
+	Seed       int64            `yaml:"seed"`
+	PromptsDir string           `yaml:"prompts_dir"`
+	Run        RunConfig        `yaml:"run"`
+
+	MaxConcurrentGenerations int      `yaml:"max_concurrent_generations"`
+	TopicSource              string   `yaml:"topic_source"`
+	StaticTopics             []string `yaml:"static_topics"`
 }
 
 type GroqConfig struct {
-	Model string `yaml:"model"`
+	Model             string `yaml:"model"`
+	RequestsPerMinute int    `yaml:"requests_per_minute"`
 }
 
 type ElevenLabsConfig struct {
-	Enabled        bool        `yaml:"enabled"`
-	HostVoice      VoiceConfig `yaml:"host_voice"`
-	GuestVoice     VoiceConfig `yaml:"guest_voice"`
-	TTSParallelism int         `yaml:"tts_parallelism"`
-	Speed          float64     `yaml:"speed"`
-	Stability      float64     `yaml:"stability"`
-	Similarity     float64     `yaml:"similarity"`
+	Enabled        bool              `yaml:"enabled"`
+	HostVoice      VoiceConfig       `yaml:"host_voice"`
+	GuestVoice     VoiceConfig       `yaml:"guest_voice"`
+	TTSParallelism int               `yaml:"tts_parallelism"`
+	Speed          float64           `yaml:"speed"`
+	Stability      float64           `yaml:"stability"`
+	Similarity     float64           `yaml:"similarity"`
+	Model          string            `yaml:"model"`
+	SpeakerAliases map[string]string `yaml:"speaker_aliases"`
 }
 
 type VoiceConfig struct {
-	ID            string `yaml:"id"`
-	Name          string `yaml:"name"`
-	SubtitleColor string `yaml:"subtitle_color"`
+	ID            string  `yaml:"id"`
+	Name          string  `yaml:"name"`
+	SubtitleColor string  `yaml:"subtitle_color"`
+	Stability     float64 `yaml:"stability"`
+	Similarity    float64 `yaml:"similarity"`
+	Speed         float64 `yaml:"speed"`
 }
 
 func (v VoiceConfig) ToSpeechConfig() speech.VoiceConfig {
@@ -63,91 +104,436 @@ func (v VoiceConfig) ToSpeechConfig() speech.VoiceConfig {
 		ID:            v.ID,
 		Name:          v.Name,
 		SubtitleColor: v.SubtitleColor,
+		Stability:     v.Stability,
+		Similarity:    v.Similarity,
+		Speed:         v.Speed,
 	}
 }
 
 type ContentConfig struct {
-	WordCount        int     `yaml:"word_count"`
-	ConversationMode bool    `yaml:"conversation_mode"`
-	TargetDuration   float64 `yaml:"target_duration"`
+	WordCount           int      `yaml:"word_count"`
+	ConversationMode    bool     `yaml:"conversation_mode"`
+	TargetDuration      float64  `yaml:"target_duration"`
+	LengthTolerance     float64  `yaml:"length_tolerance"`
+	LengthRetries       *int     `yaml:"length_retries"`
+	MinTurns            int      `yaml:"min_turns"`
+	WordsPerMinute      float64  `yaml:"words_per_minute"`
+	MaxTurns            int      `yaml:"max_turns"`
+	Blocklist           []string `yaml:"blocklist"`
+	OnUnsafe            string   `yaml:"on_unsafe"`
+	HookText            string   `yaml:"hook_text"`
+	HookFromScript      bool     `yaml:"hook_from_script"`
+	HookDuration        float64  `yaml:"hook_duration"`
+	Language            string   `yaml:"language"`
+	KeepStageDirections bool     `yaml:"keep_stage_directions"`
+	Chapters            bool     `yaml:"chapters"`
 }
 
 type VideoConfig struct {
-	BackgroundDir string  `yaml:"background_dir"`
-	OutputDir     string  `yaml:"output_dir"`
-	CacheDir      string  `yaml:"cache_dir"`
-	Resolution    string  `yaml:"resolution"`
-	MaxDuration   float64 `yaml:"max_duration"`
-	Threads       int     `yaml:"threads"`
+	BackgroundDir     string  `yaml:"background_dir"`
+	OutputDir         string  `yaml:"output_dir"`
+	CacheDir          string  `yaml:"cache_dir"`
+	Resolution        string  `yaml:"resolution"`
+	MaxDuration       float64 `yaml:"max_duration"`
+	Threads           int     `yaml:"threads"`
+	ThumbnailAt       float64 `yaml:"thumbnail_at"`
+	Encoder           string  `yaml:"encoder"`
+	CRF               *int    `yaml:"crf"`
+	Preset            string  `yaml:"preset"`
+	BitrateKbps       int     `yaml:"bitrate_kbps"`
+	TwoPass           bool    `yaml:"two_pass"`
+	FilenameTemplate  string  `yaml:"filename_template"`
+	KeepArtifacts     bool    `yaml:"keep_artifacts"`
+	MinFreeMB         int     `yaml:"min_free_mb"`
+	MirrorBackground  bool    `yaml:"mirror_background"`
+	CrossfadeDuration float64 `yaml:"crossfade_duration"`
+
+	SubscribeOverlay SubscribeOverlayConfig `yaml:"subscribe_overlay"`
+	Watermark        WatermarkConfig        `yaml:"watermark"`
+}
+
+type WatermarkConfig struct {
+	Path     string  `yaml:"path"`
+	Position string  `yaml:"position"`
+	X        int     `yaml:"x"`
+	Y        int     `yaml:"y"`
+	Opacity  float64 `yaml:"opacity"`
+	Scale    float64 `yaml:"scale"`
+	Layer    string  `yaml:"layer"`
+}
+
+type SubscribeOverlayConfig struct {
+	Path     string  `yaml:"path"`
+	Duration float64 `yaml:"duration"`
 }
 
 type MusicConfig struct {
-	Enabled bool    `yaml:"enabled"`
-	Dir     string  `yaml:"dir"`
-	Volume  float64 `yaml:"volume"`
-	FadeIn  float64 `yaml:"fade_in"`
-	FadeOut float64 `yaml:"fade_out"`
+	Enabled       bool              `yaml:"enabled"`
+	Dir           string            `yaml:"dir"`
+	Volume        float64           `yaml:"volume"`
+	FadeIn        float64           `yaml:"fade_in"`
+	FadeOut       float64           `yaml:"fade_out"`
+	Ducking       bool              `yaml:"ducking"`
+	DuckThreshold float64           `yaml:"duck_threshold"`
+	DuckRatio     float64           `yaml:"duck_ratio"`
+	Mood          string            `yaml:"mood"`
+	Moods         map[string]string `yaml:"moods"`
+}
+
+type AudioConfig struct {
+	Normalize   bool    `yaml:"normalize"`
+	TargetLUFS  float64 `yaml:"target_lufs"`
+	TruePeak    float64 `yaml:"true_peak"`
+	TrimSilence bool    `yaml:"trim_silence"`
 }
 
 type SubtitlesConfig struct {
-	FontName     string  `yaml:"font_name"`
-	FontSize     int     `yaml:"font_size"`
-	PrimaryColor string  `yaml:"primary_color"`
-	OutlineColor string  `yaml:"outline_color"`
-	OutlineSize  int     `yaml:"outline_size"`
-	ShadowSize   int     `yaml:"shadow_size"`
-	Bold         bool    `yaml:"bold"`
-	Offset       float64 `yaml:"offset"`
+	FontName        string            `yaml:"font_name"`
+	FontSize        int               `yaml:"font_size"`
+	PrimaryColor    string            `yaml:"primary_color"`
+	OutlineColor    string            `yaml:"outline_color"`
+	OutlineSize     int               `yaml:"outline_size"`
+	ShadowSize      int               `yaml:"shadow_size"`
+	Bold            bool              `yaml:"bold"`
+	Offset          float64           `yaml:"offset"`
+	ExportSRT       bool              `yaml:"export_srt"`
+	EmphasisWords   map[string]string `yaml:"emphasis_words"`
+	SafeZoneBottom  int               `yaml:"safe_zone_bottom"`
+	MinWordDuration float64           `yaml:"min_word_duration"`
+	WordsPerCue     int               `yaml:"words_per_cue"`
+	BackgroundBox   bool              `yaml:"background_box"`
+	BoxColor        string            `yaml:"box_color"`
 }
 
 type YouTubeConfig struct {
 	ChannelID     string   `yaml:"channel_id"`
 	DefaultTags   []string `yaml:"default_tags"`
 	PrivacyStatus string   `yaml:"privacy_status"`
+	PlaylistID    string   `yaml:"playlist_id"`
+	PublishAt     string   `yaml:"publish_at"`
+	PublishDelay  string   `yaml:"publish_delay"`
+	UploadRetries int      `yaml:"upload_retries"`
+	Account       string   `yaml:"account"`
+
+	PrivacyBySource map[string]string         `yaml:"privacy_by_source"`
+	Accounts        map[string]YouTubeAccount `yaml:"accounts"`
+}
+
+type YouTubeAccount struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	TokenPath    string `yaml:"token_path"`
+}
+
+type TikTokConfig struct {
+	PrivacyLevel string `yaml:"privacy_level"`
 }
 
 type VisualsConfig struct {
 	Position       string  `yaml:"position"`
+	SearchProvider string  `yaml:"search_provider"`
 	MaxDisplayTime float64 `yaml:"max_display_time"`
 	ImageWidth     int     `yaml:"image_width"`
 	ImageHeight    int     `yaml:"image_height"`
+	MinWidth       int     `yaml:"min_width"`
+	MinHeight      int     `yaml:"min_height"`
+	Orientation    string  `yaml:"orientation"`
+	SearchImgSize  string  `yaml:"search_img_size"`
+	SearchImgType  string  `yaml:"search_img_type"`
 	MinGap         float64 `yaml:"min_gap"`
+	MinDuration    float64 `yaml:"min_duration"`
+	MaxDuration    float64 `yaml:"max_duration"`
 	Count          int     `yaml:"count"`
+	MaxCount       int     `yaml:"max_count"`
 	GIFEnabled     bool    `yaml:"gif_enabled"`
+	KenBurns       bool    `yaml:"ken_burns"`
 }
 
 type RedditConfig struct {
-	Subreddits []string `yaml:"subreddits"`
-	Sort       string   `yaml:"sort"`
-	PostLimit  int      `yaml:"post_limit"`
+	Subreddits       []string          `yaml:"subreddits"`
+	Sort             string            `yaml:"sort"`
+	PostLimit        int               `yaml:"post_limit"`
+	StyleBySubreddit map[string]string `yaml:"style_by_subreddit"`
+	DefaultStyle     string            `yaml:"default_style"`
+	ClientID         string            `yaml:"client_id"`
+	ClientSecret     string            `yaml:"client_secret"`
+	Username         string            `yaml:"username"`
+	Password         string            `yaml:"password"`
+	MaxAttempts      int               `yaml:"max_attempts"`
+}
+
+type UploadConfig struct {
+	MaxFileSizeMB float64 `yaml:"max_file_size_mb"`
+	Reencode      bool    `yaml:"reencode"`
+}
+
+type HackerNewsConfig struct {
+	Feed      string `yaml:"feed"`
+	PostLimit int    `yaml:"post_limit"`
+}
+
+type TimeoutsConfig struct {
+	Script   float64 `yaml:"script"`
+	Audio    float64 `yaml:"audio"`
+	Assemble float64 `yaml:"assemble"`
+	Upload   float64 `yaml:"upload"`
+}
+
+type RunConfig struct {
+	Interval float64 `yaml:"interval"`
+}
+
+type MetricsConfig struct {
+	Addr string `yaml:"addr"`
+}
+
+type HTTPConfig struct {
+	Proxy     string `yaml:"proxy"`
+	UserAgent string `yaml:"user_agent"`
 }
 
 type TelegramConfig struct {
 	DefaultChatID   int64   `yaml:"default_chat_id"`
 	PreviewDuration float64 `yaml:"preview_duration"`
+	DisablePreview  bool    `yaml:"disable_preview"`
 }
 
 func Load(ctx context.Context) (*Config, error) {
+	return LoadFile(ctx, DefaultPath)
+}
+
+func LoadFile(ctx context.Context, path string) (*Config, error) {
 	_ = godotenv.Load()
 
-	data, err := os.ReadFile("config.yaml")
+	if path == "" {
+		path = DefaultPath
+	}
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("read config.yaml: %w", err)
+		return nil, fmt.Errorf("read %s: %w", path, err)
 	}
 
 	cfg := &Config{}
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("parse config.yaml: %w", err)
+	if err := decodeFile(path, data, cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := applyEnvOverrides(reflect.ValueOf(cfg).Elem(), envPrefix); err != nil {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
 	}
 
 	cfg.GCPProject = os.Getenv("GOOGLE_CLOUD_PROJECT")
 	cfg.YouTubeTokenPath = envOr("YOUTUBE_TOKEN_PATH", "./youtube_token.json")
+	cfg.PromptsDir = envOr("CRAFTSTORY_PROMPTS", cfg.PromptsDir)
 
 	cfg.loadSecrets(ctx)
 
 	return cfg, nil
 }
 
+func decodeFile(path string, data []byte, cfg *Config) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, cfg)
+	case ".toml":
+		values, err := parseTOML(data)
+		if err != nil {
+			return err
+		}
+		converted, err := yaml.Marshal(values)
+		if err != nil {
+			return err
+		}
+		return yaml.Unmarshal(converted, cfg)
+	default:
+		return fmt.Errorf("unsupported config format %q (use .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+}
+
+func applyEnvOverrides(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(tag)
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Struct {
+			if err := applyEnvOverrides(fv, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(fv, value); err != nil {
+			return fmt.Errorf("env %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setFromEnv(fv reflect.Value, value string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported list type %s", fv.Type())
+		}
+		fv.Set(reflect.ValueOf(parseAPIKeys(value)))
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
+
+func (cfg *Config) validate() error {
+	video := cfg.Video
+	if retries := cfg.Content.LengthRetries; retries != nil && *retries < 0 {
+		return fmt.Errorf("content.length_retries must not be negative, got %d", *retries)
+	}
+	if crf := video.CRF; crf != nil && (*crf < 0 || *crf > 51) {
+		return fmt.Errorf("video.crf must be between 0 and 51, got %d", *crf)
+	}
+	if video.Preset != "" && !slices.Contains(x264Presets, video.Preset) {
+		return fmt.Errorf("video.preset %q is not one of %s", video.Preset, strings.Join(x264Presets, ", "))
+	}
+	if video.BitrateKbps < 0 {
+		return fmt.Errorf("video.bitrate_kbps must not be negative, got %d", video.BitrateKbps)
+	}
+	if video.TwoPass && video.BitrateKbps == 0 {
+		return fmt.Errorf("video.two_pass requires video.bitrate_kbps")
+	}
+	if wm := video.Watermark; wm.Path != "" {
+		if wm.Position != "" && !slices.Contains(watermarkPositions, wm.Position) {
+			return fmt.Errorf("video.watermark.position %q is not one of %s", wm.Position, strings.Join(watermarkPositions, ", "))
+		}
+		if wm.Layer != "" && !slices.Contains(watermarkLayers, wm.Layer) {
+			return fmt.Errorf("video.watermark.layer %q is not one of %s", wm.Layer, strings.Join(watermarkLayers, ", "))
+		}
+		if wm.Opacity < 0 || wm.Opacity > 1 {
+			return fmt.Errorf("video.watermark.opacity must be between 0 and 1, got %g", wm.Opacity)
+		}
+	}
+	if cfg.YouTube.PrivacyStatus != "" {
+		if err := ValidatePrivacy(cfg.YouTube.PrivacyStatus); err != nil {
+			return fmt.Errorf("youtube.privacy_status: %w", err)
+		}
+	}
+	for source, privacy := range cfg.YouTube.PrivacyBySource {
+		if err := ValidatePrivacy(privacy); err != nil {
+			return fmt.Errorf("youtube.privacy_by_source.%s: %w", source, err)
+		}
+	}
+	if cfg.YouTube.PublishAt != "" {
+		publishAt, err := time.Parse(time.RFC3339, cfg.YouTube.PublishAt)
+		if err != nil {
+			return fmt.Errorf("youtube.publish_at: %w", err)
+		}
+		if !publishAt.After(time.Now()) {
+			return fmt.Errorf("youtube.publish_at must be in the future, got %s", cfg.YouTube.PublishAt)
+		}
+		if cfg.YouTube.PublishDelay != "" {
+			return fmt.Errorf("youtube.publish_at and youtube.publish_delay cannot both be set")
+		}
+	}
+	if cfg.YouTube.PublishDelay != "" {
+		delay, err := time.ParseDuration(cfg.YouTube.PublishDelay)
+		if err != nil {
+			return fmt.Errorf("youtube.publish_delay: %w", err)
+		}
+		if delay <= 0 {
+			return fmt.Errorf("youtube.publish_delay must be positive, got %s", cfg.YouTube.PublishDelay)
+		}
+	}
+	if cfg.YouTube.PublishAt != "" || cfg.YouTube.PublishDelay != "" {
+		if cfg.YouTube.PrivacyStatus == "public" {
+			return fmt.Errorf("youtube scheduled publishing cannot be combined with public privacy_status")
+		}
+		for source, privacy := range cfg.YouTube.PrivacyBySource {
+			if privacy == "public" {
+				return fmt.Errorf("youtube scheduled publishing cannot be combined with public privacy_by_source.%s", source)
+			}
+		}
+	}
+	if account := cfg.YouTube.Account; account != "" {
+		if _, ok := cfg.YouTube.Accounts[account]; !ok {
+			return fmt.Errorf("youtube.account %q is not defined in youtube.accounts", account)
+		}
+	}
+	return nil
+}
+
+func ValidatePrivacy(privacy string) error {
+	if !slices.Contains(privacyStatuses, privacy) {
+		return fmt.Errorf("privacy %q is not one of %s", privacy, strings.Join(privacyStatuses, ", "))
+	}
+	return nil
+}
+
+func (yt YouTubeConfig) PublishAtTime() time.Time {
+	publishAt, _ := time.Parse(time.RFC3339, yt.PublishAt)
+	return publishAt
+}
+
+func (yt YouTubeConfig) PublishDelayDuration() time.Duration {
+	delay, _ := time.ParseDuration(yt.PublishDelay)
+	return delay
+}
+
+func (cfg *Config) YouTubeAccount(name string) (YouTubeAccount, error) {
+	if name == "" {
+		name = cfg.YouTube.Account
+	}
+	if name == "" {
+		return YouTubeAccount{
+			ClientID:     cfg.YouTubeClientID,
+			ClientSecret: cfg.YouTubeClientSecret,
+			TokenPath:    cfg.YouTubeTokenPath,
+		}, nil
+	}
+
+	account, ok := cfg.YouTube.Accounts[name]
+	if !ok {
+		return YouTubeAccount{}, fmt.Errorf("unknown youtube account %q", name)
+	}
+	if account.ClientID == "" && account.ClientSecret == "" {
+		account.ClientID = cfg.YouTubeClientID
+		account.ClientSecret = cfg.YouTubeClientSecret
+	}
+	if account.TokenPath == "" {
+		account.TokenPath = fmt.Sprintf("./youtube_token_%s.json", name)
+	}
+	return account, nil
+}
+
 func (cfg *Config) loadSecrets(ctx context.Context) {
 	secrets := []struct {
 		secretName string
@@ -157,11 +543,13 @@ func (cfg *Config) loadSecrets(ctx context.Context) {
 		{"groq-api-key", "GROQ_API_KEY", &cfg.GroqAPIKey},
 		{"youtube-client-id", "YOUTUBE_CLIENT_ID", &cfg.YouTubeClientID},
 		{"youtube-client-secret", "YOUTUBE_CLIENT_SECRET", &cfg.YouTubeClientSecret},
+		{"tiktok-access-token", "TIKTOK_ACCESS_TOKEN", &cfg.TikTokAccessToken},
 		{"google-search-api-key", "GOOGLE_SEARCH_API_KEY", &cfg.GoogleSearchAPIKey},
 		{"google-search-engine-id", "GOOGLE_SEARCH_ENGINE_ID", &cfg.GoogleSearchEngineID},
 		{"telegram-bot-token", "TELEGRAM_BOT_TOKEN", &cfg.TelegramBotToken},
 		{"elevenlabs-api-key", "ELEVENLABS_API_KEY", &cfg.ElevenLabsAPIKey},
 		{"tenor-api-key", "TENOR_API_KEY", &cfg.TenorAPIKey},
+		{"webhook-secret", "WEBHOOK_SECRET", &cfg.WebhookSecret},
 	}
 
 	var client *secretmanager.Client
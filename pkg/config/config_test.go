@@ -4,6 +4,8 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -213,3 +215,333 @@ func TestParseAPIKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadFile(t *testing.T) {
+	yamlConfig := `
+groq:
+  model: yaml-model
+content:
+  word_count: 150
+  conversation_mode: true
+visuals:
+  min_duration: 1.5
+youtube:
+  default_tags: [one, two]
+`
+	tomlConfig := `
+# comment
+[groq]
+model = "toml-model" # trailing comment
+
+[content]
+word_count = 150
+conversation_mode = true
+
+[visuals]
+min_duration = 1.5
+
+[youtube]
+default_tags = ["one", "two"]
+`
+
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		env       map[string]string
+		wantModel string
+		wantWords int
+		wantTags  []string
+		wantErr   bool
+	}{
+		{
+			name:      "yamlFile",
+			file:      "custom.yaml",
+			content:   yamlConfig,
+			wantModel: "yaml-model",
+			wantWords: 150,
+			wantTags:  []string{"one", "two"},
+		},
+		{
+			name:      "tomlFile",
+			file:      "custom.toml",
+			content:   tomlConfig,
+			wantModel: "toml-model",
+			wantWords: 150,
+			wantTags:  []string{"one", "two"},
+		},
+		{
+			name:    "envOverridesFile",
+			file:    "custom.toml",
+			content: tomlConfig,
+			env: map[string]string{
+				"CRAFTSTORY_GROQ_MODEL":           "env-model",
+				"CRAFTSTORY_CONTENT_WORD_COUNT":   "90",
+				"CRAFTSTORY_YOUTUBE_DEFAULT_TAGS": "a, b,c",
+			},
+			wantModel: "env-model",
+			wantWords: 90,
+			wantTags:  []string{"a", "b", "c"},
+		},
+		{
+			name:    "invalidEnvValue",
+			file:    "custom.yaml",
+			content: yamlConfig,
+			env:     map[string]string{"CRAFTSTORY_CONTENT_WORD_COUNT": "many"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupportedFormat",
+			file:    "custom.json",
+			content: `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalidTOML",
+			file:    "custom.toml",
+			content: "[groq\nmodel = 1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_ = os.WriteFile(tt.file, []byte(tt.content), 0644)
+
+			cfg, err := LoadFile(context.Background(), tt.file)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("LoadFile() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadFile() error: %v", err)
+			}
+
+			if cfg.Groq.Model != tt.wantModel {
+				t.Errorf("Groq.Model = %q, want %q", cfg.Groq.Model, tt.wantModel)
+			}
+			if cfg.Content.WordCount != tt.wantWords {
+				t.Errorf("Content.WordCount = %d, want %d", cfg.Content.WordCount, tt.wantWords)
+			}
+			if !cfg.Content.ConversationMode {
+				t.Error("Content.ConversationMode = false, want true")
+			}
+			if cfg.Visuals.MinDuration != 1.5 {
+				t.Errorf("Visuals.MinDuration = %v, want 1.5", cfg.Visuals.MinDuration)
+			}
+			if !slices.Equal(cfg.YouTube.DefaultTags, tt.wantTags) {
+				t.Errorf("YouTube.DefaultTags = %v, want %v", cfg.YouTube.DefaultTags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	data := `title = 'literal # not a comment'
+escaped = "say \"hi\""
+count = 1_000
+ratio = 0.5
+[a.b]
+list = ["x, y", 'z']
+`
+	got, err := parseTOML([]byte(data))
+	if err != nil {
+		t.Fatalf("parseTOML() error: %v", err)
+	}
+
+	if got["title"] != "literal # not a comment" {
+		t.Errorf("title = %v", got["title"])
+	}
+	if got["escaped"] != `say "hi"` {
+		t.Errorf("escaped = %v", got["escaped"])
+	}
+	if got["count"] != int64(1000) {
+		t.Errorf("count = %v", got["count"])
+	}
+	if got["ratio"] != 0.5 {
+		t.Errorf("ratio = %v", got["ratio"])
+	}
+	b := got["a"].(map[string]any)["b"].(map[string]any)
+	list := b["list"].([]any)
+	if len(list) != 2 || list[0] != "x, y" || list[1] != "z" {
+		t.Errorf("list = %v", list)
+	}
+}
+
+func TestValidateVideoEncoding(t *testing.T) {
+	zero, valid, highest, tooHigh, negative := 0, 18, 51, 52, -1
+
+	tests := []struct {
+		name    string
+		video   VideoConfig
+		wantErr string
+	}{
+		{name: "defaults", video: VideoConfig{}},
+		{name: "validSettings", video: VideoConfig{CRF: &valid, Preset: "slow", BitrateKbps: 6000}},
+		{name: "zeroCRF", video: VideoConfig{CRF: &zero}},
+		{name: "maxCRF", video: VideoConfig{CRF: &highest}},
+		{name: "crfTooHigh", video: VideoConfig{CRF: &tooHigh}, wantErr: "video.crf"},
+		{name: "negativeCRF", video: VideoConfig{CRF: &negative}, wantErr: "video.crf"},
+		{name: "unknownPreset", video: VideoConfig{Preset: "turbo"}, wantErr: "video.preset"},
+		{name: "negativeBitrate", video: VideoConfig{BitrateKbps: -1}, wantErr: "video.bitrate_kbps"},
+		{name: "twoPassWithBitrate", video: VideoConfig{TwoPass: true, BitrateKbps: 8000}},
+		{name: "twoPassWithoutBitrate", video: VideoConfig{TwoPass: true}, wantErr: "video.two_pass"},
+		{name: "watermarkCorner", video: VideoConfig{Watermark: WatermarkConfig{Path: "logo.png", Position: "bottom_right"}}},
+		{name: "unknownWatermarkPosition", video: VideoConfig{Watermark: WatermarkConfig{Path: "logo.png", Position: "middle"}}, wantErr: "video.watermark.position"},
+		{name: "unknownWatermarkLayer", video: VideoConfig{Watermark: WatermarkConfig{Path: "logo.png", Layer: "under"}}, wantErr: "video.watermark.layer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Video: tt.video}
+			err := cfg.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestYouTubeAccount(t *testing.T) {
+	cfg := &Config{
+		YouTubeClientID:     "env-id",
+		YouTubeClientSecret: "env-secret",
+		YouTubeTokenPath:    "./youtube_token.json",
+		YouTube: YouTubeConfig{
+			Accounts: map[string]YouTubeAccount{
+				"gaming": {ClientID: "gaming-id", ClientSecret: "gaming-secret", TokenPath: "./tokens/gaming.json"},
+				"news":   {},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		account   string
+		defaultTo string
+		want      YouTubeAccount
+		wantErr   bool
+	}{
+		{
+			name: "envDefault",
+			want: YouTubeAccount{ClientID: "env-id", ClientSecret: "env-secret", TokenPath: "./youtube_token.json"},
+		},
+		{
+			name:    "explicitProfile",
+			account: "gaming",
+			want:    YouTubeAccount{ClientID: "gaming-id", ClientSecret: "gaming-secret", TokenPath: "./tokens/gaming.json"},
+		},
+		{
+			name:    "profileInheritsCredentials",
+			account: "news",
+			want:    YouTubeAccount{ClientID: "env-id", ClientSecret: "env-secret", TokenPath: "./youtube_token_news.json"},
+		},
+		{
+			name:      "configuredDefaultProfile",
+			defaultTo: "gaming",
+			want:      YouTubeAccount{ClientID: "gaming-id", ClientSecret: "gaming-secret", TokenPath: "./tokens/gaming.json"},
+		},
+		{
+			name:    "unknownProfile",
+			account: "missing",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.YouTube.Account = tt.defaultTo
+			got, err := cfg.YouTubeAccount(tt.account)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("YouTubeAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("YouTubeAccount() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateYouTubePrivacy(t *testing.T) {
+	tests := []struct {
+		name    string
+		youtube YouTubeConfig
+		wantErr string
+	}{
+		{name: "unset", youtube: YouTubeConfig{}},
+		{name: "valid", youtube: YouTubeConfig{PrivacyStatus: "unlisted", PrivacyBySource: map[string]string{"reddit": "private"}}},
+		{name: "invalidStatus", youtube: YouTubeConfig{PrivacyStatus: "hidden"}, wantErr: "youtube.privacy_status"},
+		{name: "invalidSource", youtube: YouTubeConfig{PrivacyBySource: map[string]string{"reddit": "friends"}}, wantErr: "youtube.privacy_by_source.reddit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{YouTube: tt.youtube}
+			err := cfg.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateYouTubePublishDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		youtube YouTubeConfig
+		wantErr string
+	}{
+		{name: "unset", youtube: YouTubeConfig{PrivacyStatus: "public"}},
+		{name: "valid", youtube: YouTubeConfig{PrivacyStatus: "private", PublishDelay: "24h"}},
+		{name: "invalidFormat", youtube: YouTubeConfig{PublishDelay: "tomorrow"}, wantErr: "youtube.publish_delay"},
+		{name: "notPositive", youtube: YouTubeConfig{PublishDelay: "-1h"}, wantErr: "must be positive"},
+		{name: "publicPrivacy", youtube: YouTubeConfig{PrivacyStatus: "public", PublishDelay: "2h"}, wantErr: "public privacy_status"},
+		{name: "publicSourcePrivacy", youtube: YouTubeConfig{PrivacyStatus: "private", PrivacyBySource: map[string]string{"reddit": "public"}, PublishDelay: "2h"}, wantErr: "public privacy_by_source.reddit"},
+		{name: "publishAtValid", youtube: YouTubeConfig{PrivacyStatus: "private", PublishAt: "2999-01-01T09:00:00Z"}},
+		{name: "publishAtInvalidFormat", youtube: YouTubeConfig{PrivacyStatus: "private", PublishAt: "tomorrow"}, wantErr: "youtube.publish_at"},
+		{name: "publishAtPast", youtube: YouTubeConfig{PrivacyStatus: "private", PublishAt: "2000-01-01T09:00:00Z"}, wantErr: "must be in the future"},
+		{name: "publishAtPublicPrivacy", youtube: YouTubeConfig{PrivacyStatus: "public", PublishAt: "2999-01-01T09:00:00Z"}, wantErr: "public privacy_status"},
+		{name: "publishAtWithDelay", youtube: YouTubeConfig{PrivacyStatus: "private", PublishAt: "2999-01-01T09:00:00Z", PublishDelay: "2h"}, wantErr: "cannot both be set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{YouTube: tt.youtube}
+			err := cfg.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateYouTubeAccount(t *testing.T) {
+	cfg := &Config{YouTube: YouTubeConfig{Account: "missing"}}
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "youtube.account") {
+		t.Errorf("validate() error = %v, want error containing %q", err, "youtube.account")
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func parseTOML(data []byte) (map[string]any, error) {
+	root := map[string]any{}
+	table := root
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(stripTOMLComment(raw))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: unsupported table header %q", i+1, line)
+			}
+			var err error
+			table, err = tomlTable(root, strings.TrimSpace(line[1:len(line)-1]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"`)
+		parsed, err := parseTOMLValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", i+1, key, err)
+		}
+		table[key] = parsed
+	}
+
+	return root, nil
+}
+
+func tomlTable(root map[string]any, name string) (map[string]any, error) {
+	table := root
+	for _, part := range strings.Split(name, ".") {
+		part = strings.Trim(strings.TrimSpace(part), `"`)
+		if part == "" {
+			return nil, fmt.Errorf("invalid table name %q", name)
+		}
+		next, ok := table[part]
+		if !ok {
+			child := map[string]any{}
+			table[part] = child
+			table = child
+			continue
+		}
+		child, ok := next.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a table", part)
+		}
+		table = child
+	}
+	return table, nil
+}
+
+func parseTOMLValue(value string) (any, error) {
+	switch {
+	case value == "":
+		return nil, fmt.Errorf("missing value")
+	case value == "true":
+		return true, nil
+	case value == "false":
+		return false, nil
+	case strings.HasPrefix(value, `"`):
+		return strconv.Unquote(value)
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return nil, fmt.Errorf("unterminated string %s", value)
+		}
+		return value[1 : len(value)-1], nil
+	case strings.HasPrefix(value, "["):
+		return parseTOMLArray(value)
+	}
+
+	number := strings.ReplaceAll(value, "_", "")
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("unsupported value %s", value)
+}
+
+func parseTOMLArray(value string) ([]any, error) {
+	if !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("arrays must be on a single line")
+	}
+	inner := strings.TrimSpace(value[1 : len(value)-1])
+	items := []any{}
+	for _, item := range splitTOMLArray(inner) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parsed, err := parseTOMLValue(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, parsed)
+	}
+	return items, nil
+}
+
+func splitTOMLArray(inner string) []string {
+	var items []string
+	var quote rune
+	start := 0
+	for i, r := range inner {
+		switch {
+		case quote != 0:
+			if r == quote && (quote == '\'' || i == 0 || inner[i-1] != '\\') {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ',':
+			items = append(items, inner[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, inner[start:])
+}
+
+func stripTOMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote && (quote == '\'' || i == 0 || line[i-1] != '\\') {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
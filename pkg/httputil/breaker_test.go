@@ -0,0 +1,131 @@
+package httputil
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	breaker := newCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	breaker.record(true)
+	if breaker.currentState() != breakerClosed {
+		t.Fatal("expected breaker to stay closed below threshold")
+	}
+
+	breaker.record(true)
+	if breaker.currentState() != breakerOpen {
+		t.Fatal("expected breaker to open at threshold")
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() during cooldown = %v, want ErrCircuitOpen", err)
+	}
+
+	now = now.Add(time.Minute)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("allow() after cooldown = %v, want probe", err)
+	}
+	if breaker.currentState() != breakerHalfOpen {
+		t.Fatal("expected breaker to be half-open after cooldown")
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() during probe = %v, want ErrCircuitOpen", err)
+	}
+
+	breaker.record(true)
+	if breaker.currentState() != breakerOpen {
+		t.Fatal("expected failed probe to reopen breaker")
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() after failed probe = %v, want ErrCircuitOpen", err)
+	}
+
+	now = now.Add(time.Minute)
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("allow() after second cooldown = %v, want probe", err)
+	}
+	breaker.record(false)
+	if breaker.currentState() != breakerClosed {
+		t.Fatal("expected successful probe to close breaker")
+	}
+
+	breaker.record(true)
+	if breaker.currentState() != breakerClosed {
+		t.Fatal("expected failure count to reset after closing")
+	}
+}
+
+func TestRetryClientCircuitBreaker(t *testing.T) {
+	var attempts int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewRetryClient(server.Client(), RetryConfig{
+		MaxRetries:       1,
+		InitialDelay:     time.Millisecond,
+		MaxDelay:         time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Hour,
+	})
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+
+	do := func() (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	for range 2 {
+		if _, err := do(); err != nil {
+			t.Fatalf("unexpected error before breaker opened: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&attempts); got != 4 {
+		t.Fatalf("attempts = %d, want 4", got)
+	}
+
+	if _, err := do(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Do() = %v, want ErrCircuitOpen", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 4 {
+		t.Errorf("open breaker sent a request, attempts = %d", got)
+	}
+
+	healthy.Store(true)
+	now = now.Add(time.Hour)
+	resp, err := do()
+	if err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("probe status = %d, want 200", resp.StatusCode)
+	}
+	if client.breaker.currentState() != breakerClosed {
+		t.Error("expected breaker to close after successful probe")
+	}
+}
+
+func TestRetryClientWithoutBreaker(t *testing.T) {
+	client := NewRetryClient(nil, RetryConfig{})
+	if client.breaker != nil {
+		t.Error("expected no breaker when threshold is zero")
+	}
+}
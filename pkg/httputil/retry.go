@@ -4,6 +4,8 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,11 +14,16 @@ type RetryConfig struct {
 	InitialDelay time.Duration
 	MaxDelay     time.Duration
 	Multiplier   float64
+	Jitter       float64
+
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
 }
 
 type RetryClient struct {
-	client *http.Client
-	config RetryConfig
+	client  *http.Client
+	config  RetryConfig
+	breaker *circuitBreaker
 }
 
 func DefaultRetryConfig() RetryConfig {
@@ -25,6 +32,7 @@ func DefaultRetryConfig() RetryConfig {
 		InitialDelay: 500 * time.Millisecond,
 		MaxDelay:     5 * time.Second,
 		Multiplier:   2.0,
+		Jitter:       0.1,
 	}
 }
 
@@ -45,17 +53,41 @@ func NewRetryClient(client *http.Client, config RetryConfig) *RetryClient {
 	if config.Multiplier == 0 {
 		config.Multiplier = 2.0
 	}
+	config.Jitter = min(max(config.Jitter, 0), 1)
 
-	return &RetryClient{
+	retryClient := &RetryClient{
 		client: client,
 		config: config,
 	}
+	if config.BreakerThreshold > 0 {
+		if config.BreakerCooldown == 0 {
+			config.BreakerCooldown = 30 * time.Second
+		}
+		retryClient.config = config
+		retryClient.breaker = newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown)
+	}
+
+	return retryClient
 }
 
 func (c *RetryClient) Do(req *http.Request) (*http.Response, error) {
+	if c.breaker == nil {
+		return c.do(req)
+	}
+
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	c.breaker.record(err != nil || shouldRetry(resp, nil))
+	return resp, err
+}
+
+func (c *RetryClient) do(req *http.Request) (*http.Response, error) {
 	var resp *http.Response
 	var err error
 	delay := c.config.InitialDelay
+	var wait time.Duration
 
 	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
 		if attempt > 0 {
@@ -67,7 +99,9 @@ func (c *RetryClient) Do(req *http.Request) (*http.Response, error) {
 				req.Body = body
 			}
 
-			time.Sleep(applyJitter(delay))
+			if err := sleep(req, wait); err != nil {
+				return nil, err
+			}
 			delay = min(time.Duration(float64(delay)*c.config.Multiplier), c.config.MaxDelay)
 		}
 
@@ -76,7 +110,14 @@ func (c *RetryClient) Do(req *http.Request) (*http.Response, error) {
 			return resp, err
 		}
 
+		wait = applyJitter(delay, c.config.Jitter)
 		if resp != nil {
+			if retryAfter, ok := c.retryAfter(resp); ok {
+				wait = retryAfter
+			}
+			if attempt == c.config.MaxRetries {
+				return resp, nil
+			}
 			_ = resp.Body.Close()
 		}
 	}
@@ -105,7 +146,51 @@ func shouldRetry(resp *http.Response, err error) bool {
 	return resp.StatusCode >= 500 && resp.StatusCode < 600
 }
 
-func applyJitter(delay time.Duration) time.Duration {
-	jitterFactor := 0.9 + rand.Float64()*0.2
+func applyJitter(delay time.Duration, jitter float64) time.Duration {
+	if jitter == 0 {
+		return delay
+	}
+	jitterFactor := 1 - jitter + rand.Float64()*2*jitter
 	return time.Duration(float64(delay) * jitterFactor)
 }
+
+func (c *RetryClient) retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		return 0, false
+	}
+	return min(delay, c.config.MaxDelay), true
+}
+
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(at.Sub(now), 0), true
+}
+
+func sleep(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -285,6 +285,9 @@ func TestDefaultRetryConfig(t *testing.T) {
 	if config.Multiplier != 2.0 {
 		t.Errorf("expected Multiplier 2.0, got %f", config.Multiplier)
 	}
+	if config.Jitter != 0.1 {
+		t.Errorf("expected Jitter 0.1, got %f", config.Jitter)
+	}
 }
 
 func TestNewRetryClientAppliesDefaults(t *testing.T) {
@@ -404,3 +407,182 @@ func TestRetryClientDoesNotRetryOn404(t *testing.T) {
 		t.Errorf("expected 1 attempt (no retry), got %d", attempts)
 	}
 }
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "seconds", value: "3", want: 3 * time.Second, wantOK: true},
+		{name: "zero", value: "0", want: 0, wantOK: true},
+		{name: "httpDate", value: now.Add(10 * time.Second).Format(http.TimeFormat), want: 10 * time.Second, wantOK: true},
+		{name: "pastDate", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, wantOK: true},
+		{name: "empty", value: "", wantOK: false},
+		{name: "negative", value: "-5", wantOK: false},
+		{name: "garbage", value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.wantOK {
+				t.Fatalf("parseRetryAfter(%q) ok = %v, want %v", tt.value, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryClientHonorsRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		maxDelay   time.Duration
+	}{
+		{name: "seconds", status: http.StatusTooManyRequests, retryAfter: "0", maxDelay: time.Hour},
+		{name: "httpDate", status: http.StatusServiceUnavailable, retryAfter: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), maxDelay: time.Hour},
+		{name: "cappedByMaxDelay", status: http.StatusTooManyRequests, retryAfter: "3600", maxDelay: 10 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) < 2 {
+					w.Header().Set("Retry-After", tt.retryAfter)
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := NewRetryClient(server.Client(), RetryConfig{
+				MaxRetries:   1,
+				InitialDelay: tt.maxDelay,
+				MaxDelay:     tt.maxDelay,
+			})
+
+			start := time.Now()
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("expected status 200, got %d", resp.StatusCode)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("retry took %v, expected Retry-After to replace backoff", elapsed)
+			}
+		})
+	}
+}
+
+func TestRetryClientFallsBackWithoutRetryAfter(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 2 {
+			w.Header().Set("Retry-After", "later")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	delay := 50 * time.Millisecond
+	client := NewRetryClient(server.Client(), RetryConfig{
+		MaxRetries:   1,
+		InitialDelay: delay,
+		MaxDelay:     delay,
+	})
+
+	start := time.Now()
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if elapsed := time.Since(start); elapsed < delay*9/10 {
+		t.Errorf("retry took %v, expected backoff of at least %v", elapsed, delay*9/10)
+	}
+}
+
+func TestApplyJitter(t *testing.T) {
+	delay := 100 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		jitter  float64
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{name: "disabled", jitter: 0, wantMin: delay, wantMax: delay},
+		{name: "default", jitter: 0.1, wantMin: 90 * time.Millisecond, wantMax: 110 * time.Millisecond},
+		{name: "full", jitter: 1, wantMin: 0, wantMax: 200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 100 {
+				got := applyJitter(delay, tt.jitter)
+				if got < tt.wantMin || got > tt.wantMax {
+					t.Fatalf("applyJitter(%v, %v) = %v, want between %v and %v", delay, tt.jitter, got, tt.wantMin, tt.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryClientWithoutJitterIsDeterministic(t *testing.T) {
+	var timestamps []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamps = append(timestamps, time.Now())
+		if len(timestamps) < 4 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewRetryClient(server.Client(), RetryConfig{
+		MaxRetries:   3,
+		InitialDelay: 20 * time.Millisecond,
+		MaxDelay:     time.Second,
+		Multiplier:   2.0,
+		Jitter:       0,
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if len(timestamps) != 4 {
+		t.Fatalf("expected 4 attempts, got %d", len(timestamps))
+	}
+
+	tolerance := 15 * time.Millisecond
+	expectedDelays := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond}
+	for i, expected := range expectedDelays {
+		got := timestamps[i+1].Sub(timestamps[i])
+		if got < expected || got > expected+tolerance {
+			t.Errorf("delay %d: expected %v (+%v), got %v", i+1, expected, tolerance, got)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package httputil
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type TransportConfig struct {
+	Proxy     string
+	UserAgent string
+}
+
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func NewTransport(cfg TransportConfig) (http.RoundTripper, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %q", cfg.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.UserAgent == "" {
+		return transport, nil
+	}
+	return &userAgentTransport{base: transport, userAgent: cfg.UserAgent}, nil
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req)
+}
@@ -2,20 +2,69 @@ package prompts
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"text/template"
 
 	"gopkg.in/yaml.v3"
 )
 
-const defaultPromptsPath = "prompts.yaml"
+const (
+	defaultPromptsPath = "prompts.yaml"
+	overrideExt        = ".tmpl"
+)
+
+var templateParams = map[string]any{
+	"script.single":        ScriptParams{},
+	"script.conversation":  ConversationParams{},
+	"script.visuals":       VisualsParams{},
+	"title.generate":       TitleParams{},
+	"description.generate": DescriptionParams{},
+	"tags.generate":        TagsParams{},
+}
+
+var languageNames = map[string]string{
+	"ar": "Arabic",
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"hi": "Hindi",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"lt": "Lithuanian",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"sv": "Swedish",
+	"tr": "Turkish",
+	"uk": "Ukrainian",
+	"zh": "Chinese",
+}
+
+var requiredPlaceholders = map[string][]string{
+	"script.single":        {"Topic"},
+	"script.conversation":  {"SpeakerList"},
+	"script.visuals":       {"Script"},
+	"title.generate":       {"Script"},
+	"description.generate": {"Script"},
+	"tags.generate":        {"Script"},
+}
 
 type Prompts struct {
-	System SystemPrompts `yaml:"system"`
-	Script ScriptPrompts `yaml:"script"`
-	Title  TitlePrompts  `yaml:"title"`
-	Tags   TagsPrompts   `yaml:"tags"`
+	System      SystemPrompts      `yaml:"system"`
+	Script      ScriptPrompts      `yaml:"script"`
+	Title       TitlePrompts       `yaml:"title"`
+	Description DescriptionPrompts `yaml:"description"`
+	Tags        TagsPrompts        `yaml:"tags"`
 }
 
 type SystemPrompts struct {
@@ -23,6 +72,7 @@ type SystemPrompts struct {
 	Conversation string `yaml:"conversation"`
 	Visuals      string `yaml:"visuals"`
 	Title        string `yaml:"title"`
+	Description  string `yaml:"description"`
 	Tags         string `yaml:"tags"`
 }
 
@@ -36,6 +86,10 @@ type TitlePrompts struct {
 	Generate string `yaml:"generate"`
 }
 
+type DescriptionPrompts struct {
+	Generate string `yaml:"generate"`
+}
+
 type TagsPrompts struct {
 	Generate string `yaml:"generate"`
 }
@@ -43,6 +97,7 @@ type TagsPrompts struct {
 type ScriptParams struct {
 	Topic     string
 	WordCount int
+	Language  string
 }
 
 type ConversationParams struct {
@@ -51,6 +106,7 @@ type ConversationParams struct {
 	SpeakerList  string
 	FirstSpeaker string
 	LastSpeaker  string
+	Language     string
 }
 
 type VisualsParams struct {
@@ -62,6 +118,10 @@ type TitleParams struct {
 	Script string
 }
 
+type DescriptionParams struct {
+	Script string
+}
+
 type TagsParams struct {
 	Script string
 	Count  int
@@ -82,9 +142,137 @@ func LoadFrom(path string) (*Prompts, error) {
 		return nil, fmt.Errorf("failed to parse prompts file: %w", err)
 	}
 
+	if err := p.checkTemplates(); err != nil {
+		return nil, fmt.Errorf("invalid prompts file %s: %w", path, err)
+	}
+
 	return &p, nil
 }
 
+func LoadWithOverrides(dir string) (*Prompts, error) {
+	p, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if dir != "" {
+		if err := p.applyOverrides(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Prompts) applyOverrides(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read prompts dir: %w", err)
+	}
+
+	templates := p.templates()
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != overrideExt {
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), overrideExt)
+		dest, ok := templates[name]
+		if !ok {
+			return fmt.Errorf("unknown prompt override %q (expected one of: %s)", entry.Name(), strings.Join(templateNames(templates), ", "))
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("read prompt override %s: %w", entry.Name(), err)
+		}
+		*dest = string(data)
+	}
+
+	return nil
+}
+
+func (p *Prompts) Validate() error {
+	if err := p.checkTemplates(); err != nil {
+		return err
+	}
+
+	templates := p.templates()
+	var errs []error
+	for _, name := range templateNames(templates) {
+		for _, field := range requiredPlaceholders[name] {
+			if !regexp.MustCompile(`\{\{-?\s*\.` + field + `\b`).MatchString(*templates[name]) {
+				errs = append(errs, fmt.Errorf("prompt %s: missing required placeholder {{.%s}}", name, field))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Prompts) checkTemplates() error {
+	templates := p.templates()
+
+	var errs []error
+	for _, name := range templateNames(templates) {
+		t, err := template.New(name).Parse(*templates[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("prompt %s: %w", name, err))
+			continue
+		}
+
+		params, ok := templateParams[name]
+		if !ok {
+			continue
+		}
+		if err := t.Execute(io.Discard, params); err != nil {
+			errs = append(errs, fmt.Errorf("prompt %s uses a placeholder %T does not provide: %w", name, params, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Prompts) templates() map[string]*string {
+	return map[string]*string{
+		"system.default":       &p.System.Default,
+		"system.conversation":  &p.System.Conversation,
+		"system.visuals":       &p.System.Visuals,
+		"system.title":         &p.System.Title,
+		"system.description":   &p.System.Description,
+		"system.tags":          &p.System.Tags,
+		"script.single":        &p.Script.Single,
+		"script.conversation":  &p.Script.Conversation,
+		"script.visuals":       &p.Script.Visuals,
+		"title.generate":       &p.Title.Generate,
+		"description.generate": &p.Description.Generate,
+		"tags.generate":        &p.Tags.Generate,
+	}
+}
+
+func templateNames(templates map[string]*string) []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func LanguageName(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return languageNames["en"]
+	}
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
 func (p *Prompts) RenderScript(params ScriptParams) (string, error) {
 	return render(p.Script.Single, params)
 }
@@ -101,6 +289,10 @@ func (p *Prompts) RenderTitle(params TitleParams) (string, error) {
 	return render(p.Title.Generate, params)
 }
 
+func (p *Prompts) RenderDescription(params DescriptionParams) (string, error) {
+	return render(p.Description.Generate, params)
+}
+
 func (p *Prompts) RenderTags(params TagsParams) (string, error) {
 	return render(p.Tags.Generate, params)
 }
@@ -3,6 +3,7 @@ package prompts
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -92,6 +93,177 @@ func TestLoadFromInvalid(t *testing.T) {
 	}
 }
 
+func TestLoadWithOverrides(t *testing.T) {
+	promptsContent := `
+system:
+  title: "Default title system"
+script:
+  single: "Write about {{.Topic}}"
+  conversation: "Talk with {{.SpeakerList}}"
+  visuals: "Visuals for {{.Script}}"
+title:
+  generate: "Default title for {{.Script}}"
+description:
+  generate: "Describe {{.Script}}"
+tags:
+  generate: "Tag {{.Script}}"
+`
+
+	tests := []struct {
+		name       string
+		overrides  map[string]string
+		wantErr    string
+		wantTitle  string
+		wantSystem string
+	}{
+		{
+			name:       "noOverrides",
+			wantTitle:  "Default title for {{.Script}}",
+			wantSystem: "Default title system",
+		},
+		{
+			name: "overridesOneTemplate",
+			overrides: map[string]string{
+				"title.generate.tmpl": "Custom title for {{ .Script }}",
+				"README.md":           "ignored",
+			},
+			wantTitle:  "Custom title for {{ .Script }}",
+			wantSystem: "Default title system",
+		},
+		{
+			name:      "unknownOverride",
+			overrides: map[string]string{"titel.generate.tmpl": "typo {{.Script}}"},
+			wantErr:   "unknown prompt override",
+		},
+		{
+			name:      "missingPlaceholder",
+			overrides: map[string]string{"title.generate.tmpl": "A title without the script"},
+			wantErr:   "missing required placeholder {{.Script}}",
+		},
+		{
+			name:      "unknownPlaceholder",
+			overrides: map[string]string{"title.generate.tmpl": "Title for {{.Script}} by {{.Author}}"},
+			wantErr:   "can't evaluate field Author",
+		},
+		{
+			name:      "invalidTemplate",
+			overrides: map[string]string{"tags.generate.tmpl": "Tag {{.Script"},
+			wantErr:   "prompt tags.generate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			t.Chdir(tmpDir)
+			if err := os.WriteFile("prompts.yaml", []byte(promptsContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			overrideDir := ""
+			if tt.overrides != nil {
+				overrideDir = filepath.Join(tmpDir, "overrides")
+				if err := os.Mkdir(overrideDir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				for name, content := range tt.overrides {
+					if err := os.WriteFile(filepath.Join(overrideDir, name), []byte(content), 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			p, err := LoadWithOverrides(overrideDir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadWithOverrides() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadWithOverrides() error = %v", err)
+			}
+
+			if p.Title.Generate != tt.wantTitle {
+				t.Errorf("Title.Generate = %q, want %q", p.Title.Generate, tt.wantTitle)
+			}
+			if p.System.Title != tt.wantSystem {
+				t.Errorf("System.Title = %q, want %q", p.System.Title, tt.wantSystem)
+			}
+		})
+	}
+}
+
+func TestLoadWithOverridesMissingDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("prompts.yaml", []byte("system:\n  default: x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadWithOverrides("/nonexistent/prompts"); err == nil {
+		t.Error("expected error for missing prompts dir")
+	}
+}
+
+func TestBundledPromptsValid(t *testing.T) {
+	p, err := LoadFrom("../../prompts.yaml")
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestLoadFromUnknownPlaceholder(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantContain []string
+	}{
+		{
+			name:        "conversationTypo",
+			content:     "script:\n  conversation: \"Talk between {{.Speakers}}\"\n",
+			wantContain: []string{"script.conversation", "ConversationParams", "Speakers"},
+		},
+		{
+			name:        "tagsTypo",
+			content:     "tags:\n  generate: \"{{.Count}} tags for {{.Scrpit}}\"\n",
+			wantContain: []string{"tags.generate", "TagsParams", "Scrpit"},
+		},
+		{
+			name:        "systemPromptNotExecuted",
+			content:     "system:\n  default: \"plain text\"\n",
+			wantContain: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promptsPath := filepath.Join(t.TempDir(), "prompts.yaml")
+			if err := os.WriteFile(promptsPath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadFrom(promptsPath)
+			if tt.wantContain == nil {
+				if err != nil {
+					t.Errorf("LoadFrom() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("LoadFrom() expected error for unknown placeholder")
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("LoadFrom() error = %q, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
 func TestRenderScript(t *testing.T) {
 	p := &Prompts{
 		Script: ScriptPrompts{
@@ -113,6 +285,52 @@ func TestRenderScript(t *testing.T) {
 	}
 }
 
+func TestRenderScriptLanguage(t *testing.T) {
+	p := &Prompts{
+		Script: ScriptPrompts{
+			Single:       "Script about {{.Topic}}. Write the script in {{.Language}}.",
+			Conversation: "Conversation with {{.SpeakerList}}. Write the script in {{.Language}}.",
+		},
+	}
+
+	script, err := p.RenderScript(ScriptParams{Topic: "space", Language: LanguageName("lt")})
+	if err != nil {
+		t.Fatalf("RenderScript() error = %v", err)
+	}
+	if want := "Script about space. Write the script in Lithuanian."; script != want {
+		t.Errorf("RenderScript() = %q, want %q", script, want)
+	}
+
+	conversation, err := p.RenderConversation(ConversationParams{SpeakerList: "Host, Guest", Language: LanguageName("es")})
+	if err != nil {
+		t.Fatalf("RenderConversation() error = %v", err)
+	}
+	if want := "Conversation with Host, Guest. Write the script in Spanish."; conversation != want {
+		t.Errorf("RenderConversation() = %q, want %q", conversation, want)
+	}
+}
+
+func TestLanguageName(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{name: "empty", code: "", want: "English"},
+		{name: "known", code: "de", want: "German"},
+		{name: "upperCase", code: "JA", want: "Japanese"},
+		{name: "unknown", code: "Esperanto", want: "esperanto"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LanguageName(tt.code); got != tt.want {
+				t.Errorf("LanguageName(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestRenderConversation(t *testing.T) {
 	p := &Prompts{
 		Script: ScriptPrompts{
@@ -175,6 +393,24 @@ func TestRenderTitle(t *testing.T) {
 	}
 }
 
+func TestRenderDescription(t *testing.T) {
+	p := &Prompts{
+		Description: DescriptionPrompts{
+			Generate: "Describe: {{.Script}}",
+		},
+	}
+
+	result, err := p.RenderDescription(DescriptionParams{Script: "A story about space"})
+	if err != nil {
+		t.Fatalf("RenderDescription() error = %v", err)
+	}
+
+	expected := "Describe: A story about space"
+	if result != expected {
+		t.Errorf("RenderDescription() = %q, want %q", result, expected)
+	}
+}
+
 func TestRenderInvalidTemplate(t *testing.T) {
 	p := &Prompts{
 		Script: ScriptPrompts{